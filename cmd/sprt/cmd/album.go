@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+var albumPlay bool
+
+var albumCmd = &cobra.Command{
+	Use:   "album [name]",
+	Short: "Show an album and its tracklist",
+	Long: `Show the tracklist, release date and label of the album of the currently playing track,
+or of the album matching the given name, Spotify URI or open.spotify.com link.
+Use --play to start playing the album from its first track.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showAlbum(strings.Join(args, " "), albumPlay)
+	},
+}
+
+// showAlbum prints the album and optionally starts playing it.
+func showAlbum(ref string, play bool) error {
+	ctx := commandContext()
+
+	albumID, err := resolveAlbumID(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	album, err := searchUseCase.GetAlbum(ctx, albumID)
+	if err != nil {
+		return fmt.Errorf("failed to get album: %w", err)
+	}
+	if hideExplicit() {
+		album.Tracks = usecase.WithoutExplicit(album.Tracks)
+	}
+
+	if play {
+		if err := playerUseCase.PlayURI(ctx, album.URI); err != nil {
+			return fmt.Errorf("failed to start playback: %w", err)
+		}
+		recordRequest(ctx, album.URI, album.Name, "album")
+		return printResult(album, "Playing %s by %s\n", album.Name, album.Artist)
+	}
+	if jsonOutput {
+		return printJSON(album)
+	}
+
+	details := []string{album.Artist}
+	if album.ReleaseDate != "" {
+		details = append(details, album.ReleaseDate)
+	}
+	if album.Label != "" {
+		details = append(details, album.Label)
+	}
+	details = append(details, fmt.Sprintf("%d tracks", album.TotalTracks))
+
+	fmt.Println(album.Name)
+	fmt.Printf("%s\n\n", strings.Join(details, " · "))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tTITLE\tARTIST\tDURATION")
+	for i, track := range album.Tracks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, track.Title, track.Artist, formatDuration(track.DurationMs))
+	}
+
+	return w.Flush()
+}
+
+// resolveAlbumID resolves an album URI, link or name to its ID.
+// An empty reference means the album of the currently playing track.
+func resolveAlbumID(ctx context.Context, ref string) (string, error) {
+	if ref == "" {
+		track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get currently playing track: %w", err)
+		}
+		if track.AlbumID == "" {
+			return "", fmt.Errorf("the currently playing item doesn't belong to an album")
+		}
+		return track.AlbumID, nil
+	}
+
+	if id, ok := parseSpotifyID(ref, "album"); ok {
+		return id, nil
+	}
+
+	results, err := searchUseCase.Search(ctx, ref, "album", 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to search for album: %w", err)
+	}
+	if len(results) == 0 {
+		return "", fmt.Errorf("no album found for %q", ref)
+	}
+
+	return results[0].ID, nil
+}
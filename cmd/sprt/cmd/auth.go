@@ -3,12 +3,19 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/browser"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/muhadif/sprt/infrastructure/secret"
 	httpinterface "github.com/muhadif/sprt/interfaces/http"
 	"github.com/muhadif/sprt/interfaces/tui"
 	"github.com/spf13/cobra"
@@ -20,12 +27,30 @@ var authCmd = &cobra.Command{
 	Long:  `Commands for authenticating with Spotify.`,
 }
 
+var (
+	authInitManual    bool
+	authInitNoBrowser bool
+)
+
 var authInitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize authentication with Spotify",
-	Long:  `Initialize authentication with Spotify by providing your client ID and secret.`,
+	Long: `Initialize authentication with Spotify by providing your client ID and secret.
+On servers without a browser, --manual skips the local callback server: open the printed URL
+on any machine, then paste back the URL you were redirected to, or just its code parameter.
+The authorization URL is opened in your default browser unless --no-browser is given.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return initAuth(authUseCase)
+		return initAuth(authUseCase, authInitManual, !authInitNoBrowser)
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether you are logged in to Spotify",
+	Long: `Show the stored client ID, whether you are logged in, when the access token expires and the
+scopes it grants. Nothing is sent to Spotify; "sprt auth test" checks that the tokens work.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showAuthStatus(authUseCase)
 	},
 }
 
@@ -38,69 +63,412 @@ var authTestCmd = &cobra.Command{
 	},
 }
 
+var (
+	authScopesYes       bool
+	authScopesNoBrowser bool
+)
+
+var authScopesCmd = &cobra.Command{
+	Use:   "scopes",
+	Short: "Show granted and required API scopes",
+	Long: `Show the Spotify API scopes needed by each enabled feature and whether your token grants them.
+When scopes are missing, you are offered to re-authorize so that only the missing scopes are added.
+Features can be limited with "auth.features" in ~/.sprt/config.json.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showScopes(authUseCase, authScopesYes, !authScopesNoBrowser)
+	},
+}
+
+var authLogoutKeepClient bool
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Delete the stored Spotify credentials",
+	Long: `Securely delete the stored access and refresh tokens, and your client ID and secret
+unless --keep-client is given. To also revoke sprt's access, remove the app at
+https://www.spotify.com/account/apps/.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return logout(authUseCase, authLogoutKeepClient)
+	},
+}
+
+var authEncryptKey string
+
+var authEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the stored credentials",
+	Long: `Encrypt ~/.sprt/auth.json so that the client secret and tokens aren't stored in plaintext.
+With --key machine (the default), the key is derived from this machine's ID and your user, so the file
+only opens on this account. With --key passphrase, the key is derived from a passphrase read from
+` + secret.PassphraseEnv + ` or asked for on the terminal. --key none decrypts the file again.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return encryptCredentials(authUseCase, authEncryptKey)
+	},
+}
+
 // init function is no longer needed as commands are initialized in root.go
 // through the InitializeCommands function
 
 // initAuth initializes the authentication process.
-func initAuth(authUseCase usecase.AuthUseCase) error {
+func initAuth(authUseCase usecase.AuthUseCase, manual, openBrowser bool) error {
 	fmt.Println("Initializing Spotify authentication...")
 
 	// Use the TUI to get client ID and client secret
-	clientID, clientSecret, err := tui.RunAuthUI()
+	clientID, clientSecret, err := tui.RunAuthUI(appConfig)
 	if err != nil {
 		return fmt.Errorf("failed to get authentication credentials: %w", err)
 	}
 
 	// Initialize authentication with the provided credentials
-	authURL, err := authUseCase.InitAuth(context.Background(), clientID, clientSecret)
+	authURL, err := authUseCase.InitAuth(commandContext(), clientID, clientSecret)
 	if err != nil {
 		return fmt.Errorf("failed to initialize authentication: %w", err)
 	}
 
-	// Start the callback server
+	if manual {
+		err = authorizeManually(authUseCase, authURL)
+	} else {
+		err = authorize(authUseCase, authURL, clientID, clientSecret, openBrowser)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Authentication process completed.")
+	return nil
+}
+
+// authorize shows the authorization URL, opening it in the browser when asked,
+// and waits for Spotify to redirect to the callback server.
+func authorize(authUseCase usecase.AuthUseCase, authURL, clientID, clientSecret string, openBrowser bool) error {
+	// Start the callback server; it's stopped however the authorization ends
 	callbackServer := httpinterface.NewCallbackServer(authUseCase)
-	go func() {
+	app.Go(func(context.Context) {
 		err := callbackServer.Start(8080)
 		if err != nil && err != http.ErrServerClosed {
 			fmt.Printf("Error starting callback server: %v\n", err)
 		}
+	})
+	defer func() {
+		if err := callbackServer.Stop(context.Background()); err != nil {
+			fmt.Printf("Error stopping callback server: %v\n", err)
+		}
 	}()
 
+	// Fall back to showing the URL when no browser can be started
+	browserOpened := openBrowser && browser.Open(authURL) == nil
+
 	// Use the TUI to display the authorization URL and wait for completion
-	err = tui.RunAuthWaitingUI(authURL, clientID, clientSecret)
+	err := tui.RunAuthWaitingUI(appConfig, authURL, clientID, clientSecret, browserOpened, callbackServer.Done())
 	if err != nil {
 		return fmt.Errorf("error in authentication UI: %w", err)
 	}
 
-	// Stop the callback server
-	ctx := context.Background()
-	if err := callbackServer.Stop(ctx); err != nil {
-		fmt.Printf("Error stopping callback server: %v\n", err)
+	return nil
+}
+
+// startAuthorization starts the authorization of the TUI's authentication screen, opening the
+// authorization URL in the browser. The callback server runs until the callback arrives or ctx ends.
+func startAuthorization(ctx context.Context, clientID, clientSecret string) (*tui.Authorization, error) {
+	authURL, err := authUseCase.InitAuth(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize authentication: %w", err)
+	}
+
+	// The TUI takes the terminal, so the server starts without announcing itself
+	callbackServer := httpinterface.NewCallbackServer(authUseCase)
+	stopped := make(chan error, 1)
+	app.Go(func(context.Context) {
+		stopped <- callbackServer.Listen(8080)
+	})
+
+	done := make(chan error, 1)
+	app.Go(func(context.Context) {
+		var err error
+		select {
+		case err = <-callbackServer.Done():
+		case err = <-stopped:
+			err = fmt.Errorf("callback server stopped: %w", err)
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		done <- err
+		if err := callbackServer.Stop(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error stopping callback server: %v\n", err)
+		}
+	})
+
+	return &tui.Authorization{
+		URL:           authURL,
+		BrowserOpened: browser.Open(authURL) == nil,
+		Done:          done,
+	}, nil
+}
+
+// authStatus is the stored authorization, as printed by "sprt auth status --json".
+type authStatus struct {
+	ClientID string `json:"client_id"`
+	LoggedIn bool   `json:"logged_in"`
+	// ExpiresAt is when the access token expires; it is refreshed automatically
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	Scopes        []string   `json:"scopes"`
+	MissingScopes []string   `json:"missing_scopes"`
+}
+
+// showAuthStatus prints the stored authorization without calling Spotify.
+func showAuthStatus(authUseCase usecase.AuthUseCase) error {
+	auth, err := authUseCase.GetToken(commandContext())
+	if err != nil {
+		return fmt.Errorf("failed to get the stored credentials: %w", err)
 	}
 
-	fmt.Println("Authentication process completed.")
+	status := authStatus{
+		ClientID:      auth.ClientID,
+		LoggedIn:      auth.RefreshToken != "",
+		Scopes:        []string{},
+		MissingScopes: []string{},
+	}
+	if status.LoggedIn {
+		status.Scopes = strings.Fields(auth.Scope)
+		if missing := usecase.MissingScopes(status.Scopes, authUseCase.RequiredScopes()); missing != nil {
+			status.MissingScopes = missing
+		}
+		if auth.ExpiresAt != 0 {
+			expiresAt := time.Unix(auth.ExpiresAt, 0)
+			status.ExpiresAt = &expiresAt
+		}
+	}
+	if jsonOutput {
+		return printJSON(status)
+	}
+
+	switch {
+	case status.ClientID == "":
+		fmt.Println("Not logged in. Run 'sprt auth init' to log in.")
+		return nil
+	case !status.LoggedIn:
+		fmt.Printf("Not logged in, with the client %s. Run 'sprt auth scopes' or 'sprt auth init' to log in.\n", status.ClientID)
+		return nil
+	}
+
+	fmt.Printf("Logged in with the client %s\n", status.ClientID)
+	if status.ExpiresAt != nil {
+		verb := "expires"
+		if auth.IsExpired() {
+			verb = "expired"
+		}
+		fmt.Printf("Access token %s at %s and is refreshed automatically\n", verb, status.ExpiresAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("Granted scopes: %s\n", strings.Join(status.Scopes, ", "))
+	if len(status.MissingScopes) > 0 {
+		fmt.Printf("Missing scopes: %s. Run 'sprt auth scopes' to grant them.\n", strings.Join(status.MissingScopes, ", "))
+	}
 	return nil
 }
 
-// testCurrentlyPlaying tests the authentication by retrieving the currently playing track.
-func testCurrentlyPlaying(authUseCase usecase.AuthUseCase) error {
-	fmt.Println("Testing authentication by retrieving currently playing track...")
+// logout deletes the stored tokens and, unless keepClient is set, the client credentials.
+func logout(authUseCase usecase.AuthUseCase, keepClient bool) error {
+	if err := authUseCase.Logout(commandContext(), keepClient); err != nil {
+		return fmt.Errorf("failed to log out: %w", err)
+	}
+
+	if keepClient {
+		fmt.Println("Logged out. Your client credentials were kept; run 'sprt auth scopes' or 'sprt auth init' to log in again.")
+	} else {
+		fmt.Println("Logged out and deleted your client credentials.")
+	}
+	return nil
+}
+
+// authorizeManually prints the authorization URL and completes the token exchange
+// with the redirect URL or code pasted back by the user, without a callback server.
+func authorizeManually(authUseCase usecase.AuthUseCase, authURL string) error {
+	fmt.Printf("\nOpen this URL in a browser on any machine and authorize sprt:\n\n%s\n\n", authURL)
+	fmt.Println("The browser is then redirected to a local URL that fails to load on this machine; that's expected.")
 
-	track, err := authUseCase.GetCurrentlyPlaying(context.Background())
+	input, err := promptInput("Paste the URL from the address bar (or its code parameter): ")
 	if err != nil {
-		return fmt.Errorf("failed to get currently playing track: %w", err)
+		return fmt.Errorf("failed to read the redirect URL: %w", err)
+	}
+	code, err := parseAuthCode(input)
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext()
+	if err := authUseCase.HandleCallback(ctx, code); err != nil {
+		return err
+	}
+	if err := authUseCase.ExchangeCodeForToken(ctx); err != nil {
+		return fmt.Errorf("failed to exchange the code for a token: %w", err)
+	}
+
+	return nil
+}
+
+// parseAuthCode extracts the authorization code from a pasted redirect URL, or returns
+// the input itself when it's a bare code.
+func parseAuthCode(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("no redirect URL or code given")
+	}
+	if !strings.Contains(input, "?") && !strings.Contains(input, "=") {
+		return input, nil
+	}
+
+	query := input
+	if i := strings.Index(input, "?"); i != -1 {
+		query = input[i+1:]
+	}
+	values, err := url.ParseQuery(strings.SplitN(query, "#", 2)[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the redirect URL: %w", err)
+	}
+	if reason := values.Get("error"); reason != "" {
+		return "", fmt.Errorf("authorization was denied: %s", reason)
+	}
+	code := values.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("the redirect URL has no code parameter")
+	}
+
+	return code, nil
+}
+
+// encryptCredentials re-stores the credentials with the given key source.
+func encryptCredentials(authUseCase usecase.AuthUseCase, source string) error {
+	switch source {
+	case secret.KeyMachine, secret.KeyPassphrase, jsonfile.EncryptionNone:
+	default:
+		return fmt.Errorf("unknown key %q (expected machine, passphrase or none)", source)
 	}
 
-	// Check if no track is playing
-	if track == "No track currently playing" {
-		// Show waiting UI instead of just printing the message
-		return tui.RunWaitingTrackUI(authUseCase)
+	if err := authUseCase.EncryptCredentials(commandContext(), source); err != nil {
+		return fmt.Errorf("failed to encrypt credentials: %w", err)
 	}
 
-	fmt.Println(track)
+	switch source {
+	case jsonfile.EncryptionNone:
+		fmt.Println("Credentials are now stored in plaintext.")
+	case secret.KeyPassphrase:
+		fmt.Printf("Credentials encrypted with your passphrase. Set %s for non-interactive use such as status bars.\n", secret.PassphraseEnv)
+	default:
+		fmt.Println("Credentials encrypted with a key derived from this machine and user.")
+	}
 	return nil
 }
 
+// scopeStatus is a scope needed by a feature, as printed by "sprt auth scopes --json".
+type scopeStatus struct {
+	Feature string `json:"feature"`
+	Scope   string `json:"scope"`
+	Granted bool   `json:"granted"`
+}
+
+// showScopes prints the required scopes per feature and re-authorizes when some are missing.
+func showScopes(authUseCase usecase.AuthUseCase, skipConfirmation, openBrowser bool) error {
+	ctx := commandContext()
+
+	granted, err := authUseCase.GrantedScopes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get granted scopes: %w", err)
+	}
+	missing := usecase.MissingScopes(granted, authUseCase.RequiredScopes())
+
+	isMissing := make(map[string]bool, len(missing))
+	for _, scope := range missing {
+		isMissing[scope] = true
+	}
+
+	// JSON output only reports the scopes, re-authorizing needs the terminal
+	if jsonOutput {
+		var statuses []scopeStatus
+		for _, feature := range authUseCase.Features() {
+			for _, scope := range feature.Scopes {
+				statuses = append(statuses, scopeStatus{Feature: feature.Name, Scope: scope, Granted: !isMissing[scope]})
+			}
+		}
+		return printJSON(statuses)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FEATURE\tSCOPE\tSTATUS")
+	for _, feature := range authUseCase.Features() {
+		for _, scope := range feature.Scopes {
+			status := "granted"
+			if isMissing[scope] {
+				status = "missing"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", feature.Name, scope, status)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		fmt.Println("\nAll required scopes are granted.")
+		return nil
+	}
+
+	fmt.Printf("\n%d required scope(s) missing: %s\n", len(missing), strings.Join(missing, ", "))
+	if !skipConfirmation {
+		answer, err := promptInput("Re-authorize to grant them? [Y/n] ")
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if answer != "" && answer != "y" && answer != "Y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	authURL, err := authUseCase.Reauthorize(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare re-authorization: %w", err)
+	}
+	auth, err := authUseCase.GetToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get client credentials: %w", err)
+	}
+	if err := authorize(authUseCase, authURL, auth.ClientID, auth.ClientSecret, openBrowser); err != nil {
+		return err
+	}
+
+	// Check that the new token grants what was asked for
+	granted, err = authUseCase.GrantedScopes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get granted scopes: %w", err)
+	}
+	if missing := usecase.MissingScopes(granted, authUseCase.RequiredScopes()); len(missing) > 0 {
+		return fmt.Errorf("scopes still missing after re-authorization: %s", strings.Join(missing, ", "))
+	}
+
+	fmt.Println("All required scopes are granted.")
+	return nil
+}
+
+// testCurrentlyPlaying tests the authentication by retrieving the currently playing track.
+func testCurrentlyPlaying(authUseCase usecase.AuthUseCase) error {
+	progressf("Testing authentication by retrieving currently playing track...\n")
+
+	track, err := authUseCase.GetCurrentlyPlaying(commandContext())
+	if err != nil {
+		// Check if no track is playing
+		if errors.Is(err, usecase.ErrNothingPlaying) {
+			if jsonOutput {
+				return printJSON(nil)
+			}
+			// Show waiting UI instead of just printing the message
+			return tui.RunWaitingTrackUI(commandContext(), appConfig, authUseCase)
+		}
+		return fmt.Errorf("failed to get currently playing track: %w", err)
+	}
+
+	return printResult(track, "Currently playing: %s by %s from the album %s\n", track.Title, track.Artist, track.Album)
+}
+
 // promptInput prompts the user for input with the given message.
 func promptInput(message string) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/infrastructure/artwork"
+	"github.com/muhadif/sprt/infrastructure/httpclient"
+	"github.com/muhadif/sprt/interfaces/card"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cardOutput string
+	cardCover  bool
+	cardWidth  int
+	cardPNG    string
+)
+
+var cardCmd = &cobra.Command{
+	Use:   "card",
+	Short: "Render a shareable now playing card",
+	Long: `Render a box-drawn "now playing" card with the title, artist, album and progress,
+for pasting into chats. --cover adds a tiny cover drawn with 24-bit ANSI colors,
+which only shows up where colors are rendered, such as terminals.
+--png renders the card with its cover to an image for posting to social media instead,
+using the lyric colors from ~/.sprt/ui_config.json.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cardPNG != "" {
+			return renderCardPNG(cardPNG)
+		}
+		return renderCard(cardOutput, cardCover, cardWidth)
+	},
+}
+
+// renderCard renders the card of the playing item to stdout or to the output file.
+func renderCard(output string, withCover bool, width int) error {
+	ctx := commandContext()
+
+	track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get currently playing track: %w", err)
+	}
+
+	var cover image.Image
+	if withCover && track.ImageURL != "" {
+		// The card is still worth sharing without its cover
+		if cover, err = fetchCover(ctx, track.ImageURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	rendered := card.Render(track, card.Options{Width: width, Cover: cover})
+
+	if output == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write card: %w", err)
+	}
+	fmt.Printf("Card written to %s\n", output)
+	return nil
+}
+
+// renderCardPNG renders the card of the playing item with its cover to a PNG file.
+func renderCardPNG(path string) error {
+	ctx := commandContext()
+
+	track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get currently playing track: %w", err)
+	}
+
+	var cover image.Image
+	if track.ImageURL != "" {
+		if cover, err = fetchCover(ctx, track.ImageURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create card image: %w", err)
+	}
+	defer file.Close()
+
+	if err := card.RenderPNG(file, track, cover, cardTheme()); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write card image: %w", err)
+	}
+
+	fmt.Printf("Card written to %s\n", path)
+	return nil
+}
+
+// fetchCover downloads a cover with the HTTP settings from the config, reusing the covers
+// kept in the art cache.
+func fetchCover(ctx context.Context, url string) (image.Image, error) {
+	cache := artwork.NewCache(httpclient.New(appConfig.API), "", int64(appConfig.API.ArtCacheMB)<<20)
+	return cache.Fetch(ctx, url)
+}
+
+// cardTheme takes the PNG card colors from the lyric styles of the UI config.
+func cardTheme() card.Theme {
+	theme := card.DefaultTheme()
+
+	uiConfig, err := config.LoadUIConfig()
+	if err != nil {
+		return theme
+	}
+	current, other := uiConfig.Lyric.CurrentLineStyle, uiConfig.Lyric.OtherLineStyle
+
+	theme.Accent = card.ParseHexColor(current.ForegroundColor, theme.Accent)
+	theme.Text = card.ParseHexColor(other.ForegroundColor, theme.Text)
+	theme.Background = card.ParseHexColor(current.BackgroundColor, theme.Background)
+	return theme
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/infrastructure/secret"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanAutoSkip   bool
+	cleanMaskLyrics bool
+)
+
+// cleanPassphraseEnv is the environment variable read before asking for the passphrase of the
+// clean-content mode.
+const cleanPassphraseEnv = "SPRT_CLEAN_PASSPHRASE"
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Show or change the clean-content mode",
+	Long: `Show the clean-content mode. While it is on, explicit tracks are hidden from search, album,
+playlist and library listings, explicit words in the lyric displays are masked, and with
+--auto-skip "sprt daemon" skips explicit tracks as they start.
+
+A passphrase set with "sprt clean lock" is asked for before the mode can be turned off or relaxed.`,
+	Annotations: map[string]string{offlineAnnotation: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showCleanMode()
+	},
+}
+
+var cleanOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Turn the clean-content mode on",
+	Long: `Turn the clean-content mode on. --auto-skip and --mask-lyrics change those settings;
+turning either of them off needs the passphrase when the mode is locked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeCleanMode(func(clean *config.CleanConfig) {
+			clean.Enabled = true
+			if cmd.Flags().Changed("auto-skip") {
+				clean.AutoSkip = cleanAutoSkip
+			}
+			if cmd.Flags().Changed("mask-lyrics") {
+				clean.MaskLyrics = cleanMaskLyrics
+			}
+		})
+	},
+}
+
+var cleanOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Turn the clean-content mode off",
+	Long:  `Turn the clean-content mode off, asking for the passphrase when it is locked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeCleanMode(func(clean *config.CleanConfig) {
+			clean.Enabled = false
+		})
+	},
+}
+
+var cleanLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Protect the clean-content mode with a passphrase",
+	Long: `Set the passphrase needed to turn the clean-content mode off or relax it. Changing an existing
+passphrase asks for the current one first. The passphrase is read from ` + cleanPassphraseEnv + ` or
+asked for on the terminal.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return lockCleanMode()
+	},
+}
+
+var cleanUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Remove the clean-content passphrase",
+	Long:  `Remove the passphrase of the clean-content mode after asking for it. The mode itself stays as it is.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configErr != nil {
+			return configErr
+		}
+		cfg := appConfig
+		if cfg.Clean.Lock == nil {
+			return errors.New("the clean-content mode isn't locked")
+		}
+		if err := checkCleanPassphrase(cfg.Clean.Lock); err != nil {
+			return err
+		}
+
+		cfg.Clean.Lock = nil
+		if err := config.SaveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Println("Removed the clean-content passphrase.")
+		return nil
+	},
+}
+
+// cleanStatus is the clean-content mode as printed by "sprt clean".
+type cleanStatus struct {
+	Enabled    bool `json:"enabled"`
+	MaskLyrics bool `json:"mask_lyrics"`
+	AutoSkip   bool `json:"auto_skip"`
+	Locked     bool `json:"locked"`
+}
+
+// showCleanMode prints the clean-content settings.
+func showCleanMode() error {
+	if configErr != nil {
+		return configErr
+	}
+	cfg := appConfig
+	status := cleanStatus{
+		Enabled:    cfg.Clean.Enabled,
+		MaskLyrics: cfg.Clean.MaskLyrics,
+		AutoSkip:   cfg.Clean.AutoSkip,
+		Locked:     cfg.Clean.Lock != nil,
+	}
+	if jsonOutput {
+		return printJSON(status)
+	}
+
+	fmt.Printf("Clean-content mode: %s\n", onOff(status.Enabled))
+	fmt.Printf("Mask lyrics:        %s\n", onOff(status.MaskLyrics))
+	fmt.Printf("Auto-skip:          %s\n", onOff(status.AutoSkip))
+	fmt.Printf("Locked:             %s\n", onOff(status.Locked))
+	return nil
+}
+
+// changeCleanMode applies change to the clean-content settings and saves them. Relaxing any
+// setting of a locked mode needs the passphrase.
+func changeCleanMode(change func(clean *config.CleanConfig)) error {
+	// Saving the defaults of a config that can't be loaded would replace it
+	if configErr != nil {
+		return configErr
+	}
+	cfg := appConfig
+	before := cfg.Clean
+	change(&cfg.Clean)
+
+	relaxed := (before.Enabled && !cfg.Clean.Enabled) ||
+		(before.MaskLyrics && !cfg.Clean.MaskLyrics) ||
+		(before.AutoSkip && !cfg.Clean.AutoSkip)
+	if relaxed {
+		if err := checkCleanPassphrase(before.Lock); err != nil {
+			return err
+		}
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return err
+	}
+	return showCleanMode()
+}
+
+// lockCleanMode sets the passphrase of the clean-content mode.
+func lockCleanMode() error {
+	if configErr != nil {
+		return configErr
+	}
+	cfg := appConfig
+	if err := checkCleanPassphrase(cfg.Clean.Lock); err != nil {
+		return err
+	}
+
+	salt, err := secret.NewSalt()
+	if err != nil {
+		return err
+	}
+	hash, err := cleanPassphraseHash(salt, true)
+	if err != nil {
+		return err
+	}
+
+	cfg.Clean.Lock = &config.CleanLock{Salt: salt, Hash: hash}
+	if err := config.SaveConfig(cfg); err != nil {
+		return err
+	}
+	fmt.Println("Locked the clean-content mode, its passphrase is needed to turn it off.")
+	return nil
+}
+
+// checkCleanPassphrase asks for the passphrase of a locked clean-content mode and checks it;
+// a nil lock needs no passphrase.
+func checkCleanPassphrase(lock *config.CleanLock) error {
+	if lock == nil {
+		return nil
+	}
+
+	hash, err := cleanPassphraseHash(lock.Salt, false)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(hash, lock.Hash) != 1 {
+		return errors.New("wrong passphrase, the clean-content mode is locked")
+	}
+	return nil
+}
+
+// cleanPassphraseHash asks for the passphrase of the clean-content mode and hashes it with salt;
+// when confirm is set it's asked for twice. It's not the passphrase of the credentials, so the
+// one set for scripts can't unlock the mode.
+func cleanPassphraseHash(salt []byte, confirm bool) ([]byte, error) {
+	passphrase, err := secret.ReadPassphrase(cleanPassphraseEnv, "Clean-content passphrase: ", confirm)
+	if err != nil {
+		return nil, err
+	}
+	return secret.PassphraseKey(passphrase, salt)
+}
+
+// hideExplicit reports whether explicit tracks are left out of listings by the clean-content mode.
+func hideExplicit() bool {
+	return appConfig.Clean.Enabled
+}
+
+// onOff formats a setting as "on" or "off".
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/muhadif/sprt/interfaces/tui"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Edit the configuration",
+	Long: `Edit the configuration of sprt, kept in ~/.sprt.
+
+config.json holds the settings of the commands and ui_config.json the look of the lyric display.`,
+	Annotations: map[string]string{offlineAnnotation: ""},
+}
+
+var configUICmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Edit the look of the lyric display with a live preview",
+	Long: `Edit the line styles, animation and layout of the lyric display and its themes, with a preview
+that shows every change right away. The result is written to ~/.sprt/ui_config.json.
+
+Keybindings:
+  ↑ / ↓    select a setting
+  ← / →    change it: step numbers, cycle choices and themes, turn switches on and off
+  enter    type in a color, number or the name to save the styles as a theme
+  ctrl+s   save
+  ?        show the keybindings
+  q        quit; quitting with unsaved changes asks to press q again`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tui.RunConfigUI(commandContext(), appConfig)
+	},
+}
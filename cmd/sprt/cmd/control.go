@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muhadif/sprt/infrastructure/notification"
+	"github.com/spf13/cobra"
+)
+
+// Playback actions of "sprt control", also bound to global hotkeys by the daemon.
+const (
+	actionPlayPause = "play-pause"
+	actionNext      = "next"
+	actionPrevious  = "previous"
+	actionLike      = "like"
+)
+
+var controlCmd = &cobra.Command{
+	Use:   "control <action>",
+	Short: "Control playback",
+	Long: `Run a playback action, for keyboard shortcuts and scripts:
+  play-pause  toggle play/pause
+  next        next track
+  previous    previous track
+  like        save the current track to your library`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runControl(commandContext(), args[0])
+	},
+}
+
+// runControl runs a playback action.
+func runControl(ctx context.Context, action string) error {
+	switch action {
+	case actionPlayPause:
+		return togglePlayback(ctx)
+	case actionNext:
+		return playerUseCase.Next(ctx)
+	case actionPrevious:
+		return playerUseCase.Previous(ctx)
+	case actionLike:
+		return likeCurrentTrack(ctx)
+	default:
+		return fmt.Errorf("unknown action %q (expected %s, %s, %s or %s)", action, actionPlayPause, actionNext, actionPrevious, actionLike)
+	}
+}
+
+// togglePlayback pauses playback when playing and resumes it otherwise.
+func togglePlayback(ctx context.Context) error {
+	state, err := playerUseCase.GetPlaybackState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get playback state: %w", err)
+	}
+	if state.IsPlaying {
+		return playerUseCase.Pause(ctx)
+	}
+	return playerUseCase.Play(ctx)
+}
+
+// likeCurrentTrack saves the current track to the library, confirming with a desktop
+// notification since hotkeys have no terminal to print to.
+func likeCurrentTrack(ctx context.Context) error {
+	track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current track: %w", err)
+	}
+	if track.IsEpisode() {
+		return fmt.Errorf("episodes can't be saved to your library")
+	}
+
+	if err := libraryUseCase.SaveTracks(ctx, []string{track.ID}); err != nil {
+		return err
+	}
+
+	_ = notification.NewNotifier(appConfig.Notifications).Notify("Saved to your library", fmt.Sprintf("%s — %s", track.Title, track.Artist))
+
+	return printResult(track, "Saved %s by %s to your library\n", track.Title, track.Artist)
+}
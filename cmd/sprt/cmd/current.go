@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/muhadif/sprt/domain/usecase"
 	"github.com/muhadif/sprt/interfaces/tui"
@@ -13,56 +13,44 @@ import (
 var currentCmd = &cobra.Command{
 	Use:   "current",
 	Short: "Get currently playing track",
-	Long:  `Get information about your currently playing track on Spotify.`,
+	Long:  `Get information about your currently playing track on Spotify, including the active device and listening session.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return getCurrentlyPlaying(authUseCase)
+		// A running daemon answers without another request to Spotify
+		if client := daemonClient(); client != nil {
+			return getCurrentlyPlaying(client)
+		}
+		return getCurrentlyPlaying(playerUseCase)
 	},
 }
 
+// playbackStateSource reports the playback state, e.g. polled from Spotify or asked of the daemon.
+type playbackStateSource interface {
+	GetPlaybackState(ctx context.Context) (*usecase.PlaybackState, error)
+}
+
 // init function is no longer needed as commands are initialized in root.go
 // through the InitializeCommands function
 
 // getCurrentlyPlaying retrieves the user's currently playing track.
-func getCurrentlyPlaying(authUseCase usecase.AuthUseCase) error {
-	fmt.Println("Retrieving currently playing track...")
+func getCurrentlyPlaying(source playbackStateSource) error {
+	progressf("Retrieving currently playing track...\n")
 
-	trackInfo, err := authUseCase.GetCurrentlyPlaying(context.Background())
+	state, err := source.GetPlaybackState(commandContext())
 	if err != nil {
+		if errors.Is(err, usecase.ErrNothingPlaying) {
+			if jsonOutput {
+				return printJSON(nil)
+			}
+			// Show waiting UI instead of just printing the message
+			return tui.RunWaitingTrackUI(commandContext(), appConfig, authUseCase)
+		}
 		return fmt.Errorf("failed to get currently playing track: %w", err)
 	}
 
-	// Check if no track is playing
-	if trackInfo == "No track currently playing" {
-		// Show waiting UI instead of just printing the message
-		return tui.RunWaitingTrackUI(authUseCase)
+	if jsonOutput {
+		return printJSON(state)
 	}
 
-	// Parse the track information from the string
-	// Format is: "Currently playing: {title} by {artist} from the album {album}"
-	title, artist, album := parseTrackInfo(trackInfo)
-
 	// Use the TUI to display the track
-	return tui.RunCurrentTrackUI(artist, title, album, "Unknown", "Unknown", true)
-}
-
-// parseTrackInfo parses the track information from the formatted string
-func parseTrackInfo(trackInfo string) (title, artist, album string) {
-	// Remove the "Currently playing: " prefix
-	trackInfo = strings.TrimPrefix(trackInfo, "Currently playing: ")
-
-	// Split by " by " to get title and the rest
-	parts := strings.Split(trackInfo, " by ")
-	if len(parts) < 2 {
-		return trackInfo, "", ""
-	}
-
-	title = parts[0]
-
-	// Split the rest by " from the album " to get artist and album
-	parts = strings.Split(parts[1], " from the album ")
-	if len(parts) < 2 {
-		return title, parts[0], ""
-	}
-
-	return title, parts[0], parts[1]
+	return tui.RunCurrentPlaybackUI(appConfig, state)
 }
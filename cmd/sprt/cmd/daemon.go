@@ -0,0 +1,596 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/focus"
+	"github.com/muhadif/sprt/infrastructure/hook"
+	"github.com/muhadif/sprt/infrastructure/hotkey"
+	"github.com/muhadif/sprt/infrastructure/httpclient"
+	"github.com/muhadif/sprt/infrastructure/listenbrainz"
+	"github.com/muhadif/sprt/infrastructure/notification"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/muhadif/sprt/infrastructure/persistence/memory"
+	"github.com/muhadif/sprt/infrastructure/systemd"
+	"github.com/muhadif/sprt/infrastructure/title"
+	httpinterface "github.com/muhadif/sprt/interfaces/http"
+	"github.com/muhadif/sprt/interfaces/ipc"
+	"github.com/spf13/cobra"
+)
+
+// profileCheckInterval is how often the daemon checks for a profile starting or ending.
+const profileCheckInterval = 30 * time.Second
+
+// healthCheckInterval is how often the daemon polls Spotify and lrclib.net for its health report;
+// the daemon is unhealthy after three polls in a row fail.
+const healthCheckInterval = 30 * time.Second
+
+// daemonHealthAddr is the address of the health endpoint; empty disables it.
+var daemonHealthAddr string
+
+// daemonSocket is the path of the control socket; empty disables it.
+var daemonSocket string
+
+// daemonEventsAddr is the address of the event stream; empty disables it.
+var daemonEventsAddr string
+
+// listenRetryInterval is how often the daemon retries submitting queued listens.
+const listenRetryInterval = 5 * time.Minute
+
+// Buttons on the track-change notifications of the daemon.
+var trackActions = []notification.Action{
+	{Key: actionLike, Label: "Like"},
+	{Key: actionNext, Label: "Skip"},
+}
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the background daemon",
+	Long: `Run in the background until interrupted, applying the time-of-day profiles from the
+"profiles" section of ~/.sprt/config.json. When a profile starts its volume is set on the active
+device, and its theme and notification setting are picked up by the lyric displays and notifications.
+
+With the "title" section of the config enabled, the terminal window or tmux pane title shows the
+current track until the daemon stops.
+
+The "hotkeys" section registers global keyboard shortcuts for the actions of "sprt control" with the
+desktop while the daemon runs. This is supported on GNOME; elsewhere bind "sprt control <action>" in
+the keyboard settings of your desktop or window manager.
+
+With "actions" enabled in the "notifications" section, the daemon shows the track-change
+notifications with Like and Skip buttons where the notification tool supports them.
+
+In the clean-content mode with auto-skip, see "sprt clean", explicit tracks are skipped as they start.
+
+The daemon answers other sprt invocations and third-party tools on a UNIX socket, by default
+~/.sprt/daemon.sock, with one JSON request per line: {"id":1,"method":"status"}. The methods are
+status, lyric, playback, lyrics, play-pause, next and subscribe, which sends an event whenever the
+track, the play state or the lyric line changes. --socket "" turns the socket off.
+
+While the daemon listens on the default socket, "sprt current", "sprt status" and "sprt lyric pipe"
+read the playback state and lyrics from it instead of polling Spotify, unless --no-daemon is given.
+
+The "hooks" section runs shell commands on the track, play, pause, stop and lyric events, with
+the track and the lyric line in SPRT_* environment variables, e.g. for scrobbling scripts.
+
+With a token in the "listenbrainz" section, the tracks played are submitted to ListenBrainz once
+half of them, or four minutes, were played. Listens are queued in ~/.sprt/listenbrainz-queue.jsonl
+while ListenBrainz can't be reached and submitted when it's back.
+
+With --events-addr the daemon streams the same events as server-sent events at /events, for OBS
+browser sources and web dashboards: the state first, then track, status and lyric events.
+
+With --health-addr the daemon serves its health at /healthz: 200 OK while it is authorized and
+Spotify answers, 503 Service Unavailable while starting or otherwise. As a systemd service with
+Type=notify it reports readiness after the first check and feeds the watchdog while healthy.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDaemon()
+	},
+}
+
+// runDaemon applies the time-of-day profiles until interrupted.
+func runDaemon() error {
+	ctx := commandContext()
+	// The daemon runs unattended, so it doesn't go on with the defaults of a broken config
+	if configErr != nil {
+		return configErr
+	}
+	cfg := appConfig
+	profiles, err := configuredProfiles(cfg)
+	if err != nil {
+		return err
+	}
+
+	profileRepo := jsonfile.NewProfileRepository("")
+	profileUseCase := usecase.NewProfileUseCase(profileRepo, playerUseCase, profiles)
+
+	// Nothing applies the profile once the daemon is gone
+	defer profileRepo.ClearActiveProfile(context.Background())
+
+	windowTitle, err := title.New(cfg.Title.Mode)
+	if err != nil {
+		return fmt.Errorf("invalid title in the config: %w", err)
+	}
+	defer windowTitle.Clear()
+
+	playing := &nowPlaying{
+		title:        windowTitle,
+		format:       cfg.Title.Format,
+		skipExplicit: cfg.Clean.Enabled && cfg.Clean.AutoSkip,
+	}
+	if cfg.Notifications.Actions && cfg.Notifications.TrackChange {
+		playing.notifier = notification.NewNotifier(cfg.Notifications)
+	}
+
+	// Playback is only followed when something needs it. Everything following it shares the
+	// polls of one watcher; the track changes reach the window title, the notifications and the
+	// auto-skip from its state, and a nil channel never fires without it
+	follow := playing.title != nil || playing.notifier != nil || playing.skipExplicit
+	serve := daemonSocket != "" || daemonEventsAddr != "" || len(cfg.Hooks) > 0 || cfg.ListenBrainz.Token != ""
+	stateRepo := memory.NewStateRepository()
+	stateUseCase := usecase.NewStateUseCase(stateRepo, playerUseCase, lyricUseCase, cfg.Output.HistorySize)
+	var playback usecase.PlaybackSource = playerUseCase
+	var states <-chan *entity.PlaybackSnapshot
+	if follow || serve {
+		playback = stateUseCase
+		if follow {
+			updates, unsubscribe := stateRepo.Subscribe()
+			defer unsubscribe()
+			states = updates
+		}
+		app.Go(func(ctx context.Context) {
+			if err := stateUseCase.Watch(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: stopped following playback: %v\n", err)
+			}
+		})
+	}
+
+	unregister, err := registerHotkeys(cfg.Hotkeys)
+	if err != nil {
+		return err
+	}
+	defer unregister()
+
+	if daemonHealthAddr != "" || systemd.Supervised() {
+		healthUseCase := usecase.NewHealthUseCase(authUseCase, playback, httpclient.New(cfg.API))
+		if daemonHealthAddr != "" {
+			server := httpinterface.NewHealthServer(healthUseCase, 3*healthCheckInterval)
+			if err := server.Start(daemonHealthAddr); err != nil {
+				return err
+			}
+			defer server.Stop(context.Background())
+			fmt.Printf("Serving health at http://%s/healthz\n", daemonHealthAddr)
+		}
+		app.Go(func(ctx context.Context) {
+			monitorHealth(ctx, healthUseCase)
+		})
+		defer systemd.Notify(systemd.Stopping)
+	}
+
+	if serve {
+		stop, err := servePlaybackState(cfg, stateUseCase, stateRepo)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	fmt.Printf("Daemon running with %d profiles, press Ctrl+C to stop...\n", len(profiles))
+
+	ticker := time.NewTicker(profileCheckInterval)
+	defer ticker.Stop()
+
+	applyProfile(ctx, profileUseCase)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			applyProfile(ctx, profileUseCase)
+		case snapshot := <-states:
+			playing.follow(ctx, snapshot, stateUseCase)
+		}
+	}
+}
+
+// daemonClient returns a client of the running daemon, or nil when none listens on the
+// default socket or --no-daemon is given.
+func daemonClient() *ipc.Client {
+	if noDaemon {
+		return nil
+	}
+	client := ipc.NewClient(ipc.DefaultSocketPath())
+	if !client.Available(commandContext()) {
+		return nil
+	}
+	return client
+}
+
+// servePlaybackState serves the state followed by stateUseCase on the control socket, with the
+// playback actions, and as the event stream, runs the hooks and submits the listens to
+// ListenBrainz, as far as they are enabled. It returns a function stopping the servers.
+func servePlaybackState(cfg *config.Config, stateUseCase usecase.StateUseCase, stateRepo *memory.StateRepository) (func(), error) {
+	hooks, err := hook.New(cfg.Hooks)
+	if err != nil {
+		return nil, err
+	}
+
+	var stops []func()
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}
+
+	if daemonSocket != "" {
+		server := ipc.NewServer(stateUseCase, stateRepo, lyricUseCase, runControl)
+		if err := server.Start(daemonSocket); err != nil {
+			return nil, err
+		}
+		stops = append(stops, func() {
+			if err := server.Stop(context.Background()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to stop the control socket: %v\n", err)
+			}
+		})
+		fmt.Printf("Listening for control requests on %s\n", daemonSocket)
+	}
+
+	if daemonEventsAddr != "" {
+		server := httpinterface.NewEventServer(stateUseCase, stateRepo)
+		if err := server.Start(daemonEventsAddr); err != nil {
+			stop()
+			return nil, err
+		}
+		stops = append(stops, func() {
+			if err := server.Stop(context.Background()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to stop the event server: %v\n", err)
+			}
+		})
+		fmt.Printf("Streaming playback events at http://%s/events\n", daemonEventsAddr)
+	}
+
+	if len(cfg.Hooks) > 0 {
+		app.Go(hooks.Run)
+		app.Go(func(ctx context.Context) {
+			ipc.FollowEvents(ctx, stateUseCase, stateRepo, func(event ipc.Event) bool {
+				names := hookEvents(event)
+				// Hooks are held back during Do-Not-Disturb like the notifications
+				if len(names) == 0 || focus.Suppressed(cfg.Notifications.DoNotDisturb) {
+					return true
+				}
+				for _, name := range names {
+					hooks.Fire(name, event.State)
+				}
+				return true
+			})
+		})
+		fmt.Printf("Running %d hooks on playback events\n", len(cfg.Hooks))
+	}
+
+	if cfg.ListenBrainz.Token != "" {
+		submitter := listenbrainz.New(httpclient.New(cfg.API), cfg.ListenBrainz)
+		scrobbleUseCase := usecase.NewScrobbleUseCase(submitter, jsonfile.NewListenQueueRepository(""))
+		app.Go(func(ctx context.Context) {
+			submitQueuedListens(ctx, scrobbleUseCase)
+		})
+		app.Go(func(ctx context.Context) {
+			ipc.FollowEvents(ctx, stateUseCase, stateRepo, func(event ipc.Event) bool {
+				if err := scrobbleUseCase.Observe(ctx, event.State); err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+				return true
+			})
+			// The listen of the track playing when the daemon stops is submitted next time
+			if err := scrobbleUseCase.Finish(context.Background(), time.Now()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		})
+		fmt.Printf("Submitting listens to %s\n", submitter.Name())
+	}
+
+	return stop, nil
+}
+
+// submitQueuedListens submits the listens queued while the listening service couldn't be
+// reached, right away and then every listenRetryInterval until ctx ends.
+func submitQueuedListens(ctx context.Context, scrobbleUseCase usecase.ScrobbleUseCase) {
+	ticker := time.NewTicker(listenRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		if submitted, err := scrobbleUseCase.Submit(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if submitted > 0 {
+			fmt.Printf("Submitted %d queued listens\n", submitted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hookEvents names the hook events of a playback event. The state the daemon starts with counts
+// as its track starting to play.
+func hookEvents(event ipc.Event) []string {
+	state := event.State
+	var events []string
+	if (event.Event == ipc.EventTrack || event.Event == ipc.EventState) && state.Track != nil {
+		events = append(events, hook.EventTrack)
+	}
+	if event.Event == ipc.EventStatus || event.Event == ipc.EventState {
+		switch state.Status {
+		case entity.StatusPlaying:
+			events = append(events, hook.EventPlay)
+		case entity.StatusPaused:
+			events = append(events, hook.EventPause)
+		case entity.StatusStopped:
+			// Starting without playback stops nothing
+			if event.Event == ipc.EventStatus {
+				events = append(events, hook.EventStop)
+			}
+		}
+	}
+	if event.Event == ipc.EventLyric && state.Lyric != nil {
+		events = append(events, hook.EventLyric)
+	}
+	return events
+}
+
+// applyProfile switches to the profile of the current time and logs changes.
+func applyProfile(ctx context.Context, profileUseCase usecase.ProfileUseCase) {
+	now := time.Now()
+	active, changed, err := profileUseCase.Apply(ctx, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Warning: %v\n", now.Format("15:04"), err)
+	}
+	if !changed {
+		return
+	}
+
+	if active == nil {
+		fmt.Printf("%s No profile active\n", now.Format("15:04"))
+		return
+	}
+	fmt.Printf("%s Profile %s active until %s\n", now.Format("15:04"), active.Name, active.Until.Format("15:04"))
+}
+
+// monitorHealth checks the health of the daemon until ctx is done. Under systemd it reports
+// readiness once the first check finished and keeps the watchdog fed while the daemon is healthy.
+func monitorHealth(ctx context.Context, healthUseCase usecase.HealthUseCase) {
+	healthUseCase.Check(ctx, time.Now())
+	if err := systemd.Notify(systemd.Ready); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	checkTicker := time.NewTicker(healthCheckInterval)
+	defer checkTicker.Stop()
+
+	// A nil channel never fires when the watchdog is off
+	var watchdogTick <-chan time.Time
+	if interval := systemd.WatchdogInterval(); interval > 0 {
+		watchdogTicker := time.NewTicker(interval)
+		defer watchdogTicker.Stop()
+		watchdogTick = watchdogTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-checkTicker.C:
+			healthUseCase.Check(ctx, now)
+		case now := <-watchdogTick:
+			// Without the keep-alive systemd restarts the daemon
+			if healthUseCase.Report(now, 3*healthCheckInterval).Status != usecase.HealthUnhealthy {
+				systemd.Notify(systemd.Watchdog)
+			}
+		}
+	}
+}
+
+// registerHotkeys registers the configured global hotkeys and returns a function removing them.
+// Desktops without support only get a warning.
+func registerHotkeys(hotkeys []config.HotkeyConfig) (func(), error) {
+	noop := func() {}
+	if len(hotkeys) == 0 {
+		return noop, nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the sprt executable: %w", err)
+	}
+
+	bindings := make([]hotkey.Binding, len(hotkeys))
+	for i, h := range hotkeys {
+		switch h.Action {
+		case actionPlayPause, actionNext, actionPrevious, actionLike:
+		default:
+			return nil, fmt.Errorf("invalid hotkey %s in the config: unknown action %q", h.Keys, h.Action)
+		}
+		for _, b := range bindings[:i] {
+			if b.Name == h.Action {
+				return nil, fmt.Errorf("invalid hotkey %s in the config: action %s is already bound to %s", h.Keys, h.Action, b.Keys)
+			}
+		}
+		bindings[i] = hotkey.Binding{
+			Name:    h.Action,
+			Keys:    h.Keys,
+			Command: fmt.Sprintf("'%s' control %s", strings.ReplaceAll(exe, "'", `'\''`), h.Action),
+		}
+	}
+
+	unregister, err := hotkey.Register(bindings)
+	if errors.Is(err, hotkey.ErrUnsupported) {
+		fmt.Fprintf(os.Stderr, "Warning: %v, bind \"sprt control <action>\" in your keyboard settings instead\n", err)
+		return noop, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to register hotkeys: %w", err)
+	}
+
+	fmt.Printf("Registered %d hotkeys\n", len(bindings))
+	return func() {
+		if err := unregister(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}, nil
+}
+
+// nowPlaying follows the current track for the window title, track-change notifications
+// and skipping explicit tracks.
+type nowPlaying struct {
+	title  *title.Title
+	format string
+	// notifier shows track changes with buttons; nil leaves notifications to the lyric displays
+	notifier *notification.Notifier
+	trackID  string
+	// followedID is the track of the last state followed, set once following tells the states of
+	// another track from those of the same one
+	followedID string
+	following  bool
+	// skipExplicit skips explicit tracks in the clean-content mode; skippedID is the last one skipped
+	skipExplicit bool
+	skippedID    string
+}
+
+// follow updates the current track when the state of playback has another one, with its
+// details from the watcher's last poll of playback.
+func (p *nowPlaying) follow(ctx context.Context, snapshot *entity.PlaybackSnapshot, playback usecase.PlaybackSource) {
+	id := ""
+	if snapshot.Track != nil {
+		id = snapshot.Track.ID
+	}
+	if p.following && p.followedID == id {
+		return
+	}
+
+	// Other errors keep everything as it is, and the track is looked at again with the next state
+	state, err := playback.GetPlaybackState(ctx)
+	switch {
+	case errors.Is(err, usecase.ErrNothingPlaying):
+		_ = p.title.Clear()
+		p.trackID = ""
+	case err != nil:
+		return
+	default:
+		p.update(ctx, &state.CurrentlyPlaying)
+	}
+	p.followedID, p.following = id, true
+}
+
+// update puts the track in the window title, or skips it when it's explicit in the
+// clean-content mode, and announces track changes.
+func (p *nowPlaying) update(ctx context.Context, track *usecase.CurrentlyPlaying) {
+	if p.skipExplicit && track.Explicit {
+		p.skip(ctx, track)
+		return
+	}
+	p.skippedID = ""
+	_ = p.title.Set(title.Format(p.format, track.Title, track.Artist, track.Album))
+
+	// The track playing when the daemon starts isn't a change
+	if p.notifier != nil && p.trackID != "" && track.ID != p.trackID {
+		track := *track
+		app.Go(func(ctx context.Context) {
+			notifyTrack(ctx, p.notifier, track)
+		})
+	}
+	p.trackID = track.ID
+}
+
+// skip skips an explicit track once; states still reporting it until the next track starts are ignored.
+func (p *nowPlaying) skip(ctx context.Context, track *usecase.CurrentlyPlaying) {
+	if track.ID == p.skippedID {
+		return
+	}
+	p.skippedID = track.ID
+
+	now := time.Now().Format("15:04")
+	if err := playerUseCase.Next(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Warning: failed to skip explicit track: %v\n", now, err)
+		return
+	}
+	fmt.Printf("%s Skipped explicit track %s by %s\n", now, track.Title, track.Artist)
+}
+
+// notifyTrack shows a track-change notification with buttons and runs the clicked action.
+func notifyTrack(ctx context.Context, notifier *notification.Notifier, track usecase.CurrentlyPlaying) {
+	action, err := notifier.NotifyWithActions(ctx, "Now playing", fmt.Sprintf("%s — %s", track.Title, track.Artist), trackActions)
+	if err != nil || action == "" {
+		return
+	}
+
+	now := time.Now().Format("15:04")
+	switch action {
+	case actionLike:
+		if track.IsEpisode() {
+			return
+		}
+		err = libraryUseCase.SaveTracks(ctx, []string{track.ID})
+		if err == nil {
+			fmt.Printf("%s Saved %s by %s to your library\n", now, track.Title, track.Artist)
+		}
+	case actionNext:
+		// Only skip the notified track, not one that started since
+		current, currentErr := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+		if currentErr != nil || current.ID != track.ID {
+			return
+		}
+		err = playerUseCase.Next(ctx)
+		if err == nil {
+			fmt.Printf("%s Skipped %s\n", now, track.Title)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Warning: %v\n", now, err)
+	}
+}
+
+// configuredProfiles converts the time-of-day profiles from the configuration, rejecting invalid ones.
+func configuredProfiles(cfg *config.Config) ([]usecase.Profile, error) {
+	profiles := make([]usecase.Profile, len(cfg.Profiles))
+	for i, p := range cfg.Profiles {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i+1)
+		}
+
+		from, err := usecase.ParseClock(p.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid profile %s in the config: %w", name, err)
+		}
+		to, err := usecase.ParseClock(p.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid profile %s in the config: %w", name, err)
+		}
+
+		profiles[i] = usecase.Profile{
+			Name:          name,
+			From:          from,
+			To:            to,
+			Volume:        p.Volume,
+			Theme:         p.Theme,
+			Notifications: p.Notifications,
+		}
+		for _, d := range p.Days {
+			day, err := usecase.ParseWeekday(d)
+			if err != nil {
+				return nil, fmt.Errorf("invalid profile %s in the config: %w", name, err)
+			}
+			profiles[i].Days = append(profiles[i].Days, day)
+		}
+	}
+	return profiles, nil
+}
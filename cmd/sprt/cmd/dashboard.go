@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/interfaces/tui"
+	"github.com/spf13/cobra"
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show the playback state, queue, devices and lyrics at a glance",
+	Long: `Show the currently playing track, the lyrics, the queue and your devices on one screen.
+
+The panels are loaded at the same time and each one is shown as soon as its data arrives.
+
+Keybindings:
+  r    reload the panels
+  ?    show the keybindings
+  q    quit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tui.RunDashboardUI(commandContext(), appConfig, usecase.NewDashboardUseCase(playerUseCase, lyricUseCase))
+	},
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var deviceCmd = &cobra.Command{
+	Use:   "device",
+	Short: "Spotify Connect device commands",
+	Long:  `Commands for the Spotify Connect devices of your account.`,
+}
+
+var deviceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your Spotify Connect devices",
+	Long:  `List the Spotify Connect devices available to your account with their type and volume, marking the active one.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listDevices()
+	},
+}
+
+// listDevices prints the available devices as a table or as JSON.
+func listDevices() error {
+	devices, err := playerUseCase.GetDevices(commandContext())
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(devices)
+	}
+
+	if len(devices) == 0 {
+		fmt.Println("No devices are available. Open Spotify on a device to use it.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tDEVICE\tTYPE\tVOLUME")
+	for _, device := range devices {
+		active := ""
+		if device.IsActive {
+			active = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d%%\n", active, device.Name, device.Type, device.VolumePercent)
+	}
+
+	return w.Flush()
+}
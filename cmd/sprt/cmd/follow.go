@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+var followCmd = &cobra.Command{
+	Use:   "follow",
+	Short: "Follow artists",
+	Long:  `Commands for following artists on Spotify.`,
+}
+
+var followArtistCmd = &cobra.Command{
+	Use:   "artist [name]",
+	Short: "Follow an artist",
+	Long: `Follow the artist matching the given name, Spotify URI or open.spotify.com link.
+Without an argument, the first artist of the currently playing track is followed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return followArtist(strings.Join(args, " "), true)
+	},
+}
+
+var unfollowCmd = &cobra.Command{
+	Use:   "unfollow",
+	Short: "Unfollow artists",
+	Long:  `Commands for unfollowing artists on Spotify.`,
+}
+
+var unfollowArtistCmd = &cobra.Command{
+	Use:   "artist [name]",
+	Short: "Unfollow an artist",
+	Long: `Unfollow the artist matching the given name, Spotify URI or open.spotify.com link.
+Without an argument, the first artist of the currently playing track is unfollowed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return followArtist(strings.Join(args, " "), false)
+	},
+}
+
+var followingCmd = &cobra.Command{
+	Use:   "following",
+	Short: "List the artists you follow",
+	Long:  `List the artists you follow with their genres and follower counts.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showFollowing()
+	},
+}
+
+// followArtist follows or unfollows the artist matching the given reference.
+func followArtist(ref string, follow bool) error {
+	ctx := commandContext()
+
+	artist, err := resolveArtist(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	if follow {
+		if err := libraryUseCase.FollowArtists(ctx, []string{artist.ID}); err != nil {
+			return fmt.Errorf("failed to follow artist: %w", err)
+		}
+		return printResult(artist, "Followed %s\n", artist.Name)
+	}
+
+	if err := libraryUseCase.UnfollowArtists(ctx, []string{artist.ID}); err != nil {
+		return fmt.Errorf("failed to unfollow artist: %w", err)
+	}
+	return printResult(artist, "Unfollowed %s\n", artist.Name)
+}
+
+// showFollowing prints the followed artists as a table or as JSON.
+func showFollowing() error {
+	artists, err := libraryUseCase.GetFollowedArtists(commandContext())
+	if err != nil {
+		return fmt.Errorf("failed to get followed artists: %w", err)
+	}
+
+	if jsonOutput {
+		return printJSON(artists)
+	}
+
+	if len(artists) == 0 {
+		fmt.Println("You don't follow any artists.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ARTIST\tGENRES\tFOLLOWERS")
+	for _, artist := range artists {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", artist.Name, strings.Join(artist.Genres, ", "), artist.Followers)
+	}
+
+	return w.Flush()
+}
+
+// resolveArtist resolves an artist URI, link or name to an artist.
+// An empty reference means the first artist of the currently playing track.
+func resolveArtist(ctx context.Context, ref string) (*usecase.Artist, error) {
+	if ref == "" {
+		track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get currently playing track: %w", err)
+		}
+		// The artist field joins all artists of the track
+		ref, _, _ = strings.Cut(track.Artist, ", ")
+		if ref == "" {
+			return nil, fmt.Errorf("the currently playing item has no artist")
+		}
+	}
+
+	if id, ok := parseSpotifyID(ref, "artist"); ok {
+		artist, err := searchUseCase.GetArtist(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get artist: %w", err)
+		}
+		return artist, nil
+	}
+
+	results, err := searchUseCase.Search(ctx, ref, "artist", 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for artist: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no artist found for %q", ref)
+	}
+
+	return &usecase.Artist{ID: results[0].ID, URI: results[0].URI, Name: results[0].Name}, nil
+}
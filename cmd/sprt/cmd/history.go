@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/spf13/cobra"
+)
+
+var historyLimit int
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Listening history commands",
+	Long:  `Commands for browsing your Spotify listening history.`,
+}
+
+var historyRecentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List recently played tracks",
+	Long: `List your most recently played tracks, newest first, with the time they were played.
+Spotify keeps at most the last 50 tracks.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showRecentlyPlayed(historyLimit)
+	},
+}
+
+var historySyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Record recently played tracks in the local history",
+	Long: `Record the tracks from Spotify's recently played list in the local history at ~/.sprt/history.jsonl.
+Spotify only keeps the last 50 plays, so run this regularly, e.g. hourly from cron, to keep a complete
+history for "sprt onrepeat" and "sprt stats". Reached listening goals with notify set are announced.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		historyUseCase := newHistoryUseCase()
+
+		added, err := historyUseCase.Sync(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sync listening history: %w", err)
+		}
+		result := struct {
+			Added int `json:"added"`
+		}{added}
+		if err := printResult(result, "Recorded %d new plays.\n", added); err != nil {
+			return err
+		}
+
+		checkGoals(ctx, historyUseCase)
+		return nil
+	},
+}
+
+// newHistoryUseCase creates the local listening history use case.
+func newHistoryUseCase() usecase.HistoryUseCase {
+	return usecase.NewHistoryUseCase(jsonfile.NewPlayHistoryRepository(""), playerUseCase)
+}
+
+// showRecentlyPlayed prints the recently played tracks as a table or as JSON.
+func showRecentlyPlayed(limit int) error {
+	tracks, err := playerUseCase.GetRecentlyPlayed(commandContext(), limit)
+	if err != nil {
+		return fmt.Errorf("failed to get recently played tracks: %w", err)
+	}
+
+	if jsonOutput {
+		return printJSON(tracks)
+	}
+
+	if len(tracks) == 0 {
+		fmt.Println("No recently played tracks.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLAYED\tTITLE\tARTIST\tDURATION")
+	for _, track := range tracks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", track.PlayedAt.Local().Format("2006-01-02 15:04"),
+			track.Title, track.Artist, formatDuration(track.DurationMs))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/interfaces/tui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	libraryLimit       int
+	libraryInteractive bool
+)
+
+var libraryCmd = &cobra.Command{
+	Use:   "library",
+	Short: "Library commands",
+	Long:  `Commands for browsing the tracks saved in your Spotify library.`,
+}
+
+var libraryTracksCmd = &cobra.Command{
+	Use:   "tracks",
+	Short: "List your saved tracks",
+	Long: `List the tracks saved in your library ("Liked Songs"), most recently saved first.
+Large libraries are fetched page by page; use --limit to stop early.
+
+With --interactive the tracks open in a browser instead:
+  ↑ / ↓    navigate (more tracks load as you scroll)
+  enter    play the track
+  a        add the track to the queue
+  u        remove the track from your library
+  ?        show the keybindings
+  q        quit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if libraryInteractive {
+			return tui.RunLibraryUI(commandContext(), appConfig, playerUseCase, libraryUseCase)
+		}
+		return showSavedTracks(libraryLimit)
+	},
+}
+
+// showSavedTracks prints up to limit saved tracks, or all of them when limit is 0, as a table or as JSON.
+func showSavedTracks(limit int) error {
+	tracks, err := libraryUseCase.ListSavedTracks(commandContext(), limit)
+	if err != nil {
+		return err
+	}
+	if hideExplicit() {
+		tracks = usecase.WithoutExplicit(tracks)
+	}
+
+	if jsonOutput {
+		return printJSON(tracks)
+	}
+
+	if len(tracks) == 0 {
+		fmt.Println("No saved tracks.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tTITLE\tARTIST\tDURATION\tADDED")
+	for i, track := range tracks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, track.Title, track.Artist,
+			formatDuration(track.DurationMs), track.AddedAt.Local().Format("2006-01-02"))
+	}
+
+	return w.Flush()
+}
@@ -2,12 +2,19 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/lrc"
 	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/editor"
+	"github.com/muhadif/sprt/infrastructure/recording"
+	"github.com/muhadif/sprt/infrastructure/sink"
 	"github.com/muhadif/sprt/interfaces/tui"
 	"github.com/spf13/cobra"
 )
@@ -15,13 +22,35 @@ import (
 var lyricCmd = &cobra.Command{
 	Use:   "lyric",
 	Short: "Lyric commands",
-	Long:  `Commands for displaying lyrics for the currently playing track.`,
+	Long: `Commands for displaying and saving lyrics for the currently playing track.
+
+With --record the player polls, lyric lookups and lyric updates of the display are logged with
+timestamps to a JSON Lines file. "sprt replay <file>" plays the capture back, e.g. to attach a
+reproducible capture to a report about lyrics drifting out of sync.`,
 }
 
 var pipeLyricCmd = &cobra.Command{
 	Use:   "pipe",
 	Short: "Display synchronized lyrics for the currently playing track",
-	Long:  `Display synchronized lyrics for the currently playing track from lrclib.net.`,
+	Long: `Display synchronized lyrics for the currently playing track from lrclib.net.
+
+The current line is published to the sinks configured in ~/.sprt/config.json. Pass --sink,
+repeated for several, to publish to these sinks instead:
+
+  terminal            the current line, redrawn in place when no display is shown
+  stdout              one line per update; the display is hidden so the output can be piped
+  ndjson              one JSON object per update, see --ndjson
+  notification        a desktop notification per line
+  file:<path>         a file replaced with the current line (repeatable)
+  now-playing:<path>  a JSON file replaced with the playing track on every change
+  fifo:<path>         a named pipe receiving one line per update
+  socket:<path>       a UNIX socket broadcasting one line per update
+  http:<addr>         the HTML overlay and /line JSON, e.g. http:127.0.0.1:8975
+
+With --ndjson every update is printed to stdout as a JSON object on a line of its own, with
+its type (line, separator or clear), the text, the line index and timing, the playback
+progress and the track, for scripts and bots to consume. The configured file, FIFO, socket
+and overlay sinks keep running alongside.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return displaySyncedLyrics()
 	},
@@ -30,79 +59,350 @@ var pipeLyricCmd = &cobra.Command{
 var showLyricCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Display lyrics for the currently playing track with a nice UI",
-	Long:  `Display lyrics for the currently playing track from lrclib.net with a nice UI.`,
+	Long: `Display lyrics for the currently playing track from lrclib.net with a nice UI.
+Press ? for the keybindings; [ and ] shift lyrics that are out of sync.
+
+With --kiosk the lyrics are shown read-only, e.g. on a public display: searching and saving
+snippets are disabled, the key hints are hidden and only Ctrl+C quits.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return displayLyricsWithUI()
 	},
 }
 
+var exportLyricCmd = &cobra.Command{
+	Use:   "export [path]",
+	Short: "Save the lyrics of the currently playing track to a file",
+	Long: `Save the synchronized lyrics of the currently playing track to a file, e.g. to keep them next
+to your local music collection. The path defaults to "<artist> - <title>.<format>" in the current
+directory; "-" prints the lyrics instead.
+
+The formats are:
+
+  lrc  synchronized lyrics, with the artist, title, album and length in the tags
+  txt  the text of the lines
+  srt  SubRip subtitles, showing every line until the next one starts
+
+Without --format, the format follows the extension of the path, and is lrc otherwise.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ""
+		if len(args) > 0 {
+			path = args[0]
+		}
+		return exportLyrics(path)
+	},
+}
+
 // init function is no longer needed as commands are initialized in root.go
 // through the InitializeCommands function
 
 // displayLyricsWithUI displays lyrics for the currently playing track with a nice UI.
 func displayLyricsWithUI() error {
-	// Create the player use case
-	playerUseCase := usecase.NewPlayerUseCase(authUseCase)
-
 	// Get the currently playing track
-	track, err := playerUseCase.GetCurrentlyPlayingDetails(context.Background())
+	track, err := playerUseCase.GetCurrentlyPlayingDetails(commandContext())
 	if err != nil {
-		// Check if the error is "no track currently playing"
-		if err.Error() == "no track currently playing" {
+		if errors.Is(err, usecase.ErrNothingPlaying) {
 			// Show waiting UI instead of returning an error
-			return tui.RunWaitingTrackUI(authUseCase)
+			return tui.RunWaitingTrackUI(commandContext(), appConfig, authUseCase)
 		}
 		return fmt.Errorf("failed to get currently playing track: %w", err)
 	}
 
-	// Create a context that can be cancelled
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Handle Ctrl+C to gracefully exit
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		cancel()
-		fmt.Println("\nStopping lyrics display...")
-		os.Exit(0)
-	}()
+	feed, finish, err := lyricFeed(track.ProgressMs, playerUseCase, lyricUseCase)
+	if err != nil {
+		return err
+	}
 
-	// Run the lyric UI
-	return tui.RunLyricUI(ctx, track.ProgressMs, playerUseCase)
+	// Run the lyric UI until it quits or sprt is interrupted
+	err = tui.RunLyricUI(commandContext(), appConfig, feed, playerUseCase, kioskMode)
+	return errors.Join(err, finish())
 }
 
+// pipeSinks are the sinks given with --sink, replacing the configured ones
+var pipeSinks []string
+
+// pipeNDJSON prints the updates as JSON objects, set with --ndjson
+var pipeNDJSON bool
+
 // displaySyncedLyrics displays synchronized lyrics for the currently playing track.
 func displaySyncedLyrics() error {
-	// Create the player use case
-	playerUseCase := usecase.NewPlayerUseCase(authUseCase)
+	// The sinks given with --sink replace the configured ones
+	var sinks config.SinkConfig
+	if len(pipeSinks) > 0 {
+		parsed, err := sink.ParseSpecs(pipeSinks)
+		if err != nil {
+			return err
+		}
+		sinks = parsed
+	} else {
+		sinks = appConfig.Sinks
+		if pipeNDJSON {
+			// The JSON objects take the place of the terminal output
+			sinks.Terminal, sinks.Stdout = false, false
+		}
+	}
+	if pipeNDJSON {
+		sinks.NDJSON = true
+	}
+	// Without a display stdout only carries the lyrics, also while waiting for a track
+	headless := sinks.Stdout || sinks.NDJSON
+
+	// A running daemon reports the track and the lyrics it found, so several pipes don't each
+	// poll Spotify and look up the lyrics
+	var tracks usecase.TrackSource = playerUseCase
+	var source usecase.LyricSource = lyricUseCase
+	if client := daemonClient(); client != nil {
+		tracks, source = client, client
+	}
 
 	// Get the currently playing track
-	track, err := playerUseCase.GetCurrentlyPlayingDetails(context.Background())
+	startTimeMs := 0
+	track, err := tracks.GetCurrentlyPlayingDetails(commandContext())
+	if err == nil {
+		startTimeMs = track.ProgressMs
+	} else if !errors.Is(err, usecase.ErrNothingPlaying) {
+		return fmt.Errorf("failed to get currently playing track: %w", err)
+	} else if !headless {
+		// Show waiting UI instead of returning an error
+		return tui.RunWaitingTrackUI(commandContext(), appConfig, authUseCase)
+	}
+
+	feed, finish, err := lyricFeed(startTimeMs, tracks, source)
 	if err != nil {
-		// Check if the error is "no track currently playing"
-		if err.Error() == "no track currently playing" {
-			// Show waiting UI instead of returning an error
-			return tui.RunWaitingTrackUI(authUseCase)
+		return err
+	}
+
+	// Run the pipe lyric UI until it quits or sprt is interrupted; the sinks are closed either way
+	err = tui.RunPipeLyricUI(commandContext(), appConfig, feed, &sinks)
+	return errors.Join(err, finish())
+}
+
+// lyricRecord is the file the lyric displays record to, set with --record
+var lyricRecord string
+
+// lyricFeed returns the feed of the lyric engine following tracks from startTimeMs, recorded
+// to the file given with --record, and a function finishing the recording.
+func lyricFeed(startTimeMs int, tracks usecase.TrackSource, source usecase.LyricSource) (tui.LyricFeed, func() error, error) {
+	if lyricRecord == "" {
+		feed := func(ctx context.Context) <-chan *usecase.LyricUpdate {
+			return usecase.LyricChannel(ctx, startTimeMs, tracks, source)
 		}
+		return feed, func() error { return nil }, nil
+	}
+
+	recorder, err := recording.Create(lyricRecord, startTimeMs)
+	if err != nil {
+		return nil, nil, err
+	}
+	tracks, source = recorder.Tracks(tracks), recorder.Lyrics(source)
+	feed := func(ctx context.Context) <-chan *usecase.LyricUpdate {
+		return recorder.Updates(ctx, usecase.LyricChannel(ctx, startTimeMs, tracks, source))
+	}
+	finish := func() error {
+		if err := recorder.Close(); err != nil {
+			return err
+		}
+		// stdout may carry the lyrics
+		fmt.Fprintf(os.Stderr, "Recorded the session to %s, replay it with \"sprt replay %s\"\n", lyricRecord, lyricRecord)
+		return nil
+	}
+	return feed, finish, nil
+}
+
+var editLyricCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Correct the lyrics of the currently playing track in your editor",
+	Long: `Open the lyrics of the currently playing track in $VISUAL or $EDITOR, e.g. to fix typos or
+timing, and keep them as a local LRC file that is used instead of the lyrics of lrclib.net.
+
+The file is created in the local lyrics directory, ~/.lyrics by default (lyrics.dir in
+~/.sprt/config.json), from the lyrics currently shown, and opened there again the next time.
+The corrections are used the next time the track's lyrics are looked up, also by a running
+daemon.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editLyrics()
+	},
+}
+
+// editLyrics opens the local lyrics of the playing track in the editor, creating them from the
+// lyrics found online first.
+func editLyrics() error {
+	ctx := commandContext()
+	var tracks usecase.TrackSource = playerUseCase
+	var source usecase.LyricSource = lyricUseCase
+	if client := daemonClient(); client != nil {
+		tracks, source = client, client
+	}
+
+	track, err := tracks.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
 		return fmt.Errorf("failed to get currently playing track: %w", err)
 	}
+	if track.IsEpisode() {
+		return fmt.Errorf("lyrics aren't available for podcasts")
+	}
+	path, err := lyricUseCase.LocalLyricsPath(track.Artist, track.Title)
+	if err != nil {
+		return err
+	}
 
-	// Create a context that can be cancelled
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// Start from the lyrics shown now, or from the tags of the track when there are none
+		text := fmt.Sprintf("[ar:%s]\n[ti:%s]\n[al:%s]\n", track.Artist, track.Title, track.Album)
+		lyrics, err := source.GetLyrics(ctx, track.Artist, track.Title, track.Album, track.DurationMs)
+		if err == nil {
+			if exported, err := usecase.ExportLyrics(lyrics, track, usecase.ExportLRC); err == nil {
+				text = exported
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create the lyrics directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			return fmt.Errorf("failed to write lyrics: %w", err)
+		}
+	}
+
+	if err := editor.Open(ctx, path); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the edited lyrics: %w", err)
+	}
+	// Lyrics without timestamps are left to lrclib.net
+	result := struct {
+		Track  *usecase.CurrentlyPlaying `json:"track"`
+		Path   string                    `json:"path"`
+		Synced bool                      `json:"synced"`
+	}{track, path, len(lrc.Parse(string(data))) > 0}
+	if !result.Synced {
+		return printResult(result, "%s has no timestamped lines, so lrclib.net is still used for %s by %s\n", path, track.Title, track.Artist)
+	}
+	return printResult(result, "Saved the lyrics of %s by %s to %s\n", track.Title, track.Artist, path)
+}
 
-	// Handle Ctrl+C to gracefully exit
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		cancel()
-		fmt.Println("\nStopping lyrics display...")
-		os.Exit(0)
-	}()
+var matchLyricCmd = &cobra.Command{
+	Use:   "match",
+	Short: "Choose between the versions of lyrics found for the currently playing track",
+	Long: `Choose between the lyrics lrclib.net has for the currently playing track.
 
-	// Run the pipe lyric UI
-	return tui.RunPipeLyricUI(ctx, track.ProgressMs, playerUseCase)
+lrclib.net often has several versions of a track, such as live or remixed ones, and the first
+synchronized one isn't always right. The picker lists them with their album and duration; the
+chosen version is remembered in ~/.sprt/lyric_matches.json and used for the track from then on,
+also by a running daemon once it looks the lyrics up again. --match-index chooses the version
+with that number in the list without showing the picker.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return matchLyrics()
+	},
+}
+
+// matchIndex is the 1-based number of the version chosen by "sprt lyric match", set with
+// --match-index; 0 shows the picker
+var matchIndex int
+
+// matchLyrics chooses the version of the lyrics of the playing track.
+func matchLyrics() error {
+	ctx := commandContext()
+	var tracks usecase.TrackSource = playerUseCase
+	if client := daemonClient(); client != nil {
+		tracks = client
+	}
+
+	track, err := tracks.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get currently playing track: %w", err)
+	}
+	if track.IsEpisode() {
+		return fmt.Errorf("lyrics aren't available for podcasts")
+	}
+	matches, err := lyricUseCase.SearchLyrics(ctx, track.Artist, track.Title, track.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to search lyrics: %w", err)
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no lyrics found for %s by %s", track.Title, track.Artist)
+	}
+
+	index := matchIndex - 1
+	if matchIndex == 0 {
+		if index, err = tui.RunLyricMatchPicker(ctx, appConfig, track.Title+" by "+track.Artist, matches); err != nil {
+			return err
+		}
+		if index < 0 {
+			return nil
+		}
+	} else if index < 0 || index >= len(matches) {
+		return fmt.Errorf("--match-index must be between 1 and %d, the number of versions found", len(matches))
+	}
+
+	match := matches[index]
+	match.Picked = true
+	if err := lyricUseCase.ChooseLyrics(ctx, track.Artist, track.Title, match.ID); err != nil {
+		return err
+	}
+	result := struct {
+		Track  *usecase.CurrentlyPlaying `json:"track"`
+		Lyrics usecase.LyricMatch        `json:"lyrics"`
+	}{track, match}
+	return printResult(result, "Using the lyrics of %s – %s (%s) for %s by %s\n", match.Name, match.Artist, tui.LyricMatchDetails(match), track.Title, track.Artist)
+}
+
+// exportFormat is the format of "sprt lyric export", set with --format
+var exportFormat string
+
+// exportLyrics saves the lyrics of the playing track to path in exportFormat.
+func exportLyrics(path string) error {
+	if exportFormat != "" && !slices.Contains(usecase.ExportFormats, exportFormat) {
+		return fmt.Errorf("unknown format %q (expected lrc, txt or srt)", exportFormat)
+	}
+
+	ctx := commandContext()
+	var tracks usecase.TrackSource = playerUseCase
+	var source usecase.LyricSource = lyricUseCase
+	if client := daemonClient(); client != nil {
+		tracks, source = client, client
+	}
+
+	track, err := tracks.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get currently playing track: %w", err)
+	}
+	if track.IsEpisode() {
+		return fmt.Errorf("lyrics aren't available for podcasts")
+	}
+	lyrics, err := source.GetLyrics(ctx, track.Artist, track.Title, track.Album, track.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to get lyrics: %w", err)
+	}
+
+	format := exportFormat
+	if format == "" {
+		format = usecase.ExportLRC
+		if ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."); slices.Contains(usecase.ExportFormats, ext) {
+			format = ext
+		}
+	}
+	text, err := usecase.ExportLyrics(lyrics, track, format)
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		fmt.Print(text)
+		return nil
+	}
+	if path == "" {
+		path = usecase.LyricFileName(track.Artist, track.Title, format)
+	}
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return fmt.Errorf("failed to write lyrics: %w", err)
+	}
+	result := struct {
+		Track  *usecase.CurrentlyPlaying `json:"track"`
+		Path   string                    `json:"path"`
+		Format string                    `json:"format"`
+	}{track, path, format}
+	return printResult(result, "Saved the lyrics of %s by %s to %s\n", track.Title, track.Artist, path)
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+// onRepeatPlaylistName is the name of the playlist built by --playlist.
+const onRepeatPlaylistName = "On Repeat (local)"
+
+var (
+	onRepeatMinPlays int
+	onRepeatDays     int
+	onRepeatPlaylist bool
+)
+
+var onRepeatCmd = &cobra.Command{
+	Use:   "onrepeat",
+	Short: "List the tracks you've had on repeat",
+	Long: `List the tracks played at least --min times in the last --days days, from your local listening history.
+The history is synced with Spotify's recently played tracks first. Spotify only keeps the last 50 plays,
+so run "sprt history sync" regularly, e.g. from cron, to record everything you listen to.
+--playlist saves the tracks to a private "` + onRepeatPlaylistName + `" playlist, replacing its previous tracks.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showOnRepeat(onRepeatMinPlays, onRepeatDays, onRepeatPlaylist)
+	},
+}
+
+// showOnRepeat prints the tracks on repeat and optionally saves them to the On Repeat playlist.
+func showOnRepeat(minPlays, days int, savePlaylist bool) error {
+	ctx := commandContext()
+	historyUseCase := newHistoryUseCase()
+
+	// A failed sync still leaves the plays recorded earlier
+	if _, err := historyUseCase.Sync(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to sync listening history: %v\n", err)
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	tracks, err := historyUseCase.OnRepeat(ctx, since, max(minPlays, 1))
+	if err != nil {
+		return fmt.Errorf("failed to read listening history: %w", err)
+	}
+
+	if jsonOutput {
+		if err := printJSON(tracks); err != nil {
+			return err
+		}
+	} else if len(tracks) == 0 {
+		fmt.Printf("No tracks played %d or more times in the last %d days.\n", minPlays, days)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PLAYS\tTITLE\tARTIST\tLAST PLAYED")
+		for _, track := range tracks {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", track.Plays, track.Title, track.Artist,
+				track.LastPlayedAt.Local().Format("2006-01-02 15:04"))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if savePlaylist {
+		return saveOnRepeatPlaylist(ctx, tracks, !jsonOutput)
+	}
+	return nil
+}
+
+// saveOnRepeatPlaylist replaces the tracks of the On Repeat playlist, creating it when needed.
+func saveOnRepeatPlaylist(ctx context.Context, tracks []usecase.RepeatedTrack, verbose bool) error {
+	playlists, err := playlistUseCase.ListPlaylists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	var playlist *usecase.Playlist
+	for i := range playlists {
+		if playlists[i].Name == onRepeatPlaylistName {
+			playlist = &playlists[i]
+			break
+		}
+	}
+	if playlist == nil {
+		playlist, err = playlistUseCase.CreatePlaylist(ctx, onRepeatPlaylistName,
+			"Tracks on repeat in the last week, built by sprt from your listening history.", false)
+		if err != nil {
+			return fmt.Errorf("failed to create playlist: %w", err)
+		}
+	}
+
+	uris := make([]string, len(tracks))
+	for i, track := range tracks {
+		uris[i] = track.URI
+	}
+	if err := playlistUseCase.ReplaceTracks(ctx, playlist.ID, uris); err != nil {
+		return fmt.Errorf("failed to update playlist: %w", err)
+	}
+
+	if verbose {
+		fmt.Printf("\nSaved %d tracks to %s\n", len(uris), playlist.Name)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// jsonOutput makes commands print JSON instead of text or a TUI, set with --json.
+var jsonOutput bool
+
+// printJSON prints v as indented JSON on stdout.
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printResult prints v as JSON with --json and the formatted text otherwise,
+// e.g. to confirm an action.
+func printResult(v interface{}, format string, args ...interface{}) error {
+	if jsonOutput {
+		return printJSON(v)
+	}
+	fmt.Printf(format, args...)
+	return nil
+}
+
+// progressf prints a progress message, which is left out of JSON output.
+func progressf(format string, args ...interface{}) {
+	if !jsonOutput {
+		fmt.Printf(format, args...)
+	}
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+var playlistCmd = &cobra.Command{
+	Use:   "playlist",
+	Short: "Playlist commands",
+	Long:  `Commands for managing your Spotify playlists.`,
+}
+
+var playlistAddCurrentCmd = &cobra.Command{
+	Use:   "add-current <playlist>",
+	Short: "Add the currently playing track to a playlist",
+	Long: `Add the currently playing track to one of your playlists.
+The playlist is matched by ID or by name; partial names are accepted as long as they are unambiguous.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return addCurrentToPlaylist(strings.Join(args, " "))
+	},
+}
+
+var playlistShowCmd = &cobra.Command{
+	Use:   "show <name|id>",
+	Short: "Show the tracks of a playlist",
+	Long: `Show all tracks of a playlist with their durations and the date they were added.
+The playlist is matched by name among your playlists, or given as an ID, URI or open.spotify.com link.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showPlaylist(strings.Join(args, " "))
+	},
+}
+
+var (
+	playlistPublic      bool
+	playlistDescription string
+	playlistYes         bool
+)
+
+var playlistCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new playlist",
+	Long:  `Create a new playlist in your library. Playlists are private unless --public is given.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createPlaylist(strings.Join(args, " "), playlistDescription, playlistPublic)
+	},
+}
+
+var playlistDeleteCmd = &cobra.Command{
+	Use:   "delete <name|id>",
+	Short: "Delete a playlist",
+	Long: `Delete a playlist from your library after confirmation.
+Spotify doesn't delete playlists outright; they are unfollowed, which removes them from your library.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return deletePlaylist(strings.Join(args, " "), playlistYes)
+	},
+}
+
+// addCurrentToPlaylist appends the currently playing track to the playlist matching the given name.
+func addCurrentToPlaylist(name string) error {
+	ctx := commandContext()
+
+	track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get currently playing track: %w", err)
+	}
+	if track.URI == "" {
+		return fmt.Errorf("the currently playing item can't be added to a playlist")
+	}
+
+	playlist, err := findPlaylist(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := playlistUseCase.AddTracks(ctx, playlist.ID, []string{track.URI}); err != nil {
+		return fmt.Errorf("failed to add track to playlist: %w", err)
+	}
+
+	result := struct {
+		Track    *usecase.CurrentlyPlaying `json:"track"`
+		Playlist *usecase.Playlist         `json:"playlist"`
+	}{track, playlist}
+	return printResult(result, "Added %s by %s to %s\n", track.Title, track.Artist, playlist.Name)
+}
+
+// showPlaylist prints all tracks of the playlist matching the given name or ID.
+func showPlaylist(ref string) error {
+	ctx := commandContext()
+
+	// Links and URIs don't need to be looked up among the user's playlists
+	playlistID, name := "", ref
+	if id, ok := parseSpotifyID(ref, "playlist"); ok {
+		playlistID = id
+	} else if playlist, err := findPlaylist(ctx, ref); err == nil {
+		playlistID, name = playlist.ID, playlist.Name
+	} else if isSpotifyID(ref) {
+		// Someone else's playlist given by its bare ID
+		playlistID = ref
+	} else {
+		return err
+	}
+
+	tracks, err := playlistUseCase.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+	if hideExplicit() {
+		tracks = usecase.WithoutExplicit(tracks)
+	}
+
+	if jsonOutput {
+		return printJSON(struct {
+			ID     string                  `json:"id"`
+			Name   string                  `json:"name"`
+			Tracks []usecase.PlaylistTrack `json:"tracks"`
+		}{playlistID, name, tracks})
+	}
+
+	fmt.Printf("%s (%d tracks)\n\n", name, len(tracks))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tTITLE\tARTIST\tDURATION\tADDED")
+	for i, track := range tracks {
+		added := ""
+		if !track.AddedAt.IsZero() {
+			added = track.AddedAt.Local().Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, track.Title, track.Artist, formatDuration(track.DurationMs), added)
+	}
+
+	return w.Flush()
+}
+
+// createPlaylist creates a new playlist.
+func createPlaylist(name, description string, public bool) error {
+	playlist, err := playlistUseCase.CreatePlaylist(commandContext(), name, description, public)
+	if err != nil {
+		return fmt.Errorf("failed to create playlist: %w", err)
+	}
+
+	visibility := "private"
+	if playlist.Public {
+		visibility = "public"
+	}
+	return printResult(playlist, "Created %s playlist %s (%s)\n", visibility, playlist.Name, playlist.URI)
+}
+
+// deletePlaylist deletes the playlist matching the given name or ID after confirmation.
+func deletePlaylist(ref string, skipConfirmation bool) error {
+	ctx := commandContext()
+
+	playlist, err := findPlaylist(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	if !skipConfirmation {
+		// The question would end up in the JSON output
+		if jsonOutput {
+			return fmt.Errorf("deleting a playlist with --json needs --yes")
+		}
+		answer, err := promptInput(fmt.Sprintf("Delete playlist %q (%d tracks)? [y/N] ", playlist.Name, playlist.TrackCount))
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if answer != "y" && answer != "Y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := playlistUseCase.DeletePlaylist(ctx, playlist.ID); err != nil {
+		return fmt.Errorf("failed to delete playlist: %w", err)
+	}
+
+	return printResult(playlist, "Deleted playlist %s\n", playlist.Name)
+}
+
+// findPlaylist resolves a playlist name or ID against the user's playlists.
+func findPlaylist(ctx context.Context, name string) (*usecase.Playlist, error) {
+	playlists, err := playlistUseCase.ListPlaylists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	return usecase.MatchPlaylist(playlists, name)
+}
+
+// isSpotifyID reports whether s looks like a bare 22 character base-62 Spotify ID.
+func isSpotifyID(s string) bool {
+	if len(s) != 22 {
+		return false
+	}
+	for _, c := range s {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// formatDuration formats a duration in milliseconds as m:ss.
+func formatDuration(ms int) string {
+	totalSeconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", totalSeconds/60, totalSeconds%60)
+}
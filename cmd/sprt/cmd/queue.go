@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Playback queue commands",
+	Long:  `Commands for managing your Spotify playback queue.`,
+}
+
+var queueAddCmd = &cobra.Command{
+	Use:   "add <uri|search terms>",
+	Short: "Add a track to the playback queue",
+	Long: `Add a track to the end of your Spotify playback queue.
+The track can be given as a Spotify URI (spotify:track:...), an open.spotify.com link,
+or as search terms, in which case the best matching track is queued.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return addToQueue(strings.Join(args, " "))
+	},
+}
+
+// addToQueue resolves the given track reference and adds it to the playback queue.
+func addToQueue(ref string) error {
+	ctx := commandContext()
+
+	track, err := resolveTrack(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	if err := playerUseCase.AddToQueue(ctx, track.URI); err != nil {
+		return fmt.Errorf("failed to add track to queue: %w", err)
+	}
+	recordRequest(ctx, track.URI, track.Title, "queue add")
+
+	return printResult(track, "Added to queue: %s by %s\n", track.Title, track.Artist)
+}
+
+// resolveTrack resolves a Spotify URI, open.spotify.com link or search terms to a track.
+func resolveTrack(ctx context.Context, ref string) (*usecase.Track, error) {
+	if id, ok := parseTrackID(ref); ok {
+		track, err := searchUseCase.GetTrack(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track: %w", err)
+		}
+		return track, nil
+	}
+
+	tracks, err := searchUseCase.SearchTracks(ctx, ref, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for track: %w", err)
+	}
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("no track found for %q", ref)
+	}
+
+	return &tracks[0], nil
+}
+
+// parseTrackID extracts the track ID from a Spotify track URI or open.spotify.com link.
+func parseTrackID(ref string) (string, bool) {
+	return parseSpotifyID(ref, "track")
+}
+
+// parseSpotifyID extracts the ID of an item of the given kind (track, playlist, ...)
+// from a Spotify URI or open.spotify.com link.
+func parseSpotifyID(ref, kind string) (string, bool) {
+	if prefix := "spotify:" + kind + ":"; strings.HasPrefix(ref, prefix) {
+		return strings.TrimPrefix(ref, prefix), true
+	}
+
+	for _, prefix := range []string{"https://open.spotify.com/" + kind + "/", "http://open.spotify.com/" + kind + "/"} {
+		if strings.HasPrefix(ref, prefix) {
+			id := strings.TrimPrefix(ref, prefix)
+			// Drop query parameters such as ?si=...
+			if i := strings.IndexAny(id, "?#"); i != -1 {
+				id = id[:i]
+			}
+			return id, id != ""
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+var quoteQuiet bool
+
+var quoteCmd = &cobra.Command{
+	Use:   "quote",
+	Short: "Print a random lyric line from your listening history",
+	Long: `Print a random lyric line from one of your recently played tracks.
+Tracks you played more often are more likely to be quoted. Add it to your shell rc file or MOTD
+with --quiet so that a missing connection doesn't print an error.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := printQuote()
+		if err != nil && quoteQuiet {
+			return nil
+		}
+		return err
+	},
+}
+
+// printQuote picks a quote and prints it formatted or as JSON.
+func printQuote() error {
+	quoteUseCase := usecase.NewQuoteUseCase(playerUseCase, lyricUseCase)
+
+	quote, err := quoteUseCase.RandomQuote(commandContext())
+	if err != nil {
+		return fmt.Errorf("failed to pick a quote: %w", err)
+	}
+
+	return printResult(quote, "  “%s”\n      — %s, %s\n", quote.Text, quote.Artist, quote.Title)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/recording"
+	"github.com/muhadif/sprt/interfaces/tui"
+	"github.com/spf13/cobra"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Replay a recorded lyric session",
+	Long: `Replay a session recorded with "sprt lyric show --record <file>" or "sprt lyric pipe --record <file>".
+
+The recorded player polls and lyrics are fed back through the lyric engine and shown in the lyric
+display with their original timing, so lyrics drifting out of sync can be reproduced without
+Spotify. When the recording ends the display stays as it was at the end. The display is
+read-only; Ctrl+C quits.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{offlineAnnotation: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return replaySession(args[0])
+	},
+}
+
+// replaySession shows the recorded session at path in the lyric display.
+func replaySession(path string) error {
+	replay, err := recording.Open(path)
+	if err != nil {
+		return err
+	}
+
+	feed := func(ctx context.Context) <-chan *usecase.LyricUpdate {
+		return usecase.LyricChannel(ctx, replay.StartMs(), replay, replay)
+	}
+	// Keys would control the Spotify of whoever replays, so the display is kiosk
+	return tui.RunLyricUI(commandContext(), appConfig, feed, nil, true)
+}
@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
+	"github.com/muhadif/sprt/config"
 	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/httpclient"
+	"github.com/muhadif/sprt/infrastructure/lifecycle"
+	"github.com/muhadif/sprt/interfaces/card"
+	"github.com/muhadif/sprt/interfaces/ipc"
 	"github.com/muhadif/sprt/interfaces/tui"
 	"github.com/spf13/cobra"
 )
@@ -19,26 +26,114 @@ var (
 
 // Use cases
 var (
-	authUseCase   usecase.AuthUseCase
-	playerUseCase usecase.PlayerUseCase
-	lyricUseCase  usecase.LyricUseCase
+	authUseCase     usecase.AuthUseCase
+	playerUseCase   usecase.PlayerUseCase
+	lyricUseCase    usecase.LyricUseCase
+	searchUseCase   usecase.SearchUseCase
+	playlistUseCase usecase.PlaylistUseCase
+	libraryUseCase  usecase.LibraryUseCase
 )
 
+// UseCases are the use cases commands run with.
+type UseCases struct {
+	Auth     usecase.AuthUseCase
+	Player   usecase.PlayerUseCase
+	Lyric    usecase.LyricUseCase
+	Search   usecase.SearchUseCase
+	Playlist usecase.PlaylistUseCase
+	Library  usecase.LibraryUseCase
+}
+
+// newUseCases builds the use cases with the config, loading the credentials. skipChecks
+// leaves out the startup checks, set with --skip-checks.
+var newUseCases func(cfg *config.Config, skipChecks bool) UseCases
+
+// appConfig is the config commands and screens run with, loaded once by setupConfig. When it
+// can't be loaded it holds the defaults, and configErr tells why.
+var (
+	appConfig *config.Config
+	configErr error
+)
+
+// skipChecks leaves out the startup checks, set with --skip-checks
+var skipChecks bool
+
+// noDaemon makes the client commands poll Spotify themselves, set with --no-daemon
+var noDaemon bool
+
+// offlineAnnotation marks commands that don't use Spotify, so they run without loading
+// the credentials. Subcommands of a marked command are offline too.
+const offlineAnnotation = "sprt/offline"
+
 var rootCmd = &cobra.Command{
 	Use:   "sprt",
 	Short: "sprt - A command-line interface for Spotify",
 	Long: `sprt is a command-line interface for interacting with Spotify.
 It allows you to authenticate with Spotify, get information about your currently playing track,
 and display synchronized lyrics for the current track.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		setupTimeout()
+		if err := setupDebugLog(); err != nil {
+			return err
+		}
+		setupConfig()
+		if !isOffline(cmd) {
+			setupUseCases()
+		}
+		return nil
+	},
+}
+
+// Debug logging flags
+var (
+	verbose bool
+	logFile string
+)
+
+// commandTimeout bounds how long a command may run; 0 means no limit
+var commandTimeout time.Duration
+
+// app stops the goroutines, servers and files of a command when it returns or sprt is
+// interrupted with SIGINT or SIGTERM. It's created by Execute.
+var app *lifecycle.Manager
+
+// commandCtx is the context commands run in, ending on SIGINT, SIGTERM or when the timeout passes.
+// Its cancel function is released at the shutdown.
+var (
+	commandCtx    = context.Background()
+	cancelCommand context.CancelFunc
+)
+
+// commandContext returns the context commands run in, which ends when sprt is interrupted
+// or --timeout passes.
+func commandContext() context.Context {
+	return commandCtx
+}
+
+// setupTimeout bounds the command context by --timeout.
+func setupTimeout() {
+	if commandTimeout > 0 && cancelCommand == nil {
+		commandCtx, cancelCommand = context.WithTimeout(app.Context(), commandTimeout)
+		app.OnShutdown("timeout", func(context.Context) error {
+			cancelCommand()
+			return nil
+		})
+	}
+}
+
+// commandError explains errors caused by the timeout passing.
+func commandError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && commandCtx.Err() != nil {
+		return fmt.Errorf("timed out after %s: %w", commandTimeout, err)
+	}
+	return err
 }
 
-// InitializeCommands initializes all commands with the provided use cases and version information.
-// This is called by main.main() to set up dependency injection.
-func InitializeCommands(auth usecase.AuthUseCase, player usecase.PlayerUseCase, lyric usecase.LyricUseCase, ver, com, dt string) {
-	// Set use cases
-	authUseCase = auth
-	playerUseCase = player
-	lyricUseCase = lyric
+// InitializeCommands initializes all commands with the provided use case constructor and version information.
+// This is called by main.main() to set up dependency injection; the use cases are built when a command
+// first needs them, so commands like "sprt version" start without reading the credentials.
+func InitializeCommands(useCases func(cfg *config.Config, skipChecks bool) UseCases, ver, com, dt string) {
+	newUseCases = useCases
 
 	// Set version information
 	version = ver
@@ -46,77 +141,377 @@ func InitializeCommands(auth usecase.AuthUseCase, player usecase.PlayerUseCase,
 	date = dt
 
 	// Initialize all commands
+	initAlbumCommand()
 	initAuthCommand()
+	initCardCommand()
+	initCleanCommand()
+	initConfigCommand()
+	initControlCommand()
 	initCurrentCommand()
+	initDaemonCommand()
+	initDashboardCommand()
+	initDeviceCommand()
+	initFollowCommand()
+	initHistoryCommand()
+	initLibraryCommand()
 	initLyricCommand()
+	initOnRepeatCommand()
+	initQueueCommand()
+	initPlaylistCommand()
+	initQuoteCommand()
+	initReplayCommand()
+	initSearchCommand()
+	initSessionCommand()
+	initSnippetsCommand()
+	initStateCommand()
+	initStatsCommand()
+	initStatusCommand()
+	initUICommand()
 	initVersionCommand()
+
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "log every Spotify and lyrics request to stderr (or SPRT_DEBUG=1)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "write the request log to this file instead of stderr (or SPRT_DEBUG=<file>)")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "print machine-readable JSON instead of text")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "timeout", 0, "stop the command after this long, e.g. 30s or 5m (default no limit)")
+	rootCmd.PersistentFlags().BoolVar(&skipChecks, "skip-checks", false, "skip the startup checks of the config and their warnings")
+	rootCmd.PersistentFlags().BoolVar(&noDaemon, "no-daemon", false, "poll Spotify directly instead of asking a running daemon")
+}
+
+// setupConfig loads the config unless it was loaded already. Commands go on with the defaults
+// when it can't be loaded, so a broken config doesn't lock out every command.
+func setupConfig() {
+	if appConfig != nil {
+		return
+	}
+	appConfig, configErr = config.LoadConfig()
+	if configErr != nil && !skipChecks {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load config, using the defaults: %v\n", configErr)
+	}
+}
+
+// setupUseCases builds the use cases unless they were built already.
+func setupUseCases() {
+	if authUseCase != nil {
+		return
+	}
+	setupConfig()
+	useCases := newUseCases(appConfig, skipChecks)
+	authUseCase = useCases.Auth
+	playerUseCase = useCases.Player
+	lyricUseCase = useCases.Lyric
+	searchUseCase = useCases.Search
+	playlistUseCase = useCases.Playlist
+	libraryUseCase = useCases.Library
+}
+
+// isOffline tells whether cmd runs without Spotify: cobra's help and completion commands,
+// and commands marked with offlineAnnotation.
+func isOffline(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+		if _, ok := c.Annotations[offlineAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setupDebugLog turns on the request log when asked for by a flag or the SPRT_DEBUG environment variable.
+// The log file stays open until the shutdown.
+func setupDebugLog() error {
+	env := os.Getenv(httpclient.DebugEnv)
+	file := logFile
+	switch env {
+	case "", "0", "false":
+	case "1", "true":
+		verbose = true
+	default:
+		if file == "" {
+			file = env
+		}
+	}
+
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		httpclient.SetDebugLog(f)
+		app.OnShutdown("log file", func(context.Context) error {
+			httpclient.SetDebugLog(nil)
+			return f.Close()
+		})
+	} else if verbose {
+		httpclient.SetDebugLog(os.Stderr)
+	}
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Whatever the command started is stopped before sprt exits, also when it's interrupted.
 func Execute() {
-	// Check if any arguments were provided
+	app = lifecycle.New(context.Background())
+	commandCtx = app.Context()
+
+	var err error
 	if len(os.Args) > 1 {
 		// If arguments were provided, use the standard Cobra command execution
-		if err := rootCmd.Execute(); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		return
+		err = rootCmd.Execute()
+	} else {
+		// If no arguments were provided, show the TUI menu
+		err = showTUIMenu()
 	}
 
-	// If no arguments were provided, show the TUI menu
-	showTUIMenu()
+	if shutdownErr := app.Shutdown(); shutdownErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", shutdownErr)
+	}
+	os.Exit(exitCode(err))
 }
 
-// showTUIMenu displays the TUI menu and executes the selected command
-func showTUIMenu() {
-	// Run the main menu with transitions
-	choice, err := tui.RunMenuWithTransition(authUseCase, playerUseCase, lyricUseCase, version, date, commit)
-	if err != nil {
-		fmt.Printf("Error running menu: %v\n", err)
-		os.Exit(1)
+// exitCode prints the error of the command and returns the exit status of sprt. Commands cut
+// off by a signal exit like shells report it, without the errors of the cancelled requests;
+// commands that stop on a signal, like the daemon, succeed.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
 	}
+	if code := app.ExitCode(); code != 0 {
+		return code
+	}
+	fmt.Println(commandError(err))
+	return 1
+}
 
-	// If the user chose to quit, exit
-	if choice == "quit" || choice == "" {
-		return
+// showTUIMenu runs the app showing the TUI menu and the screens opened from it
+func showTUIMenu() error {
+	// Flags aren't parsed without arguments, but SPRT_DEBUG still applies
+	if err := setupDebugLog(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
+	setupUseCases()
 
-	// Execute the selected command
-	// Split the choice into separate arguments
-	args := strings.Split(choice, " ")
-	os.Args = append(os.Args, args...)
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	useCases := tui.AppUseCases{
+		Auth:      authUseCase,
+		Player:    playerUseCase,
+		Lyric:     lyricUseCase,
+		Search:    searchUseCase,
+		Playlist:  playlistUseCase,
+		Library:   libraryUseCase,
+		Authorize: startAuthorization,
+	}
+	if err := tui.RunApp(commandContext(), appConfig, useCases, version, date, commit); err != nil {
+		return fmt.Errorf("error running menu: %w", err)
 	}
+	return nil
 }
 
 // Helper functions to initialize each command
+func initAlbumCommand() {
+	rootCmd.AddCommand(albumCmd)
+	albumCmd.Flags().BoolVar(&albumPlay, "play", false, "start playing the album")
+}
+
 func initAuthCommand() {
 	rootCmd.AddCommand(authCmd)
 	authCmd.AddCommand(authInitCmd)
+	authInitCmd.Flags().BoolVar(&authInitManual, "manual", false, "paste the redirect URL instead of starting the callback server")
+	authInitCmd.Flags().BoolVar(&authInitNoBrowser, "no-browser", false, "show the authorization URL without opening a browser")
+	authCmd.AddCommand(authStatusCmd)
 	authCmd.AddCommand(authTestCmd)
+	authCmd.AddCommand(authScopesCmd)
+	authScopesCmd.Flags().BoolVarP(&authScopesYes, "yes", "y", false, "re-authorize without asking when scopes are missing")
+	authScopesCmd.Flags().BoolVar(&authScopesNoBrowser, "no-browser", false, "show the authorization URL without opening a browser")
+	authCmd.AddCommand(authEncryptCmd)
+	authEncryptCmd.Flags().StringVar(&authEncryptKey, "key", "machine", "key source: machine, passphrase or none to decrypt")
+	authCmd.AddCommand(authLogoutCmd)
+	authLogoutCmd.Flags().BoolVar(&authLogoutKeepClient, "keep-client", false, "keep the client ID and secret for logging in again")
+}
+
+func initCardCommand() {
+	rootCmd.AddCommand(cardCmd)
+	cardCmd.Flags().StringVarP(&cardOutput, "output", "o", "", "write the card to this file instead of stdout")
+	cardCmd.Flags().BoolVar(&cardCover, "cover", false, "draw a tiny cover with ANSI colors")
+	cardCmd.Flags().IntVar(&cardWidth, "width", card.DefaultWidth, "width of the card in terminal cells")
+	cardCmd.Flags().StringVar(&cardPNG, "png", "", "render the card with its cover to this PNG file")
+}
+
+func initCleanCommand() {
+	rootCmd.AddCommand(cleanCmd)
+	cleanCmd.AddCommand(cleanOnCmd)
+	cleanCmd.AddCommand(cleanOffCmd)
+	cleanCmd.AddCommand(cleanLockCmd)
+	cleanCmd.AddCommand(cleanUnlockCmd)
+	cleanOnCmd.Flags().BoolVar(&cleanAutoSkip, "auto-skip", false, "skip explicit tracks while \"sprt daemon\" runs")
+	cleanOnCmd.Flags().BoolVar(&cleanMaskLyrics, "mask-lyrics", true, "mask explicit words in the lyric displays")
+}
+
+func initConfigCommand() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configUICmd)
+}
+
+func initControlCommand() {
+	rootCmd.AddCommand(controlCmd)
 }
 
 func initCurrentCommand() {
 	rootCmd.AddCommand(currentCmd)
 }
 
+func initDaemonCommand() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.Flags().StringVar(&daemonEventsAddr, "events-addr", "", "stream playback events at /events on this address, e.g. 127.0.0.1:8977")
+	daemonCmd.Flags().StringVar(&daemonHealthAddr, "health-addr", "", "serve the daemon's health at /healthz on this address, e.g. 127.0.0.1:8976")
+	daemonCmd.Flags().StringVar(&daemonSocket, "socket", ipc.DefaultSocketPath(), "path of the control socket; empty turns it off")
+}
+
+func initDashboardCommand() {
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+func initDeviceCommand() {
+	rootCmd.AddCommand(deviceCmd)
+	deviceCmd.AddCommand(deviceListCmd)
+}
+
+func initFollowCommand() {
+	rootCmd.AddCommand(followCmd)
+	followCmd.AddCommand(followArtistCmd)
+	rootCmd.AddCommand(unfollowCmd)
+	unfollowCmd.AddCommand(unfollowArtistCmd)
+	rootCmd.AddCommand(followingCmd)
+}
+
+func initHistoryCommand() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyRecentCmd)
+	historyRecentCmd.Flags().IntVarP(&historyLimit, "limit", "l", 20, "number of tracks to show (max 50)")
+	historyCmd.AddCommand(historySyncCmd)
+}
+
+func initLibraryCommand() {
+	rootCmd.AddCommand(libraryCmd)
+	libraryCmd.AddCommand(libraryTracksCmd)
+	libraryTracksCmd.Flags().IntVarP(&libraryLimit, "limit", "l", 0, "maximum number of tracks to list (default all)")
+	libraryTracksCmd.Flags().BoolVarP(&libraryInteractive, "interactive", "i", false, "browse the tracks with play, queue and unlike actions")
+}
+
 func initLyricCommand() {
 	rootCmd.AddCommand(lyricCmd)
+	lyricCmd.AddCommand(editLyricCmd)
+	lyricCmd.AddCommand(exportLyricCmd)
+	exportLyricCmd.Flags().StringVar(&exportFormat, "format", "", "file format: lrc, txt or srt (default from the extension of the path, else lrc)")
+	lyricCmd.AddCommand(matchLyricCmd)
+	matchLyricCmd.Flags().IntVar(&matchIndex, "match-index", 0, "choose the version with this number in the picker's list without showing it")
 	lyricCmd.AddCommand(pipeLyricCmd)
+	pipeLyricCmd.Flags().BoolVar(&pipeNDJSON, "ndjson", false, "print one JSON object per update to stdout instead of showing the display")
+	pipeLyricCmd.Flags().StringArrayVar(&pipeSinks, "sink", nil, "publish to this sink instead of the configured ones, e.g. stdout or file:/tmp/lyric.txt (repeatable)")
 	lyricCmd.AddCommand(showLyricCmd)
+	showLyricCmd.Flags().BoolVar(&kioskMode, "kiosk", false, "read-only display: disable the keybindings and hide the key hints")
+	for _, c := range []*cobra.Command{pipeLyricCmd, showLyricCmd} {
+		c.Flags().StringVar(&lyricRecord, "record", "", "log the player polls and lyric events to this JSON Lines file for \"sprt replay\"")
+	}
+}
+
+func initOnRepeatCommand() {
+	rootCmd.AddCommand(onRepeatCmd)
+	onRepeatCmd.Flags().IntVar(&onRepeatMinPlays, "min", 3, "minimum number of plays")
+	onRepeatCmd.Flags().IntVar(&onRepeatDays, "days", 7, "number of days to look back")
+	onRepeatCmd.Flags().BoolVar(&onRepeatPlaylist, "playlist", false, "save the tracks to the \""+onRepeatPlaylistName+"\" playlist")
+}
+
+func initQueueCommand() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueAddCmd)
+}
+
+func initPlaylistCommand() {
+	rootCmd.AddCommand(playlistCmd)
+	playlistCmd.AddCommand(playlistAddCurrentCmd)
+	playlistCmd.AddCommand(playlistShowCmd)
+	playlistCmd.AddCommand(playlistCreateCmd)
+	playlistCmd.AddCommand(playlistDeleteCmd)
+	playlistCreateCmd.Flags().BoolVar(&playlistPublic, "public", false, "make the playlist public")
+	playlistCreateCmd.Flags().StringVar(&playlistDescription, "description", "", "playlist description")
+	playlistDeleteCmd.Flags().BoolVarP(&playlistYes, "yes", "y", false, "skip the confirmation prompt")
+}
+
+func initQuoteCommand() {
+	rootCmd.AddCommand(quoteCmd)
+	quoteCmd.Flags().BoolVarP(&quoteQuiet, "quiet", "q", false, "print nothing instead of an error when no quote can be found")
+}
+
+func initReplayCommand() {
+	rootCmd.AddCommand(replayCmd)
+}
+
+func initSearchCommand() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().StringVarP(&searchType, "type", "t", "track", "type of item to search for: track, album, artist or playlist")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 10, "maximum number of results")
+	searchCmd.Flags().IntVar(&searchPlay, "play", 0, "start playing the Nth result")
+}
+
+func initSessionCommand() {
+	rootCmd.AddCommand(sessionCmd)
+	sessionCmd.AddCommand(sessionStartCmd)
+	sessionCmd.AddCommand(sessionStopCmd)
+	sessionCmd.AddCommand(sessionStatusCmd)
+	for _, c := range []*cobra.Command{sessionStopCmd, sessionStatusCmd} {
+		c.Flags().StringVar(&sessionFormat, "format", sessionFormatText, "log format: text or markdown")
+		c.Flags().StringVarP(&sessionOutput, "output", "o", "", "write the log to this file instead of stdout")
+	}
+}
+
+func initSnippetsCommand() {
+	rootCmd.AddCommand(snippetsCmd)
+	snippetsCmd.AddCommand(snippetsListCmd)
+	snippetsCmd.AddCommand(snippetsPlayCmd)
+}
+
+func initStateCommand() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateWatchCmd)
+	stateWatchCmd.Flags().StringVar(&stateFile, "file", "", "path of the state file (default ~/.sprt/state.json)")
+}
+
+func initStatsCommand() {
+	rootCmd.AddCommand(statsCmd)
+}
+
+func initStatusCommand() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.AddCommand(statusClickCmd)
+	statusCmd.Flags().StringVar(&statusFormat, "format", "plain", "output format: plain, waybar, i3status-rust, slstatus or polybar")
+	statusCmd.Flags().BoolVar(&statusPolybar, "polybar", false, "shorthand for --format polybar")
+	statusCmd.Flags().BoolVarP(&statusFollow, "follow", "f", false, "keep running and print a new line whenever the status changes")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", time.Second, "how often --follow refreshes the status")
+	statusCmd.Flags().BoolVar(&statusProgressBar, "progress-bar", false, "append a progress bar (default from config)")
+	statusCmd.Flags().IntVar(&statusProgressWidth, "progress-width", 10, "number of cells in the progress bar")
+	statusCmd.Flags().IntVar(&statusMaxWidth, "max-width", 0, "limit artist and title to this many terminal cells")
+	statusCmd.Flags().BoolVar(&statusMarquee, "marquee", false, "scroll text longer than --max-width across invocations")
+}
+
+func initUICommand() {
+	rootCmd.AddCommand(uiCmd)
+	uiCmd.Flags().BoolVar(&kioskMode, "kiosk", false, "read-only display: disable the playback keys and hide the key hints")
 }
 
 // Version command
 var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print the version information",
-	Long:  `Print the version, build date, and commit hash of the application.`,
+	Use:         "version",
+	Short:       "Print the version information",
+	Long:        `Print the version, build date, and commit hash of the application.`,
+	Annotations: map[string]string{offlineAnnotation: ""},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return tui.RunVersionUI(version, date, commit)
+		if jsonOutput {
+			return printJSON(map[string]string{"version": version, "commit": commit, "date": date})
+		}
+		return tui.RunVersionUI(appConfig, version, date, commit)
 	},
 }
 
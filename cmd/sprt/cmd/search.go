@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchType  string
+	searchLimit int
+	searchPlay  int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the Spotify catalog",
+	Long: `Search the Spotify catalog for tracks, albums, artists or playlists and print a numbered result list.
+Use --play N to start playing the Nth result on the active device.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return search(strings.Join(args, " "), searchType, searchLimit, searchPlay)
+	},
+}
+
+// search prints the search results and optionally plays one of them.
+func search(query, itemType string, limit, play int) error {
+	ctx := commandContext()
+
+	results, err := searchUseCase.Search(ctx, query, itemType, limit)
+	if err != nil {
+		return fmt.Errorf("failed to search: %w", err)
+	}
+	if hideExplicit() {
+		results = usecase.WithoutExplicit(results)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no %s found for %q", itemType, query)
+	}
+
+	if play > 0 {
+		if play > len(results) {
+			return fmt.Errorf("result %d doesn't exist, there are %d results", play, len(results))
+		}
+
+		result := results[play-1]
+		if err := playerUseCase.PlayURI(ctx, result.URI); err != nil {
+			return fmt.Errorf("failed to start playback: %w", err)
+		}
+		recordRequest(ctx, result.URI, result.Name, "search")
+
+		return printResult(result, "Playing %s (%s)\n", result.Name, result.Detail)
+	}
+	if jsonOutput {
+		return printJSON(results)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, result := range results {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, result.Name, result.Detail, result.URI)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/spf13/cobra"
+)
+
+// requesterEnv names who runs sprt in session logs, e.g. on a shared machine.
+const requesterEnv = "SPRT_REQUESTER"
+
+// Session log formats.
+const (
+	sessionFormatText     = "text"
+	sessionFormatMarkdown = "markdown"
+)
+
+var (
+	sessionFormat string
+	sessionOutput string
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Listening session commands",
+	Long: `Commands for recording a listening session, e.g. a radio-show style evening or the shared
+office speaker, and exporting the log of everything played during it.
+
+Tracks, albums and playlists played or queued with sprt during a session are listed with who
+asked for them: the ` + requesterEnv + ` environment variable, or the user name.`,
+}
+
+var sessionStartCmd = &cobra.Command{
+	Use:   "start [name]",
+	Short: "Start recording a listening session",
+	Long:  `Start recording a listening session with the given name, "Listening session" by default.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.Join(args, " ")
+		if name == "" {
+			name = "Listening session"
+		}
+
+		session, err := newSessionUseCase().Start(commandContext(), name, time.Now())
+		if err != nil {
+			return err
+		}
+		return printResult(session, "Recording %s since %s. Run \"sprt session stop\" to export its log.\n",
+			session.Name, session.StartedAt.Format("15:04"))
+	},
+}
+
+var sessionStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the listening session and export its log",
+	Long: `Stop the running listening session and print its log, or write it to a file with --output.
+Spotify only keeps the last 50 plays, so for sessions longer than a few hours run
+"sprt session status" or "sprt history sync" in between.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log, err := newSessionUseCase().Stop(commandContext(), time.Now())
+		if err != nil {
+			return err
+		}
+		return exportSessionLog(log)
+	},
+}
+
+var sessionStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the log of the running session",
+	Long:  `Print the log of the running listening session so far, keeping it running.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log, err := newSessionUseCase().Log(commandContext(), time.Now())
+		if err != nil {
+			return err
+		}
+		return exportSessionLog(log)
+	},
+}
+
+// newSessionUseCase creates the listening session use case.
+func newSessionUseCase() usecase.SessionUseCase {
+	return usecase.NewSessionUseCase(jsonfile.NewSessionRepository(""), newHistoryUseCase())
+}
+
+// recordRequest notes in the running session, if any, that uri was played or queued by the
+// given command. Failing to record it never fails the command.
+func recordRequest(ctx context.Context, uri, name, via string) {
+	err := newSessionUseCase().Request(ctx, entity.SessionRequest{
+		At:   time.Now(),
+		URI:  uri,
+		Name: name,
+		By:   requester(),
+		Via:  via,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the request in the session: %v\n", err)
+	}
+}
+
+// requester returns who runs sprt, from SPRT_REQUESTER or the user name.
+func requester() string {
+	if name := os.Getenv(requesterEnv); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// exportSessionLog prints the session log as JSON or in the chosen format, to --output if given.
+func exportSessionLog(log *usecase.SessionLog) error {
+	if jsonOutput {
+		return printJSON(log)
+	}
+
+	var write func(io.Writer, *usecase.SessionLog) error
+	switch sessionFormat {
+	case sessionFormatText:
+		write = writeSessionText
+	case sessionFormatMarkdown:
+		write = writeSessionMarkdown
+	default:
+		return fmt.Errorf("invalid format %q (expected %s or %s)", sessionFormat, sessionFormatText, sessionFormatMarkdown)
+	}
+
+	if sessionOutput == "" {
+		return write(os.Stdout, log)
+	}
+
+	file, err := os.Create(sessionOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create session log: %w", err)
+	}
+	if err := write(file, log); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write session log: %w", err)
+	}
+	fmt.Printf("Saved the log of %s with %d tracks to %s\n", log.Name, len(log.Entries), sessionOutput)
+	return nil
+}
+
+// sessionSummary describes when the session ran and how many tracks were played.
+func sessionSummary(log *usecase.SessionLog) string {
+	start, stop := log.StartedAt.Local(), log.StoppedAt.Local()
+	return fmt.Sprintf("%s, %s–%s, %d tracks", start.Format("Mon 2 Jan 2006"),
+		start.Format("15:04"), stop.Format("15:04"), len(log.Entries))
+}
+
+// requestedBy describes who asked for the entry, or is empty when nobody did through sprt.
+func requestedBy(entry usecase.SessionEntry) string {
+	if entry.RequestedBy == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", entry.RequestedBy, entry.Via)
+}
+
+// writeSessionText writes the session log as an aligned plain-text table.
+func writeSessionText(out io.Writer, log *usecase.SessionLog) error {
+	fmt.Fprintf(out, "%s\n%s\n\n", log.Name, sessionSummary(log))
+	if len(log.Entries) == 0 {
+		_, err := fmt.Fprintln(out, "Nothing was played.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTITLE\tARTIST\tREQUESTED BY")
+	for _, entry := range log.Entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.PlayedAt.Local().Format("15:04"), entry.Title, entry.Artist, requestedBy(entry))
+	}
+	return w.Flush()
+}
+
+// writeSessionMarkdown writes the session log as a Markdown table, e.g. for show notes.
+func writeSessionMarkdown(out io.Writer, log *usecase.SessionLog) error {
+	fmt.Fprintf(out, "# %s\n\n_%s_\n\n", log.Name, sessionSummary(log))
+	if len(log.Entries) == 0 {
+		_, err := fmt.Fprintln(out, "Nothing was played.")
+		return err
+	}
+
+	escape := strings.NewReplacer("|", `\|`)
+	fmt.Fprintln(out, "| Time | Title | Artist | Requested by |")
+	fmt.Fprintln(out, "|------|-------|--------|--------------|")
+	for _, entry := range log.Entries {
+		_, err := fmt.Fprintf(out, "| %s | %s | %s | %s |\n", entry.PlayedAt.Local().Format("15:04"),
+			escape.Replace(entry.Title), escape.Replace(entry.Artist), escape.Replace(requestedBy(entry)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/spf13/cobra"
+)
+
+var snippetsCmd = &cobra.Command{
+	Use:   "snippets",
+	Short: "Saved lyric snippet commands",
+	Long: `Commands for revisiting your favorite lyric moments.
+Snippets are saved by pressing s in "sprt lyric show" and stored in ~/.sprt/snippets.json.`,
+}
+
+var snippetsListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List saved snippets",
+	Long:        `List your saved lyric snippets, numbered for "sprt snippets play".`,
+	Annotations: map[string]string{offlineAnnotation: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listSnippets()
+	},
+}
+
+var snippetsPlayCmd = &cobra.Command{
+	Use:   "play <n>",
+	Short: "Play a saved snippet",
+	Long:  `Play the track of the Nth saved snippet, starting at its lyric line.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid snippet number %q", args[0])
+		}
+		return playSnippet(n)
+	},
+}
+
+// listSnippets prints the saved snippets as a table or as JSON.
+func listSnippets() error {
+	snippets, err := jsonfile.NewSnippetRepository("").ListSnippets(commandContext())
+	if err != nil {
+		return fmt.Errorf("failed to load snippets: %w", err)
+	}
+
+	if jsonOutput {
+		return printJSON(snippets)
+	}
+
+	if len(snippets) == 0 {
+		fmt.Println("No saved snippets. Press s in \"sprt lyric show\" to save the current line.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tAT\tLINE\tTITLE\tARTIST")
+	for i, snippet := range snippets {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, formatDuration(snippet.StartTimeMs),
+			snippet.Text, snippet.Title, snippet.Artist)
+	}
+
+	return w.Flush()
+}
+
+// playSnippet plays the track of the Nth saved snippet from its lyric line.
+func playSnippet(n int) error {
+	ctx := commandContext()
+
+	snippets, err := jsonfile.NewSnippetRepository("").ListSnippets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load snippets: %w", err)
+	}
+	if n < 1 || n > len(snippets) {
+		return fmt.Errorf("no snippet %d, there are %d saved snippets", n, len(snippets))
+	}
+
+	snippet := snippets[n-1]
+	if err := playerUseCase.PlayTrackAt(ctx, snippet.TrackURI, snippet.StartTimeMs); err != nil {
+		return fmt.Errorf("failed to play snippet: %w", err)
+	}
+	recordRequest(ctx, snippet.TrackURI, snippet.Title, "snippets play")
+
+	return printResult(snippet, "Playing %s by %s from %s: %s\n", snippet.Title, snippet.Artist,
+		formatDuration(snippet.StartTimeMs), snippet.Text)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/filelock"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/spf13/cobra"
+)
+
+var stateFile string
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Playback state file commands",
+	Long:  `Commands for publishing the playback state to a JSON file for external widgets.`,
+}
+
+var stateWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Continuously write the playback state to a JSON file",
+	Long: `Continuously write the currently playing track, progress, active device and current lyric line
+to a JSON file (default ~/.sprt/state.json). The file is replaced atomically on every update,
+so widgets can read it at any time without seeing a partial write.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return watchState(stateFile)
+	},
+}
+
+// watchState keeps the state file up to date until interrupted.
+func watchState(path string) error {
+	ctx := commandContext()
+	// Like the daemon, the watcher runs unattended and doesn't go on with the defaults
+	if configErr != nil {
+		return configErr
+	}
+	cfg := appConfig
+
+	// A second watcher would overwrite the file with its own, slightly different state
+	if path == "" {
+		path = jsonfile.DefaultStatePath()
+	}
+	unlock, err := filelock.TryLock(path)
+	if errors.Is(err, filelock.ErrLocked) {
+		return fmt.Errorf("another sprt state watch is writing %s", path)
+	}
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	stateUseCase := usecase.NewStateUseCase(jsonfile.NewStateRepository(path), playerUseCase, lyricUseCase, cfg.Output.HistorySize)
+
+	fmt.Println("Writing playback state, press Ctrl+C to stop...")
+	if err := stateUseCase.Watch(ctx); err != nil {
+		return fmt.Errorf("failed to write playback state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/notification"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show listening statistics and goal progress",
+	Long: `Show this week's listening, your daily listening streak and the progress towards the
+listening goals from the "goals" section of ~/.sprt/config.json.
+The statistics come from the local history, which is synced first; run "sprt history sync"
+regularly, e.g. from cron, to record everything you listen to.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showStats()
+	},
+}
+
+// statsOutput is the JSON output of "sprt stats".
+type statsOutput struct {
+	Week struct {
+		Plays   int     `json:"plays"`
+		Hours   float64 `json:"hours"`
+		Artists int     `json:"artists"`
+	} `json:"week"`
+	StreakDays int          `json:"streak_days"`
+	Goals      []goalOutput `json:"goals"`
+}
+
+// goalOutput is the progress of a listening goal in the JSON output of "sprt stats".
+type goalOutput struct {
+	Goal   string  `json:"goal"`
+	Type   string  `json:"type"`
+	Period string  `json:"period"`
+	Target float64 `json:"target"`
+	Value  float64 `json:"value"`
+	Done   bool    `json:"done"`
+	Streak int     `json:"streak"`
+}
+
+// showStats prints the listening statistics and the progress of the configured goals.
+func showStats() error {
+	ctx := commandContext()
+	historyUseCase := newHistoryUseCase()
+
+	// A failed sync still leaves the plays recorded earlier
+	if _, err := historyUseCase.Sync(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to sync listening history: %v\n", err)
+	}
+
+	plays, err := historyUseCase.Plays(ctx, time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to read listening history: %w", err)
+	}
+
+	goals, err := configuredGoals(appConfig)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	week := usecase.GoalsProgress(plays, []usecase.Goal{
+		{Type: usecase.GoalTracks, Target: 1, Period: usecase.PeriodWeek},
+		{Type: usecase.GoalHours, Target: 1, Period: usecase.PeriodWeek},
+		{Type: usecase.GoalArtists, Target: 1, Period: usecase.PeriodWeek},
+	}, now)
+	streak := usecase.ListeningStreak(plays, now)
+	progress := usecase.GoalsProgress(plays, goals, now)
+
+	if jsonOutput {
+		output := statsOutput{StreakDays: streak, Goals: make([]goalOutput, len(progress))}
+		output.Week.Plays = int(week[0].Value)
+		output.Week.Hours = week[1].Value
+		output.Week.Artists = int(week[2].Value)
+		for i, p := range progress {
+			output.Goals[i] = goalOutput{
+				Goal:   p.Goal.String(),
+				Type:   p.Type,
+				Period: p.Period,
+				Target: p.Target,
+				Value:  p.Value,
+				Done:   p.Done(),
+				Streak: p.Streak,
+			}
+		}
+		if err := printJSON(output); err != nil {
+			return err
+		}
+		announceGoals(ctx, appConfig, progress)
+		return nil
+	}
+
+	fmt.Printf("This week: %.0f plays, %.1f hours, %.0f artists\n", week[0].Value, week[1].Value, week[2].Value)
+	fmt.Printf("Listening streak: %s\n", pluralize(streak, "day"))
+
+	if len(goals) == 0 {
+		fmt.Println("\nNo listening goals configured. Add them to the \"goals\" section of ~/.sprt/config.json.")
+		return nil
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GOAL\tPROGRESS\tSTREAK")
+	for _, p := range progress {
+		mark := ""
+		if p.Done() {
+			mark = " ✓"
+		}
+		fmt.Fprintf(w, "%s\t%s%s\t%s\n", p.Goal, p.Summary(), mark, pluralize(p.Streak, p.Period))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	announceGoals(ctx, appConfig, progress)
+	return nil
+}
+
+// configuredGoals converts the goals from the configuration, rejecting invalid ones.
+func configuredGoals(cfg *config.Config) ([]usecase.Goal, error) {
+	goals := make([]usecase.Goal, len(cfg.Goals))
+	for i, g := range cfg.Goals {
+		goals[i] = usecase.Goal{Type: g.Type, Target: g.Target, Period: g.Period}
+		if err := goals[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid goal %d in the config: %w", i+1, err)
+		}
+	}
+	return goals, nil
+}
+
+// announceGoals shows a notification for each reached goal with notify set,
+// at most once per period. Failures only print a warning.
+func announceGoals(ctx context.Context, cfg *config.Config, progress []usecase.GoalProgress) {
+	goalRepo := jsonfile.NewGoalRepository("")
+	milestones, err := goalRepo.LoadMilestones(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+
+	notifier := notification.NewNotifier(cfg.Notifications)
+	announced := false
+	for i, p := range progress {
+		key := p.Goal.String()
+		if !cfg.Goals[i].Notify || !p.Done() || milestones.Announced[key] == p.PeriodKey() {
+			continue
+		}
+		if err := notifier.Notify("Listening goal reached", p.Summary()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		milestones.Announced[key] = p.PeriodKey()
+		announced = true
+	}
+
+	if announced {
+		if err := goalRepo.SaveMilestones(ctx, milestones); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
+
+// checkGoals announces the goals reached in the local history, e.g. after a sync.
+func checkGoals(ctx context.Context, historyUseCase usecase.HistoryUseCase) {
+	if len(appConfig.Goals) == 0 {
+		return
+	}
+
+	goals, err := configuredGoals(appConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+
+	plays, err := historyUseCase.Plays(ctx, time.Time{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read listening history: %v\n", err)
+		return
+	}
+
+	announceGoals(ctx, appConfig, usecase.GoalsProgress(plays, goals, time.Now()))
+}
+
+// pluralize formats a count with a unit, e.g. "1 day" or "3 weeks".
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/muhadif/sprt/interfaces/status"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusFormat        string
+	statusProgressBar   bool
+	statusProgressWidth int
+	statusMaxWidth      int
+	statusMarquee       bool
+	statusPolybar       bool
+	statusFollow        bool
+	statusInterval      time.Duration
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print a one-line playback status",
+	Long: `Print a one-line playback status for status bars such as waybar.
+When "sprt state watch" is running the state file is used, so frequent polling doesn't hit the Spotify API.
+Use --format to print a preset for your bar: plain, waybar, i3status-rust, slstatus or polybar.
+With --max-width and --marquee, long names scroll by one cell on every invocation.
+
+With --follow the command keeps running and prints a new line whenever the status changes, for bars
+that read a command's output continuously, such as polybar with tail = true or i3blocks with
+interval=persist. A scrolling name then moves on every --interval.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printStatus(cmd)
+	},
+}
+
+var statusClickCmd = &cobra.Command{
+	Use:   "click <button>",
+	Short: "Handle a status bar click",
+	Long: `Handle a click on the status bar block, so bars can bind their click events without wrapper scripts.
+The button is given by name or by its i3bar number:
+  left (1)   toggle play/pause
+  middle (2) previous track
+  right (3)  next track
+  up (4)     volume up
+  down (5)   volume down`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return handleStatusClick(args[0])
+	},
+}
+
+// snapshotSource reports the playback snapshot, e.g. from the state use case or the daemon.
+type snapshotSource interface {
+	Current(ctx context.Context) (*entity.PlaybackSnapshot, error)
+}
+
+// clickVolumeStep is the volume change in percent for scroll clicks.
+const clickVolumeStep = 5
+
+// printStatus prints the current playback status in the requested format.
+func printStatus(cmd *cobra.Command) error {
+	opts := status.Options{
+		ProgressBar:      appConfig.Output.ProgressBar,
+		ProgressBarWidth: appConfig.Output.ProgressBarWidth,
+		StoppedText:      appConfig.Output.StoppedText,
+		MaxWidth:         appConfig.Output.MaxWidth,
+		Marquee:          appConfig.Output.Marquee,
+	}
+	// Flags override the configuration only when given explicitly
+	if cmd.Flags().Changed("progress-bar") {
+		opts.ProgressBar = statusProgressBar
+	}
+	if cmd.Flags().Changed("progress-width") {
+		opts.ProgressBarWidth = statusProgressWidth
+	}
+	if cmd.Flags().Changed("max-width") {
+		opts.MaxWidth = statusMaxWidth
+	}
+	if cmd.Flags().Changed("marquee") {
+		opts.Marquee = statusMarquee
+	}
+
+	format := statusFormat
+	if statusPolybar {
+		if cmd.Flags().Changed("format") && statusFormat != status.FormatPolybar {
+			return fmt.Errorf("--polybar can't be combined with --format %s", statusFormat)
+		}
+		format = status.FormatPolybar
+	}
+
+	// A running daemon answers from the state it keeps, otherwise the state file or Spotify is used
+	var source snapshotSource = usecase.NewStateUseCase(jsonfile.NewStateRepository(""), playerUseCase, lyricUseCase, appConfig.Output.HistorySize)
+	if client := daemonClient(); client != nil {
+		source = client
+	}
+	ctx := commandContext()
+	if statusFollow {
+		if jsonOutput {
+			return fmt.Errorf("--follow prints status lines, use --format waybar for a JSON object per line")
+		}
+		if _, err := status.Format(&entity.PlaybackSnapshot{}, format, opts); err != nil {
+			return err
+		}
+		return followStatus(ctx, source, format, opts)
+	}
+
+	snapshot, err := source.Current(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get playback status: %w", err)
+	}
+
+	if jsonOutput {
+		return printJSON(snapshot)
+	}
+
+	if opts.Marquee && opts.MaxWidth > 0 {
+		opts.MarqueeOffset = advanceMarquee(ctx, status.Text(snapshot))
+	}
+
+	output, err := status.Format(snapshot, format, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(output)
+	return nil
+}
+
+// followStatus prints the status line every interval, but only when it differs from the last
+// one printed, until ctx is done or the command is interrupted. The marquee advances in memory.
+// Failed polls are reported on stderr and keep the last line, so the bar doesn't lose the block.
+func followStatus(ctx context.Context, source snapshotSource, format string, opts status.Options) error {
+	ticker := time.NewTicker(max(statusInterval, 100*time.Millisecond))
+	defer ticker.Stop()
+
+	last, marqueeText := "", ""
+	for {
+		snapshot, err := source.Current(ctx)
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to get playback status: %v\n", err)
+		}
+		if err == nil {
+			// The marquee starts over on a new track
+			if text := status.Text(snapshot); text != marqueeText {
+				marqueeText, opts.MarqueeOffset = text, 0
+			}
+
+			output, err := status.Format(snapshot, format, opts)
+			if err != nil {
+				return err
+			}
+			if output != last {
+				fmt.Println(output)
+				last = output
+			}
+			opts.MarqueeOffset++
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// advanceMarquee returns the scroll position for text and stores the next one.
+// The position restarts whenever the text changes, e.g. on a new track.
+func advanceMarquee(ctx context.Context, text string) int {
+	marqueeRepo := jsonfile.NewMarqueeRepository("")
+
+	offset := 0
+	if state, err := marqueeRepo.LoadMarquee(ctx); err == nil && state.Text == text {
+		offset = state.Offset
+	}
+
+	// A failed save only means the marquee doesn't move on the next invocation
+	_ = marqueeRepo.SaveMarquee(ctx, &entity.MarqueeState{Text: text, Offset: offset + 1})
+
+	return offset
+}
+
+// handleStatusClick runs the playback action bound to a status bar button.
+func handleStatusClick(button string) error {
+	ctx := commandContext()
+
+	switch button {
+	case "left", "1":
+		return togglePlayback(ctx)
+	case "middle", "2":
+		return playerUseCase.Previous(ctx)
+	case "right", "3":
+		return playerUseCase.Next(ctx)
+	case "up", "4", "down", "5":
+		state, err := playerUseCase.GetPlaybackState(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get playback state: %w", err)
+		}
+		delta := clickVolumeStep
+		if button == "down" || button == "5" {
+			delta = -clickVolumeStep
+		}
+		return playerUseCase.SetVolume(ctx, max(0, min(100, state.Device.VolumePercent+delta)))
+	default:
+		return fmt.Errorf("unknown button %q (expected left, middle, right, up or down)", button)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/muhadif/sprt/interfaces/tui"
+	"github.com/spf13/cobra"
+)
+
+// kioskMode makes the player and lyric screens read-only, set with --kiosk.
+var kioskMode bool
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Open the interactive player",
+	Long: `Open an interactive player that shows the currently playing track and lets you control playback.
+
+Keybindings:
+  space    play/pause
+  n / p    next / previous track
+  ← / →    seek backward / forward 10 seconds
+  + / -    volume up / down
+  s        toggle shuffle
+  r        cycle repeat mode
+  l        open lyrics
+  ctrl+p   find and run an action by name
+  ?        show the keybindings
+  q        quit
+
+The keys can be changed in the keybindings section of ~/.sprt/config.json.
+
+With --kiosk the player only shows what's playing, e.g. on a public display:
+the playback keys are disabled, the key hints are hidden and only Ctrl+C quits.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tui.RunPlayerUI(commandContext(), appConfig, playerUseCase, kioskMode)
+	},
+}
@@ -2,9 +2,15 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/muhadif/sprt/cmd/sprt/cmd"
+	"github.com/muhadif/sprt/config"
 	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/httpclient"
 	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/muhadif/sprt/infrastructure/spotify"
 )
 
 // Version information set by GoReleaser at build time
@@ -15,17 +21,38 @@ var (
 )
 
 func main() {
+	// Initialize commands with version information; the use cases are built once a command needs them
+	cmd.InitializeCommands(newUseCases, version, commit, date)
+
+	// Execute the root command
+	cmd.Execute()
+}
+
+// newUseCases builds the use cases with the config. The startup checks warn about
+// problems in the config unless skipChecks is set.
+func newUseCases(cfg *config.Config, skipChecks bool) cmd.UseCases {
 	// Initialize repositories
 	authRepo := jsonfile.NewAuthRepository()
 
-	// Initialize use cases
-	authUseCase := usecase.NewAuthUseCase(authRepo)
-	playerUseCase := usecase.NewPlayerUseCase(authUseCase)
-	lyricUseCase := usecase.NewLyricUseCase()
+	// Request the scopes of the features enabled in the config
+	features, err := usecase.EnabledFeatures(cfg.Auth.Features)
+	if err != nil {
+		if !skipChecks && cfg.StartupChecks {
+			fmt.Fprintf(os.Stderr, "Warning: invalid auth.features in config, requesting all scopes: %v\n", err)
+		}
+		features = usecase.Features
+	}
 
-	// Initialize commands with version information
-	cmd.InitializeCommands(authUseCase, playerUseCase, lyricUseCase, version, commit, date)
+	// All use cases share one Spotify Web API client
+	spotifyClient := spotify.NewClient(authRepo, cfg.API)
 
-	// Execute the root command
-	cmd.Execute()
+	// Initialize use cases
+	return cmd.UseCases{
+		Auth:     usecase.NewAuthUseCase(authRepo, spotifyClient, features),
+		Player:   usecase.NewPlayerUseCase(authRepo, spotifyClient),
+		Lyric:    usecase.NewLyricUseCase(httpclient.New(cfg.API), jsonfile.NewLyricMatchRepository(""), cfg.Lyrics.Dir, cfg.Lyrics.EstimateTiming),
+		Search:   usecase.NewSearchUseCase(spotifyClient),
+		Playlist: usecase.NewPlaylistUseCase(spotifyClient),
+		Library:  usecase.NewLibraryUseCase(spotifyClient),
+	}
 }
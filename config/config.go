@@ -0,0 +1,403 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds the application configuration
+type Config struct {
+	Notifications NotificationConfig `json:"notifications"`
+	IdleReminder  IdleReminderConfig `json:"idleReminder"`
+	Output        OutputConfig       `json:"output"`
+	Sinks         SinkConfig         `json:"sinks"`
+	Auth          AuthConfig         `json:"auth"`
+	Goals         []GoalConfig       `json:"goals"`
+	API           APIConfig          `json:"api"`
+	Lyrics        LyricsConfig       `json:"lyrics"`
+	// Profiles adjust behavior by time of day while "sprt daemon" runs
+	Profiles []ProfileConfig `json:"profiles"`
+	Title    TitleConfig     `json:"title"`
+	// Hotkeys are global keyboard shortcuts registered while "sprt daemon" runs
+	Hotkeys []HotkeyConfig `json:"hotkeys"`
+	// Hooks are shell commands run on playback events while "sprt daemon" runs
+	Hooks []HookConfig `json:"hooks"`
+	// ListenBrainz submits the tracks played while "sprt daemon" runs
+	ListenBrainz ListenBrainzConfig `json:"listenbrainz"`
+	// Clean is the clean-content mode, changed with "sprt clean"
+	Clean CleanConfig `json:"clean"`
+	// Keybindings maps the actions of the TUIs to their keys, e.g. "quit": ["q"]; actions left
+	// out keep their default keys, listed with "?" in the TUIs
+	Keybindings map[string][]string `json:"keybindings"`
+	// Mouse lets the TUIs be clicked and scrolled; turn off for terminals that capture the mouse,
+	// e.g. to select text
+	Mouse bool `json:"mouse"`
+	// StartupChecks validates the config before commands that talk to Spotify run and warns about
+	// problems; turn off, or pass --skip-checks, to keep prompt and status bar commands quiet
+	StartupChecks bool `json:"startupChecks"`
+}
+
+// NotificationConfig holds the configuration for desktop notifications
+type NotificationConfig struct {
+	Enabled     bool `json:"enabled"`     // Master switch for desktop notifications
+	TrackChange bool `json:"trackChange"` // Show a notification when the track changes
+	// DoNotDisturb controls how the OS Do-Not-Disturb/Focus mode is honored:
+	// "auto" detects it, "on" always treats it as active, "off" ignores it
+	DoNotDisturb string `json:"doNotDisturb"`
+	// Actions adds Like and Skip buttons to the track-change notifications, which are then shown
+	// by "sprt daemon" instead of the lyric displays
+	Actions bool `json:"actions"`
+}
+
+// IdleReminderConfig holds the configuration for the paused-track reminder
+type IdleReminderConfig struct {
+	Enabled bool   `json:"enabled"`
+	Minutes int    `json:"minutes"` // How long a track must stay paused
+	Action  string `json:"action"`  // "notify", "clear" or "both"
+}
+
+// OutputConfig holds the configuration for the lyric file and status outputs
+type OutputConfig struct {
+	// StaleAfterSeconds is the grace period after playback stops before the
+	// outputs switch to the stopped state; 0 disables the expiry
+	StaleAfterSeconds int    `json:"staleAfterSeconds"`
+	StoppedText       string `json:"stoppedText"` // Written to the lyric file when stopped
+	ProgressBar       bool   `json:"progressBar"` // Append a ▰▱ progress bar to status outputs
+	ProgressBarWidth  int    `json:"progressBarWidth"`
+	MaxWidth          int    `json:"maxWidth"` // Character budget for artist and title; 0 is unlimited
+	Marquee           bool   `json:"marquee"`  // Scroll text longer than maxWidth instead of truncating
+	// HistorySize is the number of previous lyric lines kept for external tools
+	HistorySize int `json:"historySize"`
+	// SongSeparator emits a "── Title – Artist ──" line into streaming outputs on track changes
+	SongSeparator bool `json:"songSeparator"`
+	// Transforms are applied in order to lyric lines before they are displayed or written
+	Transforms []TransformConfig `json:"transforms"`
+}
+
+// TransformConfig holds one step of the lyric text transform chain.
+type TransformConfig struct {
+	// Type is "uppercase", "lowercase", "strip-punctuation", "strip-brackets" or "regex"
+	Type    string `json:"type"`
+	Pattern string `json:"pattern,omitempty"` // Regular expression for the regex type
+	Replace string `json:"replace,omitempty"` // Replacement for the regex type; $1 refers to groups
+	// CurrentOnly limits the transform to the current line in the lyric display
+	CurrentOnly bool `json:"currentOnly,omitempty"`
+}
+
+// APIConfig holds the configuration for requests to the Spotify Web API
+type APIConfig struct {
+	// RateLimitRetries is how often a request rejected with 429 Too Many Requests is retried
+	// after waiting for the time Spotify asks for; 0 disables the retries
+	RateLimitRetries int `json:"rateLimitRetries"`
+	// ConnectTimeoutSeconds limits connecting to a server, including the TLS handshake
+	ConnectTimeoutSeconds int `json:"connectTimeoutSeconds"`
+	// RequestTimeoutSeconds limits a whole request, including retries and reading the response
+	RequestTimeoutSeconds int `json:"requestTimeoutSeconds"`
+	// NetworkRetries is how often a request failing with a network error or a 502, 503 or 504
+	// status is retried, with exponential backoff; 0 disables the retries
+	NetworkRetries int `json:"networkRetries"`
+	// CacheSeconds is how long responses other than the playback state are reused without asking
+	// Spotify again; after that they are revalidated with their ETag. 0 only revalidates
+	CacheSeconds int `json:"cacheSeconds"`
+	// ArtCacheMB limits the size of the album art kept in ~/.sprt/cache/art, evicting the least
+	// recently used images beyond it; 0 turns the cache off
+	ArtCacheMB int `json:"artCacheMB"`
+	// Proxy is the URL of the proxy for all requests, e.g. "http://proxy:3128"; empty uses
+	// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	Proxy string `json:"proxy,omitempty"`
+	// CAFile is a PEM bundle of certificate authorities trusted in addition to the system's
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// LyricsConfig holds the configuration for looking up lyrics
+type LyricsConfig struct {
+	// Dir holds curated LRC files named "Artist - Title.lrc", preferred to lrclib.net;
+	// empty only uses lrclib.net
+	Dir string `json:"dir"`
+	// EstimateTiming spreads the lines of unsynced lyrics evenly over the track so they
+	// advance; otherwise they are shown whole to scroll through
+	EstimateTiming bool `json:"estimateTiming"`
+	// Translation shows a translation beneath each line in "sprt lyric show"
+	Translation TranslationConfig `json:"translation"`
+}
+
+// TranslationConfig holds the configuration for translating lyrics
+type TranslationConfig struct {
+	// Provider is "libretranslate" or "deepl"; empty turns translation off
+	Provider string `json:"provider"`
+	// URL is the server of LibreTranslate, or the DeepL Pro API instead of the free one
+	URL string `json:"url,omitempty"`
+	// APIKey authenticates with the provider; LibreTranslate servers may not need one
+	APIKey string `json:"apiKey,omitempty"`
+	// Target is the language translated into, e.g. "en" or "de"
+	Target string `json:"target"`
+}
+
+// TitleConfig holds the configuration for showing the current track in the window title
+// while the lyric displays or "sprt daemon" run
+type TitleConfig struct {
+	// Mode is "off", "auto", "terminal" for the terminal window title or "tmux" for the pane title
+	Mode string `json:"mode"`
+	// Format is the title with {title}, {artist} and {album} replaced by the current track
+	Format string `json:"format"`
+}
+
+// HotkeyConfig holds one global keyboard shortcut registered by "sprt daemon"
+type HotkeyConfig struct {
+	Keys   string `json:"keys"`   // Accelerator, e.g. "<Super>F9"
+	Action string `json:"action"` // "play-pause", "next", "previous" or "like"
+}
+
+// HookConfig holds one shell command run by "sprt daemon" on a playback event, with the track
+// and the lyric line in SPRT_* environment variables
+type HookConfig struct {
+	Event   string `json:"event"`   // "track", "play", "pause", "stop" or "lyric"
+	Command string `json:"command"` // Run with sh -c, or cmd /C on Windows
+}
+
+// ListenBrainzConfig holds the configuration for submitting listens to ListenBrainz
+type ListenBrainzConfig struct {
+	// Token is the user token from https://listenbrainz.org/settings/; empty turns submitting off
+	Token string `json:"token"`
+	// URL is the API of another server with the ListenBrainz API; empty is listenbrainz.org
+	URL string `json:"url,omitempty"`
+}
+
+// ProfileConfig holds one time-of-day profile applied by "sprt daemon"
+type ProfileConfig struct {
+	Name string   `json:"name"`
+	From string   `json:"from"`           // Start time, e.g. "22:00"
+	To   string   `json:"to"`             // End time, e.g. "07:00"; times before From end the next day
+	Days []string `json:"days,omitempty"` // Weekdays the window starts on, e.g. ["mon", "tue"]; empty is every day
+	// Volume is set on the active device when the profile starts
+	Volume *int `json:"volume,omitempty"`
+	// Theme is the name of a theme from ui_config.json used by the lyric displays
+	Theme string `json:"theme,omitempty"`
+	// Notifications turns desktop notifications on or off during the profile
+	Notifications *bool `json:"notifications,omitempty"`
+}
+
+// GoalConfig holds one listening goal shown by "sprt stats"
+type GoalConfig struct {
+	Type   string  `json:"type"`   // "hours", "tracks", "artists" or "new-artists"
+	Target float64 `json:"target"` // Amount to reach in each period
+	Period string  `json:"period"` // "day", "week" or "month"
+	// Notify shows a desktop notification when the goal is reached in a period
+	Notify bool `json:"notify"`
+}
+
+// CleanConfig holds the configuration for the clean-content mode, which hides explicit content
+type CleanConfig struct {
+	Enabled bool `json:"enabled"` // Hide explicit tracks from search and browse results
+	// MaskLyrics masks explicit words in the lyric displays while the mode is enabled
+	MaskLyrics bool `json:"maskLyrics"`
+	// AutoSkip skips explicit tracks while "sprt daemon" runs and the mode is enabled
+	AutoSkip bool `json:"autoSkip"`
+	// Lock holds the passphrase needed to relax these settings; nil leaves them unlocked
+	Lock *CleanLock `json:"lock,omitempty"`
+}
+
+// CleanLock holds the salted hash of the clean-content passphrase
+type CleanLock struct {
+	Salt []byte `json:"salt"`
+	Hash []byte `json:"hash"`
+}
+
+// AuthConfig holds the configuration for Spotify authorization
+type AuthConfig struct {
+	// Features limits the requested scopes to these features, see "sprt auth scopes";
+	// empty requests the scopes of every feature
+	Features []string `json:"features"`
+}
+
+// SinkConfig holds the configuration for the destinations of the current lyric line.
+// Any number of sinks can be enabled at the same time; empty paths disable a sink.
+type SinkConfig struct {
+	Terminal     bool     `json:"terminal"`     // Print lines to the terminal when no TUI is shown
+	Stdout       bool     `json:"stdout"`       // Print one line per update to stdout instead of showing the pipe display
+	NDJSON       bool     `json:"ndjson"`       // Print one JSON object per update to stdout instead of showing the pipe display
+	File         string   `json:"file"`         // File replaced with the current line
+	Files        []string `json:"files"`        // More files replaced with the current line
+	NowPlaying   string   `json:"nowPlaying"`   // JSON file replaced with the playing track on every change
+	FIFO         string   `json:"fifo"`         // Named pipe receiving one line per update
+	Socket       string   `json:"socket"`       // UNIX socket broadcasting one line per update
+	HTTPAddr     string   `json:"httpAddr"`     // Address of the HTML overlay server, e.g. "127.0.0.1:8975"
+	Notification bool     `json:"notification"` // Show every line as a desktop notification
+}
+
+// DefaultConfig returns the default application configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Notifications: NotificationConfig{
+			Enabled:      false,
+			TrackChange:  true,
+			DoNotDisturb: "auto",
+		},
+		IdleReminder: IdleReminderConfig{
+			Enabled: false,
+			Minutes: 10,
+			Action:  "notify",
+		},
+		Output: OutputConfig{
+			StaleAfterSeconds: 30,
+			StoppedText:       "",
+			ProgressBar:       false,
+			ProgressBarWidth:  10,
+			MaxWidth:          0,
+			Marquee:           false,
+			HistorySize:       5,
+			SongSeparator:     true,
+		},
+		Sinks: SinkConfig{
+			Terminal: true,
+			File:     "/tmp/current-lyric.txt",
+		},
+		API: APIConfig{
+			RateLimitRetries:      3,
+			ConnectTimeoutSeconds: 10,
+			RequestTimeoutSeconds: 30,
+			NetworkRetries:        2,
+			CacheSeconds:          30,
+			ArtCacheMB:            50,
+		},
+		Lyrics: LyricsConfig{
+			Dir: "~/.lyrics",
+			Translation: TranslationConfig{
+				Target: "en",
+			},
+		},
+		Title: TitleConfig{
+			Mode:   "off",
+			Format: "♪ {title} – {artist}",
+		},
+		Clean: CleanConfig{
+			Enabled:    false,
+			MaskLyrics: true,
+			AutoSkip:   false,
+		},
+		Keybindings:   DefaultKeybindings(),
+		Mouse:         true,
+		StartupChecks: true,
+	}
+}
+
+// DefaultKeybindings returns the default keys of the actions of the TUIs. "space" names the
+// space bar; Ctrl+C quits whatever quit is bound to.
+func DefaultKeybindings() map[string][]string {
+	return map[string][]string{
+		"quit":           {"q"},
+		"back":           {"esc"},
+		"help":           {"?"},
+		"palette":        {"ctrl+p"},
+		"up":             {"up", "k"},
+		"down":           {"down", "j"},
+		"page-up":        {"pgup", "ctrl+u"},
+		"page-down":      {"pgdown", "ctrl+d"},
+		"top":            {"home", "g"},
+		"bottom":         {"end", "G"},
+		"select":         {"enter"},
+		"play-pause":     {"space"},
+		"next-track":     {"n"},
+		"previous-track": {"p"},
+		"seek-backward":  {"left"},
+		"seek-forward":   {"right"},
+		"volume-up":      {"+", "="},
+		"volume-down":    {"-", "_"},
+		"shuffle":        {"s"},
+		"repeat":         {"r"},
+		"lyrics":         {"l"},
+		"search":         {"/"},
+		"search-next":    {"n"},
+		"search-prev":    {"N"},
+		"snippet":        {"s"},
+		"translation":    {"t"},
+		"follow":         {"f"},
+		"offset-up":      {"]"},
+		"offset-down":    {"["},
+		"reload":         {"r"},
+		"queue":          {"a"},
+		"unlike":         {"u"},
+		"decrease":       {"left", "h"},
+		"increase":       {"right", "l"},
+		"save":           {"ctrl+s"},
+	}
+}
+
+// ConfigDir returns the directory where sprt stores its configuration
+func ConfigDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".sprt"), nil
+}
+
+// LoadConfig loads the application configuration from the config file
+func LoadConfig() (*Config, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return DefaultConfig(), err
+	}
+	configFile := filepath.Join(configDir, "config.json")
+
+	// Check if the config file exists
+	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		// Create the default config
+		config := DefaultConfig()
+
+		// Save the default config
+		if err := SaveConfig(config); err != nil {
+			return config, fmt.Errorf("failed to save default config: %w", err)
+		}
+
+		return config, nil
+	}
+
+	// Read the config file
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return DefaultConfig(), fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Parse the config on top of the defaults so new options get sensible values
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return DefaultConfig(), fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return config, nil
+}
+
+// SaveConfig saves the application configuration to the config file
+func SaveConfig(config *Config) error {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return err
+	}
+	configFile := filepath.Join(configDir, "config.json")
+
+	// Create the config directory if it doesn't exist
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// Marshal the config to JSON
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	// Write the config file; it holds the ListenBrainz token and the translation API key, so only
+	// the user may read it, also when it was created readable by others
+	if err := os.WriteFile(configFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(configFile, 0600); err != nil {
+		return fmt.Errorf("failed to restrict config file permissions: %w", err)
+	}
+
+	return nil
+}
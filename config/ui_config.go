@@ -10,6 +10,14 @@ import (
 // UIConfig holds the configuration for the UI
 type UIConfig struct {
 	Lyric LyricConfig `json:"lyric"`
+	// Themes are named sets of line styles, selected by time-of-day profiles
+	Themes map[string]ThemeConfig `json:"themes"`
+}
+
+// ThemeConfig holds the line styles of a theme
+type ThemeConfig struct {
+	CurrentLineStyle StyleConfig `json:"currentLineStyle"`
+	OtherLineStyle   StyleConfig `json:"otherLineStyle"`
 }
 
 // LyricConfig holds the configuration for the lyric display
@@ -19,6 +27,56 @@ type LyricConfig struct {
 	Width            int             `json:"width"`
 	Height           int             `json:"height"`
 	Animation        AnimationConfig `json:"animation"`
+	// InterludeSeconds is the shortest instrumental gap shown with an interlude indicator; 0 disables it
+	InterludeSeconds int         `json:"interludeSeconds"`
+	Duet             DuetConfig  `json:"duet"`
+	Pulse            PulseConfig `json:"pulse"`
+	// BigText renders the current line larger: "off", "wide" (double-width characters)
+	// or "block" (three-row box-drawing letters)
+	BigText string `json:"bigText"`
+	// Layout is "full" for a scrolling page of lyrics or "focused" for just the
+	// previous, current and next lines centered with wide spacing
+	Layout string `json:"layout"`
+	// Karaoke highlights the words of the current line as they are sung, for lyrics with word
+	// timestamps (enhanced LRC); it is skipped while BigText is on
+	Karaoke bool `json:"karaoke"`
+	// LineSpacing is the number of blank lines between lyric lines
+	LineSpacing int `json:"lineSpacing"`
+	// PaddingTop and PaddingBottom are blank lines above and below the lyrics
+	PaddingTop    int `json:"paddingTop"`
+	PaddingBottom int `json:"paddingBottom"`
+	// MarginX is the number of columns kept free on the left and right of the lyrics
+	MarginX int `json:"marginX"`
+	// Bidi is "terminal" to leave right-to-left text to terminals with bidi support,
+	// or "reorder" to reorder it for terminals that print characters as they come
+	Bidi string    `json:"bidi"`
+	Cue  CueConfig `json:"cue"`
+}
+
+// CueConfig holds the configuration for the sound cue on lyric line changes
+type CueConfig struct {
+	// Mode is "off", "line" for every line or "section" for the first line after a break
+	Mode string `json:"mode"`
+	// Sound is "bell" for the terminal bell or "system" to play File
+	Sound string `json:"sound"`
+	// File is the sound file played by the system sound; empty uses the platform default
+	File string `json:"file"`
+}
+
+// PulseConfig holds the configuration for the background pulse of the current line
+type PulseConfig struct {
+	Enabled    bool    `json:"enabled"`
+	Color      string  `json:"color"`      // Background color at the peak of the pulse
+	Intensity  float64 `json:"intensity"`  // Peak strength from 0 (invisible) to 1 (full color)
+	DurationMs int     `json:"durationMs"` // How long a pulse takes to fade out
+	Beats      bool    `json:"beats"`      // Also pulse on the beats from Spotify's audio analysis
+}
+
+// DuetConfig holds the configuration for lyrics with duet parts
+type DuetConfig struct {
+	// PartColors maps a part ("M", "F", "D", "V1", "V2") to the color of its lines
+	PartColors map[string]string `json:"partColors"`
+	Columns    bool              `json:"columns"` // Align the first voice left and the second right
 }
 
 // AnimationConfig holds the configuration for animations
@@ -66,10 +124,63 @@ func DefaultUIConfig() *UIConfig {
 				FadeSteps:     5,
 				SlideDistance: 3,
 			},
+			InterludeSeconds: 8,
+			Duet: DuetConfig{
+				PartColors: map[string]string{
+					"M":  "#5FAFFF", // Blue
+					"F":  "#FF87D7", // Pink
+					"D":  "#D7AF5F", // Gold
+					"V1": "#5FAFFF",
+					"V2": "#FF87D7",
+				},
+				Columns: false,
+			},
+			Pulse: PulseConfig{
+				Enabled:    false,
+				Color:      "#5F5F87",
+				Intensity:  0.5,
+				DurationMs: 250,
+				Beats:      false,
+			},
+			BigText: "off",
+			Layout:  "full",
+			// No extra spacing, matching the compact default look
+			LineSpacing:   0,
+			PaddingTop:    0,
+			PaddingBottom: 0,
+			MarginX:       0,
+			Bidi:          "terminal",
+			Cue: CueConfig{
+				Mode:  "off",
+				Sound: "bell",
+				File:  "",
+			},
+		},
+		Themes: map[string]ThemeConfig{
+			"dark": {
+				CurrentLineStyle: StyleConfig{ForegroundColor: "#00FF00", Bold: true},
+				OtherLineStyle:   StyleConfig{ForegroundColor: "#FFFFFF"},
+			},
+			"light": {
+				CurrentLineStyle: StyleConfig{ForegroundColor: "#007A33", Bold: true},
+				OtherLineStyle:   StyleConfig{ForegroundColor: "#303030"},
+			},
 		},
 	}
 }
 
+// ApplyTheme replaces the lyric line styles with those of the named theme.
+func (c *UIConfig) ApplyTheme(name string) error {
+	theme, ok := c.Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+
+	c.Lyric.CurrentLineStyle = theme.CurrentLineStyle
+	c.Lyric.OtherLineStyle = theme.OtherLineStyle
+	return nil
+}
+
 // LoadUIConfig loads the UI configuration from the config file
 func LoadUIConfig() (*UIConfig, error) {
 	// Get the home directory
@@ -106,13 +217,13 @@ func LoadUIConfig() (*UIConfig, error) {
 		return DefaultUIConfig(), fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Parse the config
-	var config UIConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	// Parse the config on top of the defaults so new options get sensible values
+	config := DefaultUIConfig()
+	if err := json.Unmarshal(data, config); err != nil {
 		return DefaultUIConfig(), fmt.Errorf("failed to parse config file: %w", err)
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // SaveUIConfig saves the UI configuration to the config file
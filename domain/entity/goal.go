@@ -0,0 +1,8 @@
+package entity
+
+// GoalMilestones records which listening goal milestones were announced.
+type GoalMilestones struct {
+	// Announced maps a goal, e.g. "5 hours per week", to the start of the last period
+	// its milestone was announced in
+	Announced map[string]string `json:"announced"`
+}
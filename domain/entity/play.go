@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Play represents a track play recorded in the local listening history.
+type Play struct {
+	PlayedAt   time.Time `json:"played_at"`
+	TrackID    string    `json:"track_id"`
+	TrackURI   string    `json:"track_uri"`
+	Title      string    `json:"title"`
+	Artist     string    `json:"artist"`
+	Album      string    `json:"album"`
+	DurationMs int       `json:"duration_ms"`
+	ContextURI string    `json:"context_uri,omitempty"` // Album or playlist the track was played from
+}
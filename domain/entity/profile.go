@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// ActiveProfile represents the time-of-day profile currently applied by the daemon.
+type ActiveProfile struct {
+	Name  string `json:"name"`
+	Theme string `json:"theme,omitempty"`
+	// Notifications turns desktop notifications on or off; nil leaves the configuration alone
+	Notifications *bool     `json:"notifications,omitempty"`
+	Until         time.Time `json:"until"` // End of the profile's time window
+}
+
+// ActiveAt reports whether the profile still applies at the given time.
+// A profile left behind by a daemon that stopped runs out at the end of its window.
+func (p *ActiveProfile) ActiveAt(now time.Time) bool {
+	return p != nil && now.Before(p.Until)
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Session represents a listening session recorded between "sprt session start" and "sprt session stop".
+type Session struct {
+	Name      string           `json:"name"`
+	StartedAt time.Time        `json:"started_at"`
+	Requests  []SessionRequest `json:"requests,omitempty"`
+}
+
+// SessionRequest represents a track, album or playlist someone asked sprt to play or queue
+// during a session.
+type SessionRequest struct {
+	At   time.Time `json:"at"`
+	URI  string    `json:"uri"`
+	Name string    `json:"name"`
+	By   string    `json:"by"`  // Who asked for it
+	Via  string    `json:"via"` // The command that asked for it, e.g. "queue add"
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Snippet represents a bookmarked lyric line of a track.
+type Snippet struct {
+	SavedAt     time.Time `json:"saved_at"`
+	TrackID     string    `json:"track_id"`
+	TrackURI    string    `json:"track_uri"`
+	Title       string    `json:"title"`
+	Artist      string    `json:"artist"`
+	Album       string    `json:"album"`
+	Text        string    `json:"text"`
+	StartTimeMs int       `json:"start_time_ms"`
+}
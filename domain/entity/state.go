@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// Playback statuses reported in a PlaybackSnapshot.
+const (
+	StatusPlaying = "playing"
+	StatusPaused  = "paused"
+	StatusStopped = "stopped"
+)
+
+// PlaybackSnapshot represents the playback state shared with external consumers.
+type PlaybackSnapshot struct {
+	UpdatedAt  time.Time       `json:"updated_at"`
+	Status     string          `json:"status"`
+	ProgressMs int             `json:"progress_ms"`
+	Track      *SnapshotTrack  `json:"track"`
+	Device     *SnapshotDevice `json:"device"`
+	Lyric      *SnapshotLyric  `json:"lyric"`
+	// History holds the lines displayed before the current one, oldest first
+	History []SnapshotLyric `json:"history"`
+}
+
+// SnapshotTrack represents the track in a PlaybackSnapshot.
+type SnapshotTrack struct {
+	ID         string `json:"id"`
+	URI        string `json:"uri"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+// SnapshotDevice represents the active device in a PlaybackSnapshot.
+type SnapshotDevice struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	VolumePercent int    `json:"volume_percent"`
+}
+
+// SnapshotLyric represents the current lyric line in a PlaybackSnapshot.
+type SnapshotLyric struct {
+	Index       int    `json:"index"`
+	Text        string `json:"text"`
+	StartTimeMs int    `json:"start_time_ms"`
+	EndTimeMs   int    `json:"end_time_ms"`
+}
+
+// MarqueeState represents the scroll position of a marquee across status invocations.
+type MarqueeState struct {
+	Text   string `json:"text"`
+	Offset int    `json:"offset"`
+}
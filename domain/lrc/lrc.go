@@ -0,0 +1,226 @@
+// Package lrc parses and writes synchronized lyrics in the LRC format, e.g. "[01:02.50]text",
+// including the word timestamps of enhanced LRC, e.g. "[01:02.50]<01:02.50>some <01:03.10>text".
+package lrc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Line is a lyric line with the time it starts at.
+type Line struct {
+	StartTimeMs int
+	Text        string
+	// Words are the timed words of an enhanced LRC line, which make up its text; nil when the
+	// line has no word timestamps
+	Words []Word
+}
+
+// Word is a word of an enhanced LRC line with the time it is sung at. Its text includes the
+// space following it.
+type Word struct {
+	StartTimeMs int
+	Text        string
+}
+
+// Parse parses LRC lyrics into their lines, ordered by start time. A line may start with
+// several timestamps, e.g. for a repeated chorus, and is returned once for each of them.
+// Lines without a timestamp and metadata tags like "[ar:Artist]" are skipped, as is a UTF-8
+// byte order mark. Word timestamps like "<01:02.50>" are taken out of the text into the words
+// of the line, shifted along for the repeats of a line.
+func Parse(lyrics string) []Line {
+	lyrics = strings.TrimPrefix(lyrics, byteOrderMark)
+	lines := make([]Line, 0, strings.Count(lyrics, "\n")+1)
+	sorted := true
+	for len(lyrics) > 0 {
+		var raw string
+		raw, lyrics, _ = strings.Cut(lyrics, "\n")
+		raw = strings.TrimSuffix(raw, "\r")
+
+		// Collect the timestamps in front of the text
+		first := len(lines)
+		for strings.HasPrefix(raw, "[") {
+			end := strings.IndexByte(raw, ']')
+			if end == -1 {
+				break
+			}
+			startTimeMs, ok := ParseTimestamp(raw[1:end])
+			if !ok {
+				break
+			}
+			lines = append(lines, Line{StartTimeMs: startTimeMs})
+			raw = raw[end+1:]
+		}
+
+		text, words := parseWords(raw)
+		for i := first; i < len(lines); i++ {
+			lines[i].Text = text
+			if words != nil {
+				shift := lines[i].StartTimeMs - lines[first].StartTimeMs
+				lines[i].Words = make([]Word, len(words))
+				for j, word := range words {
+					lines[i].Words[j] = Word{StartTimeMs: word.StartTimeMs + shift, Text: word.Text}
+				}
+			}
+			if i > 0 && lines[i].StartTimeMs < lines[i-1].StartTimeMs {
+				sorted = false
+			}
+		}
+	}
+
+	if !sorted {
+		sort.SliceStable(lines, func(i, j int) bool {
+			return lines[i].StartTimeMs < lines[j].StartTimeMs
+		})
+	}
+	return lines
+}
+
+// parseWords takes the word timestamps out of the text of a line and returns the text with the
+// timed words, or nil words when the line has no word timestamps. Text in front of the first
+// timestamp is left out of the words.
+func parseWords(raw string) (string, []Word) {
+	if !strings.Contains(raw, "<") {
+		return raw, nil
+	}
+
+	var text strings.Builder
+	var words []Word
+	// add adds text to the line and its last word
+	add := func(s string) {
+		text.WriteString(s)
+		if len(words) > 0 {
+			words[len(words)-1].Text += s
+		}
+	}
+	for len(raw) > 0 {
+		start := strings.IndexByte(raw, '<')
+		end := strings.IndexByte(raw[max(start, 0):], '>')
+		if start == -1 || end == -1 {
+			add(raw)
+			break
+		}
+		end += start
+
+		startTimeMs, ok := ParseTimestamp(raw[start+1 : end])
+		if !ok {
+			// A "<" that doesn't start a timestamp, as in "I <3 you"
+			add(raw[:start+1])
+			raw = raw[start+1:]
+			continue
+		}
+		add(raw[:start])
+		words = append(words, Word{StartTimeMs: startTimeMs})
+		raw = raw[end+1:]
+	}
+
+	// A timestamp closing the line marks when the last word ends, not another word
+	if n := len(words); n > 0 && words[n-1].Text == "" {
+		words = words[:n-1]
+	}
+	if len(words) == 0 {
+		return text.String(), nil
+	}
+	return text.String(), words
+}
+
+// byteOrderMark starts files saved as UTF-8 by some editors.
+const byteOrderMark = "\uFEFF"
+
+// Tags returns the metadata tags of LRC lyrics by their lowercase name, e.g. "ar" for the
+// artist in "[ar:Queen]", "ti" for the title, "al" for the album and "offset" for the
+// milliseconds the lines are due sooner, or later when negative. Values are trimmed.
+func Tags(lyrics string) map[string]string {
+	tags := make(map[string]string)
+	for _, raw := range strings.Split(strings.TrimPrefix(lyrics, byteOrderMark), "\n") {
+		raw = strings.TrimSpace(raw)
+		if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+			continue
+		}
+		name, value, found := strings.Cut(raw[1:len(raw)-1], ":")
+		if !found || name == "" || strings.ContainsAny(name, "0123456789[]") {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// ParseTimestamp parses a timestamp like "01:02.50" (minutes, seconds and hundredths) into
+// milliseconds. The fraction may have one to three digits, or be left out.
+func ParseTimestamp(timestamp string) (ms int, ok bool) {
+	minutesText, rest, found := strings.Cut(timestamp, ":")
+	if !found {
+		return 0, false
+	}
+	secondsText, fraction, hasFraction := strings.Cut(rest, ".")
+
+	minutes, ok := parseDigits(minutesText, 5)
+	if !ok {
+		return 0, false
+	}
+	seconds, ok := parseDigits(secondsText, 2)
+	if !ok {
+		return 0, false
+	}
+
+	ms = minutes*60*1000 + seconds*1000
+	if hasFraction {
+		digits, ok := parseDigits(fraction, 3)
+		if !ok {
+			return 0, false
+		}
+		// Scale the fraction to milliseconds by its number of digits
+		for i := len(fraction); i < 3; i++ {
+			digits *= 10
+		}
+		ms += digits
+	}
+	return ms, true
+}
+
+// parseDigits parses a non-negative number of one to maxDigits decimal digits.
+func parseDigits(s string, maxDigits int) (int, bool) {
+	if len(s) == 0 || len(s) > maxDigits {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, true
+}
+
+// Format writes lines in the LRC format, one timestamped line each, with word timestamps for
+// lines with words. A line containing a line break is cut at it, as LRC lines can't span lines.
+func Format(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		text := line.Text
+		if len(line.Words) > 0 {
+			// Text in front of the words, e.g. a duet part marker, has no timestamp
+			var words, timed strings.Builder
+			for _, word := range line.Words {
+				words.WriteString(word.Text)
+				timed.WriteString("<" + FormatTimestamp(word.StartTimeMs) + ">" + word.Text)
+			}
+			if prefix, ok := strings.CutSuffix(text, words.String()); ok {
+				text = prefix + timed.String()
+			}
+		}
+		text, _, _ = strings.Cut(text, "\n")
+		b.WriteString("[" + FormatTimestamp(line.StartTimeMs) + "]" + text + "\n")
+	}
+	return b.String()
+}
+
+// FormatTimestamp formats milliseconds as a timestamp like "01:02.50", in minutes, seconds and
+// hundredths, the precision most players read. Negative times are written as zero.
+func FormatTimestamp(ms int) string {
+	ms = max(ms, 0)
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
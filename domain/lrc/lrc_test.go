@@ -0,0 +1,194 @@
+package lrc_test
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/muhadif/sprt/domain/lrc"
+)
+
+func TestParse(t *testing.T) {
+	lyrics := "[ar:Queen]\n" +
+		"[00:00.60] Is this the real life?\r\n" +
+		"not a line\n" +
+		"\n" +
+		"[00:07.5]Caught in a landslide\n" +
+		"[00:04.150][00:20.00]Is this just fantasy?\n" +
+		"[01:02]\n"
+
+	want := []lrc.Line{
+		{StartTimeMs: 600, Text: " Is this the real life?"},
+		{StartTimeMs: 4150, Text: "Is this just fantasy?"},
+		{StartTimeMs: 7500, Text: "Caught in a landslide"},
+		{StartTimeMs: 20000, Text: "Is this just fantasy?"},
+		{StartTimeMs: 62000, Text: ""},
+	}
+	got := lrc.Parse(lyrics)
+	if len(got) != len(want) {
+		t.Fatalf("Parse() returned %d lines %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseWords(t *testing.T) {
+	lyrics := "[00:10.00][00:30.00]<00:10.00>I <00:10.50>love <3 <00:11.20>you<00:12.00>\n" +
+		"[00:20.00]M: <00:20.40>Mama\n"
+
+	want := []lrc.Line{
+		{StartTimeMs: 10000, Text: "I love <3 you", Words: []lrc.Word{{10000, "I "}, {10500, "love <3 "}, {11200, "you"}}},
+		{StartTimeMs: 20000, Text: "M: Mama", Words: []lrc.Word{{20400, "Mama"}}},
+		{StartTimeMs: 30000, Text: "I love <3 you", Words: []lrc.Word{{30000, "I "}, {30500, "love <3 "}, {31200, "you"}}},
+	}
+	got := lrc.Parse(lyrics)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse() = %+v, want %+v", got, want)
+	}
+
+	// The words are written again, after the text in front of them
+	if formatted := lrc.Format(got[1:2]); formatted != "[00:20.00]M: <00:20.40>Mama\n" {
+		t.Errorf("Format() = %q, want the word timestamps", formatted)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		timestamp string
+		want      int
+		ok        bool
+	}{
+		{"00:00.00", 0, true},
+		{"01:02.50", 62500, true},
+		{"01:02.5", 62500, true},
+		{"01:02.505", 62505, true},
+		{"01:02", 62000, true},
+		{"123:00.00", 7380000, true},
+		{"ar:Queen", 0, false},
+		{"-1:00.00", 0, false},
+		{"01:02.", 0, false},
+		{"01:02.5050", 0, false},
+		{"0102.50", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := lrc.ParseTimestamp(tt.timestamp)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseTimestamp(%q) = %d, %v, want %d, %v", tt.timestamp, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	lyrics := "\uFEFF[ar: Queen ]\n[TI:Bohemian Rhapsody]\n[offset:-250]\n[00:00.60]Is this the real life?\n[length: 5:54]\r\n"
+
+	want := map[string]string{"ar": "Queen", "ti": "Bohemian Rhapsody", "offset": "-250", "length": "5:54"}
+	got := lrc.Tags(lyrics)
+	if len(got) != len(want) {
+		t.Fatalf("Tags() = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("Tags()[%q] = %q, want %q", name, got[name], value)
+		}
+	}
+
+	// The byte order mark doesn't hide the first line
+	if lines := lrc.Parse("\uFEFF[00:01.00]First"); len(lines) != 1 || lines[0].Text != "First" {
+		t.Errorf("Parse() with a byte order mark = %+v, want the first line", lines)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	lines := []lrc.Line{
+		{StartTimeMs: 600, Text: " Is this the real life?"},
+		{StartTimeMs: 62505, Text: "Is this just fantasy?\nCaught in a landslide"},
+		{StartTimeMs: 7380000, Text: ""},
+	}
+
+	want := "[00:00.60] Is this the real life?\n" +
+		"[01:02.50]Is this just fantasy?\n" +
+		"[123:00.00]\n"
+	if got := lrc.Format(lines); got != want {
+		t.Fatalf("Format() = %q, want %q", got, want)
+	}
+
+	// Times in hundredths survive parsing the written lyrics again
+	parsed := lrc.Parse(want)
+	if len(parsed) != len(lines) || !reflect.DeepEqual(parsed[0], lines[0]) || parsed[1].StartTimeMs != 62500 {
+		t.Errorf("Parse(Format()) = %+v, want the lines again", parsed)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("[00:00.60] Is this the real life?\n[00:04.15] Is this just fantasy?")
+	f.Add("[ar:Queen]\n[00:04.150][00:20.00]Chorus\r\n[01:02]")
+	f.Add("[[]]\n[:.]\n[99999:99.999]")
+	f.Add("[00:01.00]<00:01.00>Word <00:01.50>by <3 <00:02.00>word<00:03.00>")
+
+	f.Fuzz(func(t *testing.T, lyrics string) {
+		lines := lrc.Parse(lyrics)
+		if !sort.SliceIsSorted(lines, func(i, j int) bool { return lines[i].StartTimeMs < lines[j].StartTimeMs }) {
+			t.Errorf("Parse(%q) lines are not ordered by start time: %+v", lyrics, lines)
+		}
+		for _, line := range lines {
+			if line.StartTimeMs < 0 {
+				t.Errorf("Parse(%q) returned a negative start time: %+v", lyrics, line)
+			}
+			if strings.Contains(line.Text, "\n") {
+				t.Errorf("Parse(%q) returned a line spanning lines: %q", lyrics, line.Text)
+			}
+			var words strings.Builder
+			for _, word := range line.Words {
+				words.WriteString(word.Text)
+			}
+			if !strings.HasSuffix(line.Text, words.String()) {
+				t.Errorf("Parse(%q) returned words %+v that don't end the text %q", lyrics, line.Words, line.Text)
+			}
+		}
+	})
+}
+
+func FuzzParseTimestamp(f *testing.F) {
+	f.Add("01:02.50")
+	f.Add("1:2.5")
+	f.Add("ar:Queen")
+
+	f.Fuzz(func(t *testing.T, timestamp string) {
+		ms, ok := lrc.ParseTimestamp(timestamp)
+		if !ok {
+			return
+		}
+		if ms < 0 {
+			t.Errorf("ParseTimestamp(%q) = %d, want a non-negative time", timestamp, ms)
+		}
+		// Valid timestamps survive formatting them again
+		again, ok := lrc.ParseTimestamp(fmt.Sprintf("%d:%02d.%03d", ms/60000, ms/1000%60, ms%1000))
+		if !ok || again != ms {
+			t.Errorf("ParseTimestamp(%q) = %d, reparsing gives %d, %v", timestamp, ms, again, ok)
+		}
+	})
+}
+
+// longLyrics returns LRC lyrics with n lines, one per second.
+func longLyrics(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "[%02d:%02d.%02d] Line number %d of a very long song\n", i/60, i%60, i%100, i)
+	}
+	return sb.String()
+}
+
+func BenchmarkParse(b *testing.B) {
+	lyrics := longLyrics(10000)
+	b.SetBytes(int64(len(lyrics)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lrc.Parse(lyrics)
+	}
+}
@@ -18,9 +18,24 @@ type AuthRepository interface {
 	// GetAuthCode retrieves the stored authorization code.
 	GetAuthCode(ctx context.Context) (string, error)
 
-	// StoreToken saves the access and refresh tokens.
+	// StoreToken saves the access and refresh tokens. Callers hold LockToken, so tokens
+	// another process stored meanwhile aren't overwritten.
 	StoreToken(ctx context.Context, auth *entity.SpotifyAuth) error
 
 	// GetToken retrieves the stored authentication data.
 	GetToken(ctx context.Context) (*entity.SpotifyAuth, error)
+
+	// LockToken keeps other sprt processes from changing the stored tokens until the returned
+	// function is called, and reloads them, so tokens another process stored are picked up.
+	LockToken(ctx context.Context) (func(), error)
+
+	// DeleteToken securely erases the stored tokens, keeping the client credentials.
+	DeleteToken(ctx context.Context) error
+
+	// DeleteAll securely erases the stored tokens and client credentials.
+	DeleteAll(ctx context.Context) error
+
+	// SetEncryption re-stores the credentials encrypted with a key from the given source,
+	// "passphrase" or "machine", or in plaintext for "none".
+	SetEncryption(ctx context.Context, source string) error
 }
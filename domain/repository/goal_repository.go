@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// GoalRepository defines the interface for persisting announced goal milestones.
+type GoalRepository interface {
+	// SaveMilestones stores the announced milestones.
+	SaveMilestones(ctx context.Context, milestones *entity.GoalMilestones) error
+
+	// LoadMilestones retrieves the announced milestones; none were announced when nothing is stored.
+	LoadMilestones(ctx context.Context) (*entity.GoalMilestones, error)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// ListenSubmitter defines the interface for submitting listens to a listening service like
+// ListenBrainz.
+type ListenSubmitter interface {
+	// Name returns the name of the service for messages, e.g. "ListenBrainz".
+	Name() string
+
+	// NowPlaying reports the track that just started playing.
+	NowPlaying(ctx context.Context, play entity.Play) error
+
+	// SubmitListens submits finished listens, oldest first.
+	SubmitListens(ctx context.Context, plays []entity.Play) error
+}
+
+// ListenQueueRepository defines the interface for the listens waiting to be submitted, kept
+// while the service can't be reached.
+type ListenQueueRepository interface {
+	// QueueListens appends listens to the queue.
+	QueueListens(ctx context.Context, plays []entity.Play) error
+
+	// QueuedListens retrieves the queued listens, oldest first.
+	QueuedListens(ctx context.Context) ([]entity.Play, error)
+
+	// RemoveListens removes the first n queued listens once they are submitted.
+	RemoveListens(ctx context.Context, n int) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "context"
+
+// LyricMatchRepository defines the interface for the lyrics chosen for tracks when lrclib.net
+// has several versions, such as live or remixed ones.
+type LyricMatchRepository interface {
+	// GetLyricMatch retrieves the lrclib.net ID of the lyrics chosen for a track, or 0 when
+	// none were chosen.
+	GetLyricMatch(ctx context.Context, artist, title string) (int, error)
+
+	// SaveLyricMatch remembers the lrclib.net ID of the lyrics chosen for a track.
+	SaveLyricMatch(ctx context.Context, artist, title string, id int) error
+}
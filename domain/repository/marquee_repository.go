@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// MarqueeRepository defines the interface for persisting the marquee scroll position.
+type MarqueeRepository interface {
+	// SaveMarquee stores the marquee scroll position.
+	SaveMarquee(ctx context.Context, state *entity.MarqueeState) error
+
+	// LoadMarquee retrieves the stored marquee scroll position.
+	LoadMarquee(ctx context.Context) (*entity.MarqueeState, error)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import "context"
+
+// Page is one page of a paged Spotify response.
+type Page[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+	Total int    `json:"total"`
+}
+
+// Paginator walks the pages of a paged Spotify response one at a time, following their "next"
+// links, so endpoints don't loop over offset and limit themselves:
+//
+//	pages := repository.NewPaginator[item](client, "/me/playlists?limit=50", "")
+//	for pages.Next(ctx) {
+//		for _, item := range pages.Items() { ... }
+//	}
+//	if err := pages.Err(); err != nil { ... }
+type Paginator[T any] struct {
+	client SpotifyClient
+	key    string
+	next   string
+	// first is a page received along with another object, returned by the first Next
+	first *Page[T]
+	page  *Page[T]
+	err   error
+}
+
+// NewPaginator creates a paginator starting with the page at path. Responses that nest the page
+// in an object, like search results, name its field with key; an empty key reads the page from
+// the top level.
+func NewPaginator[T any](client SpotifyClient, path, key string) *Paginator[T] {
+	return &Paginator[T]{client: client, key: key, next: path}
+}
+
+// ContinuePages creates a paginator starting with a page that came embedded in another
+// response, such as the first tracks of an album, and requesting the pages after it.
+func ContinuePages[T any](client SpotifyClient, first Page[T]) *Paginator[T] {
+	return &Paginator[T]{client: client, first: &first}
+}
+
+// Next moves to the next page, requesting it from Spotify. It returns false after the last
+// page, when ctx is cancelled or when a request fails; Err tells these apart.
+func (p *Paginator[T]) Next(ctx context.Context) bool {
+	if p.err != nil {
+		return false
+	}
+	if p.first != nil {
+		p.page, p.next, p.first = p.first, p.first.Next, nil
+		return true
+	}
+	if p.next == "" {
+		return false
+	}
+	if p.err = ctx.Err(); p.err != nil {
+		return false
+	}
+
+	page, err := getPage[T](ctx, p.client, p.next, p.key)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.page, p.next = page, page.Next
+	return true
+}
+
+// Items returns the items of the current page.
+func (p *Paginator[T]) Items() []T {
+	if p.page == nil {
+		return nil
+	}
+	return p.page.Items
+}
+
+// Total returns the number of items on all pages, as reported by the current page.
+func (p *Paginator[T]) Total() int {
+	if p.page == nil {
+		return 0
+	}
+	return p.page.Total
+}
+
+// Err returns the error that stopped the paginator, or nil after the last page.
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// CollectPages requests path and follows the "next" links of the responses until the last page,
+// returning the items of all pages. key is the field of the page as for NewPaginator. A limit
+// above zero stops once that many items are collected. Cancelling ctx stops between pages.
+func CollectPages[T any](ctx context.Context, client SpotifyClient, path, key string, limit int) ([]T, error) {
+	var items []T
+	pages := NewPaginator[T](client, path, key)
+	for (limit <= 0 || len(items) < limit) && pages.Next(ctx) {
+		items = append(items, pages.Items()...)
+	}
+	if err := pages.Err(); err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// getPage requests a single page, reading it from the field named key when key is set.
+func getPage[T any](ctx context.Context, client SpotifyClient, path, key string) (*Page[T], error) {
+	if key == "" {
+		var page Page[T]
+		if err := client.Get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		return &page, nil
+	}
+
+	var response map[string]*Page[T]
+	if err := client.Get(ctx, path, &response); err != nil {
+		return nil, err
+	}
+	if page := response[key]; page != nil {
+		return page, nil
+	}
+	return &Page[T]{}, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// PlayHistoryRepository defines the interface for the local listening history,
+// which outlives the 50 plays Spotify keeps.
+type PlayHistoryRepository interface {
+	// AddPlays records the plays newer than the latest stored one and returns how many were added.
+	AddPlays(ctx context.Context, plays []entity.Play) (int, error)
+
+	// ListPlays retrieves the plays at or after since, oldest first.
+	ListPlays(ctx context.Context, since time.Time) ([]entity.Play, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// ProfileRepository defines the interface for sharing the active time-of-day profile.
+type ProfileRepository interface {
+	// SaveActiveProfile stores the active profile.
+	SaveActiveProfile(ctx context.Context, profile *entity.ActiveProfile) error
+
+	// LoadActiveProfile retrieves the active profile, or nil when none is stored.
+	LoadActiveProfile(ctx context.Context) (*entity.ActiveProfile, error)
+
+	// ClearActiveProfile removes the stored profile.
+	ClearActiveProfile(ctx context.Context) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// SessionRepository defines the interface for storing the running listening session.
+type SessionRepository interface {
+	// SaveSession stores the running session.
+	SaveSession(ctx context.Context, session *entity.Session) error
+
+	// LoadSession retrieves the running session, or nil when none is stored.
+	LoadSession(ctx context.Context) (*entity.Session, error)
+
+	// ClearSession removes the stored session.
+	ClearSession(ctx context.Context) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// SnippetRepository defines the interface for storing bookmarked lyric lines.
+type SnippetRepository interface {
+	// AddSnippet appends a snippet to the store.
+	AddSnippet(ctx context.Context, snippet *entity.Snippet) error
+
+	// ListSnippets retrieves all stored snippets, oldest first.
+	ListSnippets(ctx context.Context) ([]entity.Snippet, error)
+}
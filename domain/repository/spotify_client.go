@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// ErrNoContent is returned when a response is expected but Spotify answers with 204 No Content,
+// e.g. when nothing is playing.
+var ErrNoContent = errors.New("no content")
+
+// RateLimitError is returned when Spotify keeps rejecting requests with 429 Too Many Requests.
+type RateLimitError struct {
+	// RetryAfter is how long Spotify asked to wait before the next request
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited by Spotify, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// SpotifyClient defines the interface for calling the Spotify Web API with the stored credentials.
+// Paths are relative to the API base URL, e.g. "/me/player"; the "next" URLs of paged responses
+// are accepted as well. Rate-limited requests are retried before a *RateLimitError is returned. Request bodies are encoded and responses decoded as JSON; a nil v
+// discards the response.
+type SpotifyClient interface {
+	// Do sends an authenticated request with the given method.
+	Do(ctx context.Context, method, path string, body, v interface{}) error
+
+	// Get sends an authenticated GET request.
+	Get(ctx context.Context, path string, v interface{}) error
+
+	// Post sends an authenticated POST request.
+	Post(ctx context.Context, path string, body, v interface{}) error
+
+	// Put sends an authenticated PUT request.
+	Put(ctx context.Context, path string, body, v interface{}) error
+
+	// Delete sends an authenticated DELETE request.
+	Delete(ctx context.Context, path string, body, v interface{}) error
+
+	// ExchangeCode exchanges an authorization code for tokens and stores them.
+	ExchangeCode(ctx context.Context, code, redirectURI string) (*entity.SpotifyAuth, error)
+
+	// RefreshToken refreshes the access token using the stored refresh token and stores it.
+	RefreshToken(ctx context.Context) (*entity.SpotifyAuth, error)
+}
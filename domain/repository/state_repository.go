@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// StateRepository defines the interface for publishing the playback state to external consumers.
+type StateRepository interface {
+	// SaveState stores the latest playback snapshot.
+	SaveState(ctx context.Context, snapshot *entity.PlaybackSnapshot) error
+
+	// LoadState retrieves the last stored playback snapshot.
+	LoadState(ctx context.Context) (*entity.PlaybackSnapshot, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "context"
+
+// Translator defines the interface for translating text with a service like LibreTranslate or
+// DeepL.
+type Translator interface {
+	// Translate translates texts into the target language, e.g. "en", detecting the language
+	// they are in. The translations are in the order of the texts.
+	Translate(ctx context.Context, texts []string, target string) ([]string, error)
+}
@@ -3,14 +3,10 @@ package usecase
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	"github.com/muhadif/sprt/domain/entity"
 	"github.com/muhadif/sprt/domain/repository"
@@ -27,25 +23,53 @@ type AuthUseCase interface {
 	// ExchangeCodeForToken exchanges the authorization code for an access token.
 	ExchangeCodeForToken(ctx context.Context) error
 
-	// GetCurrentlyPlaying retrieves the user's currently playing track.
-	GetCurrentlyPlaying(ctx context.Context) (string, error)
+	// GetCurrentlyPlaying retrieves the user's currently playing track, e.g. to test the authorization.
+	GetCurrentlyPlaying(ctx context.Context) (*Track, error)
 
 	// GetToken retrieves the stored authentication data.
 	GetToken(ctx context.Context) (*entity.SpotifyAuth, error)
 
 	// RefreshToken refreshes the access token using the refresh token.
 	RefreshToken(ctx context.Context) (*entity.SpotifyAuth, error)
+
+	// Features returns the enabled features whose scopes are requested.
+	Features() []Feature
+
+	// RequiredScopes returns the scopes needed by the enabled features.
+	RequiredScopes() []string
+
+	// GrantedScopes returns the scopes granted to the stored token.
+	GrantedScopes(ctx context.Context) ([]string, error)
+
+	// Reauthorize returns an authorization URL for the stored client that requests
+	// the required scopes on top of the granted ones.
+	Reauthorize(ctx context.Context) (string, error)
+
+	// Logout erases the stored tokens, and the client credentials unless keepClient is set.
+	Logout(ctx context.Context, keepClient bool) error
+
+	// EncryptCredentials re-stores the credentials encrypted with a key from the given source,
+	// "passphrase" or "machine", or in plaintext for "none".
+	EncryptCredentials(ctx context.Context, source string) error
 }
 
 // authUseCase implements the AuthUseCase interface.
 type authUseCase struct {
 	authRepo repository.AuthRepository
+	client   repository.SpotifyClient
+	features []Feature
 }
 
+// redirectURI is where Spotify sends the user back to after authorization.
+const redirectURI = "http://127.0.0.1:8080/callback"
+
 // NewAuthUseCase creates a new instance of AuthUseCase.
-func NewAuthUseCase(authRepo repository.AuthRepository) AuthUseCase {
+// Authorization requests the union of the scopes needed by the given features.
+func NewAuthUseCase(authRepo repository.AuthRepository, client repository.SpotifyClient, features []Feature) AuthUseCase {
 	return &authUseCase{
 		authRepo: authRepo,
+		client:   client,
+		features: features,
 	}
 }
 
@@ -57,7 +81,7 @@ func (a *authUseCase) InitAuth(ctx context.Context, clientID, clientSecret strin
 	}
 
 	// Generate the authorization URL
-	authURL := generateAuthURL(clientID)
+	authURL := generateAuthURL(clientID, a.RequiredScopes())
 	return authURL, nil
 }
 
@@ -78,158 +102,28 @@ func (a *authUseCase) ExchangeCodeForToken(ctx context.Context) error {
 		return fmt.Errorf("failed to get authorization code: %w", err)
 	}
 
-	// Get client credentials
-	auth, err := a.authRepo.GetToken(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get client credentials: %w", err)
-	}
-
-	// Prepare the request to exchange the code for a token
-	tokenURL := "https://accounts.spotify.com/api/token"
-	data := url.Values{}
-	data.Set("grant_type", "authorization_code")
-	data.Set("code", code)
-	data.Set("redirect_uri", "http://127.0.0.1:8080/callback")
-
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create token request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	// Set basic auth with client ID and secret
-	authHeader := base64.StdEncoding.EncodeToString([]byte(auth.ClientID + ":" + auth.ClientSecret))
-	req.Header.Set("Authorization", "Basic "+authHeader)
-
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
+	if _, err := a.client.ExchangeCode(ctx, code, redirectURI); err != nil {
 		return fmt.Errorf("failed to exchange code for token: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// Read the response
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read token response: %w", err)
-	}
-
-	// Check for error response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Parse the response
-	var tokenResponse struct {
-		AccessToken  string `json:"access_token"`
-		TokenType    string `json:"token_type"`
-		ExpiresIn    int    `json:"expires_in"`
-		RefreshToken string `json:"refresh_token"`
-		Scope        string `json:"scope"`
-	}
-	if err := json.Unmarshal(body, &tokenResponse); err != nil {
-		return fmt.Errorf("failed to parse token response: %w", err)
-	}
-
-	// Update the auth object
-	auth = &entity.SpotifyAuth{
-		ClientID:     auth.ClientID,
-		ClientSecret: auth.ClientSecret,
-		AccessToken:  tokenResponse.AccessToken,
-		RefreshToken: tokenResponse.RefreshToken,
-		ExpiresIn:    tokenResponse.ExpiresIn,
-		TokenType:    tokenResponse.TokenType,
-		Scope:        tokenResponse.Scope,
-		ExpiresAt:    time.Now().Unix() + int64(tokenResponse.ExpiresIn),
-	}
-
-	// Store the token
-	if err := a.authRepo.StoreToken(ctx, auth); err != nil {
-		return fmt.Errorf("failed to store token: %w", err)
-	}
 
 	return nil
 }
 
-// GetCurrentlyPlaying retrieves the user's currently playing track.
-func (a *authUseCase) GetCurrentlyPlaying(ctx context.Context) (string, error) {
-	// Get the token
-	auth, err := a.authRepo.GetToken(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to get token: %w", err)
-	}
-
-	// Check if the token is expired and attempt to refresh it
-	if auth.IsExpired() {
-		// Try to refresh the token
-		auth, err = a.RefreshToken(ctx)
-		if err != nil {
-			return "", fmt.Errorf("failed to refresh token: %w", err)
-		}
-	}
-
-	// Make a request to Spotify's API
-	apiURL := "https://api.spotify.com/v1/me/player/currently-playing"
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create API request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("%s %s", auth.TokenType, auth.AccessToken))
-
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to get currently playing track: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for error response
-	if resp.StatusCode == http.StatusNoContent {
-		return "No track currently playing", nil
-	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Read the response
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read API response: %w", err)
-	}
-
-	// Parse the response
+// GetCurrentlyPlaying retrieves the user's currently playing track, e.g. to test the authorization.
+func (a *authUseCase) GetCurrentlyPlaying(ctx context.Context) (*Track, error) {
 	var trackResponse struct {
-		Item struct {
-			Name  string `json:"name"`
-			Album struct {
-				Name string `json:"name"`
-			} `json:"album"`
-			Artists []struct {
-				Name string `json:"name"`
-			} `json:"artists"`
-		} `json:"item"`
+		Item *spotifyTrack `json:"item"`
 	}
-	if err := json.Unmarshal(body, &trackResponse); err != nil {
-		return "", fmt.Errorf("failed to parse API response: %w", err)
+	err := a.client.Get(ctx, "/me/player/currently-playing", &trackResponse)
+	if errors.Is(err, repository.ErrNoContent) || err == nil && trackResponse.Item == nil {
+		return nil, ErrNothingPlaying
 	}
-
-	// Format the track information
-	artistNames := make([]string, len(trackResponse.Item.Artists))
-	for i, artist := range trackResponse.Item.Artists {
-		artistNames[i] = artist.Name
+	if err != nil {
+		return nil, fmt.Errorf("failed to get currently playing track: %w", err)
 	}
 
-	return fmt.Sprintf("Currently playing: %s by %s from the album %s",
-		trackResponse.Item.Name,
-		strings.Join(artistNames, ", "),
-		trackResponse.Item.Album.Name), nil
+	track := trackResponse.Item.toTrack()
+	return &track, nil
 }
 
 // GetToken retrieves the stored authentication data.
@@ -239,97 +133,62 @@ func (a *authUseCase) GetToken(ctx context.Context) (*entity.SpotifyAuth, error)
 
 // RefreshToken refreshes the access token using the refresh token.
 func (a *authUseCase) RefreshToken(ctx context.Context) (*entity.SpotifyAuth, error) {
-	// Get the current auth data
-	auth, err := a.authRepo.GetToken(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token: %w", err)
-	}
-
-	// Check if we have a refresh token
-	if auth.RefreshToken == "" {
-		return nil, fmt.Errorf("no refresh token available")
-	}
-
-	// Prepare the request to refresh the token
-	tokenURL := "https://accounts.spotify.com/api/token"
-	data := url.Values{}
-	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", auth.RefreshToken)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create token refresh request: %w", err)
-	}
+	return a.client.RefreshToken(ctx)
+}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+// Features returns the enabled features whose scopes are requested.
+func (a *authUseCase) Features() []Feature {
+	return a.features
+}
 
-	// Set basic auth with client ID and secret
-	authHeader := base64.StdEncoding.EncodeToString([]byte(auth.ClientID + ":" + auth.ClientSecret))
-	req.Header.Set("Authorization", "Basic "+authHeader)
+// RequiredScopes returns the scopes needed by the enabled features.
+func (a *authUseCase) RequiredScopes() []string {
+	return RequiredScopes(a.features)
+}
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// GrantedScopes returns the scopes granted to the stored token.
+func (a *authUseCase) GrantedScopes(ctx context.Context) ([]string, error) {
+	auth, err := a.authRepo.GetToken(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to refresh token: %w", err)
+		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
-	defer resp.Body.Close()
+	return strings.Fields(auth.Scope), nil
+}
 
-	// Read the response
-	body, err := ioutil.ReadAll(resp.Body)
+// Reauthorize returns an authorization URL for the stored client that requests
+// the required scopes on top of the granted ones, so no feature loses access.
+func (a *authUseCase) Reauthorize(ctx context.Context) (string, error) {
+	auth, err := a.authRepo.GetToken(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read token response: %w", err)
+		return "", fmt.Errorf("failed to get client credentials: %w", err)
 	}
-
-	// Check for error response
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+	if auth.ClientID == "" {
+		return "", fmt.Errorf("no client credentials stored, run 'sprt auth init' first")
 	}
 
-	// Parse the response
-	var tokenResponse struct {
-		AccessToken  string `json:"access_token"`
-		TokenType    string `json:"token_type"`
-		ExpiresIn    int    `json:"expires_in"`
-		RefreshToken string `json:"refresh_token"`
-		Scope        string `json:"scope"`
-	}
-	if err := json.Unmarshal(body, &tokenResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse token response: %w", err)
-	}
+	granted := strings.Fields(auth.Scope)
+	scopes := append(granted, MissingScopes(granted, a.RequiredScopes())...)
 
-	// Update the auth object
-	newAuth := &entity.SpotifyAuth{
-		ClientID:     auth.ClientID,
-		ClientSecret: auth.ClientSecret,
-		AccessToken:  tokenResponse.AccessToken,
-		TokenType:    tokenResponse.TokenType,
-		ExpiresIn:    tokenResponse.ExpiresIn,
-		Scope:        tokenResponse.Scope,
-		ExpiresAt:    time.Now().Unix() + int64(tokenResponse.ExpiresIn),
-	}
-
-	// Keep the existing refresh token if a new one wasn't provided
-	if tokenResponse.RefreshToken != "" {
-		newAuth.RefreshToken = tokenResponse.RefreshToken
-	} else {
-		newAuth.RefreshToken = auth.RefreshToken
-	}
+	return generateAuthURL(auth.ClientID, scopes), nil
+}
 
-	// Store the updated token
-	if err := a.authRepo.StoreToken(ctx, newAuth); err != nil {
-		return nil, fmt.Errorf("failed to store refreshed token: %w", err)
+// Logout erases the stored tokens, and the client credentials unless keepClient is set.
+func (a *authUseCase) Logout(ctx context.Context, keepClient bool) error {
+	if keepClient {
+		return a.authRepo.DeleteToken(ctx)
 	}
+	return a.authRepo.DeleteAll(ctx)
+}
 
-	return newAuth, nil
+// EncryptCredentials re-stores the credentials encrypted with a key from the given source.
+func (a *authUseCase) EncryptCredentials(ctx context.Context, source string) error {
+	return a.authRepo.SetEncryption(ctx, source)
 }
 
-// generateAuthURL generates the authorization URL for Spotify.
-func generateAuthURL(clientID string) string {
+// generateAuthURL generates the authorization URL for Spotify requesting the given scopes.
+func generateAuthURL(clientID string, scopes []string) string {
 	baseURL := "https://accounts.spotify.com/authorize"
-	redirectURI := "http://127.0.0.1:8080/callback"
-	scope := "user-read-currently-playing"
+	scope := strings.Join(scopes, " ")
 
 	params := url.Values{}
 	params.Add("client_id", clientID)
@@ -0,0 +1,18 @@
+package usecase
+
+// explicitItem is a catalog item that Spotify can mark as explicit.
+type explicitItem interface {
+	IsExplicit() bool
+}
+
+// WithoutExplicit returns the items that aren't marked as explicit, keeping their order.
+// It is used to hide explicit content in the clean-content mode.
+func WithoutExplicit[T explicitItem](items []T) []T {
+	clean := make([]T, 0, len(items))
+	for _, item := range items {
+		if !item.IsExplicit() {
+			clean = append(clean, item)
+		}
+	}
+	return clean
+}
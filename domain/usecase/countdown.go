@@ -0,0 +1,24 @@
+package usecase
+
+import "fmt"
+
+// countdownMinIntroMs is the shortest intro that gets a countdown before the first line.
+const countdownMinIntroMs = 5000
+
+// IntroCountdown returns the countdown shown before the first line of a long intro,
+// e.g. "♪ starting in 3…". It returns an empty string once the first line is due,
+// or when the intro is too short to need one.
+func IntroCountdown(lyrics *Lyrics, progressMs int) string {
+	if lyrics == nil || len(lyrics.Lines) == 0 {
+		return ""
+	}
+
+	firstStartMs := lyrics.Lines[0].StartTimeMs
+	if firstStartMs < countdownMinIntroMs || progressMs >= firstStartMs {
+		return ""
+	}
+
+	// Round up so the countdown reads 1 during the last second
+	remainingSeconds := (firstStartMs - progressMs + 999) / 1000
+	return fmt.Sprintf("♪ starting in %d…", remainingSeconds)
+}
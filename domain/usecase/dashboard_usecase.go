@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Dashboard panels, loaded independently of each other.
+const (
+	PanelPlayback = "playback"
+	PanelQueue    = "queue"
+	PanelDevices  = "devices"
+	PanelLyrics   = "lyrics"
+)
+
+// DashboardUpdate carries the data of one dashboard panel, or the error loading it.
+type DashboardUpdate struct {
+	Panel    string
+	Playback *PlaybackState
+	Queue    []Track
+	Devices  []Device
+	Lyrics   *Lyrics
+	Err      error
+}
+
+// errNoLyricsForEpisode is reported by the lyrics panel while a podcast episode plays.
+var errNoLyricsForEpisode = errors.New("podcast episodes have no lyrics")
+
+// DashboardUseCase defines the interface for loading the data shown by the dashboard.
+type DashboardUseCase interface {
+	// Load fetches the playback state, the queue, the devices and the lyrics of the current track
+	// concurrently. Every panel is sent on the channel as soon as its data arrives; the channel is
+	// closed once all panels are loaded.
+	Load(ctx context.Context) <-chan DashboardUpdate
+}
+
+// dashboardUseCase implements the DashboardUseCase interface.
+type dashboardUseCase struct {
+	playerUseCase PlayerUseCase
+	lyricUseCase  LyricUseCase
+}
+
+// NewDashboardUseCase creates a new instance of DashboardUseCase.
+func NewDashboardUseCase(playerUseCase PlayerUseCase, lyricUseCase LyricUseCase) DashboardUseCase {
+	return &dashboardUseCase{
+		playerUseCase: playerUseCase,
+		lyricUseCase:  lyricUseCase,
+	}
+}
+
+// dashboardPanels is the number of updates sent by Load.
+const dashboardPanels = 4
+
+// Load fetches the panels concurrently and sends each one as soon as it is loaded.
+func (d *dashboardUseCase) Load(ctx context.Context) <-chan DashboardUpdate {
+	// Buffered for every panel, so loading finishes even if nobody reads the updates
+	updates := make(chan DashboardUpdate, dashboardPanels)
+
+	// A failing panel doesn't stop the others, its error is shown in its place
+	var g errgroup.Group
+	g.Go(func() error {
+		state, err := d.playerUseCase.GetPlaybackState(ctx)
+		updates <- DashboardUpdate{Panel: PanelPlayback, Playback: state, Err: err}
+
+		// The lyrics depend on the track, so they are fetched once it is known
+		switch {
+		case err != nil:
+			updates <- DashboardUpdate{Panel: PanelLyrics, Err: err}
+		case state.IsEpisode():
+			updates <- DashboardUpdate{Panel: PanelLyrics, Err: errNoLyricsForEpisode}
+		default:
+			lyrics, err := d.lyricUseCase.GetLyrics(ctx, state.Artist, state.Title, state.Album, state.DurationMs)
+			updates <- DashboardUpdate{Panel: PanelLyrics, Lyrics: lyrics, Err: err}
+		}
+		return nil
+	})
+	g.Go(func() error {
+		queue, err := d.playerUseCase.GetQueue(ctx)
+		updates <- DashboardUpdate{Panel: PanelQueue, Queue: queue, Err: err}
+		return nil
+	})
+	g.Go(func() error {
+		devices, err := d.playerUseCase.GetDevices(ctx)
+		updates <- DashboardUpdate{Panel: PanelDevices, Devices: devices, Err: err}
+		return nil
+	})
+
+	go func() {
+		g.Wait()
+		close(updates)
+	}()
+	return updates
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// Goal types, measured over the plays of a period.
+const (
+	GoalHours      = "hours"       // Hours of listening
+	GoalTracks     = "tracks"      // Number of plays
+	GoalArtists    = "artists"     // Distinct artists
+	GoalNewArtists = "new-artists" // Artists heard for the first time in the local history
+)
+
+// Goal periods.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week" // Starting on Monday
+	PeriodMonth = "month"
+)
+
+// Goal represents a listening goal such as "listen 5 hours per week".
+type Goal struct {
+	Type   string
+	Target float64
+	Period string
+}
+
+// Validate checks the goal type, target and period.
+func (g Goal) Validate() error {
+	switch g.Type {
+	case GoalHours, GoalTracks, GoalArtists, GoalNewArtists:
+	default:
+		return fmt.Errorf("unknown goal type %q (expected hours, tracks, artists or new-artists)", g.Type)
+	}
+	switch g.Period {
+	case PeriodDay, PeriodWeek, PeriodMonth:
+	default:
+		return fmt.Errorf("unknown goal period %q (expected day, week or month)", g.Period)
+	}
+	if g.Target <= 0 {
+		return fmt.Errorf("the target of the %s goal must be positive", g.Type)
+	}
+	return nil
+}
+
+// String describes the goal, e.g. "5 hours per week".
+func (g Goal) String() string {
+	return fmt.Sprintf("%s %s per %s", formatGoalValue(g.Target), g.Type, g.Period)
+}
+
+// GoalProgress represents the progress towards a goal in the current period.
+type GoalProgress struct {
+	Goal
+	Value       float64
+	PeriodStart time.Time
+	// Streak is the number of consecutive periods the goal was met, counting the
+	// current one only once it's met
+	Streak int
+}
+
+// Done reports whether the goal is met in the current period.
+func (p GoalProgress) Done() bool {
+	return p.Value >= p.Target
+}
+
+// PeriodKey identifies the current period, e.g. for remembering that a milestone was announced.
+func (p GoalProgress) PeriodKey() string {
+	return p.PeriodStart.Format("2006-01-02")
+}
+
+// Summary describes the progress, e.g. "3.5/5 hours this week".
+func (p GoalProgress) Summary() string {
+	period := "this " + p.Period
+	if p.Period == PeriodDay {
+		period = "today"
+	}
+	return fmt.Sprintf("%s/%s %s %s", formatGoalValue(p.Value), formatGoalValue(p.Target), p.Type, period)
+}
+
+// maxStreakPeriods bounds how far back streaks are counted.
+const maxStreakPeriods = 366
+
+// GoalsProgress computes the progress of each goal at the given time from the listening history,
+// which must be sorted oldest first.
+func GoalsProgress(plays []entity.Play, goals []Goal, now time.Time) []GoalProgress {
+	firstHeard := firstArtistPlays(plays)
+
+	progress := make([]GoalProgress, len(goals))
+	for i, goal := range goals {
+		start := periodStart(now, goal.Period)
+		progress[i] = GoalProgress{
+			Goal:        goal,
+			Value:       goalValue(goal, plays, firstHeard, start, now),
+			PeriodStart: start,
+		}
+		if progress[i].Done() {
+			progress[i].Streak = 1
+		}
+
+		// Walk back through the completed periods while the goal was met
+		end := start
+		for n := 0; n < maxStreakPeriods; n++ {
+			if len(plays) == 0 || !end.After(plays[0].PlayedAt) {
+				break
+			}
+			previous := periodStart(end.Add(-time.Nanosecond), goal.Period)
+			if goalValue(goal, plays, firstHeard, previous, end) < goal.Target {
+				break
+			}
+			progress[i].Streak++
+			end = previous
+		}
+	}
+
+	return progress
+}
+
+// ListeningStreak returns the number of consecutive days with at least one play,
+// ending today, or yesterday when nothing was played yet today.
+func ListeningStreak(plays []entity.Play, now time.Time) int {
+	days := make(map[string]bool)
+	for _, play := range plays {
+		days[play.PlayedAt.In(now.Location()).Format("2006-01-02")] = true
+	}
+
+	day := periodStart(now, PeriodDay)
+	if !days[day.Format("2006-01-02")] {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for days[day.Format("2006-01-02")] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// goalValue measures the goal over the plays in [start, end).
+func goalValue(goal Goal, plays []entity.Play, firstHeard map[string]time.Time, start, end time.Time) float64 {
+	durationMs := 0
+	count := 0
+	artists := make(map[string]bool)
+	for _, play := range plays {
+		if play.PlayedAt.Before(start) || !play.PlayedAt.Before(end) {
+			continue
+		}
+		durationMs += play.DurationMs
+		count++
+		for _, artist := range splitArtists(play.Artist) {
+			if goal.Type == GoalNewArtists && firstHeard[artist].Before(start) {
+				continue
+			}
+			artists[artist] = true
+		}
+	}
+
+	switch goal.Type {
+	case GoalHours:
+		return float64(durationMs) / float64(time.Hour.Milliseconds())
+	case GoalTracks:
+		return float64(count)
+	default:
+		return float64(len(artists))
+	}
+}
+
+// firstArtistPlays returns when each artist was first heard.
+func firstArtistPlays(plays []entity.Play) map[string]time.Time {
+	first := make(map[string]time.Time)
+	for _, play := range plays {
+		for _, artist := range splitArtists(play.Artist) {
+			if t, ok := first[artist]; !ok || play.PlayedAt.Before(t) {
+				first[artist] = play.PlayedAt
+			}
+		}
+	}
+	return first
+}
+
+// splitArtists splits the comma-separated artists of a play.
+func splitArtists(artist string) []string {
+	var artists []string
+	for _, name := range strings.Split(artist, ", ") {
+		if name = strings.TrimSpace(name); name != "" {
+			artists = append(artists, name)
+		}
+	}
+	return artists
+}
+
+// periodStart returns the start of the period containing t, in t's location.
+func periodStart(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch period {
+	case PeriodWeek:
+		// Weeks start on Monday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case PeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// formatGoalValue formats a goal value with at most one decimal.
+func formatGoalValue(v float64) string {
+	if v == float64(int(v)) {
+		return fmt.Sprintf("%d", int(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
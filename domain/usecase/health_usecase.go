@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Health states reported by the daemon.
+const (
+	HealthStarting  = "starting"  // No check has finished yet
+	HealthOK        = "ok"        // Authorized and Spotify answered recently
+	HealthDegraded  = "degraded"  // Spotify works, but lyrics can't be fetched
+	HealthUnhealthy = "unhealthy" // Not authorized, or Spotify hasn't answered recently
+)
+
+// lyricsProviderURL is requested to check that lyrics can be fetched.
+const lyricsProviderURL = "https://lrclib.net/"
+
+// HealthReport is the health of the daemon, served at /healthz.
+type HealthReport struct {
+	Status string     `json:"status"`
+	Auth   AuthHealth `json:"auth"`
+	// LastPoll is when Spotify last answered a playback poll
+	LastPoll  *time.Time                `json:"last_poll,omitempty"`
+	Providers map[string]ProviderHealth `json:"providers"`
+}
+
+// AuthHealth tells whether sprt can make authorized requests.
+type AuthHealth struct {
+	Valid     bool       `json:"valid"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// ProviderHealth is the result of the last request to a remote service.
+type ProviderHealth struct {
+	Reachable bool      `json:"reachable"`
+	CheckedAt time.Time `json:"checked_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// HealthUseCase defines the interface for checking the health of a long-running sprt process.
+type HealthUseCase interface {
+	// Check polls Spotify and the lyrics provider and updates the report.
+	Check(ctx context.Context, now time.Time)
+
+	// Report returns the health as of the last check; a poll older than staleAfter is unhealthy.
+	Report(now time.Time, staleAfter time.Duration) HealthReport
+}
+
+// PlaybackSource reports the playback state, e.g. polled from Spotify by the player use case or
+// followed by the state use case.
+type PlaybackSource interface {
+	GetPlaybackState(ctx context.Context) (*PlaybackState, error)
+}
+
+// healthUseCase implements the HealthUseCase interface.
+type healthUseCase struct {
+	authUseCase AuthUseCase
+	playback    PlaybackSource
+	httpClient  *http.Client
+
+	mu       sync.Mutex
+	checked  bool
+	auth     AuthHealth
+	lastPoll time.Time
+	spotify  ProviderHealth
+	lyrics   ProviderHealth
+}
+
+// NewHealthUseCase creates a new instance of HealthUseCase that checks Spotify with the state
+// of playback and the lyrics provider with the given client.
+func NewHealthUseCase(authUseCase AuthUseCase, playback PlaybackSource, httpClient *http.Client) HealthUseCase {
+	return &healthUseCase{
+		authUseCase: authUseCase,
+		playback:    playback,
+		httpClient:  httpClient,
+	}
+}
+
+// Check polls Spotify and the lyrics provider and updates the report.
+func (h *healthUseCase) Check(ctx context.Context, now time.Time) {
+	spotify := ProviderHealth{Reachable: true, CheckedAt: now}
+	_, err := h.playback.GetPlaybackState(ctx)
+	// Nothing playing is a valid answer
+	if err != nil && !errors.Is(err, ErrNothingPlaying) {
+		spotify = ProviderHealth{CheckedAt: now, Error: err.Error()}
+	}
+
+	// The token is read after the poll, which refreshes it when needed
+	auth := AuthHealth{}
+	token, err := h.authUseCase.GetToken(ctx)
+	switch {
+	case err != nil:
+		auth.Error = err.Error()
+	case token.AccessToken == "":
+		auth.Error = "not authorized, run 'sprt auth init'"
+	default:
+		expiresAt := time.Unix(token.ExpiresAt, 0)
+		auth.ExpiresAt = &expiresAt
+		auth.Valid = !token.IsExpired() || token.RefreshToken != ""
+		if !auth.Valid {
+			auth.Error = "the access token expired and can't be refreshed"
+		}
+	}
+
+	lyrics := ProviderHealth{Reachable: true, CheckedAt: now}
+	if err := h.probe(ctx, lyricsProviderURL); err != nil {
+		lyrics = ProviderHealth{CheckedAt: now, Error: err.Error()}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checked = true
+	h.auth = auth
+	h.spotify = spotify
+	h.lyrics = lyrics
+	if spotify.Reachable {
+		h.lastPoll = now
+	}
+}
+
+// probe checks that url answers without a server error.
+func (h *healthUseCase) probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("server answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Report returns the health as of the last check.
+func (h *healthUseCase) Report(now time.Time, staleAfter time.Duration) HealthReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	report := HealthReport{
+		Status: HealthStarting,
+		Auth:   h.auth,
+		Providers: map[string]ProviderHealth{
+			"spotify": h.spotify,
+			"lrclib":  h.lyrics,
+		},
+	}
+	if !h.checked {
+		report.Providers = map[string]ProviderHealth{}
+		return report
+	}
+
+	if !h.lastPoll.IsZero() {
+		lastPoll := h.lastPoll
+		report.LastPoll = &lastPoll
+	}
+
+	switch {
+	case !h.auth.Valid || h.lastPoll.IsZero() || now.Sub(h.lastPoll) > staleAfter:
+		report.Status = HealthUnhealthy
+	case !h.lyrics.Reachable:
+		report.Status = HealthDegraded
+	default:
+		report.Status = HealthOK
+	}
+	return report
+}
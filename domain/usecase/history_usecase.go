@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// HistoryUseCase defines the interface for the local listening history.
+type HistoryUseCase interface {
+	// Sync records the plays from Spotify's recently played tracks in the local history
+	// and returns how many were new. Spotify keeps only the last 50 plays, so syncing
+	// regularly is what builds up the history.
+	Sync(ctx context.Context) (int, error)
+
+	// Plays retrieves the locally recorded plays at or after since, oldest first.
+	Plays(ctx context.Context, since time.Time) ([]entity.Play, error)
+
+	// OnRepeat returns the tracks played at least minPlays times since the given time,
+	// most played first.
+	OnRepeat(ctx context.Context, since time.Time, minPlays int) ([]RepeatedTrack, error)
+}
+
+// RepeatedTrack represents a track with the number of times it was played.
+type RepeatedTrack struct {
+	Track
+	Plays        int       `json:"plays"`
+	LastPlayedAt time.Time `json:"last_played_at"`
+}
+
+// historyUseCase implements the HistoryUseCase interface.
+type historyUseCase struct {
+	historyRepo   repository.PlayHistoryRepository
+	playerUseCase PlayerUseCase
+}
+
+// NewHistoryUseCase creates a new instance of HistoryUseCase.
+func NewHistoryUseCase(historyRepo repository.PlayHistoryRepository, playerUseCase PlayerUseCase) HistoryUseCase {
+	return &historyUseCase{
+		historyRepo:   historyRepo,
+		playerUseCase: playerUseCase,
+	}
+}
+
+// Sync records the plays from Spotify's recently played tracks in the local history.
+func (h *historyUseCase) Sync(ctx context.Context) (int, error) {
+	played, err := h.playerUseCase.GetRecentlyPlayed(ctx, maxRecentlyPlayed)
+	if err != nil {
+		return 0, err
+	}
+
+	plays := make([]entity.Play, len(played))
+	for i, p := range played {
+		plays[i] = entity.Play{
+			PlayedAt:   p.PlayedAt,
+			TrackID:    p.ID,
+			TrackURI:   p.URI,
+			Title:      p.Title,
+			Artist:     p.Artist,
+			Album:      p.Album,
+			DurationMs: p.DurationMs,
+			ContextURI: p.ContextURI,
+		}
+	}
+
+	added, err := h.historyRepo.AddPlays(ctx, plays)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record plays: %w", err)
+	}
+	return added, nil
+}
+
+// Plays retrieves the locally recorded plays at or after since, oldest first.
+func (h *historyUseCase) Plays(ctx context.Context, since time.Time) ([]entity.Play, error) {
+	return h.historyRepo.ListPlays(ctx, since)
+}
+
+// OnRepeat returns the tracks played at least minPlays times since the given time.
+func (h *historyUseCase) OnRepeat(ctx context.Context, since time.Time, minPlays int) ([]RepeatedTrack, error) {
+	plays, err := h.historyRepo.ListPlays(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]*RepeatedTrack)
+	for _, play := range plays {
+		track, ok := counts[play.TrackID]
+		if !ok {
+			track = &RepeatedTrack{Track: Track{
+				ID:         play.TrackID,
+				URI:        play.TrackURI,
+				Title:      play.Title,
+				Artist:     play.Artist,
+				Album:      play.Album,
+				DurationMs: play.DurationMs,
+			}}
+			counts[play.TrackID] = track
+		}
+		track.Plays++
+		if play.PlayedAt.After(track.LastPlayedAt) {
+			track.LastPlayedAt = play.PlayedAt
+		}
+	}
+
+	var repeated []RepeatedTrack
+	for _, track := range counts {
+		if track.Plays >= minPlays {
+			repeated = append(repeated, *track)
+		}
+	}
+	sort.Slice(repeated, func(i, j int) bool {
+		if repeated[i].Plays != repeated[j].Plays {
+			return repeated[i].Plays > repeated[j].Plays
+		}
+		return repeated[i].LastPlayedAt.After(repeated[j].LastPlayedAt)
+	})
+
+	return repeated, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import "time"
+
+// IdleTracker detects when the same track has stayed paused for longer than a threshold.
+type IdleTracker struct {
+	threshold   time.Duration
+	trackID     string
+	pausedSince time.Time
+	reminded    bool
+}
+
+// NewIdleTracker creates a new IdleTracker with the given threshold.
+func NewIdleTracker(threshold time.Duration) *IdleTracker {
+	return &IdleTracker{
+		threshold: threshold,
+	}
+}
+
+// Observe records the latest playback state of the given track.
+func (t *IdleTracker) Observe(trackID string, isPlaying bool, now time.Time) {
+	if isPlaying || trackID != t.trackID {
+		// Playback resumed or moved on, start over
+		t.trackID = trackID
+		t.pausedSince = time.Time{}
+		t.reminded = false
+	}
+
+	if !isPlaying && t.pausedSince.IsZero() {
+		t.pausedSince = now
+	}
+}
+
+// Due reports whether the track has been paused past the threshold.
+// It returns true only once per pause.
+func (t *IdleTracker) Due(now time.Time) bool {
+	if t.reminded || t.pausedSince.IsZero() || now.Sub(t.pausedSince) < t.threshold {
+		return false
+	}
+
+	t.reminded = true
+	return true
+}
+
+// PausedFor returns how long the current track has been paused.
+func (t *IdleTracker) PausedFor(now time.Time) time.Duration {
+	if t.pausedSince.IsZero() {
+		return 0
+	}
+	return now.Sub(t.pausedSince)
+}
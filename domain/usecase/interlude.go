@@ -0,0 +1,40 @@
+package usecase
+
+import "strings"
+
+// interludeDots is the number of dots in the interlude progress indicator.
+const interludeDots = 10
+
+// Interlude returns the indicator shown during an instrumental gap, e.g. "♪ ♪ ♪  ····●·····",
+// or an empty string when the position isn't in a gap of at least thresholdMs.
+//
+// Lines don't carry the time their singing ends, so a line is assumed to be sung within
+// thresholdMs; a line that lasts longer is followed by a gap until the next line.
+// Empty lines, which LRC files use to mark breaks, are gaps from their start.
+// After the last line the gap lasts until durationMs, the length of the track, if known.
+func Interlude(lyrics *Lyrics, index, progressMs, durationMs, thresholdMs int) string {
+	if lyrics == nil || thresholdMs <= 0 || index < 0 || index >= len(lyrics.Lines) {
+		return ""
+	}
+
+	line := lyrics.Lines[index]
+	endMs := line.EndTimeMs
+	if index == len(lyrics.Lines)-1 {
+		// The outro lasts until the end of the track
+		endMs = durationMs
+	}
+	if endMs-line.StartTimeMs < thresholdMs {
+		return ""
+	}
+
+	startMs := line.StartTimeMs + thresholdMs
+	if strings.TrimSpace(line.Text) == "" {
+		startMs = line.StartTimeMs
+	}
+	if progressMs < startMs || progressMs >= endMs {
+		return ""
+	}
+
+	position := (progressMs - startMs) * interludeDots / (endMs - startMs)
+	return "♪ ♪ ♪  " + strings.Repeat("·", position) + "●" + strings.Repeat("·", interludeDots-1-position)
+}
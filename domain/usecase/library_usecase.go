@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// LibraryUseCase defines the interface for use cases around the user's saved items.
+type LibraryUseCase interface {
+	// GetSavedTracks retrieves a page of the user's saved tracks, most recently saved first.
+	GetSavedTracks(ctx context.Context, offset, limit int) (*SavedTracksPage, error)
+
+	// ListSavedTracks retrieves up to limit of the user's saved tracks, or all of them when limit is 0,
+	// most recently saved first.
+	ListSavedTracks(ctx context.Context, limit int) ([]SavedTrack, error)
+
+	// SaveTracks adds the tracks with the given IDs to the user's saved tracks.
+	SaveTracks(ctx context.Context, ids []string) error
+
+	// RemoveSavedTracks removes the tracks with the given IDs from the user's saved tracks.
+	RemoveSavedTracks(ctx context.Context, ids []string) error
+
+	// GetFollowedArtists retrieves all artists the user follows.
+	GetFollowedArtists(ctx context.Context) ([]Artist, error)
+
+	// FollowArtists makes the user follow the artists with the given IDs.
+	FollowArtists(ctx context.Context, ids []string) error
+
+	// UnfollowArtists makes the user stop following the artists with the given IDs.
+	UnfollowArtists(ctx context.Context, ids []string) error
+}
+
+// SavedTrack represents a track in the user's library.
+type SavedTrack struct {
+	Track
+	AddedAt time.Time `json:"added_at"`
+}
+
+// SavedTracksPage is one page of the user's saved tracks.
+type SavedTracksPage struct {
+	Tracks []SavedTrack
+	// Total is the number of saved tracks in the whole library
+	Total int
+	// NextOffset is the offset of the following page, or -1 after the last page
+	NextOffset int
+}
+
+// maxFollowIDsPerRequest is the maximum number of IDs Spotify accepts in a single follow request.
+const maxFollowIDsPerRequest = 50
+
+// maxSavedTracksPerRequest is the maximum page size and number of IDs Spotify accepts for saved tracks.
+const maxSavedTracksPerRequest = 50
+
+// libraryUseCase implements the LibraryUseCase interface.
+type libraryUseCase struct {
+	client repository.SpotifyClient
+}
+
+// NewLibraryUseCase creates a new instance of LibraryUseCase.
+func NewLibraryUseCase(client repository.SpotifyClient) LibraryUseCase {
+	return &libraryUseCase{
+		client: client,
+	}
+}
+
+// savedTrackItem is a saved track as listed by the Spotify Web API.
+type savedTrackItem struct {
+	AddedAt time.Time    `json:"added_at"`
+	Track   spotifyTrack `json:"track"`
+}
+
+// GetSavedTracks retrieves a page of the user's saved tracks, most recently saved first.
+func (l *libraryUseCase) GetSavedTracks(ctx context.Context, offset, limit int) (*SavedTracksPage, error) {
+	if limit <= 0 || limit > maxSavedTracksPerRequest {
+		limit = maxSavedTracksPerRequest
+	}
+
+	params := url.Values{}
+	params.Set("offset", fmt.Sprint(offset))
+	params.Set("limit", fmt.Sprint(limit))
+
+	// The library screen loads one page at a time, so this one isn't followed by a paginator
+	var page repository.Page[savedTrackItem]
+	if err := l.client.Get(ctx, "/me/tracks?"+params.Encode(), &page); err != nil {
+		return nil, fmt.Errorf("failed to get saved tracks: %w", err)
+	}
+
+	result := &SavedTracksPage{
+		Tracks:     make([]SavedTrack, len(page.Items)),
+		Total:      page.Total,
+		NextOffset: -1,
+	}
+	for i, item := range page.Items {
+		result.Tracks[i] = SavedTrack{
+			Track:   item.Track.toTrack(),
+			AddedAt: item.AddedAt,
+		}
+	}
+	if page.Next != "" {
+		result.NextOffset = offset + len(page.Items)
+	}
+
+	return result, nil
+}
+
+// ListSavedTracks retrieves up to limit of the user's saved tracks, or all of them when limit is 0,
+// most recently saved first.
+func (l *libraryUseCase) ListSavedTracks(ctx context.Context, limit int) ([]SavedTrack, error) {
+	pageSize := maxSavedTracksPerRequest
+	if limit > 0 {
+		pageSize = min(limit, maxSavedTracksPerRequest)
+	}
+
+	items, err := repository.CollectPages[savedTrackItem](ctx, l.client, fmt.Sprintf("/me/tracks?limit=%d", pageSize), "", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved tracks: %w", err)
+	}
+
+	tracks := make([]SavedTrack, len(items))
+	for i, item := range items {
+		tracks[i] = SavedTrack{
+			Track:   item.Track.toTrack(),
+			AddedAt: item.AddedAt,
+		}
+	}
+
+	return tracks, nil
+}
+
+// SaveTracks adds the tracks with the given IDs to the user's saved tracks.
+func (l *libraryUseCase) SaveTracks(ctx context.Context, ids []string) error {
+	for start := 0; start < len(ids); start += maxSavedTracksPerRequest {
+		end := min(start+maxSavedTracksPerRequest, len(ids))
+
+		if err := l.client.Put(ctx, "/me/tracks?ids="+url.QueryEscape(strings.Join(ids[start:end], ",")), nil, nil); err != nil {
+			return fmt.Errorf("failed to save tracks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveSavedTracks removes the tracks with the given IDs from the user's saved tracks.
+func (l *libraryUseCase) RemoveSavedTracks(ctx context.Context, ids []string) error {
+	for start := 0; start < len(ids); start += maxSavedTracksPerRequest {
+		end := min(start+maxSavedTracksPerRequest, len(ids))
+
+		if err := l.client.Delete(ctx, "/me/tracks?ids="+url.QueryEscape(strings.Join(ids[start:end], ",")), nil, nil); err != nil {
+			return fmt.Errorf("failed to remove saved tracks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetFollowedArtists retrieves all artists the user follows.
+func (l *libraryUseCase) GetFollowedArtists(ctx context.Context) ([]Artist, error) {
+	// Followed artists are paged with a cursor rather than an offset, the next links cover both
+	items, err := repository.CollectPages[spotifyArtist](ctx, l.client, "/me/following?type=artist&limit=50", "artists", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followed artists: %w", err)
+	}
+
+	artists := make([]Artist, len(items))
+	for i, item := range items {
+		artists[i] = item.toArtist()
+	}
+
+	return artists, nil
+}
+
+// FollowArtists makes the user follow the artists with the given IDs.
+func (l *libraryUseCase) FollowArtists(ctx context.Context, ids []string) error {
+	return l.changeFollowing(ctx, http.MethodPut, ids)
+}
+
+// UnfollowArtists makes the user stop following the artists with the given IDs.
+func (l *libraryUseCase) UnfollowArtists(ctx context.Context, ids []string) error {
+	return l.changeFollowing(ctx, http.MethodDelete, ids)
+}
+
+// changeFollowing follows (PUT) or unfollows (DELETE) artists in batches.
+func (l *libraryUseCase) changeFollowing(ctx context.Context, method string, ids []string) error {
+	for start := 0; start < len(ids); start += maxFollowIDsPerRequest {
+		end := min(start+maxFollowIDsPerRequest, len(ids))
+
+		if err := l.client.Do(ctx, method, "/me/following?type=artist&ids="+url.QueryEscape(strings.Join(ids[start:end], ",")), nil, nil); err != nil {
+			return fmt.Errorf("failed to update followed artists: %w", err)
+		}
+	}
+
+	return nil
+}
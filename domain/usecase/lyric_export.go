@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muhadif/sprt/domain/lrc"
+)
+
+// Formats lyrics can be exported to.
+const (
+	ExportLRC  = "lrc" // Synchronized lyrics with the track in the tags
+	ExportText = "txt" // The text of the lines
+	ExportSRT  = "srt" // SubRip subtitles, e.g. for videos
+)
+
+// ExportFormats lists the export formats.
+var ExportFormats = []string{ExportLRC, ExportText, ExportSRT}
+
+// ExportLyrics writes the lyrics of track in the given format. The LRC timestamps are rebuilt
+// from the start times of the lines and words, and the duet parts are marked again as "M: ".
+func ExportLyrics(lyrics *Lyrics, track *CurrentlyPlaying, format string) (string, error) {
+	if lyrics == nil || len(lyrics.Lines) == 0 {
+		return "", fmt.Errorf("no synced lyrics found for %s by %s", track.Title, track.Artist)
+	}
+	if !lyrics.Synced && format != ExportText {
+		return "", fmt.Errorf("only unsynced lyrics found for %s by %s, which can only be exported as %s", track.Title, track.Artist, ExportText)
+	}
+
+	switch format {
+	case ExportLRC:
+		return exportLRC(lyrics, track), nil
+	case ExportText:
+		return exportText(lyrics), nil
+	case ExportSRT:
+		return exportSRT(lyrics), nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (expected %s)", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+// exportLRC writes the lyrics as LRC, headed by the tags of the track.
+func exportLRC(lyrics *Lyrics, track *CurrentlyPlaying) string {
+	var b strings.Builder
+	tags := [][2]string{{"ar", track.Artist}, {"ti", track.Title}, {"al", track.Album}}
+	if track.DurationMs > 0 {
+		length := track.DurationMs / 1000
+		tags = append(tags, [2]string{"length", fmt.Sprintf("%d:%02d", length/60, length%60)})
+	}
+	tags = append(tags, [2]string{"re", "sprt"})
+	for _, tag := range tags {
+		if tag[1] != "" {
+			fmt.Fprintf(&b, "[%s:%s]\n", tag[0], tag[1])
+		}
+	}
+
+	lines := make([]lrc.Line, len(lyrics.Lines))
+	for i, line := range lyrics.Lines {
+		lines[i] = lrc.Line{StartTimeMs: line.StartTimeMs, Text: line.Text}
+		for _, word := range line.Words {
+			lines[i].Words = append(lines[i].Words, lrc.Word{StartTimeMs: word.StartTimeMs, Text: word.Text})
+		}
+		if line.Part != "" {
+			lines[i].Text = line.Part + ": " + line.Text
+		}
+	}
+	b.WriteString(lrc.Format(lines))
+	return b.String()
+}
+
+// exportText writes the text of the lines, one per line; the empty lines between verses stay.
+func exportText(lyrics *Lyrics) string {
+	var b strings.Builder
+	for _, line := range lyrics.Lines {
+		b.WriteString(strings.TrimSpace(line.Text) + "\n")
+	}
+	return b.String()
+}
+
+// exportSRT writes the lines as SubRip subtitles shown from their start to their end time.
+// Empty lines, the breaks between verses, get no subtitle.
+func exportSRT(lyrics *Lyrics) string {
+	var b strings.Builder
+	n := 0
+	for _, line := range lyrics.Lines {
+		text := strings.TrimSpace(line.Text)
+		if text == "" {
+			continue
+		}
+		n++
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", n, srtTimestamp(line.StartTimeMs), srtTimestamp(line.EndTimeMs), text)
+	}
+	return b.String()
+}
+
+// LyricFileName returns the name of the lyric file of a track, "<artist> - <title>.<ext>",
+// without the characters file systems reject. Exports are saved under it and local lyrics
+// looked up by it.
+func LyricFileName(artist, title, ext string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, artist+" - "+title)
+	return name + "." + ext
+}
+
+// srtTimestamp formats milliseconds as a SubRip timestamp like "00:01:02,500".
+func srtTimestamp(ms int) string {
+	ms = max(ms, 0)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
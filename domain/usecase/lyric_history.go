@@ -0,0 +1,49 @@
+package usecase
+
+// HistoryLine is a lyric line recorded in a LyricHistory.
+type HistoryLine struct {
+	Index int
+	Line
+}
+
+// LyricHistory is a ring buffer of the most recently displayed lyric lines.
+type LyricHistory struct {
+	lines []HistoryLine
+	next  int
+	full  bool
+}
+
+// NewLyricHistory creates a new LyricHistory keeping up to size lines.
+// A size of 0 or less keeps no history.
+func NewLyricHistory(size int) *LyricHistory {
+	return &LyricHistory{
+		lines: make([]HistoryLine, max(size, 0)),
+	}
+}
+
+// Add records a displayed line, evicting the oldest one when the buffer is full.
+func (h *LyricHistory) Add(index int, line Line) {
+	if len(h.lines) == 0 {
+		return
+	}
+
+	h.lines[h.next] = HistoryLine{Index: index, Line: line}
+	h.next = (h.next + 1) % len(h.lines)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Lines returns the recorded lines, oldest first.
+func (h *LyricHistory) Lines() []HistoryLine {
+	if !h.full {
+		return append([]HistoryLine(nil), h.lines[:h.next]...)
+	}
+	return append(append([]HistoryLine(nil), h.lines[h.next:]...), h.lines[:h.next]...)
+}
+
+// Reset forgets all recorded lines, e.g. when the track changes.
+func (h *LyricHistory) Reset() {
+	h.next = 0
+	h.full = false
+}
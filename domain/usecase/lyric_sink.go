@@ -0,0 +1,42 @@
+package usecase
+
+import "fmt"
+
+// LyricOutput represents a lyric line published to the lyric sinks.
+// Empty text clears the output, e.g. when playback stops.
+type LyricOutput struct {
+	Text      string
+	LineIndex int
+	// Line is the lyric line shown, with its timing; nil while an indicator or a status replaces it
+	Line       *Line
+	ProgressMs int
+	Track      *CurrentlyPlaying
+	// History holds the lines displayed before this one, oldest first
+	History []HistoryLine
+	// Separator marks a song-change announcement rather than a lyric line.
+	// Line-oriented sinks emit it to delimit songs, sinks that only show the
+	// current line ignore it.
+	Separator bool
+}
+
+// SongSeparator returns the announcement emitted when the track changes.
+func SongSeparator(track *CurrentlyPlaying) string {
+	return fmt.Sprintf("── %s – %s ──", track.Title, track.Artist)
+}
+
+// LyricSink defines the interface for destinations of the current lyric line,
+// such as the terminal, a file or a socket.
+type LyricSink interface {
+	// Write publishes the current lyric line.
+	Write(output LyricOutput) error
+
+	// Close releases the resources held by the sink.
+	Close() error
+}
+
+// TrackSink is implemented by sinks that also follow the playing track between lyric lines,
+// like play/pause changes.
+type TrackSink interface {
+	// WriteTrack publishes the playing track, or nil when nothing is playing.
+	WriteTrack(track *CurrentlyPlaying) error
+}
@@ -3,23 +3,64 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/muhadif/sprt/domain/lrc"
+	"github.com/muhadif/sprt/domain/repository"
 )
 
 // LyricUseCase defines the interface for lyric-related use cases.
 type LyricUseCase interface {
-	// GetLyrics retrieves the lyrics for the given artist, title, and album.
-	GetLyrics(ctx context.Context, artist, title, album string) (*Lyrics, error)
-	DisplaySyncedLyrics(ctx context.Context, lyrics *Lyrics, startTimeMs int, playerUseCase PlayerUseCase)
+	// GetLyrics retrieves the lyrics for the given artist, title, and album. Of several versions,
+	// the one lasting durationMs is preferred; 0 is an unknown duration.
+	GetLyrics(ctx context.Context, artist, title, album string, durationMs int) (*Lyrics, error)
 	// GetLyricChannel returns a channel that will receive lyrics updates
-	GetLyricChannel(ctx context.Context, startTimeMs int, playerUseCase PlayerUseCase) <-chan *LyricUpdate
+	GetLyricChannel(ctx context.Context, startTimeMs int, tracks TrackSource) <-chan *LyricUpdate
+	// SearchLyrics lists the lyrics found for the given artist and title, e.g. a live or a
+	// remixed version, marking the ones GetLyrics picks for a track lasting durationMs.
+	SearchLyrics(ctx context.Context, artist, title string, durationMs int) ([]LyricMatch, error)
+	// ChooseLyrics makes GetLyrics pick the lyrics with the given ID for the track from now on.
+	ChooseLyrics(ctx context.Context, artist, title string, id int) error
+	// LocalLyricsPath returns the path of the local LRC file of the track, which GetLyrics
+	// prefers to the lyrics found online; the file may not exist yet.
+	LocalLyricsPath(artist, title string) (string, error)
+}
+
+// LyricMatch describes lyrics found for a track, to choose between versions.
+type LyricMatch struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Artist       string `json:"artist"`
+	Album        string `json:"album"`
+	DurationMs   int    `json:"duration_ms"`
+	Synced       bool   `json:"synced"`
+	Instrumental bool   `json:"instrumental"`
+	// Picked is set for the lyrics GetLyrics picks
+	Picked bool `json:"picked"`
+}
+
+// TrackSource reports the playing item, e.g. polled from Spotify or asked of the daemon.
+// PlayerUseCase is one.
+type TrackSource interface {
+	GetCurrentlyPlayingDetails(ctx context.Context) (*CurrentlyPlaying, error)
+}
+
+// LyricSource looks up the lyrics of a track, e.g. from lrclib.net or the cache of the daemon.
+// LyricUseCase is one.
+type LyricSource interface {
+	GetLyrics(ctx context.Context, artist, title, album string, durationMs int) (*Lyrics, error)
 }
 
 // Lyrics represents a song's lyrics with timing information.
@@ -30,7 +71,9 @@ type Lyrics struct {
 	Album    string `json:"album"`
 	Language string `json:"language"`
 	Synced   bool   `json:"syncedLyrics"`
-	Lines    []Line `json:"lines"`
+	// Estimated is set for unsynced lyrics whose lines are spread evenly over the track
+	Estimated bool   `json:"estimated,omitempty"`
+	Lines     []Line `json:"lines"`
 }
 
 // Line represents a single line of lyrics with timing information.
@@ -38,46 +81,241 @@ type Line struct {
 	StartTimeMs int    `json:"startTimeMs"`
 	EndTimeMs   int    `json:"endTimeMs"`
 	Text        string `json:"text"`
+	// Part is the duet part singing the line, e.g. "M", "F" or "D" for both,
+	// or "V1"/"V2" for numbered voices; empty when the lyrics have no parts
+	Part string `json:"part,omitempty"`
+	// Words are the timed words making up the end of the text, from enhanced LRC lyrics, for
+	// highlighting them as they are sung; nil for lyrics without word timestamps
+	Words []Word `json:"words,omitempty"`
+}
+
+// Word represents a word of a lyric line with the time it is sung at. Its text includes the
+// space following it.
+type Word struct {
+	StartTimeMs int    `json:"startTimeMs"`
+	Text        string `json:"text"`
 }
 
 // LyricUpdate represents an update to the lyrics display.
+// Updates that only report a play/pause change carry Track but no Lyrics.
 type LyricUpdate struct {
+	Track     *CurrentlyPlaying
 	Lyrics    *Lyrics
 	Line      *Line
 	LineIndex int
 	Text      string
 	IsError   bool
 	ErrorMsg  string
+	// NothingPlaying is set when Spotify reports that nothing is playing
+	NothingPlaying bool
 }
 
 // lyricUseCase implements the LyricUseCase interface.
 type lyricUseCase struct {
-	cache     map[string]*Lyrics
-	cacheLock sync.RWMutex
+	httpClient     *http.Client
+	matchRepo      repository.LyricMatchRepository
+	localDir       string
+	estimateTiming bool
+	cache          map[string]*Lyrics
+	cacheLock      sync.RWMutex
+}
+
+// Lyric lookups answered from the cache and fetched from lrclib.net, for the metrics endpoint.
+var lyricCacheHits, lyricCacheMisses atomic.Int64
+
+// LyricCacheStats returns how many lyric lookups were answered from the cache and how many were
+// fetched, across all lyric use cases of the process.
+func LyricCacheStats() (hits, misses int64) {
+	return lyricCacheHits.Load(), lyricCacheMisses.Load()
 }
 
-// NewLyricUseCase creates a new instance of LyricUseCase.
-func NewLyricUseCase() LyricUseCase {
+// NewLyricUseCase creates a new instance of LyricUseCase that requests lyrics with the given client,
+// picking the lyrics chosen in matchRepo for tracks with several versions; nil doesn't remember
+// choices. The LRC files in localDir, named "<artist> - <title>.lrc", are preferred to the lyrics of
+// lrclib.net; an empty localDir only uses lrclib.net, and "~/" is the home directory. Tracks with
+// only unsynced lyrics get their lines spread evenly over the track when estimateTiming is set.
+func NewLyricUseCase(httpClient *http.Client, matchRepo repository.LyricMatchRepository, localDir string, estimateTiming bool) LyricUseCase {
+	if rest, ok := strings.CutPrefix(localDir, "~/"); ok {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			localDir = filepath.Join(homeDir, rest)
+		}
+	}
 	return &lyricUseCase{
-		cache: make(map[string]*Lyrics),
+		httpClient:     httpClient,
+		matchRepo:      matchRepo,
+		localDir:       localDir,
+		estimateTiming: estimateTiming,
+		cache:          make(map[string]*Lyrics),
 	}
 }
 
 // GetLyrics retrieves the lyrics for the given artist, title, and album.
-func (l *lyricUseCase) GetLyrics(ctx context.Context, artist, title, album string) (*Lyrics, error) {
+func (l *lyricUseCase) GetLyrics(ctx context.Context, artist, title, album string, durationMs int) (*Lyrics, error) {
+	// Curated local lyrics win over the ones found online; they are read every time, so
+	// edits take effect the next time the track plays
+	if lyrics := l.localLyrics(artist, title); lyrics != nil {
+		return lyrics, nil
+	}
+
 	// Create a cache key from artist and title
 	cacheKey := artist + "|" + title
 
+	// Lyrics chosen for the track, possibly by another process, replace the cached ones
+	chosenID := l.chosenMatch(ctx, artist, title)
+
 	// Check if lyrics are in the cache
 	l.cacheLock.RLock()
 	cachedLyrics, found := l.cache[cacheKey]
 	l.cacheLock.RUnlock()
 
-	if found {
+	if found && (chosenID == 0 || cachedLyrics.ID == chosenID || cachedLyrics.ID == 0) {
+		lyricCacheHits.Add(1)
 		return cachedLyrics, nil
 	}
+	lyricCacheMisses.Add(1)
+
+	// Lyrics not in cache, ask lrclib.net for the chosen ones or the exact match of the track
+	if result := l.lookup(ctx, artist, title, album, durationMs, chosenID); result != nil {
+		lyrics := l.toLyrics(result)
+		l.cacheLock.Lock()
+		l.cache[cacheKey] = lyrics
+		l.cacheLock.Unlock()
+		return lyrics, nil
+	}
+
+	// Without an exact match, search for the track
+	results, err := l.search(ctx, artist, title)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if lyrics were found
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no lyrics found for %s by %s", title, artist)
+	}
+
+	lyrics := l.toLyrics(&results[pickResult(results, chosenID, durationMs)])
+
+	// Store lyrics in cache
+	l.cacheLock.Lock()
+	l.cache[cacheKey] = lyrics
+	l.cacheLock.Unlock()
+
+	return lyrics, nil
+}
+
+// SearchLyrics lists the lyrics lrclib.net has for the track.
+func (l *lyricUseCase) SearchLyrics(ctx context.Context, artist, title string, durationMs int) ([]LyricMatch, error) {
+	results, err := l.search(ctx, artist, title)
+	if err != nil {
+		return nil, err
+	}
+
+	picked := -1
+	if len(results) > 0 {
+		picked = pickResult(results, l.chosenMatch(ctx, artist, title), durationMs)
+	}
+	matches := make([]LyricMatch, len(results))
+	for i, result := range results {
+		matches[i] = LyricMatch{
+			ID:           result.Id,
+			Name:         result.TrackName,
+			Artist:       result.ArtistName,
+			Album:        result.AlbumName,
+			DurationMs:   int(result.Duration * 1000),
+			Synced:       result.SyncedLyrics != nil,
+			Instrumental: result.Instrumental,
+			Picked:       i == picked,
+		}
+	}
+	return matches, nil
+}
+
+// ChooseLyrics remembers the choice and drops the lyrics cached for the track.
+func (l *lyricUseCase) ChooseLyrics(ctx context.Context, artist, title string, id int) error {
+	if l.matchRepo == nil {
+		return errors.New("lyric choices can't be saved")
+	}
+	if err := l.matchRepo.SaveLyricMatch(ctx, artist, title, id); err != nil {
+		return fmt.Errorf("failed to save the lyrics chosen: %w", err)
+	}
+
+	l.cacheLock.Lock()
+	delete(l.cache, artist+"|"+title)
+	l.cacheLock.Unlock()
+	return nil
+}
+
+// lrclibResult is a search result of lrclib.net.
+type lrclibResult struct {
+	Id           int     `json:"id"`
+	Name         string  `json:"name"`
+	TrackName    string  `json:"trackName"`
+	ArtistName   string  `json:"artistName"`
+	AlbumName    string  `json:"albumName"`
+	Duration     float32 `json:"duration"`
+	Instrumental bool    `json:"instrumental"`
+	PlainLyrics  *string `json:"plainLyrics"`
+	SyncedLyrics *string `json:"syncedLyrics"`
+}
+
+// lookup gets the lyrics chosen for the track from lrclib.net, or else the lyrics matching the
+// track exactly, by its artist, title, album and duration. It returns nil when there are none,
+// and for exact matches that aren't synced, for the search to find more.
+func (l *lyricUseCase) lookup(ctx context.Context, artist, title, album string, durationMs, chosenID int) *lrclibResult {
+	if chosenID != 0 {
+		if result, err := l.get(ctx, fmt.Sprintf("https://lrclib.net/api/get/%d", chosenID)); err == nil {
+			return result
+		}
+		return nil
+	}
+	if album == "" || durationMs <= 0 {
+		return nil
+	}
+
+	params := url.Values{}
+	params.Set("track_name", title)
+	params.Set("artist_name", artist)
+	params.Set("album_name", album)
+	params.Set("duration", strconv.Itoa((durationMs+500)/1000))
+	result, err := l.get(ctx, "https://lrclib.net/api/get?"+params.Encode())
+	if err != nil || result.SyncedLyrics == nil {
+		return nil
+	}
+	return result
+}
+
+// get requests the lyrics of one track from lrclib.net, answered with 404 Not Found when there
+// are none.
+func (l *lyricUseCase) get(ctx context.Context, requestURL string) (*lrclibResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lyrics: %w", err)
+	}
+	defer resp.Body.Close()
 
-	// Lyrics not in cache, fetch from API
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result lrclibResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// search searches lrclib.net for the lyrics of the track.
+func (l *lyricUseCase) search(ctx context.Context, artist, title string) ([]lrclibResult, error) {
 	// Prepare the request to lrclib.net
 	baseURL := "https://lrclib.net/api/search"
 	params := url.Values{}
@@ -91,8 +329,7 @@ func (l *lyricUseCase) GetLyrics(ctx context.Context, artist, title, album strin
 	}
 
 	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := l.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get lyrics: %w", err)
 	}
@@ -109,288 +346,366 @@ func (l *lyricUseCase) GetLyrics(ctx context.Context, artist, title, album strin
 		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	type libResponse struct {
-		Id           int     `json:"id"`
-		Name         string  `json:"name"`
-		TrackName    string  `json:"trackName"`
-		ArtistName   string  `json:"artistName"`
-		AlbumName    string  `json:"albumName"`
-		Duration     float32 `json:"duration"`
-		Instrumental bool    `json:"instrumental"`
-		PlainLyrics  *string `json:"plainLyrics"`
-		SyncedLyrics *string `json:"syncedLyrics"`
+	var results []lrclibResult
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	return results, nil
+}
 
-	var libResponses []libResponse
-	if err := json.Unmarshal(body, &libResponses); err != nil {
-		fmt.Println("err", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// lyricDurationTolerance is how far the duration of lyrics may be off the track's for them to be
+// of the same version.
+const lyricDurationTolerance = 2 * time.Second
+
+// pickResult returns the index of the result to show: the chosen one if it is among them, else
+// the synced lyrics closest to durationMs, preferring the ones within lyricDurationTolerance to
+// unsynced ones, else the first result. A durationMs of 0 takes the first synced lyrics.
+func pickResult(results []lrclibResult, chosenID, durationMs int) int {
+	for i := range results {
+		if chosenID != 0 && results[i].Id == chosenID {
+			return i
+		}
 	}
 
-	// Check if lyrics were found
-	if len(libResponses) == 0 {
-		return nil, fmt.Errorf("no lyrics found for %s by %s", title, artist)
+	// offMs returns how far the duration of a result is off the track's
+	offMs := func(i int) int {
+		if durationMs <= 0 {
+			return 0
+		}
+		return abs(int(results[i].Duration*1000) - durationMs)
+	}
+	picked := -1
+	for i := range results {
+		if results[i].SyncedLyrics != nil && (picked == -1 || offMs(i) < offMs(picked)) {
+			picked = i
+		}
+	}
+	tolerance := int(lyricDurationTolerance.Milliseconds())
+	if picked != -1 && offMs(picked) <= tolerance {
+		return picked
 	}
 
-	// Find the first synced lyrics if available
-	var selectedLyrics *libResponse
-	for i := range libResponses {
-		if libResponses[i].SyncedLyrics != nil {
-			selectedLyrics = &libResponses[i]
-			break
+	// Unsynced lyrics of the same version beat synced lyrics timed for another one
+	for i := range results {
+		if durationMs > 0 && offMs(i) <= tolerance && !results[i].Instrumental {
+			return i
 		}
 	}
-	if selectedLyrics == nil {
-		selectedLyrics = &libResponses[0]
+	return max(picked, 0)
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
 	}
+	return n
+}
 
-	// Parse the synced lyrics
+// toLyrics parses the lyrics of a search result.
+func (l *lyricUseCase) toLyrics(result *lrclibResult) *Lyrics {
 	lyrics := &Lyrics{
-		ID:     selectedLyrics.Id,
-		Name:   selectedLyrics.Name,
-		Artist: selectedLyrics.ArtistName,
-		Album:  selectedLyrics.AlbumName,
-		Synced: selectedLyrics.SyncedLyrics != nil,
+		ID:     result.Id,
+		Name:   result.Name,
+		Artist: result.ArtistName,
+		Album:  result.AlbumName,
+		Synced: result.SyncedLyrics != nil,
 		Lines:  []Line{},
 	}
 
-	if selectedLyrics.SyncedLyrics != nil {
-		// Parse the LRC format
-		lines := strings.Split(*selectedLyrics.SyncedLyrics, "\n")
-		for _, line := range lines {
-			if strings.TrimSpace(line) == "" {
-				continue
-			}
-
-			// Parse the timestamp and text
-			// Format: [mm:ss.xx]text
-			if !strings.HasPrefix(line, "[") {
-				continue
-			}
+	if result.SyncedLyrics != nil {
+		lyrics.Lines = parseSyncedLyrics(*result.SyncedLyrics)
+	} else if result.PlainLyrics != nil {
+		lyrics.Lines = parsePlainLyrics(*result.PlainLyrics)
+		if l.estimateTiming {
+			lyrics.EstimateTiming(int(result.Duration * 1000))
+		}
+	}
+	return lyrics
+}
 
-			closeBracket := strings.Index(line, "]")
-			if closeBracket == -1 {
-				continue
-			}
+// chosenMatch returns the ID of the lyrics chosen for the track, or 0 when none were chosen or
+// the choice can't be read.
+func (l *lyricUseCase) chosenMatch(ctx context.Context, artist, title string) int {
+	if l.matchRepo == nil {
+		return 0
+	}
+	id, err := l.matchRepo.GetLyricMatch(ctx, artist, title)
+	if err != nil {
+		return 0
+	}
+	return id
+}
 
-			timestamp := line[1:closeBracket]
-			text := line[closeBracket+1:]
+// LocalLyricsPath returns the path of the local LRC file of the track, matching its name
+// ignoring case, or the path it gets when there is none yet.
+func (l *lyricUseCase) LocalLyricsPath(artist, title string) (string, error) {
+	if l.localDir == "" {
+		return "", errors.New("no local lyrics directory is configured (lyrics.dir in ~/.sprt/config.json)")
+	}
 
-			// Parse the timestamp
-			var minutes, seconds, milliseconds int
-			if _, err := fmt.Sscanf(timestamp, "%d:%d.%d", &minutes, &seconds, &milliseconds); err != nil {
-				continue
+	name := LyricFileName(artist, title, "lrc")
+	path := filepath.Join(l.localDir, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		entries, _ := os.ReadDir(l.localDir)
+		for _, entry := range entries {
+			if strings.EqualFold(entry.Name(), name) {
+				return filepath.Join(l.localDir, entry.Name()), nil
 			}
-
-			// Convert to milliseconds
-			startTimeMs := minutes*60*1000 + seconds*1000 + milliseconds*10
-
-			// Add the line
-			lyrics.Lines = append(lyrics.Lines, Line{
-				StartTimeMs: startTimeMs,
-				EndTimeMs:   0, // Will be set below
-				Text:        text,
-			})
 		}
+	}
+	return path, nil
+}
 
-		// Set the end time for each line
-		for i := 0; i < len(lyrics.Lines)-1; i++ {
-			lyrics.Lines[i].EndTimeMs = lyrics.Lines[i+1].StartTimeMs
-		}
-		if len(lyrics.Lines) > 0 {
-			// Set a default end time for the last line
-			lyrics.Lines[len(lyrics.Lines)-1].EndTimeMs = lyrics.Lines[len(lyrics.Lines)-1].StartTimeMs + 5000
-		}
+// localLyrics returns the synced lyrics of the track from the local directory, or nil when there
+// are none.
+func (l *lyricUseCase) localLyrics(artist, title string) *Lyrics {
+	path, err := l.LocalLyricsPath(artist, title)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
 	}
 
-	// Store lyrics in cache
-	l.cacheLock.Lock()
-	l.cache[cacheKey] = lyrics
-	l.cacheLock.Unlock()
+	text := string(data)
+	lines := parseSyncedLyrics(text)
+	if len(lines) == 0 {
+		return nil
+	}
+	tags := lrc.Tags(text)
+	lyrics := &Lyrics{
+		Name:   tags["ti"],
+		Artist: tags["ar"],
+		Album:  tags["al"],
+		Synced: true,
+		Lines:  lines,
+	}
+	if lyrics.Name == "" {
+		lyrics.Name = title
+	}
+	if lyrics.Artist == "" {
+		lyrics.Artist = artist
+	}
+	return lyrics
+}
 
-	return lyrics, nil
+// GetLyricChannel returns a channel that will receive lyrics updates. The goroutines
+// feeding it stop once ctx ends, after which the channel is closed.
+func (l *lyricUseCase) GetLyricChannel(ctx context.Context, startTimeMs int, tracks TrackSource) <-chan *LyricUpdate {
+	return LyricChannel(ctx, startTimeMs, tracks, l)
 }
 
-// GetLyricChannel returns a channel that will receive lyrics updates
-func (l *lyricUseCase) GetLyricChannel(ctx context.Context, startTimeMs int, playerUseCase PlayerUseCase) <-chan *LyricUpdate {
+// LyricChannel returns a channel receiving the lines of the item reported by tracks as they
+// start, with the lyrics looked up in source. The goroutines feeding it stop once ctx ends,
+// after which the channel is closed.
+func LyricChannel(ctx context.Context, startTimeMs int, tracks TrackSource, source LyricSource) <-chan *LyricUpdate {
 	updateCh := make(chan *LyricUpdate, 10)
 
+	// send delivers an update unless ctx ends first, so nothing blocks once the reader is gone
+	send := func(update *LyricUpdate) {
+		select {
+		case updateCh <- update:
+		case <-ctx.Done():
+		}
+	}
+
 	go func() {
+		// The channel is closed once neither goroutine sends on it anymore, also when the
+		// first track can't be looked up
+		var polling sync.WaitGroup
+		defer func() {
+			polling.Wait()
+			close(updateCh)
+		}()
+
 		// Get the currently playing track
-		track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+		track, err := tracks.GetCurrentlyPlayingDetails(ctx)
 		if err != nil {
-			// Check if the error is "no track currently playing"
-			if err.Error() == "no track currently playing" {
-				updateCh <- &LyricUpdate{
-					IsError:  true,
-					ErrorMsg: "No track currently playing. Please start playing a track on Spotify.",
-				}
+			if errors.Is(err, ErrNothingPlaying) {
+				send(NothingPlayingUpdate())
 			} else {
-				updateCh <- &LyricUpdate{
+				send(&LyricUpdate{
 					IsError:  true,
 					ErrorMsg: fmt.Sprintf("Error getting track: %v", err),
-				}
+				})
 			}
 			return
 		}
 
-		// Get the lyrics
-		lyrics, err := l.GetLyrics(ctx, track.Artist, track.Title, track.Album)
+		// Get the lyrics; podcast episodes have none, so don't look them up
+		var lyrics *Lyrics
+		if !track.IsEpisode() {
+			lyrics, err = source.GetLyrics(ctx, track.Artist, track.Title, track.Album, track.DurationMs)
+		}
+		// failed is the song whose lyrics couldn't be looked up, tried again at retryAt
+		song, failed, retryAt := track.Title, "", time.Time{}
 		if err != nil {
-			updateCh <- &LyricUpdate{
+			song, failed, retryAt = "", track.Title, time.Now().Add(lyricsRetryInterval)
+			send(&LyricUpdate{
 				IsError:  true,
 				ErrorMsg: fmt.Sprintf("No lyrics found for %s by %s: %v", track.Title, track.Artist, err),
-			}
-		}
-
-		// Track the current song to avoid redundant fetching
-		currentSong := track.Title
-
-		// Find the current line based on the start time
-		currentLineIndex := 0
-		if lyrics != nil && len(lyrics.Lines) > 0 {
-			for i, line := range lyrics.Lines {
-				if line.StartTimeMs <= startTimeMs && startTimeMs < line.EndTimeMs {
-					currentLineIndex = i
-					break
-				} else if line.StartTimeMs > startTimeMs {
-					break
-				}
-				currentLineIndex = i
-			}
+			})
 		}
 
-		// Create a ticker to poll Spotify every 500 milliseconds
-		ticker := time.NewTicker(500 * time.Millisecond)
-		defer ticker.Stop()
+		// The poller owns what it needs to tell changes apart; everything shown is kept by the
+		// loop below, which receives each poll as a whole
+		polled := make(chan lyricPoll, 1)
+		polling.Add(1)
+		go func(song, failed string, retryAt time.Time, lyrics *Lyrics, isPlaying bool) {
+			defer polling.Done()
 
-		// Display the lyrics synchronized with the music
-		startTime := time.Now().Add(-time.Duration(startTimeMs) * time.Millisecond)
-		currentProgressMs := startTimeMs
+			// Create a ticker to poll Spotify every 500 milliseconds
+			ticker := time.NewTicker(500 * time.Millisecond)
+			defer ticker.Stop()
 
-		// Create a channel to signal when we need to update the display
-		internalUpdateCh := make(chan struct{}, 1)
-		// Initial update
-		internalUpdateCh <- struct{}{}
+			// Polling pauses while Spotify is rate limiting; the lines keep
+			// advancing from the last known progress meanwhile
+			var rateLimitedUntil time.Time
 
-		// Start a goroutine to poll Spotify
-		go func() {
 			for {
 				select {
 				case <-ctx.Done():
-					close(updateCh)
 					return
 				case <-ticker.C:
+					if time.Now().Before(rateLimitedUntil) {
+						continue
+					}
+
 					// Get the currently playing track
-					track, err := playerUseCase.GetCurrentlyPlayingDetails(ctx)
+					track, err := tracks.GetCurrentlyPlayingDetails(ctx)
 					if err != nil {
-						// Check if the error is "no track currently playing"
-						if err.Error() == "no track currently playing" {
-							updateCh <- &LyricUpdate{
-								IsError:  true,
-								ErrorMsg: "No track currently playing. Please start playing a track on Spotify.",
-							}
+						var rateLimit *repository.RateLimitError
+						if errors.As(err, &rateLimit) {
+							rateLimitedUntil = time.Now().Add(rateLimit.RetryAfter)
+							continue
+						}
+
+						if errors.Is(err, ErrNothingPlaying) {
+							send(NothingPlayingUpdate())
 						} else {
-							updateCh <- &LyricUpdate{
+							send(&LyricUpdate{
 								IsError:  true,
 								ErrorMsg: fmt.Sprintf("Error getting track: %v", err),
-							}
+							})
 						}
 						continue
 					}
 
-					// Only fetch new lyrics if the song has changed
-					if track.Title != currentSong {
-						currentSong = track.Title
-						lyrics, err = l.GetLyrics(ctx, track.Artist, track.Title, track.Album)
+					// Report play/pause changes, which don't move the active line
+					if track.IsPlaying != isPlaying {
+						isPlaying = track.IsPlaying
+						send(&LyricUpdate{Track: track})
+					}
+
+					// Only fetch new lyrics if the song has changed. A song whose lookup failed is
+					// shown without lyrics and looked up again after lyricsRetryInterval
+					if track.Title != song && (track.Title != failed || !time.Now().Before(retryAt)) {
+						lyrics, err = nil, nil
+						if !track.IsEpisode() {
+							lyrics, err = source.GetLyrics(ctx, track.Artist, track.Title, track.Album, track.DurationMs)
+						}
 						if err != nil {
-							updateCh <- &LyricUpdate{
+							song, failed, retryAt = "", track.Title, time.Now().Add(lyricsRetryInterval)
+							send(&LyricUpdate{
 								IsError:  true,
 								ErrorMsg: fmt.Sprintf("Error getting lyrics: %v", err),
-							}
-							continue
+							})
+						} else {
+							song = track.Title
 						}
 					}
 
-					// Update the progress and signal for display update
-					currentProgressMs = track.ProgressMs
-					startTime = time.Now().Add(-time.Duration(currentProgressMs) * time.Millisecond)
-
-					// Signal for update
+					// Hand the poll to the loop, replacing one it hasn't taken yet
+					poll := lyricPoll{track: track, lyrics: lyrics, progressMs: track.ProgressMs, polledAt: time.Now()}
 					select {
-					case internalUpdateCh <- struct{}{}:
+					case <-polled:
 					default:
-						// Channel already has an update pending
 					}
+					polled <- poll
 				}
 			}
+		}(song, failed, retryAt, lyrics, track.IsPlaying)
+
+		// The state shown, updated by the polls
+		currentTrack := track
+		currentProgressMs := startTimeMs
+		startTime := time.Now().Add(-time.Duration(startTimeMs) * time.Millisecond)
+
+		// nextLine signals when the next line starts
+		nextLine := make(chan struct{}, 1)
+		var nextLineTimer *time.Timer
+		defer func() {
+			if nextLineTimer != nil {
+				nextLineTimer.Stop()
+			}
 		}()
+		signalNextLine := func() {
+			select {
+			case nextLine <- struct{}{}:
+			default:
+				// Channel already has an update pending
+			}
+		}
+		// Initial update
+		signalNextLine()
 
 		activeIndex := -1 // Start with -1 to ensure first line is sent
+		var plainLyrics *Lyrics
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-internalUpdateCh:
-				if lyrics == nil || len(lyrics.Lines) == 0 {
-					if updateCh == nil {
-						continue
-					}
-					updateCh <- &LyricUpdate{
-						Text: "No lyrics to display.",
-					}
-					continue
-				}
+			case poll := <-polled:
+				currentTrack, lyrics = poll.track, poll.lyrics
+				currentProgressMs = poll.progressMs
+				startTime = poll.polledAt.Add(-time.Duration(poll.progressMs) * time.Millisecond)
+			case <-nextLine:
+			}
 
-				// Find the current line based on the current progress
-				currentLineIndex = 0
-				for i, line := range lyrics.Lines {
-					if line.StartTimeMs <= currentProgressMs && currentProgressMs < line.EndTimeMs {
-						currentLineIndex = i
-						break
-					} else if line.StartTimeMs > currentProgressMs {
-						break
-					}
-					currentLineIndex = i
-				}
+			if lyrics == nil || len(lyrics.Lines) == 0 {
+				send(NoLyricsUpdate(currentTrack))
+				continue
+			}
 
-				if activeIndex == currentLineIndex {
-					continue
+			// Unsynced lyrics are shown whole, once, as no line is current
+			if !lyrics.Synced && !lyrics.Estimated {
+				if plainLyrics != lyrics {
+					plainLyrics = lyrics
+					activeIndex = -1
+					send(PlainLyricsUpdate(currentTrack, lyrics))
 				}
+				continue
+			}
+			plainLyrics = nil
 
-				// Send the current line to the channel
-				if currentLineIndex < len(lyrics.Lines) {
-					activeIndex = currentLineIndex
-					line := lyrics.Lines[currentLineIndex]
-					text := fmt.Sprintf("      %s      ", line.Text)
-
-					updateCh <- &LyricUpdate{
-						Lyrics:    lyrics,
-						Line:      &line,
-						LineIndex: currentLineIndex,
-						Text:      text,
-					}
+			// Find the current line based on the current progress; before the first line it is shown early
+			currentLineIndex := max(0, lyrics.LineIndexAt(currentProgressMs))
 
-					// Calculate when to display the next line
-					if currentLineIndex < len(lyrics.Lines)-1 {
-						nextLine := lyrics.Lines[currentLineIndex+1]
-						waitTime := time.Until(startTime.Add(time.Duration(nextLine.StartTimeMs) * time.Millisecond))
-
-						// Set a timer to update when it's time for the next line
-						if waitTime > 0 {
-							time.AfterFunc(waitTime, func() {
-								select {
-								case internalUpdateCh <- struct{}{}:
-								default:
-									// Channel already has an update pending
-								}
-							})
-						} else {
-							// If we're already past the next line's start time, update immediately
-							go func() {
-								internalUpdateCh <- struct{}{}
-							}()
-						}
+			if activeIndex == currentLineIndex {
+				continue
+			}
+
+			// Send the current line to the channel
+			if currentLineIndex < len(lyrics.Lines) {
+				activeIndex = currentLineIndex
+				send(LineUpdate(currentTrack, lyrics, currentLineIndex))
+
+				// Calculate when to display the next line
+				if currentLineIndex < len(lyrics.Lines)-1 {
+					next := lyrics.Lines[currentLineIndex+1]
+					waitTime := time.Until(startTime.Add(time.Duration(next.StartTimeMs) * time.Millisecond))
+
+					// Set a timer to update when it's time for the next line
+					if nextLineTimer != nil {
+						nextLineTimer.Stop()
+					}
+					if waitTime > 0 {
+						nextLineTimer = time.AfterFunc(waitTime, signalNextLine)
+					} else {
+						// If we're already past the next line's start time, update immediately
+						signalNextLine()
 					}
 				}
 			}
@@ -400,32 +715,187 @@ func (l *lyricUseCase) GetLyricChannel(ctx context.Context, startTimeMs int, pla
 	return updateCh
 }
 
-// DisplaySyncedLyrics displays the lyrics synchronized with the music.
-// It polls Spotify every 3 seconds to keep the lyrics in sync with the currently playing track.
-func (l *lyricUseCase) DisplaySyncedLyrics(ctx context.Context, lyrics *Lyrics, startTimeMs int, playerUseCase PlayerUseCase) {
-	if lyrics == nil || len(lyrics.Lines) == 0 {
-		fmt.Println("No lyrics to display.")
+// lyricsRetryInterval is how long LyricChannel waits before looking up lyrics that failed again,
+// so a track without lyrics isn't looked up on every poll.
+const lyricsRetryInterval = 10 * time.Second
+
+// lyricPoll is a poll of the playing track handed from the poller of LyricChannel to its loop,
+// with the lyrics of the track
+type lyricPoll struct {
+	track      *CurrentlyPlaying
+	lyrics     *Lyrics
+	progressMs int
+	polledAt   time.Time
+}
+
+// NothingPlayingUpdate returns the update sent while nothing is playing.
+func NothingPlayingUpdate() *LyricUpdate {
+	return &LyricUpdate{
+		IsError:        true,
+		NothingPlaying: true,
+		ErrorMsg:       "No track currently playing. Please start playing a track on Spotify.",
+	}
+}
+
+// NoLyricsUpdate returns the update sent for a track without lyrics; podcast episodes name the show.
+func NoLyricsUpdate(track *CurrentlyPlaying) *LyricUpdate {
+	if track != nil && track.IsEpisode() {
+		return &LyricUpdate{
+			Track: track,
+			Text:  fmt.Sprintf("Lyrics aren't available for podcasts (%s – %s).", track.Title, track.Show),
+		}
+	}
+	return &LyricUpdate{Text: "No lyrics to display."}
+}
+
+// PlainLyricsUpdate returns the update showing unsynced lyrics, which have no current line.
+func PlainLyricsUpdate(track *CurrentlyPlaying, lyrics *Lyrics) *LyricUpdate {
+	return &LyricUpdate{
+		Track:     track,
+		Lyrics:    lyrics,
+		LineIndex: -1,
+		Text:      "Only unsynced lyrics found.",
+	}
+}
+
+// LineUpdate returns the update showing the line at index of the lyrics of track.
+func LineUpdate(track *CurrentlyPlaying, lyrics *Lyrics, index int) *LyricUpdate {
+	line := lyrics.Lines[index]
+	return &LyricUpdate{
+		Track:     track,
+		Lyrics:    lyrics,
+		Line:      &line,
+		LineIndex: index,
+		Text:      fmt.Sprintf("      %s      ", line.Text),
+	}
+}
+
+// parseSyncedLyrics parses lyrics in the LRC format into lines, shifted by their offset tag.
+// A part marker applies until the next one.
+func parseSyncedLyrics(text string) []Line {
+	offsetMs, _ := strconv.Atoi(strings.TrimPrefix(lrc.Tags(text)["offset"], "+"))
+
+	lines := []Line{}
+	part := ""
+	for _, line := range lrc.Parse(text) {
+		text := line.Text
+		if marker, rest, ok := parsePart(text); ok {
+			part, text = marker, rest
+		}
+		var words []Word
+		for _, word := range line.Words {
+			words = append(words, Word{StartTimeMs: max(0, word.StartTimeMs-offsetMs), Text: word.Text})
+		}
+		lines = append(lines, Line{
+			StartTimeMs: max(0, line.StartTimeMs-offsetMs),
+			EndTimeMs:   0, // Will be set below
+			Text:        text,
+			Part:        part,
+			Words:       trimWords(words, text),
+		})
+	}
+
+	// Set the end time for each line
+	for i := 0; i < len(lines)-1; i++ {
+		lines[i].EndTimeMs = lines[i+1].StartTimeMs
+	}
+	if len(lines) > 0 {
+		// Set a default end time for the last line
+		lines[len(lines)-1].EndTimeMs = lines[len(lines)-1].StartTimeMs + 5000
+	}
+	return lines
+}
+
+// parsePlainLyrics splits unsynced lyrics into lines without times, dropping the blank lines
+// around them but keeping the ones between verses.
+func parsePlainLyrics(text string) []Line {
+	text = strings.Trim(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if strings.TrimSpace(text) == "" {
+		return []Line{}
+	}
+
+	lines := []Line{}
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, Line{Text: strings.TrimSpace(line)})
+	}
+	return lines
+}
+
+// EstimateTiming spreads the lines of unsynced lyrics evenly over a track of durationMs, so they
+// can be followed roughly. Lyrics that are synced or of a track of unknown length stay as they are.
+func (l *Lyrics) EstimateTiming(durationMs int) {
+	if l.Synced || durationMs <= 0 || len(l.Lines) == 0 {
 		return
 	}
 
-	// Get the lyric updates channel
-	updateCh := l.GetLyricChannel(ctx, startTimeMs, playerUseCase)
+	for i := range l.Lines {
+		l.Lines[i].StartTimeMs = i * durationMs / len(l.Lines)
+		l.Lines[i].EndTimeMs = (i + 1) * durationMs / len(l.Lines)
+	}
+	l.Estimated = true
+}
+
+// trimWords trims the words to the text of their line, which may have lost a part marker or
+// spaces in front of them. Words left without text are dropped.
+func trimWords(words []Word, text string) []Word {
+	// Spaces cut off the end of the text are cut off the last word too
+	if n := len(words); n > 0 && !strings.HasSuffix(text, " ") {
+		words[n-1].Text = strings.TrimRight(words[n-1].Text, " ")
+	}
+
+	length := 0
+	for _, word := range words {
+		length += len(word.Text)
+	}
+	for excess := length - len(text); excess > 0 && len(words) > 0; {
+		cut := min(excess, len(words[0].Text))
+		words[0].Text = words[0].Text[cut:]
+		excess -= cut
+		if words[0].Text == "" {
+			words = words[1:]
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	return words
+}
+
+// partMarkers lists the duet part markers recognized at the start of a line.
+var partMarkers = []string{"M", "F", "D", "V1", "V2"}
 
-	// Process updates from the channel
-	for update := range updateCh {
-		if update.IsError {
-			fmt.Printf("\r\033[K%s", update.ErrorMsg)
-			continue
+// parsePart splits a duet part marker such as "M:" or "v1:" off the start of a lyric line.
+func parsePart(text string) (part, rest string, ok bool) {
+	marker, rest, found := strings.Cut(strings.TrimSpace(text), ":")
+	if !found {
+		return "", text, false
+	}
+
+	marker = strings.ToUpper(strings.TrimSpace(marker))
+	for _, m := range partMarkers {
+		if marker == m {
+			return marker, strings.TrimSpace(rest), true
 		}
+	}
 
-		fmt.Print("\r\033[K", update.Text)
+	return "", text, false
+}
 
-		// Write the current line to a file for external use
-		if update.Text != "" {
-			err := os.WriteFile("/tmp/current-lyric.txt", []byte(update.Text), 0644)
-			if err != nil {
-				fmt.Printf("\nError writing to file: %v", err)
-			}
+// LineIndexAt returns the index of the line sung at progressMs, or -1 before the first line.
+// The lines are ordered by start time, so the line is found by binary search.
+func (l *Lyrics) LineIndexAt(progressMs int) int {
+	next := sort.Search(len(l.Lines), func(i int) bool {
+		return l.Lines[i].StartTimeMs > progressMs
+	})
+	return next - 1
+}
+
+// HasParts reports whether any line of the lyrics is assigned to a duet part.
+func (l *Lyrics) HasParts() bool {
+	for _, line := range l.Lines {
+		if line.Part != "" {
+			return true
 		}
 	}
+	return false
 }
@@ -0,0 +1,279 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/spotify/spotifytest"
+)
+
+func TestGetLyricsPrefersSyncedLyrics(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.HandleFixture(http.MethodGet, "/api/search", "lrclib_search")
+
+	lyricUseCase := usecase.NewLyricUseCase(server.HTTPClient(), nil, "", false)
+
+	lyrics, err := lyricUseCase.GetLyrics(context.Background(), "Queen", "Bohemian Rhapsody", "A Night At The Opera", 0)
+	if err != nil {
+		t.Fatalf("GetLyrics() error = %v", err)
+	}
+
+	if lyrics.ID != 102 || !lyrics.Synced {
+		t.Fatalf("GetLyrics() picked %d synced %v, want the synced lyrics 102", lyrics.ID, lyrics.Synced)
+	}
+
+	want := []usecase.Line{
+		{StartTimeMs: 600, EndTimeMs: 4150, Text: " Is this the real life?"},
+		{StartTimeMs: 4150, EndTimeMs: 7500, Text: " Is this just fantasy?"},
+		{StartTimeMs: 7500, EndTimeMs: 12500, Text: " Caught in a landslide"},
+	}
+	if len(lyrics.Lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(lyrics.Lines), len(want))
+	}
+	for i, line := range lyrics.Lines {
+		if !reflect.DeepEqual(line, want[i]) {
+			t.Errorf("line %d = %+v, want %+v", i, line, want[i])
+		}
+	}
+
+	// A second lookup is answered from the cache
+	if _, err := lyricUseCase.GetLyrics(context.Background(), "Queen", "Bohemian Rhapsody", "", 0); err != nil {
+		t.Fatalf("GetLyrics() error = %v", err)
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("got %d requests, want the second lookup to be cached", n)
+	}
+}
+
+func TestGetLyricsNotFound(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.Handle(http.MethodGet, "/api/search", http.StatusOK, "[]")
+
+	lyricUseCase := usecase.NewLyricUseCase(server.HTTPClient(), nil, "", false)
+
+	if _, err := lyricUseCase.GetLyrics(context.Background(), "Nobody", "Nothing", "", 0); err == nil {
+		t.Error("GetLyrics() error = nil, want no lyrics found")
+	}
+}
+
+func TestGetLyricsPrefersLocalFiles(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.HandleFixture(http.MethodGet, "/api/search", "lrclib_search")
+
+	dir := t.TempDir()
+	lrcFile := "\uFEFF[ar:Queen]\n[ti:Bohemian Rhapsody]\n[offset:+500]\n[00:01.00]Mama\n[00:04.00]F: <00:04.00>Just <00:04.50>killed a man \n"
+	if err := os.WriteFile(filepath.Join(dir, "queen - bohemian rhapsody.lrc"), []byte(lrcFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lyricUseCase := usecase.NewLyricUseCase(server.HTTPClient(), nil, dir, false)
+
+	lyrics, err := lyricUseCase.GetLyrics(context.Background(), "Queen", "Bohemian Rhapsody", "", 0)
+	if err != nil {
+		t.Fatalf("GetLyrics() error = %v", err)
+	}
+	want := []usecase.Line{
+		{StartTimeMs: 500, EndTimeMs: 3500, Text: "Mama"},
+		{StartTimeMs: 3500, EndTimeMs: 8500, Text: "Just killed a man", Part: "F", Words: []usecase.Word{{3500, "Just "}, {4000, "killed a man"}}},
+	}
+	if len(lyrics.Lines) != len(want) || !reflect.DeepEqual(lyrics.Lines, want) {
+		t.Errorf("GetLyrics() lines = %+v, want %+v from the local file", lyrics.Lines, want)
+	}
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("got %d requests to lrclib.net, want the local file to be used", n)
+	}
+
+	// Tracks without a local file are still looked up online
+	if _, err := lyricUseCase.GetLyrics(context.Background(), "Queen", "Somebody to Love", "", 0); err != nil {
+		t.Fatalf("GetLyrics() error = %v", err)
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("got %d requests to lrclib.net, want 1", n)
+	}
+}
+
+// memoryMatches keeps the chosen lyrics in memory.
+type memoryMatches map[string]int
+
+func (m memoryMatches) GetLyricMatch(ctx context.Context, artist, title string) (int, error) {
+	return m[artist+"|"+title], nil
+}
+
+func (m memoryMatches) SaveLyricMatch(ctx context.Context, artist, title string, id int) error {
+	m[artist+"|"+title] = id
+	return nil
+}
+
+func TestChooseLyricsReplacesThePickedLyrics(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.HandleFixture(http.MethodGet, "/api/search", "lrclib_search")
+
+	ctx := context.Background()
+	lyricUseCase := usecase.NewLyricUseCase(server.HTTPClient(), memoryMatches{}, "", false)
+	if lyrics, err := lyricUseCase.GetLyrics(ctx, "Queen", "Bohemian Rhapsody", "", 0); err != nil || lyrics.ID != 102 {
+		t.Fatalf("GetLyrics() = %+v, %v, want the synced lyrics 102", lyrics, err)
+	}
+
+	matches, err := lyricUseCase.SearchLyrics(ctx, "Queen", "Bohemian Rhapsody", 0)
+	if err != nil {
+		t.Fatalf("SearchLyrics() error = %v", err)
+	}
+	if len(matches) != 2 || matches[0].Picked || !matches[1].Picked || matches[1].DurationMs != 355000 {
+		t.Fatalf("SearchLyrics() = %+v, want 2 matches with 102 picked", matches)
+	}
+
+	if err := lyricUseCase.ChooseLyrics(ctx, "Queen", "Bohemian Rhapsody", 101); err != nil {
+		t.Fatalf("ChooseLyrics() error = %v", err)
+	}
+	lyrics, err := lyricUseCase.GetLyrics(ctx, "Queen", "Bohemian Rhapsody", "", 0)
+	if err != nil || lyrics.ID != 101 || lyrics.Synced {
+		t.Fatalf("GetLyrics() = %+v, %v, want the chosen lyrics 101", lyrics, err)
+	}
+}
+
+func TestGetLyricsPrefersTheVersionOfTheTrackDuration(t *testing.T) {
+	results := `[
+		{"id": 1, "trackName": "Song (Live)", "artistName": "Band", "duration": 400, "syncedLyrics": "[00:01.00]Live"},
+		{"id": 2, "trackName": "Song", "artistName": "Band", "duration": 200, "syncedLyrics": "[00:01.00]Studio"},
+		{"id": 3, "trackName": "Song (Edit)", "artistName": "Band", "duration": 150, "plainLyrics": "Edit"}
+	]`
+
+	tests := []struct {
+		durationMs int
+		want       int
+	}{
+		{0, 1},      // Unknown: the first synced lyrics
+		{399000, 1}, // Within the tolerance
+		{201500, 2}, // Within the tolerance
+		{150000, 3}, // Unsynced lyrics of the same version beat synced ones of another
+		{250000, 2}, // Nothing within the tolerance: the closest synced lyrics
+	}
+	for _, tt := range tests {
+		server := spotifytest.NewServer()
+		server.Handle(http.MethodGet, "/api/search", http.StatusOK, results)
+
+		lyrics, err := usecase.NewLyricUseCase(server.HTTPClient(), nil, "", false).GetLyrics(context.Background(), "Band", "Song", "", tt.durationMs)
+		server.Close()
+		if err != nil {
+			t.Fatalf("GetLyrics() error = %v", err)
+		}
+		if lyrics.ID != tt.want {
+			t.Errorf("GetLyrics() for a %d ms track picked %d, want %d", tt.durationMs, lyrics.ID, tt.want)
+		}
+	}
+}
+
+func TestGetLyricsUsesTheExactMatch(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.Handle(http.MethodGet, "/api/get", http.StatusOK,
+		`{"id": 7, "trackName": "Song", "artistName": "Band", "albumName": "LP", "duration": 200, "syncedLyrics": "[00:01.00]Exact"}`)
+	server.Handle(http.MethodGet, "/api/get", http.StatusNotFound, `{"code": 404, "name": "TrackNotFound"}`)
+	server.HandleFixture(http.MethodGet, "/api/search", "lrclib_search")
+
+	lyricUseCase := usecase.NewLyricUseCase(server.HTTPClient(), nil, "", false)
+	lyrics, err := lyricUseCase.GetLyrics(context.Background(), "Band", "Song", "LP", 199600)
+	if err != nil || lyrics.ID != 7 {
+		t.Fatalf("GetLyrics() = %+v, %v, want the exact match 7", lyrics, err)
+	}
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Query != "album_name=LP&artist_name=Band&duration=200&track_name=Song" {
+		t.Errorf("requests = %+v, want only the exact match to be asked for", requests)
+	}
+
+	// Tracks lrclib.net has no exact match for are searched for
+	if _, err := lyricUseCase.GetLyrics(context.Background(), "Queen", "Bohemian Rhapsody", "A Night At The Opera", 355000); err != nil {
+		t.Fatalf("GetLyrics() error = %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 3 || requests[2].Path != "/api/search" {
+		t.Errorf("requests = %+v, want the search after the exact match", requests)
+	}
+}
+
+func TestGetLyricsEstimatesPlainLyrics(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.Handle(http.MethodGet, "/api/search", http.StatusOK,
+		`[{"id": 7, "trackName": "Song", "artistName": "Band", "duration": 40, "plainLyrics": "\nOne\r\nTwo\n\nThree\n", "syncedLyrics": null}]`)
+
+	lyrics, err := usecase.NewLyricUseCase(server.HTTPClient(), nil, "", true).GetLyrics(context.Background(), "Band", "Song", "", 0)
+	if err != nil {
+		t.Fatalf("GetLyrics() error = %v", err)
+	}
+	if lyrics.Synced || !lyrics.Estimated {
+		t.Fatalf("GetLyrics() synced %v estimated %v, want estimated unsynced lyrics", lyrics.Synced, lyrics.Estimated)
+	}
+	want := []usecase.Line{
+		{StartTimeMs: 0, EndTimeMs: 10000, Text: "One"},
+		{StartTimeMs: 10000, EndTimeMs: 20000, Text: "Two"},
+		{StartTimeMs: 20000, EndTimeMs: 30000, Text: ""},
+		{StartTimeMs: 30000, EndTimeMs: 40000, Text: "Three"},
+	}
+	if len(lyrics.Lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(lyrics.Lines), len(want))
+	}
+	for i, line := range lyrics.Lines {
+		if !reflect.DeepEqual(line, want[i]) {
+			t.Errorf("line %d = %+v, want %+v", i, line, want[i])
+		}
+	}
+}
+
+func TestLineIndexAt(t *testing.T) {
+	lyrics := &usecase.Lyrics{Lines: []usecase.Line{
+		{StartTimeMs: 600, EndTimeMs: 4150},
+		{StartTimeMs: 4150, EndTimeMs: 7500},
+		{StartTimeMs: 7500, EndTimeMs: 12500},
+	}}
+
+	tests := []struct {
+		progressMs int
+		want       int
+	}{
+		{0, -1},
+		{600, 0},
+		{4149, 0},
+		{4150, 1},
+		{7500, 2},
+		{60000, 2},
+	}
+	for _, tt := range tests {
+		if got := lyrics.LineIndexAt(tt.progressMs); got != tt.want {
+			t.Errorf("LineIndexAt(%d) = %d, want %d", tt.progressMs, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkLineIndexAt(b *testing.B) {
+	lyrics := &usecase.Lyrics{Lines: make([]usecase.Line, 10000)}
+	for i := range lyrics.Lines {
+		lyrics.Lines[i] = usecase.Line{StartTimeMs: i * 1000, EndTimeMs: (i + 1) * 1000}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lyrics.LineIndexAt(i % 10000 * 1000)
+	}
+}
+
+func TestLyricChannelClosesWhenNothingPlays(t *testing.T) {
+	player, server := newPlayerUseCase(t)
+	server.Handle(http.MethodGet, "/v1/me/player/currently-playing", http.StatusNoContent, "")
+
+	var updates []*usecase.LyricUpdate
+	for update := range usecase.LyricChannel(context.Background(), 0, player, nil) {
+		updates = append(updates, update)
+	}
+	if len(updates) != 1 || !updates[0].NothingPlaying {
+		t.Errorf("got updates %+v, want the nothing playing update before the channel closes", updates)
+	}
+}
@@ -2,127 +2,524 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/repository"
 )
 
+// ErrNothingPlaying is returned when no track is playing.
+var ErrNothingPlaying = errors.New("no track currently playing")
+
 // PlayerUseCase defines the interface for player-related use cases.
 type PlayerUseCase interface {
 	// GetCurrentlyPlayingDetails retrieves detailed information about the user's currently playing track.
 	GetCurrentlyPlayingDetails(ctx context.Context) (*CurrentlyPlaying, error)
+
+	// GetPlaybackState retrieves the full playback state, including the active device.
+	GetPlaybackState(ctx context.Context) (*PlaybackState, error)
+
+	// GetRecentlyPlayed retrieves up to limit of the user's most recently played tracks, newest first.
+	GetRecentlyPlayed(ctx context.Context, limit int) ([]PlayedTrack, error)
+
+	// AddToQueue adds the item with the given Spotify URI to the end of the playback queue.
+	AddToQueue(ctx context.Context, uri string) error
+
+	// GetQueue retrieves the tracks queued to play after the current one.
+	GetQueue(ctx context.Context) ([]Track, error)
+
+	// GetDevices retrieves the user's available Spotify Connect devices.
+	GetDevices(ctx context.Context) ([]Device, error)
+
+	// Play resumes playback on the active device.
+	Play(ctx context.Context) error
+
+	// PlayURI starts playing a track, album, artist or playlist on the active device.
+	PlayURI(ctx context.Context, uri string) error
+
+	// PlayTrackAt starts playing a track or episode from the given position on the active device.
+	PlayTrackAt(ctx context.Context, uri string, positionMs int) error
+
+	// Pause pauses playback on the active device.
+	Pause(ctx context.Context) error
+
+	// Next skips to the next track.
+	Next(ctx context.Context) error
+
+	// Previous skips to the previous track.
+	Previous(ctx context.Context) error
+
+	// Seek seeks to the given position in the currently playing track.
+	Seek(ctx context.Context, positionMs int) error
+
+	// SetVolume sets the volume of the active device, from 0 to 100.
+	SetVolume(ctx context.Context, percent int) error
+
+	// SetShuffle turns shuffle on or off.
+	SetShuffle(ctx context.Context, shuffle bool) error
+
+	// SetRepeat sets the repeat mode: "track", "context" or "off".
+	SetRepeat(ctx context.Context, mode string) error
+
+	// GetBeats retrieves the start times of the beats of a track in milliseconds,
+	// from Spotify's audio analysis.
+	GetBeats(ctx context.Context, trackID string) ([]int, error)
 }
 
 // CurrentlyPlaying represents detailed information about the currently playing track.
+// For podcast episodes, Title is the episode title and Artist and Show hold the show name.
 type CurrentlyPlaying struct {
-	IsPlaying   bool   `json:"is_playing"`
-	ProgressMs  int    `json:"progress_ms"`
-	Title       string `json:"title"`
-	Artist      string `json:"artist"`
-	Album       string `json:"album"`
-	ArtistNames []string
-	DurationMs  int `json:"duration_ms"`
+	ID          string   `json:"id"`
+	URI         string   `json:"uri"`
+	IsPlaying   bool     `json:"is_playing"`
+	ProgressMs  int      `json:"progress_ms"`
+	Title       string   `json:"title"`
+	Artist      string   `json:"artist"`
+	Album       string   `json:"album"`
+	AlbumID     string   `json:"album_id"`
+	ArtistNames []string `json:"artists,omitempty"`
+	DurationMs  int      `json:"duration_ms"`
+	// Type is the type of the playing item: "track" or "episode"
+	Type string `json:"type"`
+	Show string `json:"show,omitempty"`
+	// ResumePointMs is where Spotify resumes the episode, saved across devices
+	ResumePointMs int `json:"resume_point_ms,omitempty"`
+	// ImageURL is the smallest cover of the album or episode
+	ImageURL string `json:"image_url,omitempty"`
+	Explicit bool   `json:"explicit"`
+}
+
+// Playing item types reported by Spotify.
+const (
+	ItemTypeTrack   = "track"
+	ItemTypeEpisode = "episode"
+)
+
+// IsEpisode reports whether the playing item is a podcast episode.
+func (c *CurrentlyPlaying) IsEpisode() bool {
+	return c.Type == ItemTypeEpisode
+}
+
+// spotifyPlayingItem is the track or episode object returned by the Spotify Web API
+// for the item being played.
+type spotifyPlayingItem struct {
+	spotifyTrack
+	Images []spotifyImage `json:"images"`
+	Show   struct {
+		Name string `json:"name"`
+	} `json:"show"`
+	ResumePoint struct {
+		ResumePositionMs int `json:"resume_position_ms"`
+	} `json:"resume_point"`
+}
+
+// toCurrentlyPlaying converts the playing item to a CurrentlyPlaying of the given type.
+func (i spotifyPlayingItem) toCurrentlyPlaying(itemType string, isPlaying bool, progressMs int) *CurrentlyPlaying {
+	track := i.toTrack()
+	artistNames := make([]string, len(i.Artists))
+	for n, artist := range i.Artists {
+		artistNames[n] = artist.Name
+	}
+
+	result := &CurrentlyPlaying{
+		ID:          track.ID,
+		URI:         track.URI,
+		IsPlaying:   isPlaying,
+		ProgressMs:  progressMs,
+		Title:       track.Title,
+		Artist:      track.Artist,
+		Album:       track.Album,
+		AlbumID:     i.Album.ID,
+		ArtistNames: artistNames,
+		DurationMs:  track.DurationMs,
+		Type:        ItemTypeTrack,
+		ImageURL:    smallestImageURL(i.Album.Images),
+		Explicit:    track.Explicit,
+	}
+
+	if itemType == ItemTypeEpisode {
+		// Episodes have a show instead of artists and an album
+		result.Type = ItemTypeEpisode
+		result.Show = i.Show.Name
+		result.Artist = i.Show.Name
+		result.ArtistNames = []string{i.Show.Name}
+		result.ResumePointMs = i.ResumePoint.ResumePositionMs
+		result.ImageURL = smallestImageURL(i.Images)
+	}
+
+	return result
+}
+
+// PlaybackState represents the user's playback state on Spotify Connect.
+type PlaybackState struct {
+	CurrentlyPlaying
+	Device           Device `json:"device"`
+	ShuffleState     bool   `json:"shuffle_state"`
+	RepeatState      string `json:"repeat_state"`
+	ContextType      string `json:"context_type"`
+	ContextURI       string `json:"context_uri"`
+	IsPrivateSession bool   `json:"is_private_session"`
+	// Owner is the display name of the account controlling playback.
+	Owner string `json:"owner"`
+}
+
+// PlayedTrack represents a track in the user's play history.
+type PlayedTrack struct {
+	Track
+	PlayedAt   time.Time `json:"played_at"`
+	ContextURI string    `json:"context_uri,omitempty"` // Album or playlist the track was played from
+}
+
+// Device represents a Spotify Connect device.
+type Device struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	IsActive      bool   `json:"is_active"`
+	VolumePercent int    `json:"volume_percent"`
+}
+
+// IsSharedSpeaker reports whether the device is a speaker, TV or similar
+// device that is usually shared by a household rather than a personal one.
+// The Web API does not expose Spotify Jam membership, so this is the best
+// available hint that other people may be listening along.
+func (d Device) IsSharedSpeaker() bool {
+	switch strings.ToLower(d.Type) {
+	case "", "computer", "smartphone", "tablet":
+		return false
+	}
+	return true
+}
+
+// SessionDescription returns a human-readable description of the listening session.
+func (s *PlaybackState) SessionDescription() string {
+	switch {
+	case s.IsPrivateSession:
+		return "Private session"
+	case s.Device.IsSharedSpeaker():
+		return "Shared speaker"
+	default:
+		return "Solo"
+	}
 }
 
 // playerUseCase implements the PlayerUseCase interface.
 type playerUseCase struct {
-	authUseCase AuthUseCase
+	authRepo repository.AuthRepository
+	client   repository.SpotifyClient
+
+	// owner caches the display name of the account logged in with ownerToken, the refresh
+	// token, so it is looked up again after logging in to another account
+	owner      string
+	ownerToken string
+	ownerLock  sync.Mutex
 }
 
 // NewPlayerUseCase creates a new instance of PlayerUseCase.
-func NewPlayerUseCase(authUseCase AuthUseCase) PlayerUseCase {
+func NewPlayerUseCase(authRepo repository.AuthRepository, client repository.SpotifyClient) PlayerUseCase {
 	return &playerUseCase{
-		authUseCase: authUseCase,
+		authRepo: authRepo,
+		client:   client,
 	}
 }
 
 // GetCurrentlyPlayingDetails retrieves detailed information about the user's currently playing track.
 func (p *playerUseCase) GetCurrentlyPlayingDetails(ctx context.Context) (*CurrentlyPlaying, error) {
-	// Get the token
-	auth, err := p.authUseCase.GetToken(ctx)
+	var trackResponse struct {
+		IsPlaying            bool               `json:"is_playing"`
+		ProgressMs           int                `json:"progress_ms"`
+		CurrentlyPlayingType string             `json:"currently_playing_type"`
+		Item                 spotifyPlayingItem `json:"item"`
+	}
+
+	// Episodes are only returned when asked for
+	err := p.client.Get(ctx, "/me/player/currently-playing?additional_types=episode", &trackResponse)
+	if errors.Is(err, repository.ErrNoContent) {
+		return nil, ErrNothingPlaying
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get token: %w", err)
+		return nil, fmt.Errorf("failed to get currently playing track: %w", err)
 	}
 
-	// Check if the token is expired and attempt to refresh it
-	if auth.IsExpired() {
-		// Try to refresh the token
-		auth, err = p.authUseCase.RefreshToken(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to refresh token: %w", err)
-		}
+	// Create the result
+	return trackResponse.Item.toCurrentlyPlaying(trackResponse.CurrentlyPlayingType,
+		trackResponse.IsPlaying, trackResponse.ProgressMs), nil
+}
+
+// AddToQueue adds the item with the given Spotify URI to the end of the playback queue.
+func (p *playerUseCase) AddToQueue(ctx context.Context, uri string) error {
+	params := url.Values{}
+	params.Set("uri", uri)
+
+	if err := p.client.Post(ctx, "/me/player/queue?"+params.Encode(), nil, nil); err != nil {
+		return fmt.Errorf("failed to add to queue: %w", err)
 	}
 
-	// Make a request to Spotify's API
-	apiURL := "https://api.spotify.com/v1/me/player/currently-playing"
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	return nil
+}
+
+// GetQueue retrieves the tracks queued to play after the current one.
+func (p *playerUseCase) GetQueue(ctx context.Context) ([]Track, error) {
+	var queueResponse struct {
+		Queue []spotifyTrack `json:"queue"`
+	}
+	err := p.client.Get(ctx, "/me/player/queue", &queueResponse)
+	if errors.Is(err, repository.ErrNoContent) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create API request: %w", err)
+		return nil, fmt.Errorf("failed to get queue: %w", err)
 	}
 
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("%s %s", auth.TokenType, auth.AccessToken))
+	tracks := make([]Track, len(queueResponse.Queue))
+	for i, item := range queueResponse.Queue {
+		tracks[i] = item.toTrack()
+	}
+	return tracks, nil
+}
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// GetDevices retrieves the user's available Spotify Connect devices.
+func (p *playerUseCase) GetDevices(ctx context.Context) ([]Device, error) {
+	var devicesResponse struct {
+		Devices []Device `json:"devices"`
+	}
+	if err := p.client.Get(ctx, "/me/player/devices", &devicesResponse); err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+	return devicesResponse.Devices, nil
+}
+
+// GetPlaybackState retrieves the full playback state, including the active device.
+func (p *playerUseCase) GetPlaybackState(ctx context.Context) (*PlaybackState, error) {
+	var stateResponse struct {
+		IsPlaying            bool               `json:"is_playing"`
+		ProgressMs           int                `json:"progress_ms"`
+		ShuffleState         bool               `json:"shuffle_state"`
+		RepeatState          string             `json:"repeat_state"`
+		CurrentlyPlayingType string             `json:"currently_playing_type"`
+		Item                 spotifyPlayingItem `json:"item"`
+		Context              *struct {
+			Type string `json:"type"`
+			URI  string `json:"uri"`
+		} `json:"context"`
+		Device struct {
+			ID               string `json:"id"`
+			Name             string `json:"name"`
+			Type             string `json:"type"`
+			IsActive         bool   `json:"is_active"`
+			IsPrivateSession bool   `json:"is_private_session"`
+			VolumePercent    int    `json:"volume_percent"`
+		} `json:"device"`
+	}
+	err := p.client.Get(ctx, "/me/player?additional_types=episode", &stateResponse)
+	if errors.Is(err, repository.ErrNoContent) {
+		return nil, ErrNothingPlaying
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get currently playing track: %w", err)
+		return nil, fmt.Errorf("failed to get playback state: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Check for error response
-	if resp.StatusCode == http.StatusNoContent {
-		return nil, fmt.Errorf("no track currently playing")
+	state := &PlaybackState{
+		CurrentlyPlaying: *stateResponse.Item.toCurrentlyPlaying(stateResponse.CurrentlyPlayingType,
+			stateResponse.IsPlaying, stateResponse.ProgressMs),
+		Device: Device{
+			ID:            stateResponse.Device.ID,
+			Name:          stateResponse.Device.Name,
+			Type:          stateResponse.Device.Type,
+			IsActive:      stateResponse.Device.IsActive,
+			VolumePercent: stateResponse.Device.VolumePercent,
+		},
+		ShuffleState:     stateResponse.ShuffleState,
+		RepeatState:      stateResponse.RepeatState,
+		IsPrivateSession: stateResponse.Device.IsPrivateSession,
 	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	if stateResponse.Context != nil {
+		state.ContextType = stateResponse.Context.Type
+		state.ContextURI = stateResponse.Context.URI
 	}
 
-	// Read the response
-	body, err := ioutil.ReadAll(resp.Body)
+	state.Owner = p.getOwner(ctx)
+
+	return state, nil
+}
+
+// getOwner returns the display name of the account that owns the token, which is
+// the one controlling the active device. A failure here only loses the owner
+// label, so it is not fatal.
+func (p *playerUseCase) getOwner(ctx context.Context) string {
+	auth, err := p.authRepo.GetToken(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read API response: %w", err)
+		return ""
 	}
 
-	// Parse the response
-	var trackResponse struct {
-		IsPlaying  bool `json:"is_playing"`
-		ProgressMs int  `json:"progress_ms"`
-		Item       struct {
-			Name       string `json:"name"`
-			DurationMs int    `json:"duration_ms"`
-			Album      struct {
-				Name string `json:"name"`
-			} `json:"album"`
-			Artists []struct {
-				Name string `json:"name"`
-			} `json:"artists"`
-		} `json:"item"`
+	p.ownerLock.Lock()
+	defer p.ownerLock.Unlock()
+
+	if p.owner != "" && p.ownerToken == auth.RefreshToken {
+		return p.owner
 	}
-	if err := json.Unmarshal(body, &trackResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse API response: %w", err)
+
+	var profile struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := p.client.Get(ctx, "/me", &profile); err != nil {
+		return ""
 	}
 
-	// Extract artist names
-	artistNames := make([]string, len(trackResponse.Item.Artists))
-	for i, artist := range trackResponse.Item.Artists {
-		artistNames[i] = artist.Name
+	p.owner, p.ownerToken = profile.DisplayName, auth.RefreshToken
+	if p.owner == "" {
+		p.owner = profile.ID
 	}
 
-	// Create the result
-	result := &CurrentlyPlaying{
-		IsPlaying:   trackResponse.IsPlaying,
-		ProgressMs:  trackResponse.ProgressMs,
-		Title:       trackResponse.Item.Name,
-		Artist:      strings.Join(artistNames, ", "),
-		Album:       trackResponse.Item.Album.Name,
-		ArtistNames: artistNames,
-		DurationMs:  trackResponse.Item.DurationMs,
+	return p.owner
+}
+
+// maxRecentlyPlayed is the maximum number of tracks Spotify returns from the play history.
+const maxRecentlyPlayed = 50
+
+// GetRecentlyPlayed retrieves up to limit of the user's most recently played tracks, newest first.
+func (p *playerUseCase) GetRecentlyPlayed(ctx context.Context, limit int) ([]PlayedTrack, error) {
+	limit = max(1, min(limit, maxRecentlyPlayed))
+
+	type item struct {
+		Track    spotifyTrack `json:"track"`
+		PlayedAt time.Time    `json:"played_at"`
+		Context  *struct {
+			URI string `json:"uri"`
+		} `json:"context"`
+	}
+	path := fmt.Sprintf("/me/player/recently-played?limit=%d", limit)
+	items, err := repository.CollectPages[item](ctx, p.client, path, "", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recently played tracks: %w", err)
+	}
+
+	tracks := make([]PlayedTrack, len(items))
+	for i, item := range items {
+		tracks[i] = PlayedTrack{
+			Track:    item.Track.toTrack(),
+			PlayedAt: item.PlayedAt,
+		}
+		if item.Context != nil {
+			tracks[i].ContextURI = item.Context.URI
+		}
+	}
+
+	return tracks, nil
+}
+
+// PlayURI starts playing a track, album, artist or playlist on the active device.
+func (p *playerUseCase) PlayURI(ctx context.Context, uri string) error {
+	// Tracks are played directly, everything else is played as a context
+	payload := map[string]interface{}{"context_uri": uri}
+	if strings.HasPrefix(uri, "spotify:track:") || strings.HasPrefix(uri, "spotify:episode:") {
+		payload = map[string]interface{}{"uris": []string{uri}}
+	}
+
+	return p.startPlayback(ctx, payload)
+}
+
+// PlayTrackAt starts playing a track or episode from the given position on the active device.
+func (p *playerUseCase) PlayTrackAt(ctx context.Context, uri string, positionMs int) error {
+	return p.startPlayback(ctx, map[string]interface{}{
+		"uris":        []string{uri},
+		"position_ms": max(positionMs, 0),
+	})
+}
+
+// startPlayback sends a play request with the given body to the active device.
+func (p *playerUseCase) startPlayback(ctx context.Context, payload map[string]interface{}) error {
+	if err := p.client.Put(ctx, "/me/player/play", payload, nil); err != nil {
+		return fmt.Errorf("failed to start playback: %w", err)
+	}
+
+	return nil
+}
+
+// Play resumes playback on the active device.
+func (p *playerUseCase) Play(ctx context.Context) error {
+	return p.sendPlayerCommand(ctx, http.MethodPut, "/me/player/play")
+}
+
+// Pause pauses playback on the active device.
+func (p *playerUseCase) Pause(ctx context.Context) error {
+	return p.sendPlayerCommand(ctx, http.MethodPut, "/me/player/pause")
+}
+
+// Next skips to the next track.
+func (p *playerUseCase) Next(ctx context.Context) error {
+	return p.sendPlayerCommand(ctx, http.MethodPost, "/me/player/next")
+}
+
+// Previous skips to the previous track.
+func (p *playerUseCase) Previous(ctx context.Context) error {
+	return p.sendPlayerCommand(ctx, http.MethodPost, "/me/player/previous")
+}
+
+// Seek seeks to the given position in the currently playing track.
+func (p *playerUseCase) Seek(ctx context.Context, positionMs int) error {
+	if positionMs < 0 {
+		positionMs = 0
+	}
+	return p.sendPlayerCommand(ctx, http.MethodPut, fmt.Sprintf("/me/player/seek?position_ms=%d", positionMs))
+}
+
+// SetVolume sets the volume of the active device, from 0 to 100.
+func (p *playerUseCase) SetVolume(ctx context.Context, percent int) error {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	return p.sendPlayerCommand(ctx, http.MethodPut, fmt.Sprintf("/me/player/volume?volume_percent=%d", percent))
+}
+
+// SetShuffle turns shuffle on or off.
+func (p *playerUseCase) SetShuffle(ctx context.Context, shuffle bool) error {
+	return p.sendPlayerCommand(ctx, http.MethodPut, fmt.Sprintf("/me/player/shuffle?state=%t", shuffle))
+}
+
+// SetRepeat sets the repeat mode: "track", "context" or "off".
+func (p *playerUseCase) SetRepeat(ctx context.Context, mode string) error {
+	switch mode {
+	case "track", "context", "off":
+	default:
+		return fmt.Errorf("invalid repeat mode %q", mode)
+	}
+	return p.sendPlayerCommand(ctx, http.MethodPut, "/me/player/repeat?state="+mode)
+}
+
+// GetBeats retrieves the start times of the beats of a track in milliseconds.
+func (p *playerUseCase) GetBeats(ctx context.Context, trackID string) ([]int, error) {
+	var analysis struct {
+		Beats []struct {
+			Start float64 `json:"start"`
+		} `json:"beats"`
+	}
+	if err := p.client.Get(ctx, "/audio-analysis/"+url.PathEscape(trackID), &analysis); err != nil {
+		return nil, fmt.Errorf("failed to get audio analysis: %w", err)
+	}
+
+	beats := make([]int, len(analysis.Beats))
+	for i, beat := range analysis.Beats {
+		beats[i] = int(beat.Start * 1000)
+	}
+
+	return beats, nil
+}
+
+// sendPlayerCommand sends a playback control request that has no response body.
+func (p *playerUseCase) sendPlayerCommand(ctx context.Context, method, path string) error {
+	if err := p.client.Do(ctx, method, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to control playback: %w", err)
 	}
 
-	return result, nil
+	return nil
 }
@@ -0,0 +1,128 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/spotify/spotifytest"
+)
+
+func newPlayerUseCase(t *testing.T) (usecase.PlayerUseCase, *spotifytest.Server) {
+	t.Helper()
+
+	server := spotifytest.NewServer()
+	t.Cleanup(server.Close)
+
+	authRepo := spotifytest.NewAuthRepository(spotifytest.ValidAuth())
+	return usecase.NewPlayerUseCase(authRepo, server.SpotifyClient(authRepo)), server
+}
+
+func TestGetCurrentlyPlayingDetails(t *testing.T) {
+	player, server := newPlayerUseCase(t)
+	server.HandleFixture(http.MethodGet, "/v1/me/player/currently-playing", "currently_playing")
+
+	track, err := player.GetCurrentlyPlayingDetails(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentlyPlayingDetails() error = %v", err)
+	}
+
+	want := usecase.CurrentlyPlaying{
+		ID:          "4u7EnebtmKWzUH433cf5Qv",
+		URI:         "spotify:track:4u7EnebtmKWzUH433cf5Qv",
+		IsPlaying:   true,
+		ProgressMs:  42000,
+		Title:       "Bohemian Rhapsody",
+		Artist:      "Queen",
+		Album:       "A Night At The Opera",
+		AlbumID:     "6dVIqQ8qmQ5GBnJ9shOYGE",
+		DurationMs:  354947,
+		Type:        usecase.ItemTypeTrack,
+		ImageURL:    "https://i.scdn.co/image/small",
+		ArtistNames: []string{"Queen"},
+	}
+	if track.ID != want.ID || track.URI != want.URI || track.IsPlaying != want.IsPlaying ||
+		track.ProgressMs != want.ProgressMs || track.Title != want.Title || track.Artist != want.Artist ||
+		track.Album != want.Album || track.AlbumID != want.AlbumID || track.DurationMs != want.DurationMs ||
+		track.Type != want.Type || track.ImageURL != want.ImageURL ||
+		len(track.ArtistNames) != 1 || track.ArtistNames[0] != want.ArtistNames[0] {
+		t.Errorf("GetCurrentlyPlayingDetails() = %+v, want %+v", *track, want)
+	}
+
+	if query := server.Requests()[0].Query; query != "additional_types=episode" {
+		t.Errorf("query = %q, want episodes to be asked for", query)
+	}
+}
+
+func TestGetCurrentlyPlayingDetailsNothingPlaying(t *testing.T) {
+	player, server := newPlayerUseCase(t)
+	server.Handle(http.MethodGet, "/v1/me/player/currently-playing", http.StatusNoContent, "")
+
+	_, err := player.GetCurrentlyPlayingDetails(context.Background())
+	if !errors.Is(err, usecase.ErrNothingPlaying) {
+		t.Errorf("GetCurrentlyPlayingDetails() error = %v, want %v", err, usecase.ErrNothingPlaying)
+	}
+}
+
+func TestGetPlaybackState(t *testing.T) {
+	player, server := newPlayerUseCase(t)
+	server.HandleFixture(http.MethodGet, "/v1/me/player", "player")
+	server.HandleFixture(http.MethodGet, "/v1/me", "me")
+
+	state, err := player.GetPlaybackState(context.Background())
+	if err != nil {
+		t.Fatalf("GetPlaybackState() error = %v", err)
+	}
+
+	if state.Title != "Bohemian Rhapsody" || state.IsPlaying {
+		t.Errorf("track = %q playing %v, want Bohemian Rhapsody paused", state.Title, state.IsPlaying)
+	}
+	if state.Device.Name != "Living Room" || state.Device.VolumePercent != 59 {
+		t.Errorf("device = %+v, want Living Room at 59%%", state.Device)
+	}
+	if !state.ShuffleState || state.RepeatState != "context" {
+		t.Errorf("shuffle/repeat = %v/%q, want true/context", state.ShuffleState, state.RepeatState)
+	}
+	if state.ContextType != "playlist" || state.ContextURI != "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M" {
+		t.Errorf("context = %s %s, want the playlist", state.ContextType, state.ContextURI)
+	}
+	if state.SessionDescription() != "Shared speaker" {
+		t.Errorf("SessionDescription() = %q, want Shared speaker", state.SessionDescription())
+	}
+}
+
+func TestGetPlaybackStateOwnerFollowsLogin(t *testing.T) {
+	server := spotifytest.NewServer()
+	t.Cleanup(server.Close)
+	authRepo := spotifytest.NewAuthRepository(spotifytest.ValidAuth())
+	player := usecase.NewPlayerUseCase(authRepo, server.SpotifyClient(authRepo))
+	server.HandleFixture(http.MethodGet, "/v1/me/player", "player")
+	server.HandleFixture(http.MethodGet, "/v1/me", "me")
+	server.Handle(http.MethodGet, "/v1/me", http.StatusOK, `{"id":"other","display_name":"Other User"}`)
+
+	owner := func() string {
+		t.Helper()
+		state, err := player.GetPlaybackState(context.Background())
+		if err != nil {
+			t.Fatalf("GetPlaybackState() error = %v", err)
+		}
+		return state.Owner
+	}
+
+	if got := owner(); got != "Test User" {
+		t.Errorf("Owner = %q, want Test User", got)
+	}
+	if got := owner(); got != "Test User" {
+		t.Errorf("Owner = %q on the next poll, want the cached Test User", got)
+	}
+
+	// Logging in to another account replaces the refresh token
+	auth := spotifytest.ValidAuth()
+	auth.RefreshToken = "other-refresh-token"
+	authRepo.StoreToken(context.Background(), auth)
+	if got := owner(); got != "Other User" {
+		t.Errorf("Owner = %q after logging in again, want Other User", got)
+	}
+}
@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// PlaylistUseCase defines the interface for playlist-related use cases.
+type PlaylistUseCase interface {
+	// ListPlaylists retrieves all playlists owned or followed by the user.
+	ListPlaylists(ctx context.Context) ([]Playlist, error)
+
+	// AddTracks appends the tracks with the given Spotify URIs to a playlist.
+	AddTracks(ctx context.Context, playlistID string, uris []string) error
+
+	// ReplaceTracks replaces the tracks of a playlist with the tracks with the given Spotify URIs.
+	ReplaceTracks(ctx context.Context, playlistID string, uris []string) error
+
+	// GetPlaylistTracks retrieves all tracks of a playlist.
+	GetPlaylistTracks(ctx context.Context, playlistID string) ([]PlaylistTrack, error)
+
+	// CreatePlaylist creates a new playlist for the user.
+	CreatePlaylist(ctx context.Context, name, description string, public bool) (*Playlist, error)
+
+	// DeletePlaylist deletes a playlist. Spotify has no real deletion, so the user unfollows it,
+	// which removes it from their library.
+	DeletePlaylist(ctx context.Context, playlistID string) error
+}
+
+// Playlist represents a Spotify playlist.
+type Playlist struct {
+	ID          string `json:"id"`
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Owner       string `json:"owner"`
+	Public      bool   `json:"public"`
+	TrackCount  int    `json:"track_count"`
+}
+
+// PlaylistTrack represents a track in a playlist.
+type PlaylistTrack struct {
+	Track
+	AddedAt time.Time `json:"added_at"`
+	AddedBy string    `json:"added_by"`
+}
+
+// maxTracksPerRequest is the maximum number of tracks Spotify accepts in a single add request.
+const maxTracksPerRequest = 100
+
+// playlistUseCase implements the PlaylistUseCase interface.
+type playlistUseCase struct {
+	client repository.SpotifyClient
+}
+
+// NewPlaylistUseCase creates a new instance of PlaylistUseCase.
+func NewPlaylistUseCase(client repository.SpotifyClient) PlaylistUseCase {
+	return &playlistUseCase{
+		client: client,
+	}
+}
+
+// ListPlaylists retrieves all playlists owned or followed by the user.
+func (p *playlistUseCase) ListPlaylists(ctx context.Context) ([]Playlist, error) {
+	type item struct {
+		ID          string `json:"id"`
+		URI         string `json:"uri"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+		Owner       struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"owner"`
+		Tracks struct {
+			Total int `json:"total"`
+		} `json:"tracks"`
+	}
+	items, err := repository.CollectPages[item](ctx, p.client, "/me/playlists?limit=50", "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	playlists := make([]Playlist, len(items))
+	for i, item := range items {
+		owner := item.Owner.DisplayName
+		if owner == "" {
+			owner = item.Owner.ID
+		}
+		playlists[i] = Playlist{
+			ID:          item.ID,
+			URI:         item.URI,
+			Name:        item.Name,
+			Description: item.Description,
+			Owner:       owner,
+			Public:      item.Public,
+			TrackCount:  item.Tracks.Total,
+		}
+	}
+
+	return playlists, nil
+}
+
+// AddTracks appends the tracks with the given Spotify URIs to a playlist.
+func (p *playlistUseCase) AddTracks(ctx context.Context, playlistID string, uris []string) error {
+	for start := 0; start < len(uris); start += maxTracksPerRequest {
+		end := min(start+maxTracksPerRequest, len(uris))
+
+		body := map[string][]string{"uris": uris[start:end]}
+		if err := p.client.Post(ctx, "/playlists/"+url.PathEscape(playlistID)+"/tracks", body, nil); err != nil {
+			return fmt.Errorf("failed to add tracks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReplaceTracks replaces the tracks of a playlist with the tracks with the given Spotify URIs.
+func (p *playlistUseCase) ReplaceTracks(ctx context.Context, playlistID string, uris []string) error {
+	// Spotify replaces up to 100 tracks at once, the rest are appended.
+	// An empty list clears the playlist.
+	first := append([]string{}, uris[:min(len(uris), maxTracksPerRequest)]...)
+
+	body := map[string][]string{"uris": first}
+	if err := p.client.Put(ctx, "/playlists/"+url.PathEscape(playlistID)+"/tracks", body, nil); err != nil {
+		return fmt.Errorf("failed to replace tracks: %w", err)
+	}
+
+	return p.AddTracks(ctx, playlistID, uris[len(first):])
+}
+
+// GetPlaylistTracks retrieves all tracks of a playlist.
+func (p *playlistUseCase) GetPlaylistTracks(ctx context.Context, playlistID string) ([]PlaylistTrack, error) {
+	type item struct {
+		AddedAt time.Time `json:"added_at"`
+		AddedBy *struct {
+			ID string `json:"id"`
+		} `json:"added_by"`
+		// Track is null for tracks that are no longer available
+		Track *spotifyTrack `json:"track"`
+	}
+	path := "/playlists/" + url.PathEscape(playlistID) + "/tracks?limit=100"
+	items, err := repository.CollectPages[item](ctx, p.client, path, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	var tracks []PlaylistTrack
+	for _, item := range items {
+		if item.Track == nil {
+			continue
+		}
+		track := PlaylistTrack{
+			Track:   item.Track.toTrack(),
+			AddedAt: item.AddedAt,
+		}
+		if item.AddedBy != nil {
+			track.AddedBy = item.AddedBy.ID
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+// CreatePlaylist creates a new playlist for the user.
+func (p *playlistUseCase) CreatePlaylist(ctx context.Context, name, description string, public bool) (*Playlist, error) {
+	// Playlists are created under the user's ID
+	var profile struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := p.client.Get(ctx, "/me", &profile); err != nil {
+		return nil, fmt.Errorf("failed to get user profile: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"public":      public,
+	}
+
+	var created struct {
+		ID          string `json:"id"`
+		URI         string `json:"uri"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+	}
+	if err := p.client.Post(ctx, "/users/"+url.PathEscape(profile.ID)+"/playlists", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create playlist: %w", err)
+	}
+
+	owner := profile.DisplayName
+	if owner == "" {
+		owner = profile.ID
+	}
+
+	return &Playlist{
+		ID:          created.ID,
+		URI:         created.URI,
+		Name:        created.Name,
+		Description: created.Description,
+		Owner:       owner,
+		Public:      created.Public,
+	}, nil
+}
+
+// DeletePlaylist deletes a playlist by unfollowing it.
+func (p *playlistUseCase) DeletePlaylist(ctx context.Context, playlistID string) error {
+	if err := p.client.Delete(ctx, "/playlists/"+url.PathEscape(playlistID)+"/followers", nil, nil); err != nil {
+		return fmt.Errorf("failed to delete playlist: %w", err)
+	}
+
+	return nil
+}
+
+// MatchPlaylist finds the playlist that best matches the query by ID or name.
+// Names are matched case-insensitively, preferring exact matches, then prefixes,
+// then substrings and finally in-order character subsequences.
+func MatchPlaylist(playlists []Playlist, query string) (*Playlist, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil, fmt.Errorf("playlist name is empty")
+	}
+
+	type candidate struct {
+		playlist Playlist
+		score    int
+	}
+
+	var candidates []candidate
+	for _, playlist := range playlists {
+		if playlist.ID == query || playlist.URI == query {
+			match := playlist
+			return &match, nil
+		}
+		if score := matchScore(strings.ToLower(playlist.Name), q); score > 0 {
+			candidates = append(candidates, candidate{playlist: playlist, score: score})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no playlist matches %q", query)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	// Refuse to guess between equally good fuzzy matches
+	best := candidates[0]
+	if len(candidates) > 1 && candidates[1].score == best.score && best.score < exactMatchScore {
+		names := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			if c.score == best.score {
+				names = append(names, fmt.Sprintf("%q", c.playlist.Name))
+			}
+		}
+		return nil, fmt.Errorf("%q matches several playlists: %s", query, strings.Join(names, ", "))
+	}
+
+	return &best.playlist, nil
+}
+
+// Scores used by matchScore, from best to worst.
+const (
+	exactMatchScore       = 4
+	prefixMatchScore      = 3
+	substringMatchScore   = 2
+	subsequenceMatchScore = 1
+)
+
+// matchScore scores how well the lowercase name matches the lowercase query, 0 meaning no match.
+func matchScore(name, query string) int {
+	switch {
+	case name == query:
+		return exactMatchScore
+	case strings.HasPrefix(name, query):
+		return prefixMatchScore
+	case strings.Contains(name, query):
+		return substringMatchScore
+	case isSubsequence(query, name):
+		return subsequenceMatchScore
+	default:
+		return 0
+	}
+}
+
+// isSubsequence reports whether the runes of sub appear in s in order.
+func isSubsequence(sub, s string) bool {
+	r := []rune(sub)
+	i := 0
+	for _, c := range s {
+		if i < len(r) && c == r[i] {
+			i++
+		}
+	}
+	return i == len(r)
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// Profile represents a time-of-day profile, e.g. lower volume and no notifications at night.
+type Profile struct {
+	Name string
+	// From and To are minutes after midnight; a To at or before From ends the next day
+	From int
+	To   int
+	// Days limits the profile to windows starting on these weekdays; empty means every day
+	Days []time.Weekday
+	// Volume is set when the profile starts; nil leaves the volume alone
+	Volume        *int
+	Theme         string
+	Notifications *bool
+}
+
+// ProfileUseCase defines the interface for applying time-of-day profiles.
+type ProfileUseCase interface {
+	// Apply switches to the profile whose window contains now, or back to no profile,
+	// and reports whether the active profile changed.
+	Apply(ctx context.Context, now time.Time) (*entity.ActiveProfile, bool, error)
+}
+
+// profileUseCase implements the ProfileUseCase interface.
+type profileUseCase struct {
+	profileRepo   repository.ProfileRepository
+	playerUseCase PlayerUseCase
+	profiles      []Profile
+}
+
+// NewProfileUseCase creates a new instance of ProfileUseCase.
+// When the windows of several profiles overlap, the first one wins.
+func NewProfileUseCase(profileRepo repository.ProfileRepository, playerUseCase PlayerUseCase, profiles []Profile) ProfileUseCase {
+	return &profileUseCase{
+		profileRepo:   profileRepo,
+		playerUseCase: playerUseCase,
+		profiles:      profiles,
+	}
+}
+
+// Apply switches to the profile whose window contains now, or back to no profile.
+func (p *profileUseCase) Apply(ctx context.Context, now time.Time) (*entity.ActiveProfile, bool, error) {
+	previous, err := p.profileRepo.LoadActiveProfile(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !previous.ActiveAt(now) {
+		previous = nil
+	}
+
+	profile, until := MatchProfile(p.profiles, now)
+	if profile == nil {
+		if err := p.profileRepo.ClearActiveProfile(ctx); err != nil {
+			return nil, false, err
+		}
+		return nil, previous != nil, nil
+	}
+
+	active := &entity.ActiveProfile{
+		Name:          profile.Name,
+		Theme:         profile.Theme,
+		Notifications: profile.Notifications,
+		Until:         until,
+	}
+	if err := p.profileRepo.SaveActiveProfile(ctx, active); err != nil {
+		return nil, false, err
+	}
+
+	changed := previous == nil || previous.Name != active.Name
+	if changed && profile.Volume != nil {
+		if err := p.playerUseCase.SetVolume(ctx, *profile.Volume); err != nil {
+			return active, true, fmt.Errorf("failed to set the volume of profile %s: %w", profile.Name, err)
+		}
+	}
+
+	return active, changed, nil
+}
+
+// MatchProfile returns the first profile whose window contains now and the end of that window.
+func MatchProfile(profiles []Profile, now time.Time) (*Profile, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for i, profile := range profiles {
+		// A window that wraps past midnight may have started yesterday
+		for _, day := range []time.Time{today, today.AddDate(0, 0, -1)} {
+			if len(profile.Days) > 0 && !containsWeekday(profile.Days, day.Weekday()) {
+				continue
+			}
+
+			start := day.Add(time.Duration(profile.From) * time.Minute)
+			end := day.Add(time.Duration(profile.To) * time.Minute)
+			if profile.To <= profile.From {
+				end = end.AddDate(0, 0, 1)
+			}
+
+			if !now.Before(start) && now.Before(end) {
+				return &profiles[i], end
+			}
+		}
+	}
+
+	return nil, time.Time{}
+}
+
+// ParseClock parses a time of day such as "22:00" into minutes after midnight.
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ParseWeekday parses a weekday name such as "mon" or "Monday".
+func ParseWeekday(s string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", s)
+}
+
+// containsWeekday reports whether day is one of days.
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import "time"
+
+// transitionToleranceMs is how far behind the interpolated position a poll may report
+// around a track transition before it is trusted.
+const transitionToleranceMs = 3000
+
+// progressClock interpolates the playback position between polls. Around gapless track
+// transitions Spotify keeps reporting the ending track for a moment, sometimes with a position
+// behind the interpolated one; those stale polls are smoothed over so that progress bars run
+// up to the end and start over at 0 instead of jumping back first.
+type progressClock struct {
+	trackID         string
+	previousTrackID string
+	baseMs          int
+	durationMs      int
+	polledAt        time.Time
+	playing         bool
+}
+
+// observe records the position reported by a poll at now. It returns false when the poll still
+// reports the track that just ended, which should be ignored.
+func (c *progressClock) observe(track *CurrentlyPlaying, now time.Time) bool {
+	if track.ID != c.trackID {
+		if track.ID == c.previousTrackID && track.ProgressMs >= track.DurationMs-transitionToleranceMs {
+			return false
+		}
+		c.previousTrackID = c.trackID
+		c.trackID = track.ID
+		c.set(track, now)
+		return true
+	}
+
+	// A position slightly behind near the end of the track is a stale poll, not a seek
+	interpolated := c.position(now)
+	stale := c.playing && track.IsPlaying &&
+		interpolated >= c.durationMs-transitionToleranceMs &&
+		track.ProgressMs < interpolated && interpolated-track.ProgressMs <= transitionToleranceMs
+	if !stale {
+		c.set(track, now)
+	}
+	return true
+}
+
+// set anchors the interpolation at the position reported by a poll.
+func (c *progressClock) set(track *CurrentlyPlaying, now time.Time) {
+	c.baseMs = track.ProgressMs
+	c.durationMs = track.DurationMs
+	c.polledAt = now
+	c.playing = track.IsPlaying
+}
+
+// reset forgets the current track, e.g. when playback stops.
+func (c *progressClock) reset() {
+	*c = progressClock{}
+}
+
+// position returns the interpolated position at now, held at the end of the track until
+// the next one is reported.
+func (c *progressClock) position(now time.Time) int {
+	position := c.baseMs
+	if c.playing && !c.polledAt.IsZero() {
+		position += int(now.Sub(c.polledAt).Milliseconds())
+	}
+	if c.durationMs > 0 {
+		position = min(position, c.durationMs)
+	}
+	return position
+}
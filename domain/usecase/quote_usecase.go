@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// quoteHistorySize is the number of recently played tracks quotes are picked from, Spotify's maximum.
+const quoteHistorySize = 50
+
+// QuoteUseCase defines the interface for picking lyric quotes from the listening history.
+type QuoteUseCase interface {
+	// RandomQuote picks a random lyric line from a recently played track.
+	// Tracks played more often are more likely to be picked.
+	RandomQuote(ctx context.Context) (*Quote, error)
+}
+
+// Quote represents a lyric line and the track it's from.
+type Quote struct {
+	Text   string `json:"text"`
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	Album  string `json:"album"`
+}
+
+// quoteUseCase implements the QuoteUseCase interface.
+type quoteUseCase struct {
+	playerUseCase PlayerUseCase
+	lyricUseCase  LyricUseCase
+}
+
+// NewQuoteUseCase creates a new instance of QuoteUseCase.
+func NewQuoteUseCase(playerUseCase PlayerUseCase, lyricUseCase LyricUseCase) QuoteUseCase {
+	return &quoteUseCase{
+		playerUseCase: playerUseCase,
+		lyricUseCase:  lyricUseCase,
+	}
+}
+
+// weightedTrack is a recently played track with the number of times it was played.
+type weightedTrack struct {
+	track Track
+	plays int
+}
+
+// RandomQuote picks a random lyric line from a recently played track.
+func (q *quoteUseCase) RandomQuote(ctx context.Context) (*Quote, error) {
+	played, err := q.playerUseCase.GetRecentlyPlayed(ctx, quoteHistorySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listening history: %w", err)
+	}
+
+	// Count the plays of each track, keeping the order of first appearance
+	var tracks []weightedTrack
+	index := make(map[string]int)
+	for _, p := range played {
+		if i, ok := index[p.ID]; ok {
+			tracks[i].plays++
+			continue
+		}
+		index[p.ID] = len(tracks)
+		tracks = append(tracks, weightedTrack{track: p.Track, plays: 1})
+	}
+
+	// Draw tracks until one has lyrics with a line worth quoting
+	for len(tracks) > 0 {
+		i := pickWeighted(tracks)
+		track := tracks[i].track
+		tracks = append(tracks[:i], tracks[i+1:]...)
+
+		lyrics, err := q.lyricUseCase.GetLyrics(ctx, track.Artist, track.Title, track.Album, track.DurationMs)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		var lines []string
+		for _, line := range lyrics.Lines {
+			if text := strings.TrimSpace(line.Text); text != "" {
+				lines = append(lines, text)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		return &Quote{
+			Text:   lines[rand.IntN(len(lines))],
+			Title:  track.Title,
+			Artist: track.Artist,
+			Album:  track.Album,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no lyrics found for your recently played tracks")
+}
+
+// pickWeighted returns the index of a random track, weighted by its play count.
+func pickWeighted(tracks []weightedTrack) int {
+	total := 0
+	for _, t := range tracks {
+		total += t.plays
+	}
+
+	n := rand.IntN(total)
+	for i, t := range tracks {
+		if n < t.plays {
+			return i
+		}
+		n -= t.plays
+	}
+	return len(tracks) - 1
+}
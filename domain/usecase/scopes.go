@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature groups the commands that need the same Spotify API scopes.
+type Feature struct {
+	Name        string
+	Description string
+	Scopes      []string
+}
+
+// Features is the registry of features and the scopes they need.
+// The first feature is required by every command and can't be disabled.
+var Features = []Feature{
+	{
+		Name:        "now-playing",
+		Description: "Currently playing track, lyrics and status outputs",
+		Scopes:      []string{"user-read-currently-playing"},
+	},
+	{
+		Name:        "playback",
+		Description: "Devices, playback control and the queue",
+		Scopes:      []string{"user-read-playback-state", "user-modify-playback-state"},
+	},
+	{
+		Name:        "playlists",
+		Description: "Listing, creating and adding to playlists",
+		Scopes:      []string{"playlist-read-private", "playlist-modify-public", "playlist-modify-private"},
+	},
+	{
+		Name:        "history",
+		Description: "Recently played tracks",
+		Scopes:      []string{"user-read-recently-played"},
+	},
+	{
+		Name:        "library",
+		Description: "Saved tracks",
+		Scopes:      []string{"user-library-read", "user-library-modify"},
+	},
+	{
+		Name:        "follow",
+		Description: "Following artists",
+		Scopes:      []string{"user-follow-read", "user-follow-modify"},
+	},
+}
+
+// EnabledFeatures returns the registered features with the given names, always including the first one.
+// An empty list enables every feature.
+func EnabledFeatures(names []string) ([]Feature, error) {
+	if len(names) == 0 {
+		return Features, nil
+	}
+
+	enabled := map[string]bool{Features[0].Name: true}
+	for _, name := range names {
+		found := false
+		for _, feature := range Features {
+			if feature.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown feature %q (expected one of %s)", name, strings.Join(featureNames(), ", "))
+		}
+		enabled[name] = true
+	}
+
+	var features []Feature
+	for _, feature := range Features {
+		if enabled[feature.Name] {
+			features = append(features, feature)
+		}
+	}
+	return features, nil
+}
+
+// RequiredScopes returns the union of the scopes needed by the features, in registry order.
+func RequiredScopes(features []Feature) []string {
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, feature := range features {
+		for _, scope := range feature.Scopes {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// MissingScopes returns the required scopes that aren't in the granted list.
+func MissingScopes(granted, required []string) []string {
+	have := make(map[string]bool, len(granted))
+	for _, scope := range granted {
+		have[scope] = true
+	}
+
+	var missing []string
+	for _, scope := range required {
+		if !have[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// featureNames returns the names of all registered features.
+func featureNames() []string {
+	names := make([]string, len(Features))
+	for i, feature := range Features {
+		names[i] = feature.Name
+	}
+	return names
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// A listen counts once half of the track is played, or scrobbleMaxThreshold for long tracks.
+const scrobbleMaxThreshold = 4 * time.Minute
+
+// scrobbleBatchSize is the number of queued listens submitted at once.
+const scrobbleBatchSize = 100
+
+// ScrobbleUseCase defines the interface for submitting the tracks played to a listening
+// service. Listens are queued before they are submitted, so none are lost while the service
+// can't be reached.
+type ScrobbleUseCase interface {
+	// Observe follows the playback state and is called with every change: it reports tracks
+	// starting to play, and queues and submits the listens of tracks that were played long
+	// enough once they end.
+	Observe(ctx context.Context, snapshot *entity.PlaybackSnapshot) error
+
+	// Submit submits the queued listens and returns how many were submitted. The listens stay
+	// queued when the submission fails.
+	Submit(ctx context.Context) (int, error)
+
+	// Finish queues the listen of the current track if it was played long enough, for when
+	// playback stops being followed at the given time.
+	Finish(ctx context.Context, at time.Time) error
+}
+
+// scrobbleUseCase implements the ScrobbleUseCase interface.
+type scrobbleUseCase struct {
+	submitter repository.ListenSubmitter
+	queueRepo repository.ListenQueueRepository
+
+	// The track being listened to, nil when nothing plays, with the time it played for
+	mu             sync.Mutex
+	play           *entity.Play
+	played         time.Duration
+	playingSince   time.Time
+	nowPlayingSent bool
+
+	// submitMu keeps listens from being submitted twice by concurrent submissions
+	submitMu sync.Mutex
+}
+
+// NewScrobbleUseCase creates a new instance of ScrobbleUseCase submitting to submitter, with
+// the listens waiting in queueRepo.
+func NewScrobbleUseCase(submitter repository.ListenSubmitter, queueRepo repository.ListenQueueRepository) ScrobbleUseCase {
+	return &scrobbleUseCase{
+		submitter: submitter,
+		queueRepo: queueRepo,
+	}
+}
+
+// Observe follows the playback state. The time of a state is its UpdatedAt.
+func (s *scrobbleUseCase) Observe(ctx context.Context, snapshot *entity.PlaybackSnapshot) error {
+	at := snapshot.UpdatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	s.mu.Lock()
+	var errs []error
+	queued := false
+	trackID := ""
+	if snapshot.Track != nil {
+		trackID = snapshot.Track.ID
+	}
+	if (s.play == nil && trackID != "") || (s.play != nil && s.play.TrackID != trackID) {
+		var err error
+		queued, err = s.finish(ctx, at)
+		errs = append(errs, err)
+		if track := snapshot.Track; track != nil {
+			s.play = &entity.Play{
+				// The track started before the first state seen of it
+				PlayedAt:   at.Add(-time.Duration(snapshot.ProgressMs) * time.Millisecond),
+				TrackID:    track.ID,
+				TrackURI:   track.URI,
+				Title:      track.Title,
+				Artist:     track.Artist,
+				Album:      track.Album,
+				DurationMs: track.DurationMs,
+			}
+		}
+	}
+
+	var nowPlaying *entity.Play
+	if s.play != nil {
+		playing := snapshot.Status == entity.StatusPlaying
+		if playing && s.playingSince.IsZero() {
+			s.playingSince = at
+		} else if !playing && !s.playingSince.IsZero() {
+			s.played += at.Sub(s.playingSince)
+			s.playingSince = time.Time{}
+		}
+		if playing && !s.nowPlayingSent {
+			s.nowPlayingSent = true
+			play := *s.play
+			nowPlaying = &play
+		}
+	}
+	s.mu.Unlock()
+
+	// The service is asked without holding up other states
+	if nowPlaying != nil {
+		if err := s.submitter.NowPlaying(ctx, *nowPlaying); err != nil {
+			errs = append(errs, fmt.Errorf("failed to report the playing track to %s: %w", s.submitter.Name(), err))
+		}
+	}
+	if queued {
+		_, err := s.Submit(ctx)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Submit submits the queued listens in batches.
+func (s *scrobbleUseCase) Submit(ctx context.Context) (int, error) {
+	s.submitMu.Lock()
+	defer s.submitMu.Unlock()
+
+	submitted := 0
+	for {
+		queued, err := s.queueRepo.QueuedListens(ctx)
+		if err != nil {
+			return submitted, err
+		}
+		if len(queued) == 0 {
+			return submitted, nil
+		}
+
+		batch := queued[:min(len(queued), scrobbleBatchSize)]
+		if err := s.submitter.SubmitListens(ctx, batch); err != nil {
+			return submitted, fmt.Errorf("failed to submit listens to %s, %d stay queued: %w", s.submitter.Name(), len(queued), err)
+		}
+		if err := s.queueRepo.RemoveListens(ctx, len(batch)); err != nil {
+			return submitted, err
+		}
+		submitted += len(batch)
+	}
+}
+
+// Finish queues the listen of the current track if it was played long enough.
+func (s *scrobbleUseCase) Finish(ctx context.Context, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.finish(ctx, at)
+	return err
+}
+
+// finish ends the listen of the current track at the given time, queueing it if it was played
+// long enough, and reports whether it was queued. s.mu must be held.
+func (s *scrobbleUseCase) finish(ctx context.Context, at time.Time) (bool, error) {
+	play, played := s.play, s.played
+	if !s.playingSince.IsZero() {
+		played += at.Sub(s.playingSince)
+	}
+	s.play, s.played, s.playingSince, s.nowPlayingSent = nil, 0, time.Time{}, false
+
+	if play == nil || played < listenThreshold(play.DurationMs) {
+		return false, nil
+	}
+	if err := s.queueRepo.QueueListens(ctx, []entity.Play{*play}); err != nil {
+		return false, fmt.Errorf("failed to queue the listen of %s: %w", play.Title, err)
+	}
+	return true, nil
+}
+
+// listenThreshold returns how long a track must be played to count as a listen.
+func listenThreshold(durationMs int) time.Duration {
+	if durationMs <= 0 {
+		return scrobbleMaxThreshold
+	}
+	return min(time.Duration(durationMs)*time.Millisecond/2, scrobbleMaxThreshold)
+}
@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// fakeSubmitter records the submissions, failing while err is set.
+type fakeSubmitter struct {
+	err        error
+	nowPlaying []string
+	listens    []entity.Play
+}
+
+func (f *fakeSubmitter) Name() string { return "Fake" }
+
+func (f *fakeSubmitter) NowPlaying(ctx context.Context, play entity.Play) error {
+	f.nowPlaying = append(f.nowPlaying, play.TrackID)
+	return f.err
+}
+
+func (f *fakeSubmitter) SubmitListens(ctx context.Context, plays []entity.Play) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.listens = append(f.listens, plays...)
+	return nil
+}
+
+// memoryQueue keeps the queued listens in memory.
+type memoryQueue struct {
+	plays []entity.Play
+}
+
+func (q *memoryQueue) QueueListens(ctx context.Context, plays []entity.Play) error {
+	q.plays = append(q.plays, plays...)
+	return nil
+}
+
+func (q *memoryQueue) QueuedListens(ctx context.Context) ([]entity.Play, error) {
+	return append([]entity.Play(nil), q.plays...), nil
+}
+
+func (q *memoryQueue) RemoveListens(ctx context.Context, n int) error {
+	q.plays = q.plays[min(n, len(q.plays)):]
+	return nil
+}
+
+// state returns a snapshot of the track with the given ID, a 200 second one, at start plus offset.
+func state(start time.Time, offset time.Duration, id, status string) *entity.PlaybackSnapshot {
+	snapshot := &entity.PlaybackSnapshot{UpdatedAt: start.Add(offset), Status: status}
+	if id != "" {
+		snapshot.Track = &entity.SnapshotTrack{ID: id, Title: "Track " + id, Artist: "Artist", DurationMs: 200000}
+	}
+	return snapshot
+}
+
+func TestScrobbleSubmitsTracksPlayedLongEnough(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	submitter := &fakeSubmitter{}
+	scrobbleUseCase := usecase.NewScrobbleUseCase(submitter, &memoryQueue{})
+
+	states := []*entity.PlaybackSnapshot{
+		state(start, 0, "a", entity.StatusPlaying),
+		// Paused for a minute, which doesn't count towards the 100 seconds needed
+		state(start, 60*time.Second, "a", entity.StatusPaused),
+		state(start, 120*time.Second, "a", entity.StatusPlaying),
+		// 90 seconds played; skipped
+		state(start, 150*time.Second, "b", entity.StatusPlaying),
+		// 110 seconds played
+		state(start, 260*time.Second, "c", entity.StatusPlaying),
+		state(start, 400*time.Second, "", entity.StatusStopped),
+	}
+	for _, s := range states {
+		if err := scrobbleUseCase.Observe(ctx, s); err != nil {
+			t.Fatalf("Observe() error = %v", err)
+		}
+	}
+
+	if len(submitter.listens) != 2 || submitter.listens[0].TrackID != "b" || submitter.listens[1].TrackID != "c" {
+		t.Fatalf("submitted %+v, want the listens of b and c", submitter.listens)
+	}
+	if want := start.Add(150 * time.Second); !submitter.listens[0].PlayedAt.Equal(want) {
+		t.Errorf("listen of b played at %s, want %s", submitter.listens[0].PlayedAt, want)
+	}
+	if len(submitter.nowPlaying) != 3 {
+		t.Errorf("reported playing %v, want a, b and c once each", submitter.nowPlaying)
+	}
+}
+
+func TestScrobbleQueuesListensWhileOffline(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	submitter := &fakeSubmitter{err: errors.New("network is down")}
+	queue := &memoryQueue{}
+	scrobbleUseCase := usecase.NewScrobbleUseCase(submitter, queue)
+
+	scrobbleUseCase.Observe(ctx, state(start, 0, "a", entity.StatusPlaying))
+	if err := scrobbleUseCase.Observe(ctx, state(start, 150*time.Second, "b", entity.StatusPlaying)); err == nil {
+		t.Fatal("Observe() error = nil, want the failed submission")
+	}
+	if err := scrobbleUseCase.Finish(ctx, start.Add(300*time.Second)); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if len(queue.plays) != 2 {
+		t.Fatalf("queued %d listens, want a and b", len(queue.plays))
+	}
+
+	submitter.err = nil
+	submitted, err := scrobbleUseCase.Submit(ctx)
+	if err != nil || submitted != 2 {
+		t.Fatalf("Submit() = %d, %v, want 2 listens", submitted, err)
+	}
+	if len(queue.plays) != 0 || len(submitter.listens) != 2 {
+		t.Errorf("%d listens still queued and %d submitted, want all submitted", len(queue.plays), len(submitter.listens))
+	}
+}
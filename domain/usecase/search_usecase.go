@@ -0,0 +1,351 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// SearchUseCase defines the interface for search-related use cases.
+type SearchUseCase interface {
+	// SearchTracks searches the Spotify catalog for tracks matching the query.
+	SearchTracks(ctx context.Context, query string, limit int) ([]Track, error)
+
+	// GetTrack retrieves a track by its Spotify ID.
+	GetTrack(ctx context.Context, id string) (*Track, error)
+
+	// GetAlbum retrieves an album with its full tracklist by its Spotify ID.
+	GetAlbum(ctx context.Context, id string) (*Album, error)
+
+	// GetArtist retrieves an artist by its Spotify ID.
+	GetArtist(ctx context.Context, id string) (*Artist, error)
+
+	// Search searches the Spotify catalog for items of the given type
+	// ("track", "album", "artist" or "playlist") matching the query.
+	Search(ctx context.Context, query, itemType string, limit int) ([]SearchResult, error)
+}
+
+// Album represents an album in the Spotify catalog.
+type Album struct {
+	ID          string  `json:"id"`
+	URI         string  `json:"uri"`
+	Name        string  `json:"name"`
+	Artist      string  `json:"artist"`
+	ReleaseDate string  `json:"release_date"`
+	Label       string  `json:"label"`
+	TotalTracks int     `json:"total_tracks"`
+	Tracks      []Track `json:"tracks"`
+}
+
+// Artist represents an artist in the Spotify catalog.
+type Artist struct {
+	ID        string   `json:"id"`
+	URI       string   `json:"uri"`
+	Name      string   `json:"name"`
+	Genres    []string `json:"genres"`
+	Followers int      `json:"followers"`
+}
+
+// spotifyArtist is the full artist object returned by the Spotify Web API.
+type spotifyArtist struct {
+	ID        string   `json:"id"`
+	URI       string   `json:"uri"`
+	Name      string   `json:"name"`
+	Genres    []string `json:"genres"`
+	Followers struct {
+		Total int `json:"total"`
+	} `json:"followers"`
+}
+
+// toArtist converts a Spotify API artist object to an Artist.
+func (a spotifyArtist) toArtist() Artist {
+	return Artist{
+		ID:        a.ID,
+		URI:       a.URI,
+		Name:      a.Name,
+		Genres:    a.Genres,
+		Followers: a.Followers.Total,
+	}
+}
+
+// SearchResult represents a catalog item found by Search.
+type SearchResult struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	URI    string `json:"uri"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"` // Artist, owner or other context depending on the type
+	// Explicit is only reported for tracks
+	Explicit bool `json:"explicit,omitempty"`
+}
+
+// IsExplicit reports whether the result is marked as explicit.
+func (r SearchResult) IsExplicit() bool {
+	return r.Explicit
+}
+
+// SearchTypes lists the item types supported by Search.
+var SearchTypes = []string{"track", "album", "artist", "playlist"}
+
+// Track represents a track in the Spotify catalog.
+type Track struct {
+	ID         string `json:"id"`
+	URI        string `json:"uri"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	DurationMs int    `json:"duration_ms"`
+	Explicit   bool   `json:"explicit"`
+}
+
+// IsExplicit reports whether the track is marked as explicit.
+func (t Track) IsExplicit() bool {
+	return t.Explicit
+}
+
+// spotifyTrack is the track object returned by the Spotify Web API.
+type spotifyTrack struct {
+	ID         string `json:"id"`
+	URI        string `json:"uri"`
+	Name       string `json:"name"`
+	DurationMs int    `json:"duration_ms"`
+	Explicit   bool   `json:"explicit"`
+	Album      struct {
+		ID     string         `json:"id"`
+		Name   string         `json:"name"`
+		Images []spotifyImage `json:"images"`
+	} `json:"album"`
+	Artists []struct {
+		Name string `json:"name"`
+	} `json:"artists"`
+}
+
+// spotifyImage is an image object returned by the Spotify Web API, largest first in lists.
+type spotifyImage struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// smallestImageURL returns the URL of the smallest image, or an empty string when there are none.
+func smallestImageURL(images []spotifyImage) string {
+	url, width := "", 0
+	for _, image := range images {
+		if url == "" || image.Width < width {
+			url, width = image.URL, image.Width
+		}
+	}
+	return url
+}
+
+// toTrack converts a Spotify API track object to a Track.
+func (t spotifyTrack) toTrack() Track {
+	artistNames := make([]string, len(t.Artists))
+	for i, artist := range t.Artists {
+		artistNames[i] = artist.Name
+	}
+
+	return Track{
+		ID:         t.ID,
+		URI:        t.URI,
+		Title:      t.Name,
+		Artist:     strings.Join(artistNames, ", "),
+		Album:      t.Album.Name,
+		DurationMs: t.DurationMs,
+		Explicit:   t.Explicit,
+	}
+}
+
+// searchUseCase implements the SearchUseCase interface.
+type searchUseCase struct {
+	client repository.SpotifyClient
+}
+
+// NewSearchUseCase creates a new instance of SearchUseCase.
+func NewSearchUseCase(client repository.SpotifyClient) SearchUseCase {
+	return &searchUseCase{
+		client: client,
+	}
+}
+
+// maxSearchResultsPerRequest is the maximum page size Spotify accepts for search results.
+const maxSearchResultsPerRequest = 50
+
+// SearchTracks searches the Spotify catalog for tracks matching the query.
+func (s *searchUseCase) SearchTracks(ctx context.Context, query string, limit int) ([]Track, error) {
+	limit = max(1, limit)
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type", "track")
+	// Larger limits are fetched over several pages
+	params.Set("limit", strconv.Itoa(min(limit, maxSearchResultsPerRequest)))
+
+	items, err := repository.CollectPages[spotifyTrack](ctx, s.client, "/search?"+params.Encode(), "tracks", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tracks: %w", err)
+	}
+
+	tracks := make([]Track, len(items))
+	for i, item := range items {
+		tracks[i] = item.toTrack()
+	}
+
+	return tracks, nil
+}
+
+// GetTrack retrieves a track by its Spotify ID.
+func (s *searchUseCase) GetTrack(ctx context.Context, id string) (*Track, error) {
+	var trackResponse spotifyTrack
+	if err := s.client.Get(ctx, "/tracks/"+url.PathEscape(id), &trackResponse); err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+
+	track := trackResponse.toTrack()
+	return &track, nil
+}
+
+// GetArtist retrieves an artist by its Spotify ID.
+func (s *searchUseCase) GetArtist(ctx context.Context, id string) (*Artist, error) {
+	var artistResponse spotifyArtist
+	if err := s.client.Get(ctx, "/artists/"+url.PathEscape(id), &artistResponse); err != nil {
+		return nil, fmt.Errorf("failed to get artist: %w", err)
+	}
+
+	artist := artistResponse.toArtist()
+	return &artist, nil
+}
+
+// Search searches the Spotify catalog for items of the given type matching the query.
+func (s *searchUseCase) Search(ctx context.Context, query, itemType string, limit int) ([]SearchResult, error) {
+	if !slices.Contains(SearchTypes, itemType) {
+		return nil, fmt.Errorf("invalid search type %q (expected %s)", itemType, strings.Join(SearchTypes, ", "))
+	}
+
+	limit = max(1, limit)
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type", itemType)
+	// Larger limits are fetched over several pages
+	params.Set("limit", strconv.Itoa(min(limit, maxSearchResultsPerRequest)))
+
+	type namedItem struct {
+		Name string `json:"name"`
+	}
+	type item struct {
+		ID          string      `json:"id"`
+		URI         string      `json:"uri"`
+		Name        string      `json:"name"`
+		Artists     []namedItem `json:"artists"`
+		Album       namedItem   `json:"album"`
+		ReleaseDate string      `json:"release_date"`
+		Genres      []string    `json:"genres"`
+		Explicit    bool        `json:"explicit"`
+		Owner       struct {
+			DisplayName string `json:"display_name"`
+		} `json:"owner"`
+		Tracks struct {
+			Total int `json:"total"`
+		} `json:"tracks"`
+	}
+	// Results are nested by type, e.g. under "tracks". Items can contain null entries,
+	// e.g. for unavailable playlists
+	items, err := repository.CollectPages[*item](ctx, s.client, "/search?"+params.Encode(), itemType+"s", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(items))
+	for _, it := range items {
+		if it == nil {
+			continue
+		}
+
+		artistNames := make([]string, len(it.Artists))
+		for i, artist := range it.Artists {
+			artistNames[i] = artist.Name
+		}
+
+		var detail string
+		switch itemType {
+		case "track":
+			detail = strings.Join(artistNames, ", ") + " - " + it.Album.Name
+		case "album":
+			detail = strings.Join(artistNames, ", ")
+			if len(it.ReleaseDate) >= 4 {
+				detail += " (" + it.ReleaseDate[:4] + ")"
+			}
+		case "artist":
+			detail = strings.Join(it.Genres, ", ")
+		case "playlist":
+			detail = fmt.Sprintf("by %s, %d tracks", it.Owner.DisplayName, it.Tracks.Total)
+		}
+
+		results = append(results, SearchResult{
+			Type:     itemType,
+			ID:       it.ID,
+			URI:      it.URI,
+			Name:     it.Name,
+			Detail:   detail,
+			Explicit: it.Explicit,
+		})
+	}
+
+	return results, nil
+}
+
+// GetAlbum retrieves an album with its full tracklist by its Spotify ID.
+func (s *searchUseCase) GetAlbum(ctx context.Context, id string) (*Album, error) {
+	var albumResponse struct {
+		ID          string `json:"id"`
+		URI         string `json:"uri"`
+		Name        string `json:"name"`
+		ReleaseDate string `json:"release_date"`
+		Label       string `json:"label"`
+		TotalTracks int    `json:"total_tracks"`
+		Artists     []struct {
+			Name string `json:"name"`
+		} `json:"artists"`
+		Tracks repository.Page[spotifyTrack] `json:"tracks"`
+	}
+	if err := s.client.Get(ctx, "/albums/"+url.PathEscape(id), &albumResponse); err != nil {
+		return nil, fmt.Errorf("failed to get album: %w", err)
+	}
+
+	artistNames := make([]string, len(albumResponse.Artists))
+	for i, artist := range albumResponse.Artists {
+		artistNames[i] = artist.Name
+	}
+
+	album := &Album{
+		ID:          albumResponse.ID,
+		URI:         albumResponse.URI,
+		Name:        albumResponse.Name,
+		Artist:      strings.Join(artistNames, ", "),
+		ReleaseDate: albumResponse.ReleaseDate,
+		Label:       albumResponse.Label,
+		TotalTracks: albumResponse.TotalTracks,
+	}
+
+	// Long albums spread their tracklist over several pages
+	pages := repository.ContinuePages(s.client, albumResponse.Tracks)
+	for pages.Next(ctx) {
+		for _, item := range pages.Items() {
+			track := item.toTrack()
+			// Album tracks don't embed the album they belong to
+			track.Album = album.Name
+			album.Tracks = append(album.Tracks, track)
+		}
+	}
+	if err := pages.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get album tracks: %w", err)
+	}
+
+	return album, nil
+}
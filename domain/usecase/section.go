@@ -0,0 +1,19 @@
+package usecase
+
+// IsSectionStart reports whether the line at index starts a new section of the song:
+// the first line, or a line following an instrumental gap. A gap is an empty line or
+// a line that lasted at least gapMs before the next one started.
+func IsSectionStart(lyrics *Lyrics, index, gapMs int) bool {
+	if lyrics == nil || index < 0 || index >= len(lyrics.Lines) {
+		return false
+	}
+	if index == 0 {
+		return true
+	}
+
+	previous := lyrics.Lines[index-1]
+	if previous.Text == "" {
+		return true
+	}
+	return gapMs > 0 && lyrics.Lines[index].StartTimeMs-previous.StartTimeMs >= gapMs
+}
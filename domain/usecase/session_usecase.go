@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// ErrNoSession is returned when a listening session is needed but none is running.
+var ErrNoSession = errors.New(`no session is running, start one with "sprt session start"`)
+
+// SessionUseCase defines the interface for recording listening sessions.
+type SessionUseCase interface {
+	// Start starts recording a session with the given name at now.
+	Start(ctx context.Context, name string, now time.Time) (*entity.Session, error)
+
+	// Current retrieves the running session, or nil when none is running.
+	Current(ctx context.Context) (*entity.Session, error)
+
+	// Request records that someone asked for a track, album or playlist to be played or queued.
+	// It does nothing while no session is running.
+	Request(ctx context.Context, request entity.SessionRequest) error
+
+	// Log returns the log of the running session up to now without stopping it.
+	Log(ctx context.Context, now time.Time) (*SessionLog, error)
+
+	// Stop stops the running session at now and returns its log.
+	Stop(ctx context.Context, now time.Time) (*SessionLog, error)
+}
+
+// SessionLog is the list of tracks played during a session.
+type SessionLog struct {
+	Name      string         `json:"name"`
+	StartedAt time.Time      `json:"started_at"`
+	StoppedAt time.Time      `json:"stopped_at"`
+	Entries   []SessionEntry `json:"entries"`
+}
+
+// SessionEntry is a track played during a session, with who asked for it when it was requested
+// through sprt.
+type SessionEntry struct {
+	entity.Play
+	RequestedBy string `json:"requested_by,omitempty"`
+	Via         string `json:"via,omitempty"`
+}
+
+// sessionUseCase implements the SessionUseCase interface.
+type sessionUseCase struct {
+	sessionRepo    repository.SessionRepository
+	historyUseCase HistoryUseCase
+}
+
+// NewSessionUseCase creates a new instance of SessionUseCase that reads the played tracks
+// from the local listening history.
+func NewSessionUseCase(sessionRepo repository.SessionRepository, historyUseCase HistoryUseCase) SessionUseCase {
+	return &sessionUseCase{
+		sessionRepo:    sessionRepo,
+		historyUseCase: historyUseCase,
+	}
+}
+
+// Start starts recording a session with the given name at now.
+func (s *sessionUseCase) Start(ctx context.Context, name string, now time.Time) (*entity.Session, error) {
+	current, err := s.sessionRepo.LoadSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, fmt.Errorf("session %q is already running since %s", current.Name, current.StartedAt.Local().Format("15:04"))
+	}
+
+	// Plays from before the session are recorded now, so later syncs only add the session's
+	if _, err := s.historyUseCase.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("failed to sync listening history: %w", err)
+	}
+
+	session := &entity.Session{Name: name, StartedAt: now}
+	if err := s.sessionRepo.SaveSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Current retrieves the running session, or nil when none is running.
+func (s *sessionUseCase) Current(ctx context.Context) (*entity.Session, error) {
+	return s.sessionRepo.LoadSession(ctx)
+}
+
+// Request records a request in the running session.
+func (s *sessionUseCase) Request(ctx context.Context, request entity.SessionRequest) error {
+	session, err := s.sessionRepo.LoadSession(ctx)
+	if err != nil || session == nil {
+		return err
+	}
+
+	session.Requests = append(session.Requests, request)
+	return s.sessionRepo.SaveSession(ctx, session)
+}
+
+// Log returns the log of the running session up to now.
+func (s *sessionUseCase) Log(ctx context.Context, now time.Time) (*SessionLog, error) {
+	session, err := s.sessionRepo.LoadSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrNoSession
+	}
+
+	if _, err := s.historyUseCase.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("failed to sync listening history: %w", err)
+	}
+	plays, err := s.historyUseCase.Plays(ctx, session.StartedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load listening history: %w", err)
+	}
+
+	log := &SessionLog{Name: session.Name, StartedAt: session.StartedAt, StoppedAt: now, Entries: []SessionEntry{}}
+	used := make([]bool, len(session.Requests))
+	for _, play := range plays {
+		if play.PlayedAt.After(now) {
+			break
+		}
+		entry := SessionEntry{Play: play}
+		if i := matchRequest(session.Requests, used, play); i >= 0 {
+			entry.RequestedBy = session.Requests[i].By
+			entry.Via = session.Requests[i].Via
+		}
+		log.Entries = append(log.Entries, entry)
+	}
+	return log, nil
+}
+
+// Stop stops the running session at now and returns its log.
+func (s *sessionUseCase) Stop(ctx context.Context, now time.Time) (*SessionLog, error) {
+	log, err := s.Log(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.ClearSession(ctx); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// matchRequest returns the index of the request the play answers, or -1. A requested track is
+// answered by its first play after the request; a requested album or playlist by every track
+// played from it afterwards. Answered track requests are marked in used.
+func matchRequest(requests []entity.SessionRequest, used []bool, play entity.Play) int {
+	match := -1
+	for i, request := range requests {
+		if used[i] || request.At.After(play.PlayedAt) {
+			continue
+		}
+		switch request.URI {
+		case play.TrackURI:
+			used[i] = true
+			return i
+		case play.ContextURI:
+			// The latest request for the context wins, a track request still takes precedence
+			match = i
+		}
+	}
+	return match
+}
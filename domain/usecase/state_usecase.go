@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// stateWriteInterval is how often the interpolated progress and the device are written.
+const stateWriteInterval = time.Second
+
+// StateUseCase defines the interface for publishing the playback state to external consumers.
+type StateUseCase interface {
+	// Watch follows playback and keeps the stored state up to date until the context is cancelled.
+	Watch(ctx context.Context) error
+
+	// Current returns the latest playback snapshot with interpolated progress.
+	// A state file kept fresh by Watch is used when available, otherwise Spotify is polled.
+	Current(ctx context.Context) (*entity.PlaybackSnapshot, error)
+
+	// GetPlaybackState returns the full playback state Watch polled last, with interpolated
+	// progress. Spotify is polled when Watch isn't running in this process or hasn't polled recently.
+	GetPlaybackState(ctx context.Context) (*PlaybackState, error)
+}
+
+// stateFreshness is how old a stored snapshot may be before Current polls Spotify instead.
+const stateFreshness = 5 * time.Second
+
+// stateUseCase implements the StateUseCase interface.
+type stateUseCase struct {
+	stateRepo     repository.StateRepository
+	playerUseCase PlayerUseCase
+	lyricUseCase  LyricUseCase
+	historySize   int
+
+	// polled is the playback state Watch polled last, or the error polling it
+	mu     sync.Mutex
+	polled playbackPoll
+	// tracksOnly polls the current track instead of the playback state, which can't be read
+	// without the playback feature
+	tracksOnly bool
+}
+
+// playbackPoll is a poll of the playback state.
+type playbackPoll struct {
+	state *PlaybackState
+	err   error
+	at    time.Time
+}
+
+// NewStateUseCase creates a new instance of StateUseCase.
+// historySize is the number of previous lyric lines kept in the state.
+func NewStateUseCase(stateRepo repository.StateRepository, playerUseCase PlayerUseCase, lyricUseCase LyricUseCase, historySize int) StateUseCase {
+	return &stateUseCase{
+		stateRepo:     stateRepo,
+		playerUseCase: playerUseCase,
+		lyricUseCase:  lyricUseCase,
+		historySize:   historySize,
+	}
+}
+
+// Watch follows playback and keeps the stored state up to date until the context is cancelled.
+func (s *stateUseCase) Watch(ctx context.Context) error {
+	// The lyric channel polls the playback state, which also gives the device, and follows its
+	// track from the current position when something is playing
+	tracks := statePoller{s}
+	startTimeMs := 0
+	if track, err := tracks.GetCurrentlyPlayingDetails(ctx); err == nil {
+		startTimeMs = track.ProgressMs
+	}
+	updateCh := s.lyricUseCase.GetLyricChannel(ctx, startTimeMs, tracks)
+
+	writeTicker := time.NewTicker(stateWriteInterval)
+	defer writeTicker.Stop()
+
+	snapshot := &entity.PlaybackSnapshot{Status: entity.StatusStopped}
+	history := NewLyricHistory(s.historySize)
+	var clock progressClock
+
+	save := func() error {
+		snapshot.UpdatedAt = time.Now()
+		if snapshot.Track != nil {
+			snapshot.ProgressMs = clock.position(snapshot.UpdatedAt)
+		}
+		s.updateDevice(snapshot)
+		return s.stateRepo.SaveState(ctx, snapshot)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case update, ok := <-updateCh:
+			if !ok {
+				return nil
+			}
+
+			switch {
+			case update.NothingPlaying:
+				snapshot.Status = entity.StatusStopped
+				snapshot.Track = nil
+				snapshot.Lyric = nil
+				snapshot.ProgressMs = 0
+				history.Reset()
+				clock.reset()
+			case update.Track != nil:
+				if !clock.observe(update.Track, time.Now()) {
+					// A stale poll of the track that just ended
+					continue
+				}
+				applyTrack(snapshot, update.Track)
+				if snapshot.Lyric == nil {
+					// The track changed, previous lines belong to the old one
+					history.Reset()
+				}
+			}
+
+			if update.Line != nil {
+				if previous := snapshot.Lyric; previous != nil && previous.Index != update.LineIndex {
+					history.Add(previous.Index, Line{
+						StartTimeMs: previous.StartTimeMs,
+						EndTimeMs:   previous.EndTimeMs,
+						Text:        previous.Text,
+					})
+				}
+				snapshot.Lyric = &entity.SnapshotLyric{
+					Index:       update.LineIndex,
+					Text:        update.Line.Text,
+					StartTimeMs: update.Line.StartTimeMs,
+					EndTimeMs:   update.Line.EndTimeMs,
+				}
+			}
+
+			snapshot.History = snapshotHistory(history)
+
+			if err := save(); err != nil {
+				return err
+			}
+
+		case <-writeTicker.C:
+			if err := save(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Current returns the latest playback snapshot with interpolated progress.
+func (s *stateUseCase) Current(ctx context.Context) (*entity.PlaybackSnapshot, error) {
+	if snapshot, err := s.stateRepo.LoadState(ctx); err == nil && time.Since(snapshot.UpdatedAt) < stateFreshness {
+		// Advance the progress clock from the time the snapshot was written
+		if snapshot.Status == entity.StatusPlaying {
+			snapshot.ProgressMs += int(time.Since(snapshot.UpdatedAt).Milliseconds())
+			if snapshot.Track != nil && snapshot.Track.DurationMs > 0 {
+				snapshot.ProgressMs = min(snapshot.ProgressMs, snapshot.Track.DurationMs)
+			}
+		}
+		return snapshot, nil
+	}
+
+	snapshot := &entity.PlaybackSnapshot{
+		UpdatedAt: time.Now(),
+		Status:    entity.StatusStopped,
+	}
+
+	track, err := s.playerUseCase.GetCurrentlyPlayingDetails(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNothingPlaying) {
+			return snapshot, nil
+		}
+		return nil, err
+	}
+	applyTrack(snapshot, track)
+
+	return snapshot, nil
+}
+
+// GetPlaybackState returns the full playback state Watch polled last, with interpolated progress.
+func (s *stateUseCase) GetPlaybackState(ctx context.Context) (*PlaybackState, error) {
+	s.mu.Lock()
+	polled := s.polled
+	s.mu.Unlock()
+
+	if polled.at.IsZero() || time.Since(polled.at) >= stateFreshness {
+		return s.playerUseCase.GetPlaybackState(ctx)
+	}
+	if polled.err != nil {
+		return nil, polled.err
+	}
+
+	// The progress moves on since the poll
+	state := *polled.state
+	if state.IsPlaying {
+		state.ProgressMs += int(time.Since(polled.at).Milliseconds())
+		if state.DurationMs > 0 {
+			state.ProgressMs = min(state.ProgressMs, state.DurationMs)
+		}
+	}
+	return &state, nil
+}
+
+// poll polls the playback state for Watch and keeps it for GetPlaybackState, returning its
+// track. Without the playback feature only the current track is polled.
+func (s *stateUseCase) poll(ctx context.Context) (*CurrentlyPlaying, error) {
+	if s.tracksOnly {
+		return s.playerUseCase.GetCurrentlyPlayingDetails(ctx)
+	}
+
+	state, err := s.playerUseCase.GetPlaybackState(ctx)
+	var rateLimit *repository.RateLimitError
+	if err != nil && !errors.Is(err, ErrNothingPlaying) && !errors.As(err, &rateLimit) && ctx.Err() == nil {
+		// The current track can be read while the playback state can't, e.g. without its scope
+		if track, trackErr := s.playerUseCase.GetCurrentlyPlayingDetails(ctx); trackErr == nil {
+			s.tracksOnly = true
+			return track, nil
+		}
+	}
+
+	s.mu.Lock()
+	s.polled = playbackPoll{state: state, err: err, at: time.Now()}
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	track := state.CurrentlyPlaying
+	return &track, nil
+}
+
+// statePoller is the TrackSource of Watch, polling the playback state.
+type statePoller struct {
+	s *stateUseCase
+}
+
+// GetCurrentlyPlayingDetails implements TrackSource.
+func (p statePoller) GetCurrentlyPlayingDetails(ctx context.Context) (*CurrentlyPlaying, error) {
+	return p.s.poll(ctx)
+}
+
+// updateDevice puts the device of the last poll in the snapshot. Failed polls, and polls
+// without the playback-state scope, leave the device unchanged.
+func (s *stateUseCase) updateDevice(snapshot *entity.PlaybackSnapshot) {
+	s.mu.Lock()
+	state := s.polled.state
+	s.mu.Unlock()
+	if state == nil {
+		return
+	}
+
+	snapshot.Device = &entity.SnapshotDevice{
+		Name:          state.Device.Name,
+		Type:          state.Device.Type,
+		VolumePercent: state.Device.VolumePercent,
+	}
+}
+
+// applyTrack copies the track and play status into the snapshot.
+func applyTrack(snapshot *entity.PlaybackSnapshot, track *CurrentlyPlaying) {
+	if snapshot.Track == nil || snapshot.Track.ID != track.ID || snapshot.Track.Title != track.Title {
+		// The lyric line belongs to the previous track
+		snapshot.Lyric = nil
+	}
+
+	snapshot.Track = &entity.SnapshotTrack{
+		ID:         track.ID,
+		URI:        track.URI,
+		Title:      track.Title,
+		Artist:     track.Artist,
+		Album:      track.Album,
+		DurationMs: track.DurationMs,
+	}
+	snapshot.ProgressMs = track.ProgressMs
+
+	if track.IsPlaying {
+		snapshot.Status = entity.StatusPlaying
+	} else {
+		snapshot.Status = entity.StatusPaused
+	}
+}
+
+// snapshotHistory converts the recorded lyric history for the snapshot.
+func snapshotHistory(history *LyricHistory) []entity.SnapshotLyric {
+	lines := history.Lines()
+	snapshotLines := make([]entity.SnapshotLyric, len(lines))
+	for i, line := range lines {
+		snapshotLines[i] = entity.SnapshotLyric{
+			Index:       line.Index,
+			Text:        line.Text,
+			StartTimeMs: line.StartTimeMs,
+			EndTimeMs:   line.EndTimeMs,
+		}
+	}
+	return snapshotLines
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// TranslationUseCase defines the interface for translating lyrics line by line, to show the
+// translation beneath each line.
+type TranslationUseCase interface {
+	// TranslateLyrics returns the translation of each line of the lyrics, empty for empty lines
+	// and lines the translation doesn't change. Translations are cached per track.
+	TranslateLyrics(ctx context.Context, lyrics *Lyrics) ([]string, error)
+}
+
+// translationUseCase implements the TranslationUseCase interface.
+type translationUseCase struct {
+	translator repository.Translator
+	target     string
+	cache      map[string][]string
+	cacheLock  sync.Mutex
+}
+
+// NewTranslationUseCase creates a new instance of TranslationUseCase translating into the target
+// language, e.g. "en", with translator.
+func NewTranslationUseCase(translator repository.Translator, target string) TranslationUseCase {
+	return &translationUseCase{
+		translator: translator,
+		target:     target,
+		cache:      make(map[string][]string),
+	}
+}
+
+// TranslateLyrics translates each distinct line once.
+func (t *translationUseCase) TranslateLyrics(ctx context.Context, lyrics *Lyrics) ([]string, error) {
+	cacheKey := lyrics.Artist + "|" + lyrics.Name
+	t.cacheLock.Lock()
+	cached, found := t.cache[cacheKey]
+	t.cacheLock.Unlock()
+	if found && len(cached) == len(lyrics.Lines) {
+		return cached, nil
+	}
+
+	// Choruses repeat, so every distinct line is only sent once
+	var texts []string
+	index := make(map[string]int)
+	for _, line := range lyrics.Lines {
+		text := strings.TrimSpace(line.Text)
+		if _, seen := index[text]; text != "" && !seen {
+			index[text] = len(texts)
+			texts = append(texts, text)
+		}
+	}
+
+	translations := make([]string, len(lyrics.Lines))
+	if len(texts) > 0 {
+		translated, err := t.translator.Translate(ctx, texts, t.target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate the lyrics of %s: %w", lyrics.Name, err)
+		}
+		for i, line := range lyrics.Lines {
+			text := strings.TrimSpace(line.Text)
+			if text == "" {
+				continue
+			}
+			if translation := strings.TrimSpace(translated[index[text]]); !strings.EqualFold(translation, text) {
+				translations[i] = translation
+			}
+		}
+	}
+
+	t.cacheLock.Lock()
+	t.cache[cacheKey] = translations
+	t.cacheLock.Unlock()
+
+	return translations, nil
+}
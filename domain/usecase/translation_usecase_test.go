@@ -0,0 +1,49 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// prefixTranslator "translates" by prefixing the target language, counting the texts sent.
+type prefixTranslator struct {
+	sent int
+}
+
+func (p *prefixTranslator) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	p.sent += len(texts)
+	translations := make([]string, len(texts))
+	for i, text := range texts {
+		translations[i] = target + ": " + text
+		if text == "OK" {
+			translations[i] = "ok"
+		}
+	}
+	return translations, nil
+}
+
+func TestTranslateLyricsSendsDistinctLinesOnce(t *testing.T) {
+	translator := &prefixTranslator{}
+	translationUseCase := usecase.NewTranslationUseCase(translator, "en")
+	lyrics := &usecase.Lyrics{Artist: "Artist", Name: "Song", Lines: []usecase.Line{
+		{Text: "Hola"}, {Text: ""}, {Text: " Hola "}, {Text: "OK"},
+	}}
+
+	for range 2 {
+		translations, err := translationUseCase.TranslateLyrics(context.Background(), lyrics)
+		if err != nil {
+			t.Fatalf("TranslateLyrics() error = %v", err)
+		}
+		want := []string{"en: Hola", "", "en: Hola", ""}
+		for i := range want {
+			if translations[i] != want[i] {
+				t.Errorf("translation %d = %q, want %q", i, translations[i], want[i])
+			}
+		}
+	}
+	if translator.sent != 2 {
+		t.Errorf("sent %d lines, want Hola and OK once", translator.sent)
+	}
+}
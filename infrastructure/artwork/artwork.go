@@ -0,0 +1,55 @@
+// Package artwork downloads album and episode covers.
+package artwork
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Spotify serves covers as JPEG
+	_ "image/png"
+	"io"
+	"net/http"
+)
+
+// Fetch downloads and decodes the image at the given URL with the given client.
+func Fetch(ctx context.Context, client *http.Client, url string) (image.Image, error) {
+	data, err := download(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// download requests the image at the given URL and returns its encoded contents.
+func download(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cover request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download cover: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover request failed with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download cover: %w", err)
+	}
+	return data, nil
+}
+
+// decode decodes an encoded JPEG or PNG image.
+func decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cover: %w", err)
+	}
+	return img, nil
+}
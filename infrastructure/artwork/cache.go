@@ -0,0 +1,189 @@
+package artwork
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cacheIndexFile maps the URLs of the cached images to the hashes of their contents.
+const cacheIndexFile = "index.json"
+
+// cacheExt is the extension of the cached image files, which may be JPEG or PNG.
+const cacheExt = ".img"
+
+// Cache keeps downloaded images on disk so that repeat plays don't download them again.
+// The files are named by the SHA-256 hash of their contents, so an image served under several
+// URLs is stored once. Beyond the size limit the least recently used images are evicted.
+type Cache struct {
+	client   *http.Client
+	dir      string
+	maxBytes int64
+}
+
+// NewCache creates a cache of at most maxBytes in dir that downloads with the given client.
+// An empty dir defaults to ~/.sprt/cache/art; maxBytes of 0 or less turns caching off.
+func NewCache(client *http.Client, dir string, maxBytes int64) *Cache {
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		dir = filepath.Join(homeDir, ".sprt", "cache", "art")
+	}
+	return &Cache{client: client, dir: dir, maxBytes: maxBytes}
+}
+
+// Fetch returns the image at the given URL, from the cache when it was downloaded before.
+// Failing to use the cache falls back to downloading the image.
+func (c *Cache) Fetch(ctx context.Context, url string) (image.Image, error) {
+	if c.maxBytes <= 0 {
+		return Fetch(ctx, c.client, url)
+	}
+
+	index := c.loadIndex()
+	if hash, ok := index[url]; ok {
+		path := c.path(hash)
+		if data, err := os.ReadFile(path); err == nil {
+			// The modification time records the last use for the eviction
+			now := time.Now()
+			_ = os.Chtimes(path, now, now)
+			return decode(data)
+		}
+	}
+
+	data, err := download(ctx, c.client, url)
+	if err != nil {
+		return nil, err
+	}
+	img, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only images that decode are cached; a failure to store one doesn't fail the fetch
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if err := c.store(hash, data); err == nil {
+		index[url] = hash
+		c.evict(index)
+		_ = c.saveIndex(index)
+	}
+	return img, nil
+}
+
+// path returns the file of the image with the given content hash.
+func (c *Cache) path(hash string) string {
+	return filepath.Join(c.dir, hash+cacheExt)
+}
+
+// loadIndex reads the URL index, which is empty when it doesn't exist yet or can't be read.
+func (c *Cache) loadIndex() map[string]string {
+	index := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(c.dir, cacheIndexFile))
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return make(map[string]string)
+	}
+	return index
+}
+
+// saveIndex replaces the URL index.
+func (c *Cache) saveIndex(index map[string]string) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to encode art cache index: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(c.dir, cacheIndexFile), data)
+}
+
+// store writes an image under its content hash.
+func (c *Cache) store(hash string, data []byte) error {
+	return writeFileAtomic(c.path(hash), data)
+}
+
+// evict removes the least recently used images until the cache fits its size limit,
+// and drops the URLs of images that are gone from the index.
+func (c *Cache) evict(index map[string]string) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type cachedFile struct {
+		hash   string
+		size   int64
+		usedAt time.Time
+	}
+	var files []cachedFile
+	var total int64
+	for _, entry := range entries {
+		hash, ok := strings.CutSuffix(entry.Name(), cacheExt)
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{hash: hash, size: info.Size(), usedAt: info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].usedAt.Before(files[j].usedAt)
+	})
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		if total > c.maxBytes && os.Remove(c.path(file.hash)) == nil {
+			total -= file.size
+			continue
+		}
+		present[file.hash] = true
+	}
+
+	for url, hash := range index {
+		if !present[hash] {
+			delete(index, url)
+		}
+	}
+}
+
+// writeFileAtomic replaces the file at path through a temporary file, so that other processes
+// never read a partly written file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// Package editor opens files in the user's text editor.
+package editor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Command returns the editor command: $VISUAL or $EDITOR, which may have arguments such as
+// "code --wait", else vi, or Notepad on Windows.
+func Command() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// Open opens path in the editor on the terminal and waits for the editor to exit.
+func Open(ctx context.Context, path string) error {
+	command := Command()
+	cmd := exec.CommandContext(ctx, command[0], append(command[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run the editor %s: %w", command[0], err)
+	}
+	return nil
+}
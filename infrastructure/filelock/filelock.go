@@ -0,0 +1,87 @@
+// Package filelock serializes changes to the files in ~/.sprt between sprt processes, such as
+// the daemon and a one-shot command refreshing the token at once, with advisory locks held on
+// a lock file next to each file. Platforms without flock don't lock.
+package filelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// retryInterval is how often Lock tries again while another process holds the lock.
+const retryInterval = 20 * time.Millisecond
+
+// ErrLocked is returned by TryLock while another process holds the lock.
+var ErrLocked = errors.New("locked by another process")
+
+// Lock takes the lock of the file at path, waiting while another process holds it until ctx
+// ends. The returned function releases it.
+func Lock(ctx context.Context, path string) (func(), error) {
+	file, err := openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			return release(file), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// TryLock takes the lock of the file at path, or returns ErrLocked while another process
+// holds it. The returned function releases it.
+func TryLock(path string) (func(), error) {
+	file, err := openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := tryLock(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	if !locked {
+		file.Close()
+		return nil, ErrLocked
+	}
+	return release(file), nil
+}
+
+// openLockFile opens the lock file of path, creating it and its directory when missing.
+// Lock files are left in place, as removing one could let two processes lock different files.
+func openLockFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	return file, nil
+}
+
+// release returns a function unlocking and closing the lock file.
+func release(file *os.File) func() {
+	return func() {
+		unlock(file)
+		file.Close()
+	}
+}
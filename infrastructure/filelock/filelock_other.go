@@ -0,0 +1,13 @@
+//go:build !unix
+
+package filelock
+
+import "os"
+
+// tryLock always succeeds, as files aren't locked on this platform.
+func tryLock(file *os.File) (bool, error) {
+	return true, nil
+}
+
+// unlock does nothing, as files aren't locked on this platform.
+func unlock(file *os.File) {}
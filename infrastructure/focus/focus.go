@@ -0,0 +1,55 @@
+// Package focus detects whether the operating system's Do-Not-Disturb or Focus mode is active.
+package focus
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Do-Not-Disturb modes accepted in the configuration.
+const (
+	ModeAuto = "auto" // Detect the OS state
+	ModeOn   = "on"   // Always behave as if Do-Not-Disturb is active
+	ModeOff  = "off"  // Never suppress anything
+)
+
+// commandTimeout bounds how long a single detection command may run.
+const commandTimeout = time.Second
+
+// Suppressed reports whether notifications and hooks should be held back
+// for the given configured mode.
+func Suppressed(mode string) bool {
+	switch strings.ToLower(mode) {
+	case ModeOn:
+		return true
+	case ModeOff:
+		return false
+	default:
+		return IsDoNotDisturb()
+	}
+}
+
+// IsDoNotDisturb reports whether the OS Do-Not-Disturb/Focus mode is active.
+// Detection failures are treated as "not active".
+func IsDoNotDisturb() bool {
+	return isDoNotDisturb()
+}
+
+// commandOutput runs a command and returns its trimmed output.
+func commandOutput(name string, args ...string) (string, bool) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(string(out)), true
+}
@@ -0,0 +1,33 @@
+package focus
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// isDoNotDisturb checks the Focus assertions on macOS 12+ and the legacy
+// Do Not Disturb preference on older releases.
+func isDoNotDisturb() bool {
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		data, err := os.ReadFile(filepath.Join(homeDir, "Library", "DoNotDisturb", "DB", "Assertions.json"))
+		if err == nil {
+			var assertions struct {
+				Data []struct {
+					StoreAssertionRecords []json.RawMessage `json:"storeAssertionRecords"`
+				} `json:"data"`
+			}
+			if json.Unmarshal(data, &assertions) == nil {
+				for _, d := range assertions.Data {
+					if len(d.StoreAssertionRecords) > 0 {
+						return true
+					}
+				}
+				return false
+			}
+		}
+	}
+
+	out, ok := commandOutput("defaults", "-currentHost", "read", "com.apple.notificationcenterui", "doNotDisturb")
+	return ok && out == "1"
+}
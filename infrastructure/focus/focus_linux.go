@@ -0,0 +1,29 @@
+package focus
+
+import "strings"
+
+// isDoNotDisturb checks the notification daemons commonly found on Linux desktops.
+func isDoNotDisturb() bool {
+	// GNOME hides banners while Do Not Disturb is enabled
+	if out, ok := commandOutput("gsettings", "get", "org.gnome.desktop.notifications", "show-banners"); ok && out == "false" {
+		return true
+	}
+
+	// KDE Plasma and other freedesktop servers expose an Inhibited property
+	if out, ok := commandOutput("qdbus", "org.freedesktop.Notifications", "/org/freedesktop/Notifications",
+		"org.freedesktop.Notifications.Inhibited"); ok && out == "true" {
+		return true
+	}
+
+	// dunst
+	if out, ok := commandOutput("dunstctl", "is-paused"); ok && out == "true" {
+		return true
+	}
+
+	// mako
+	if out, ok := commandOutput("makoctl", "mode"); ok && strings.Contains(out, "do-not-disturb") {
+		return true
+	}
+
+	return false
+}
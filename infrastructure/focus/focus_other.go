@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package focus
+
+// isDoNotDisturb is not supported on this platform.
+func isDoNotDisturb() bool {
+	return false
+}
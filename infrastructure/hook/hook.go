@@ -0,0 +1,155 @@
+// Package hook runs the shell commands configured for playback events, passing the track and
+// the lyric line in environment variables, e.g. for scrobbling scripts, wallpapers and logs.
+package hook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// Events hooks can be configured for.
+const (
+	EventTrack = "track" // Another track started
+	EventPlay  = "play"  // Playback started or resumed
+	EventPause = "pause" // Playback was paused
+	EventStop  = "stop"  // Playback stopped, nothing is playing
+	EventLyric = "lyric" // The current lyric line changed
+)
+
+// Timeout bounds how long a hook command may run before it is killed.
+const Timeout = 30 * time.Second
+
+// queueSize is how many events may wait for the hooks before further ones are dropped.
+const queueSize = 64
+
+// Runner runs the commands of the hooks. Commands run one after another in the order of their
+// events, so a slow hook delays the later ones but never sees them out of order.
+type Runner struct {
+	commands map[string][]string
+	queue    chan job
+}
+
+// job is a command waiting to run with its environment.
+type job struct {
+	event   string
+	command string
+	env     []string
+}
+
+// New creates a runner for the configured hooks.
+func New(hooks []config.HookConfig) (*Runner, error) {
+	r := &Runner{
+		commands: make(map[string][]string),
+		queue:    make(chan job, queueSize),
+	}
+	for _, h := range hooks {
+		switch h.Event {
+		case EventTrack, EventPlay, EventPause, EventStop, EventLyric:
+		default:
+			return nil, fmt.Errorf("invalid hook %q in the config: unknown event %q (expected track, play, pause, stop or lyric)", h.Command, h.Event)
+		}
+		if strings.TrimSpace(h.Command) == "" {
+			return nil, fmt.Errorf("invalid hook for %s in the config: the command is empty", h.Event)
+		}
+		r.commands[h.Event] = append(r.commands[h.Event], h.Command)
+	}
+	return r, nil
+}
+
+// Fire queues the commands of the hooks for event, with the playback state after it. Events
+// arriving while the queue is full are dropped with a warning rather than holding up playback.
+func (r *Runner) Fire(event string, state *entity.PlaybackSnapshot) {
+	commands := r.commands[event]
+	if len(commands) == 0 {
+		return
+	}
+
+	env := append(os.Environ(), environment(event, state)...)
+	for _, command := range commands {
+		select {
+		case r.queue <- job{event: event, command: command, env: env}:
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: skipped the %s hook %q, too many hooks are waiting\n", event, command)
+		}
+	}
+}
+
+// Run runs the queued commands until ctx ends, which kills a command still running.
+func (r *Runner) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-r.queue:
+			if err := run(ctx, j); err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Warning: the %s hook %q failed: %v\n", j.event, j.command, err)
+			}
+		}
+	}
+}
+
+// run runs a command with the shell, sharing the output of sprt.
+func run(ctx context.Context, j job) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", j.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", j.command)
+	}
+	cmd.Env = j.env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("killed after %s", Timeout)
+	}
+	return err
+}
+
+// environment returns the variables describing an event and the playback state after it.
+// The variables of a missing track, device or lyric line are empty.
+func environment(event string, state *entity.PlaybackSnapshot) []string {
+	var trackID, uri, title, artist, album, durationMs, device string
+	if track := state.Track; track != nil {
+		trackID, uri, title, artist, album = track.ID, track.URI, track.Title, track.Artist, track.Album
+		durationMs = strconv.Itoa(track.DurationMs)
+	}
+	if state.Device != nil {
+		device = state.Device.Name
+	}
+	var lyric, lyricIndex, lyricStartMs, lyricEndMs string
+	if line := state.Lyric; line != nil {
+		lyric, lyricIndex = line.Text, strconv.Itoa(line.Index)
+		lyricStartMs, lyricEndMs = strconv.Itoa(line.StartTimeMs), strconv.Itoa(line.EndTimeMs)
+	}
+
+	return []string{
+		"SPRT_EVENT=" + event,
+		"SPRT_STATUS=" + state.Status,
+		"SPRT_PROGRESS_MS=" + strconv.Itoa(state.ProgressMs),
+		"SPRT_TRACK_ID=" + trackID,
+		"SPRT_TRACK_URI=" + uri,
+		"SPRT_TITLE=" + title,
+		"SPRT_ARTIST=" + artist,
+		"SPRT_ALBUM=" + album,
+		"SPRT_DURATION_MS=" + durationMs,
+		"SPRT_DEVICE=" + device,
+		"SPRT_LYRIC=" + lyric,
+		"SPRT_LYRIC_INDEX=" + lyricIndex,
+		"SPRT_LYRIC_START_MS=" + lyricStartMs,
+		"SPRT_LYRIC_END_MS=" + lyricEndMs,
+	}
+}
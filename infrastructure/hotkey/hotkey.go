@@ -0,0 +1,20 @@
+// Package hotkey registers system-wide keyboard shortcuts with the desktop environment.
+package hotkey
+
+import "errors"
+
+// ErrUnsupported is returned where sprt can't register shortcuts with the desktop environment.
+var ErrUnsupported = errors.New("global hotkeys are not supported on this desktop")
+
+// Binding is a shortcut running a command from anywhere on the desktop.
+type Binding struct {
+	Name    string // Unique name of the shortcut, e.g. "next"
+	Keys    string // Accelerator, e.g. "<Super>F9"
+	Command string // Command line run when the keys are pressed
+}
+
+// Register registers the bindings with the desktop environment and returns a function
+// removing them again. Registering a binding again replaces it.
+func Register(bindings []Binding) (unregister func() error, err error) {
+	return register(bindings)
+}
@@ -0,0 +1,137 @@
+package hotkey
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GNOME settings holding the custom keyboard shortcuts.
+const (
+	mediaKeysSchema        = "org.gnome.settings-daemon.plugins.media-keys"
+	customKeybindingSchema = "org.gnome.settings-daemon.plugins.media-keys.custom-keybinding"
+	customKeybindingsKey   = "custom-keybindings"
+	customKeybindingDir    = "/org/gnome/settings-daemon/plugins/media-keys/custom-keybindings/"
+)
+
+// commandTimeout bounds how long a single gsettings command may run.
+const commandTimeout = 5 * time.Second
+
+// register adds the bindings to GNOME's custom keyboard shortcuts.
+func register(bindings []Binding) (func() error, error) {
+	if !isGNOME() {
+		return nil, ErrUnsupported
+	}
+
+	paths := make([]string, len(bindings))
+	for i, b := range bindings {
+		paths[i] = customKeybindingDir + "sprt-" + b.Name + "/"
+		schema := customKeybindingSchema + ":" + paths[i]
+		for _, kv := range [][2]string{{"name", "sprt " + b.Name}, {"command", b.Command}, {"binding", b.Keys}} {
+			if _, err := gsettings("set", schema, kv[0], quote(kv[1])); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Shortcuts only take effect once they are listed
+	list, err := customKeybindings()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if !slices.Contains(list, path) {
+			list = append(list, path)
+		}
+	}
+	if err := setCustomKeybindings(list); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return unregister(paths)
+	}, nil
+}
+
+// unregister removes the shortcuts at paths from GNOME's custom keyboard shortcuts.
+func unregister(paths []string) error {
+	list, err := customKeybindings()
+	if err != nil {
+		return err
+	}
+	list = slices.DeleteFunc(list, func(path string) bool {
+		return slices.Contains(paths, path)
+	})
+	if err := setCustomKeybindings(list); err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		schema := customKeybindingSchema + ":" + path
+		for _, key := range []string{"name", "command", "binding"} {
+			if _, err := gsettings("reset", schema, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isGNOME reports whether sprt runs on a GNOME desktop with gsettings available.
+func isGNOME() bool {
+	if !strings.Contains(strings.ToUpper(os.Getenv("XDG_CURRENT_DESKTOP")), "GNOME") {
+		return false
+	}
+	_, err := exec.LookPath("gsettings")
+	return err == nil
+}
+
+// customKeybindings returns the paths of the listed custom keyboard shortcuts.
+func customKeybindings() ([]string, error) {
+	out, err := gsettings("get", mediaKeysSchema, customKeybindingsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The list is printed as "['/path/one/', '/path/two/']", or "@as []" when empty
+	out = strings.TrimPrefix(out, "@as ")
+	out = strings.Trim(out, "[]")
+	var list []string
+	for _, item := range strings.Split(out, ",") {
+		if item = strings.Trim(strings.TrimSpace(item), "'"); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+// setCustomKeybindings replaces the list of custom keyboard shortcuts.
+func setCustomKeybindings(list []string) error {
+	quoted := make([]string, len(list))
+	for i, path := range list {
+		quoted[i] = quote(path)
+	}
+	_, err := gsettings("set", mediaKeysSchema, customKeybindingsKey, "["+strings.Join(quoted, ", ")+"]")
+	return err
+}
+
+// quote returns s as a GVariant string literal.
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// gsettings runs a gsettings command and returns its trimmed output.
+func gsettings(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "gsettings", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to %s GNOME keyboard shortcuts: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package hotkey
+
+// register is not supported on this platform.
+func register(bindings []Binding) (func() error, error) {
+	return nil, ErrUnsupported
+}
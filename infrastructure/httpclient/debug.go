@@ -0,0 +1,65 @@
+package httpclient
+
+import (
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DebugEnv is the environment variable enabling the debug log: "1" logs to stderr,
+// any other value except "0" and "false" is the path of a log file.
+const DebugEnv = "SPRT_DEBUG"
+
+// debugLog receives one line per outbound request while debug logging is enabled.
+var debugLog atomic.Pointer[log.Logger]
+
+// SetDebugLog logs every outbound request of all clients to w; nil turns logging off.
+func SetDebugLog(w io.Writer) {
+	if w == nil {
+		debugLog.Store(nil)
+		return
+	}
+	debugLog.Store(log.New(w, "sprt: ", log.LstdFlags|log.Lmicroseconds))
+}
+
+// logTransport logs the method, URL, status, latency and rate-limit headers of each round trip.
+// Headers and bodies are never logged, so tokens stay out of the log.
+type logTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := debugLog.Load()
+	if logger == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		logger.Printf("%s %s error after %s: %v", req.Method, req.URL.Redacted(), latency, err)
+		return resp, err
+	}
+
+	logger.Printf("%s %s %d %s%s", req.Method, req.URL.Redacted(), resp.StatusCode, latency, rateLimitHeaders(resp.Header))
+	return resp, err
+}
+
+// rateLimitHeaders formats the headers servers use to announce rate limits, if any are set.
+func rateLimitHeaders(header http.Header) string {
+	var sb strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(header)) {
+		if name == "Retry-After" || strings.HasPrefix(name, "X-Ratelimit-") {
+			sb.WriteString(" " + name + "=" + strings.Join(header[name], ","))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+// Package httpclient builds the HTTP clients used for the Spotify and lrclib APIs.
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+)
+
+// Defaults used when the configuration leaves a value at zero.
+const (
+	DefaultConnectTimeout = 10 * time.Second
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// retryBaseDelay is the wait before the first retry; it doubles with each further retry.
+const retryBaseDelay = 500 * time.Millisecond
+
+// New creates an HTTP client with the configured timeouts that retries requests failing
+// with a transient network error or a 502, 503 or 504 status, backing off exponentially.
+// Requests go through the configured proxy, or the one from HTTP_PROXY and HTTPS_PROXY, and
+// trust the configured CA bundle in addition to the system's. Requests of a client with an
+// invalid proxy or CA bundle fail with the configuration error.
+func New(cfg config.APIConfig) *http.Client {
+	connectTimeout := seconds(cfg.ConnectTimeoutSeconds, DefaultConnectTimeout)
+	requestTimeout := seconds(cfg.RequestTimeoutSeconds, DefaultRequestTimeout)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+
+	var base http.RoundTripper = transport
+	retries := max(cfg.NetworkRetries, 0)
+	err := configureProxy(transport, cfg.Proxy)
+	if err == nil {
+		err = configureCA(transport, cfg.CAFile)
+	}
+	if err != nil {
+		// Retrying can't fix the configuration
+		base = errorTransport{err: err}
+		retries = 0
+	}
+
+	return &http.Client{
+		// The timeout covers the whole request, including retries and reading the body
+		Timeout: requestTimeout,
+		Transport: &retryTransport{
+			// Every attempt is counted and logged, retries included
+			base:    &metricsTransport{base: &logTransport{base: base}},
+			retries: retries,
+		},
+	}
+}
+
+// configureProxy sends requests through the proxy at rawURL, e.g. "http://proxy:3128" or
+// "socks5://127.0.0.1:1080". Empty keeps the proxy from the environment.
+func configureProxy(transport *http.Transport, rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil || proxyURL.Host == "" {
+		return fmt.Errorf("invalid api.proxy %q in the config", rawURL)
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return nil
+}
+
+// configureCA trusts the PEM certificates in file in addition to the system's. Empty trusts only the system.
+func configureCA(transport *http.Transport, file string) error {
+	if file == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read api.caFile: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("api.caFile %s contains no PEM certificates", file)
+	}
+
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return nil
+}
+
+// errorTransport fails every request, for clients with an invalid configuration.
+type errorTransport struct {
+	err error
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
+
+// seconds converts a configured number of seconds, falling back to def for zero or less.
+func seconds(n int, def time.Duration) time.Duration {
+	if n <= 0 {
+		return def
+	}
+	return time.Duration(n) * time.Second
+}
+
+// retryTransport retries requests that failed for transient reasons. Requests that change
+// something, like adding to the queue, are only retried when they weren't sent at all.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.retries || !transient(req, resp, err) || !rewindable(req) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(retryBaseDelay << attempt):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// transient reports whether a failed round trip may succeed when tried again. Only GET and HEAD
+// requests may have reached the server before failing; others are retried when the connection
+// couldn't be made, as they would be carried out twice otherwise.
+func transient(req *http.Request, resp *http.Response, err error) bool {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	if err != nil {
+		// Cancelled requests must not be retried, everything else on the network level may be
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var opErr *net.OpError
+		return idempotent || errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	if !idempotent {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// rewindable reports whether the request body can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/muhadif/sprt/infrastructure/metrics"
+)
+
+// metricsTransport counts each round trip for the metrics endpoint.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	metrics.ObserveRequest(req.URL.Host, status, time.Since(start))
+
+	return resp, err
+}
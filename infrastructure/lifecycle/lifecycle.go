@@ -0,0 +1,149 @@
+// Package lifecycle stops what sprt started when it quits, whether the command returns or sprt
+// is interrupted with SIGINT or SIGTERM: goroutines end with the context of the manager, and
+// servers, sockets and files are closed by the shutdown hooks registered for them.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ShutdownTimeout bounds how long the shutdown waits for goroutines and hooks.
+const ShutdownTimeout = 5 * time.Second
+
+// ForceExitAfter is how long a command may keep running after a signal, e.g. while blocked
+// reading a prompt, before the manager shuts down and exits by itself.
+const ForceExitAfter = time.Second
+
+// Manager ends the context of a command on SIGINT or SIGTERM and runs the cleanup once it's over.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	hooks    []hook
+	signal   os.Signal
+	shutdown sync.Once
+	err      error
+}
+
+// hook is a named cleanup function run at shutdown.
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// New creates a manager whose context ends with parent or on the first SIGINT or SIGTERM.
+// A second signal kills sprt without waiting for the cleanup.
+func New(parent context.Context) *Manager {
+	ctx, cancel := context.WithCancel(parent)
+	m := &Manager{ctx: ctx, cancel: cancel}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			m.interrupt(sig)
+		case <-ctx.Done():
+		}
+	}()
+	return m
+}
+
+// interrupt ends the context after sig and exits if the command doesn't return in time.
+func (m *Manager) interrupt(sig os.Signal) {
+	m.mu.Lock()
+	m.signal = sig
+	m.mu.Unlock()
+	m.cancel()
+
+	time.AfterFunc(ForceExitAfter, func() {
+		if err := m.Shutdown(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		os.Exit(m.ExitCode())
+	})
+}
+
+// Context returns the context commands run in, which ends on SIGINT, SIGTERM or shutdown.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Signal returns the signal that interrupted sprt, or nil.
+func (m *Manager) Signal() os.Signal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.signal
+}
+
+// ExitCode returns the exit status for the signal that interrupted sprt: 128 plus the signal
+// number as shells report it, or 0 when sprt wasn't interrupted.
+func (m *Manager) ExitCode() int {
+	if sig, ok := m.Signal().(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return 0
+}
+
+// Go runs fn in a goroutine that the shutdown waits for. fn must return once ctx ends.
+func (m *Manager) Go(fn func(ctx context.Context)) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		fn(m.ctx)
+	}()
+}
+
+// OnShutdown registers fn to run at shutdown, after the goroutines ended. Hooks run in the
+// reverse order of registration, so resources are released before the ones they depend on.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// Shutdown ends the context, waits for the goroutines started with Go and runs the hooks,
+// together bounded by ShutdownTimeout. Only the first call shuts down; later calls wait for
+// it and return the same error.
+func (m *Manager) Shutdown() error {
+	m.shutdown.Do(func() {
+		m.cancel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+
+		done := make(chan struct{})
+		go func() {
+			m.wg.Wait()
+			close(done)
+		}()
+		var errs []error
+		select {
+		case <-done:
+		case <-ctx.Done():
+			errs = append(errs, errors.New("timed out waiting for background tasks to stop"))
+		}
+
+		m.mu.Lock()
+		hooks := m.hooks
+		m.hooks = nil
+		m.mu.Unlock()
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if err := hooks[i].fn(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("failed to stop %s: %w", hooks[i].name, err))
+			}
+		}
+		m.err = errors.Join(errs...)
+	})
+	return m.err
+}
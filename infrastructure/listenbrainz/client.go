@@ -0,0 +1,153 @@
+// Package listenbrainz submits listens to ListenBrainz, or to a server with the same API.
+package listenbrainz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// DefaultURL is the API of listenbrainz.org.
+const DefaultURL = "https://api.listenbrainz.org"
+
+// Types of submissions.
+const (
+	listenSingle     = "single"
+	listenImport     = "import"
+	listenPlayingNow = "playing_now"
+)
+
+// Client submits listens with a user token. It implements repository.ListenSubmitter.
+type Client struct {
+	httpClient *http.Client
+	url        string
+	token      string
+}
+
+// New creates a client submitting with the token and to the server of cfg.
+func New(httpClient *http.Client, cfg config.ListenBrainzConfig) *Client {
+	url := strings.TrimSuffix(cfg.URL, "/")
+	if url == "" {
+		url = DefaultURL
+	}
+	return &Client{httpClient: httpClient, url: url, token: cfg.Token}
+}
+
+// Name returns the name of the service.
+func (c *Client) Name() string {
+	return "ListenBrainz"
+}
+
+// NowPlaying reports the track that just started playing.
+func (c *Client) NowPlaying(ctx context.Context, play entity.Play) error {
+	return c.submit(ctx, listenPlayingNow, []listen{{TrackMetadata: metadata(play)}})
+}
+
+// SubmitListens submits finished listens.
+func (c *Client) SubmitListens(ctx context.Context, plays []entity.Play) error {
+	if len(plays) == 0 {
+		return nil
+	}
+
+	listens := make([]listen, len(plays))
+	for i, play := range plays {
+		listens[i] = listen{ListenedAt: play.PlayedAt.Unix(), TrackMetadata: metadata(play)}
+	}
+	listenType := listenImport
+	if len(listens) == 1 {
+		listenType = listenSingle
+	}
+	return c.submit(ctx, listenType, listens)
+}
+
+// submission is the body of a submit-listens request.
+type submission struct {
+	ListenType string   `json:"listen_type"`
+	Payload    []listen `json:"payload"`
+}
+
+// listen is a listen of a submission; playing now reports have no time.
+type listen struct {
+	ListenedAt    int64         `json:"listened_at,omitempty"`
+	TrackMetadata trackMetadata `json:"track_metadata"`
+}
+
+// trackMetadata describes the track of a listen.
+type trackMetadata struct {
+	ArtistName     string         `json:"artist_name"`
+	TrackName      string         `json:"track_name"`
+	ReleaseName    string         `json:"release_name,omitempty"`
+	AdditionalInfo additionalInfo `json:"additional_info"`
+}
+
+// additionalInfo tells ListenBrainz where a listen comes from.
+type additionalInfo struct {
+	DurationMs       int    `json:"duration_ms,omitempty"`
+	SpotifyID        string `json:"spotify_id,omitempty"`
+	OriginURL        string `json:"origin_url,omitempty"`
+	MediaPlayer      string `json:"media_player"`
+	SubmissionClient string `json:"submission_client"`
+	MusicService     string `json:"music_service"`
+}
+
+// metadata describes the track of a play.
+func metadata(play entity.Play) trackMetadata {
+	info := additionalInfo{
+		DurationMs:       play.DurationMs,
+		MediaPlayer:      "Spotify",
+		SubmissionClient: "sprt",
+		MusicService:     "spotify.com",
+	}
+	if play.TrackID != "" {
+		info.SpotifyID = "https://open.spotify.com/track/" + play.TrackID
+		info.OriginURL = info.SpotifyID
+	}
+	return trackMetadata{
+		ArtistName:     play.Artist,
+		TrackName:      play.Title,
+		ReleaseName:    play.Album,
+		AdditionalInfo: info,
+	}
+}
+
+// submit posts listens to /1/submit-listens.
+func (c *Client) submit(ctx context.Context, listenType string, listens []listen) error {
+	body, err := json.Marshal(submission{ListenType: listenType, Payload: listens})
+	if err != nil {
+		return fmt.Errorf("failed to encode listens: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/1/submit-listens", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach ListenBrainz: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// Errors come as {"code": 401, "error": "Invalid authorization token."}
+	var failure struct {
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(data, &failure) != nil || failure.Error == "" {
+		failure.Error = strings.TrimSpace(string(data))
+	}
+	return fmt.Errorf("ListenBrainz request failed with status %d: %s", resp.StatusCode, failure.Error)
+}
@@ -0,0 +1,106 @@
+// Package metrics counts the requests sprt makes and writes them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// requestKey identifies a series of API request counters.
+type requestKey struct {
+	host   string
+	status string // HTTP status code, or "error" for network errors
+}
+
+// registry holds the counters of the process.
+var registry = struct {
+	mu          sync.Mutex
+	requests    map[requestKey]int64
+	durations   map[string]float64 // Seconds spent per host
+	rateLimited map[string]int64
+}{
+	requests:    make(map[requestKey]int64),
+	durations:   make(map[string]float64),
+	rateLimited: make(map[string]int64),
+}
+
+// ObserveRequest counts a request to host that ended with status after d.
+// A status of 0 counts a network error.
+func ObserveRequest(host string, status int, d time.Duration) {
+	key := requestKey{host: host, status: "error"}
+	if status != 0 {
+		key.status = strconv.Itoa(status)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.requests[key]++
+	registry.durations[host] += d.Seconds()
+	if status == 429 {
+		registry.rateLimited[host]++
+	}
+}
+
+// Write writes all metrics in the Prometheus text format. The now-playing gauge is 1 while
+// track plays and 0 while it is paused; it is left out when track is nil.
+func Write(w io.Writer, track *usecase.CurrentlyPlaying) error {
+	var sb strings.Builder
+
+	registry.mu.Lock()
+	sb.WriteString("# HELP sprt_api_requests_total Requests sent to the Spotify and lyrics APIs, including retries.\n")
+	sb.WriteString("# TYPE sprt_api_requests_total counter\n")
+	keys := slices.SortedFunc(maps.Keys(registry.requests), func(a, b requestKey) int {
+		return strings.Compare(a.host+" "+a.status, b.host+" "+b.status)
+	})
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "sprt_api_requests_total{host=%s,status=%s} %d\n", quote(key.host), quote(key.status), registry.requests[key])
+	}
+
+	sb.WriteString("# HELP sprt_api_request_duration_seconds_total Time spent waiting for API responses.\n")
+	sb.WriteString("# TYPE sprt_api_request_duration_seconds_total counter\n")
+	for _, host := range slices.Sorted(maps.Keys(registry.durations)) {
+		fmt.Fprintf(&sb, "sprt_api_request_duration_seconds_total{host=%s} %g\n", quote(host), registry.durations[host])
+	}
+
+	sb.WriteString("# HELP sprt_rate_limit_hits_total Requests rejected with 429 Too Many Requests.\n")
+	sb.WriteString("# TYPE sprt_rate_limit_hits_total counter\n")
+	for _, host := range slices.Sorted(maps.Keys(registry.rateLimited)) {
+		fmt.Fprintf(&sb, "sprt_rate_limit_hits_total{host=%s} %d\n", quote(host), registry.rateLimited[host])
+	}
+	registry.mu.Unlock()
+
+	hits, misses := usecase.LyricCacheStats()
+	sb.WriteString("# HELP sprt_lyric_cache_hits_total Lyric lookups answered from the cache.\n")
+	sb.WriteString("# TYPE sprt_lyric_cache_hits_total counter\n")
+	fmt.Fprintf(&sb, "sprt_lyric_cache_hits_total %d\n", hits)
+	sb.WriteString("# HELP sprt_lyric_cache_misses_total Lyric lookups fetched from lrclib.net.\n")
+	sb.WriteString("# TYPE sprt_lyric_cache_misses_total counter\n")
+	fmt.Fprintf(&sb, "sprt_lyric_cache_misses_total %d\n", misses)
+
+	sb.WriteString("# HELP sprt_now_playing The current track; 1 while playing, 0 while paused.\n")
+	sb.WriteString("# TYPE sprt_now_playing gauge\n")
+	if track != nil {
+		playing := 0
+		if track.IsPlaying {
+			playing = 1
+		}
+		fmt.Fprintf(&sb, "sprt_now_playing{title=%s,artist=%s,album=%s} %d\n", quote(track.Title), quote(track.Artist), quote(track.Album), playing)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// quote quotes a label value, escaping backslashes, quotes and newlines.
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
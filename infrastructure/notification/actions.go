@@ -0,0 +1,80 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/infrastructure/focus"
+)
+
+// actionTimeout is how long a notification with buttons waits for a click.
+const actionTimeout = 15 * time.Second
+
+// Action is a button on a notification.
+type Action struct {
+	Key   string // Returned when the button is clicked, e.g. "like"
+	Label string // Shown on the button, e.g. "Like"
+}
+
+// NotifyWithActions shows a desktop notification with buttons and waits until one is clicked,
+// the notification is closed or ctx is done. It returns the key of the clicked button, or ""
+// for no click. Where the notification tool has no buttons a plain notification is shown:
+// notify-send 0.7.9 or later on Linux (GNOME, KDE, dunst, mako) and alerter on macOS support them.
+func (n *Notifier) NotifyWithActions(ctx context.Context, title, body string, actions []Action) (string, error) {
+	if !n.Enabled() || focus.Suppressed(n.config.DoNotDisturb) {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, actionTimeout+5*time.Second)
+	defer cancel()
+
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		args := []string{"--app-name=sprt", "--wait", fmt.Sprintf("--expire-time=%d", actionTimeout.Milliseconds())}
+		for _, action := range actions {
+			args = append(args, "--action="+action.Key+"="+action.Label)
+		}
+		args = append(args, title, body)
+
+		out, err := exec.CommandContext(ctx, "notify-send", args...).Output()
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", nil
+			}
+			// Older versions don't know --action
+			return "", send(title, body)
+		}
+		return strings.TrimSpace(string(out)), nil
+
+	case "darwin":
+		if _, err := exec.LookPath("alerter"); err != nil {
+			return "", send(title, body)
+		}
+
+		labels := make([]string, len(actions))
+		for i, action := range actions {
+			labels[i] = action.Label
+		}
+		out, err := exec.CommandContext(ctx, "alerter", "-title", title, "-message", body,
+			"-actions", strings.Join(labels, ","), "-timeout", fmt.Sprint(int(actionTimeout.Seconds()))).Output()
+		if err != nil {
+			return "", nil
+		}
+
+		// alerter prints the label of the clicked button, or an @-prefixed event otherwise
+		clicked := strings.TrimSpace(string(out))
+		for _, action := range actions {
+			if action.Label == clicked {
+				return action.Key, nil
+			}
+		}
+		return "", nil
+
+	default:
+		return "", send(title, body)
+	}
+}
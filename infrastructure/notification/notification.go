@@ -0,0 +1,67 @@
+// Package notification sends desktop notifications.
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/infrastructure/focus"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+)
+
+// Notifier sends desktop notifications according to the notification configuration.
+type Notifier struct {
+	config config.NotificationConfig
+}
+
+// NewNotifier creates a new instance of Notifier.
+func NewNotifier(cfg config.NotificationConfig) *Notifier {
+	return &Notifier{
+		config: cfg,
+	}
+}
+
+// Enabled reports whether notifications are turned on in the configuration,
+// or by the active time-of-day profile, which takes precedence.
+func (n *Notifier) Enabled() bool {
+	profile, err := jsonfile.NewProfileRepository("").LoadActiveProfile(context.Background())
+	if err == nil && profile.ActiveAt(time.Now()) && profile.Notifications != nil {
+		return *profile.Notifications
+	}
+	return n.config.Enabled
+}
+
+// Notify shows a desktop notification unless notifications are disabled
+// or the OS Do-Not-Disturb mode is active.
+func (n *Notifier) Notify(title, body string) error {
+	if !n.Enabled() || focus.Suppressed(n.config.DoNotDisturb) {
+		return nil
+	}
+
+	return send(title, body)
+}
+
+// send shows a notification using the platform's notification tool.
+func send(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("notify-send", "--app-name=sprt", title, body)
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(body), strconv.Quote(title))
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return nil
+}
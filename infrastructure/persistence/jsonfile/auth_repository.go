@@ -1,6 +1,7 @@
 package jsonfile
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,40 +11,50 @@ import (
 
 	"github.com/muhadif/sprt/domain/entity"
 	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/filelock"
+	"github.com/muhadif/sprt/infrastructure/secret"
 )
 
 // authRepository implements the repository.AuthRepository interface using JSON file storage.
 type authRepository struct {
-	mu       sync.RWMutex
+	mu sync.RWMutex
+	// load reads the file on first use, so commands that never need the credentials
+	// don't ask for a passphrase or print warnings about the file
+	load     sync.Once
 	filePath string
 	authCode string
 	auth     *entity.SpotifyAuth
+
+	// encryption is the key source of an encrypted file, empty for plaintext
+	encryption string
+	salt       []byte
+	key        []byte // Derived on first use
+}
+
+// encryptedAuthFile is the content of an encrypted auth file.
+type encryptedAuthFile struct {
+	Encryption string `json:"encryption"` // Key source: "passphrase" or "machine"
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"` // AES-GCM encrypted SpotifyAuth JSON
 }
 
+// EncryptionNone stores the credentials in plaintext.
+const EncryptionNone = "none"
+
 // NewAuthRepository creates a new instance of the JSON file-based auth repository.
+// The file is read when the credentials are first used.
 func NewAuthRepository() repository.AuthRepository {
-	// Create the directory if it doesn't exist
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		homeDir = "."
 	}
+	filePath := filepath.Join(homeDir, ".sprt", "auth.json")
 
-	configDir := filepath.Join(homeDir, ".sprt")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		fmt.Printf("Warning: Failed to create config directory: %v\n", err)
-	}
-
-	filePath := filepath.Join(configDir, "auth.json")
-
-	repo := &authRepository{
+	return &authRepository{
 		filePath: filePath,
 		auth:     &entity.SpotifyAuth{},
 	}
-
-	// Load existing data if available
-	repo.loadFromFile()
-
-	return repo
 }
 
 // loadFromFile loads authentication data from the JSON file.
@@ -51,22 +62,51 @@ func (r *authRepository) loadFromFile() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Check if the file exists
+	// Check if the file exists; it's gone when another process logged out
 	if _, err := os.Stat(r.filePath); os.IsNotExist(err) {
+		r.auth = &entity.SpotifyAuth{}
 		return
 	}
 
 	// Read the file
 	data, err := os.ReadFile(r.filePath)
 	if err != nil {
-		fmt.Printf("Warning: Failed to read auth file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: Failed to read auth file: %v\n", err)
 		return
 	}
 
+	// Decrypt the file when it was encrypted
+	var encrypted encryptedAuthFile
+	if err := json.Unmarshal(data, &encrypted); err == nil && encrypted.Encryption != "" {
+		// A reload keeps the key unless the file was encrypted anew, so the passphrase isn't asked again
+		key := r.key
+		if r.encryption != encrypted.Encryption || !bytes.Equal(r.salt, encrypted.Salt) {
+			key = nil
+		}
+
+		// Keep the encryption even if the file can't be opened, so it's never saved in plaintext
+		r.encryption, r.salt = encrypted.Encryption, encrypted.Salt
+
+		if key == nil {
+			if key, err = secret.DeriveKey(encrypted.Encryption, encrypted.Salt, false); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to unlock auth file: %v\n", err)
+				return
+			}
+		}
+		if data, err = secret.Open(key, encrypted.Nonce, encrypted.Data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to decrypt auth file: %v\n", err)
+			return
+		}
+		r.key = key
+	} else if err == nil {
+		// Another process may have stored the file in plaintext since it was loaded
+		r.encryption, r.salt, r.key = "", nil, nil
+	}
+
 	// Parse the JSON
 	var auth entity.SpotifyAuth
 	if err := json.Unmarshal(data, &auth); err != nil {
-		fmt.Printf("Warning: Failed to parse auth file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: Failed to parse auth file: %v\n", err)
 		return
 	}
 
@@ -81,16 +121,52 @@ func (r *authRepository) saveToFile() error {
 		return fmt.Errorf("failed to marshal auth data: %w", err)
 	}
 
-	// Write to the file
-	if err := os.WriteFile(r.filePath, data, 0600); err != nil {
+	if r.encryption != "" {
+		if data, err = r.encrypt(data); err != nil {
+			return err
+		}
+	}
+
+	// Replace the file atomically, so other processes never read a partial write
+	if err := writeFileAtomic(r.filePath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write auth file: %w", err)
 	}
 
 	return nil
 }
 
-// StoreClientCredentials saves the client ID and secret.
+// encrypt wraps the auth JSON in an encrypted auth file, deriving the key if it isn't known yet.
+func (r *authRepository) encrypt(data []byte) ([]byte, error) {
+	if r.key == nil {
+		key, err := secret.DeriveKey(r.encryption, r.salt, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unlock auth file: %w", err)
+		}
+		r.key = key
+	}
+
+	nonce, ciphertext, err := secret.Seal(r.key, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt auth data: %w", err)
+	}
+
+	return json.MarshalIndent(encryptedAuthFile{
+		Encryption: r.encryption,
+		Salt:       r.salt,
+		Nonce:      nonce,
+		Data:       ciphertext,
+	}, "", "  ")
+}
+
+// StoreClientCredentials saves the client ID and secret, keeping the tokens another process
+// stored.
 func (r *authRepository) StoreClientCredentials(ctx context.Context, clientID, clientSecret string) error {
+	unlock, err := r.LockToken(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -121,8 +197,10 @@ func (r *authRepository) GetAuthCode(ctx context.Context) (string, error) {
 	return r.authCode, nil
 }
 
-// StoreToken saves the access and refresh tokens.
+// StoreToken saves the access and refresh tokens. Callers hold LockToken, which reloads the
+// file, so tokens another process stored meanwhile aren't overwritten.
 func (r *authRepository) StoreToken(ctx context.Context, auth *entity.SpotifyAuth) error {
+	r.load.Do(r.loadFromFile)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -132,6 +210,7 @@ func (r *authRepository) StoreToken(ctx context.Context, auth *entity.SpotifyAut
 
 // GetToken retrieves the stored authentication data.
 func (r *authRepository) GetToken(ctx context.Context) (*entity.SpotifyAuth, error) {
+	r.load.Do(r.loadFromFile)
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -141,3 +220,143 @@ func (r *authRepository) GetToken(ctx context.Context) (*entity.SpotifyAuth, err
 
 	return r.auth, nil
 }
+
+// LockToken keeps other sprt processes from changing the stored tokens until the returned
+// function is called, and reloads them, so tokens another process stored are picked up.
+func (r *authRepository) LockToken(ctx context.Context) (func(), error) {
+	r.load.Do(r.loadFromFile)
+	unlock, err := filelock.Lock(ctx, r.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock auth file: %w", err)
+	}
+
+	r.loadFromFile()
+	return unlock, nil
+}
+
+// DeleteToken securely erases the stored tokens, keeping the client credentials.
+func (r *authRepository) DeleteToken(ctx context.Context) error {
+	unlock, err := r.LockToken(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.authCode = ""
+	r.auth = &entity.SpotifyAuth{
+		ClientID:     r.auth.ClientID,
+		ClientSecret: r.auth.ClientSecret,
+	}
+
+	return r.replaceFile()
+}
+
+// DeleteAll securely erases the stored tokens and client credentials.
+func (r *authRepository) DeleteAll(ctx context.Context) error {
+	unlock, err := r.LockToken(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.authCode = ""
+	r.auth = &entity.SpotifyAuth{}
+
+	if err := wipeFile(r.filePath); err != nil {
+		return err
+	}
+	if err := os.Remove(r.filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove auth file: %w", err)
+	}
+
+	return nil
+}
+
+// SetEncryption re-stores the credentials encrypted with a key from the given source,
+// "passphrase" or "machine", or in plaintext for "none".
+func (r *authRepository) SetEncryption(ctx context.Context, source string) error {
+	unlock, err := r.LockToken(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.encryption != "" && r.key == nil {
+		return fmt.Errorf("the auth file couldn't be decrypted, so it can't be migrated")
+	}
+
+	if source == EncryptionNone {
+		r.encryption, r.salt, r.key = "", nil, nil
+	} else {
+		salt, err := secret.NewSalt()
+		if err != nil {
+			return err
+		}
+		key, err := secret.DeriveKey(source, salt, true)
+		if err != nil {
+			return err
+		}
+		r.encryption, r.salt, r.key = source, salt, key
+	}
+
+	return r.replaceFile()
+}
+
+// replaceFile saves the credentials in place of the file and then overwrites the old file,
+// which is kept open across the replacement, so a failed save leaves the old file intact and
+// a successful one leaves no copy of the old contents behind.
+func (r *authRepository) replaceFile() error {
+	old, err := os.OpenFile(r.filePath, os.O_WRONLY, 0)
+	if os.IsNotExist(err) {
+		return r.saveToFile()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open auth file: %w", err)
+	}
+	defer old.Close()
+
+	if err := r.saveToFile(); err != nil {
+		return err
+	}
+	return wipe(old)
+}
+
+// wipeFile overwrites the contents of the file with zeros and flushes them to disk,
+// so the secrets it held don't linger in the freed blocks. A missing file is not an error.
+func wipeFile(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open auth file: %w", err)
+	}
+	defer file.Close()
+
+	return wipe(file)
+}
+
+// wipe overwrites the contents of an open file with zeros and flushes them to disk.
+func wipe(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat auth file: %w", err)
+	}
+	if _, err := file.Write(make([]byte, info.Size())); err != nil {
+		return fmt.Errorf("failed to overwrite auth file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to flush auth file: %w", err)
+	}
+
+	return nil
+}
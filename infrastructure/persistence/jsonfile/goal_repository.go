@@ -0,0 +1,65 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// goalRepository implements the repository.GoalRepository interface using a JSON file.
+type goalRepository struct {
+	filePath string
+}
+
+// NewGoalRepository creates a new instance of the JSON file-based goal repository.
+// An empty filePath defaults to ~/.sprt/goals.json.
+func NewGoalRepository(filePath string) repository.GoalRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "goals.json")
+	}
+
+	return &goalRepository{
+		filePath: filePath,
+	}
+}
+
+// SaveMilestones stores the announced milestones.
+func (r *goalRepository) SaveMilestones(ctx context.Context, milestones *entity.GoalMilestones) error {
+	data, err := json.MarshalIndent(milestones, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal goal milestones: %w", err)
+	}
+
+	return writeFileAtomic(r.filePath, data, 0644)
+}
+
+// LoadMilestones retrieves the announced milestones.
+func (r *goalRepository) LoadMilestones(ctx context.Context) (*entity.GoalMilestones, error) {
+	milestones := &entity.GoalMilestones{Announced: make(map[string]string)}
+
+	data, err := os.ReadFile(r.filePath)
+	if os.IsNotExist(err) {
+		return milestones, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goals file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, milestones); err != nil {
+		return nil, fmt.Errorf("failed to parse goals file: %w", err)
+	}
+	if milestones.Announced == nil {
+		milestones.Announced = make(map[string]string)
+	}
+
+	return milestones, nil
+}
@@ -0,0 +1,121 @@
+package jsonfile
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/filelock"
+)
+
+// listenQueueRepository implements the repository.ListenQueueRepository interface using a JSON
+// Lines file, one listen per line.
+type listenQueueRepository struct {
+	filePath string
+}
+
+// NewListenQueueRepository creates a new instance of the JSON Lines file-based listen queue.
+// An empty filePath defaults to the ListenBrainz queue, ~/.sprt/listenbrainz-queue.jsonl.
+func NewListenQueueRepository(filePath string) repository.ListenQueueRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "listenbrainz-queue.jsonl")
+	}
+
+	return &listenQueueRepository{
+		filePath: filePath,
+	}
+}
+
+// QueueListens appends listens to the queue.
+func (r *listenQueueRepository) QueueListens(ctx context.Context, plays []entity.Play) error {
+	unlock, err := filelock.Lock(ctx, r.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock listen queue: %w", err)
+	}
+	defer unlock()
+
+	if err := os.MkdirAll(filepath.Dir(r.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.OpenFile(r.filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open listen queue: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, play := range plays {
+		if err := encoder.Encode(play); err != nil {
+			return fmt.Errorf("failed to write listen queue: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write listen queue: %w", err)
+	}
+	return nil
+}
+
+// QueuedListens retrieves the queued listens, oldest first.
+// A missing file is an empty queue; lines that can't be parsed are skipped.
+func (r *listenQueueRepository) QueuedListens(ctx context.Context) ([]entity.Play, error) {
+	file, err := os.Open(r.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open listen queue: %w", err)
+	}
+	defer file.Close()
+
+	var plays []entity.Play
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var play entity.Play
+		if err := json.Unmarshal(scanner.Bytes(), &play); err != nil {
+			continue
+		}
+		plays = append(plays, play)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read listen queue: %w", err)
+	}
+
+	return plays, nil
+}
+
+// RemoveListens removes the first n queued listens, keeping the ones queued meanwhile.
+func (r *listenQueueRepository) RemoveListens(ctx context.Context, n int) error {
+	unlock, err := filelock.Lock(ctx, r.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock listen queue: %w", err)
+	}
+	defer unlock()
+
+	plays, err := r.QueuedListens(ctx)
+	if err != nil {
+		return err
+	}
+	plays = plays[min(n, len(plays)):]
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, play := range plays {
+		if err := encoder.Encode(play); err != nil {
+			return fmt.Errorf("failed to encode listen: %w", err)
+		}
+	}
+	if err := writeFileAtomic(r.filePath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write listen queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/filelock"
+)
+
+// lyricMatchRepository implements the repository.LyricMatchRepository interface using a JSON
+// file mapping "artist|title", in lowercase, to the lrclib.net ID of the chosen lyrics.
+type lyricMatchRepository struct {
+	filePath string
+}
+
+// NewLyricMatchRepository creates a new instance of the JSON file-based lyric match repository.
+// An empty filePath defaults to ~/.sprt/lyric_matches.json.
+func NewLyricMatchRepository(filePath string) repository.LyricMatchRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "lyric_matches.json")
+	}
+
+	return &lyricMatchRepository{
+		filePath: filePath,
+	}
+}
+
+// GetLyricMatch retrieves the ID of the lyrics chosen for a track. A missing file has no choices.
+func (r *lyricMatchRepository) GetLyricMatch(ctx context.Context, artist, title string) (int, error) {
+	matches, err := r.load()
+	if err != nil {
+		return 0, err
+	}
+	return matches[matchKey(artist, title)], nil
+}
+
+// SaveLyricMatch remembers the ID of the lyrics chosen for a track.
+func (r *lyricMatchRepository) SaveLyricMatch(ctx context.Context, artist, title string, id int) error {
+	unlock, err := filelock.Lock(ctx, r.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock lyric matches: %w", err)
+	}
+	defer unlock()
+
+	matches, err := r.load()
+	if err != nil {
+		return err
+	}
+	matches[matchKey(artist, title)] = id
+
+	data, err := json.MarshalIndent(matches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lyric matches: %w", err)
+	}
+	return writeFileAtomic(r.filePath, data, 0644)
+}
+
+// load reads the chosen lyrics by track.
+func (r *lyricMatchRepository) load() (map[string]int, error) {
+	matches := make(map[string]int)
+
+	data, err := os.ReadFile(r.filePath)
+	if os.IsNotExist(err) {
+		return matches, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lyric matches file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &matches); err != nil {
+		return nil, fmt.Errorf("failed to parse lyric matches file: %w", err)
+	}
+	if matches == nil {
+		matches = make(map[string]int)
+	}
+	return matches, nil
+}
+
+// matchKey returns the key of a track, ignoring case.
+func matchKey(artist, title string) string {
+	return strings.ToLower(artist + "|" + title)
+}
@@ -0,0 +1,58 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// marqueeRepository implements the repository.MarqueeRepository interface using a JSON file.
+type marqueeRepository struct {
+	filePath string
+}
+
+// NewMarqueeRepository creates a new instance of the JSON file-based marquee repository.
+// An empty filePath defaults to ~/.sprt/marquee.json.
+func NewMarqueeRepository(filePath string) repository.MarqueeRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "marquee.json")
+	}
+
+	return &marqueeRepository{
+		filePath: filePath,
+	}
+}
+
+// SaveMarquee stores the marquee scroll position.
+func (r *marqueeRepository) SaveMarquee(ctx context.Context, state *entity.MarqueeState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal marquee state: %w", err)
+	}
+
+	return writeFileAtomic(r.filePath, data, 0644)
+}
+
+// LoadMarquee retrieves the stored marquee scroll position.
+func (r *marqueeRepository) LoadMarquee(ctx context.Context) (*entity.MarqueeState, error) {
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read marquee file: %w", err)
+	}
+
+	var state entity.MarqueeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse marquee file: %w", err)
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,121 @@
+package jsonfile
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/filelock"
+)
+
+// playHistoryRepository implements the repository.PlayHistoryRepository interface using
+// a JSON Lines file, so new plays are appended without rewriting the history.
+type playHistoryRepository struct {
+	filePath string
+}
+
+// NewPlayHistoryRepository creates a new instance of the JSON Lines file-based play history repository.
+// An empty filePath defaults to ~/.sprt/history.jsonl.
+func NewPlayHistoryRepository(filePath string) repository.PlayHistoryRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "history.jsonl")
+	}
+
+	return &playHistoryRepository{
+		filePath: filePath,
+	}
+}
+
+// AddPlays records the plays newer than the latest stored one and returns how many were added.
+// The file is locked meanwhile, so sprt processes syncing at the same time don't add a play twice.
+func (r *playHistoryRepository) AddPlays(ctx context.Context, plays []entity.Play) (int, error) {
+	unlock, err := filelock.Lock(ctx, r.filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock history file: %w", err)
+	}
+	defer unlock()
+
+	stored, err := r.ListPlays(ctx, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	var latest time.Time
+	if len(stored) > 0 {
+		latest = stored[len(stored)-1].PlayedAt
+	}
+
+	var added []entity.Play
+	for _, play := range plays {
+		if play.PlayedAt.After(latest) {
+			added = append(added, play)
+		}
+	}
+	if len(added) == 0 {
+		return 0, nil
+	}
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].PlayedAt.Before(added[j].PlayedAt)
+	})
+
+	if err := os.MkdirAll(filepath.Dir(r.filePath), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.OpenFile(r.filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, play := range added {
+		if err := encoder.Encode(play); err != nil {
+			return 0, fmt.Errorf("failed to write history file: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return len(added), nil
+}
+
+// ListPlays retrieves the plays at or after since, oldest first.
+// A missing file means nothing was recorded yet; lines that can't be parsed are skipped.
+func (r *playHistoryRepository) ListPlays(ctx context.Context, since time.Time) ([]entity.Play, error) {
+	file, err := os.Open(r.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var plays []entity.Play
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var play entity.Play
+		if err := json.Unmarshal(scanner.Bytes(), &play); err != nil {
+			continue
+		}
+		if !play.PlayedAt.Before(since) {
+			plays = append(plays, play)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return plays, nil
+}
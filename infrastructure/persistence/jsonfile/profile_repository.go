@@ -0,0 +1,69 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// profileRepository implements the repository.ProfileRepository interface using a JSON file.
+type profileRepository struct {
+	filePath string
+}
+
+// NewProfileRepository creates a new instance of the JSON file-based profile repository.
+// An empty filePath defaults to ~/.sprt/profile.json.
+func NewProfileRepository(filePath string) repository.ProfileRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "profile.json")
+	}
+
+	return &profileRepository{
+		filePath: filePath,
+	}
+}
+
+// SaveActiveProfile stores the active profile.
+func (r *profileRepository) SaveActiveProfile(ctx context.Context, profile *entity.ActiveProfile) error {
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %w", err)
+	}
+
+	return writeFileAtomic(r.filePath, data, 0644)
+}
+
+// LoadActiveProfile retrieves the active profile, or nil when none is stored.
+func (r *profileRepository) LoadActiveProfile(ctx context.Context) (*entity.ActiveProfile, error) {
+	data, err := os.ReadFile(r.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile file: %w", err)
+	}
+
+	var profile entity.ActiveProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile file: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// ClearActiveProfile removes the stored profile.
+func (r *profileRepository) ClearActiveProfile(ctx context.Context) error {
+	if err := os.Remove(r.filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove profile file: %w", err)
+	}
+	return nil
+}
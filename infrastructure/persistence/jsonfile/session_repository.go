@@ -0,0 +1,69 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// sessionRepository implements the repository.SessionRepository interface using a JSON file.
+type sessionRepository struct {
+	filePath string
+}
+
+// NewSessionRepository creates a new instance of the JSON file-based session repository.
+// An empty filePath defaults to ~/.sprt/session.json.
+func NewSessionRepository(filePath string) repository.SessionRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "session.json")
+	}
+
+	return &sessionRepository{
+		filePath: filePath,
+	}
+}
+
+// SaveSession stores the running session.
+func (r *sessionRepository) SaveSession(ctx context.Context, session *entity.Session) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	return writeFileAtomic(r.filePath, data, 0644)
+}
+
+// LoadSession retrieves the running session, or nil when none is stored.
+func (r *sessionRepository) LoadSession(ctx context.Context) (*entity.Session, error) {
+	data, err := os.ReadFile(r.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var session entity.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session file: %w", err)
+	}
+
+	return &session, nil
+}
+
+// ClearSession removes the stored session.
+func (r *sessionRepository) ClearSession(ctx context.Context) error {
+	if err := os.Remove(r.filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove session file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/filelock"
+)
+
+// snippetRepository implements the repository.SnippetRepository interface using a JSON file.
+type snippetRepository struct {
+	filePath string
+}
+
+// NewSnippetRepository creates a new instance of the JSON file-based snippet repository.
+// An empty filePath defaults to ~/.sprt/snippets.json.
+func NewSnippetRepository(filePath string) repository.SnippetRepository {
+	if filePath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		filePath = filepath.Join(homeDir, ".sprt", "snippets.json")
+	}
+
+	return &snippetRepository{
+		filePath: filePath,
+	}
+}
+
+// AddSnippet appends a snippet to the store. The file is locked meanwhile, so snippets saved
+// by other sprt processes at the same time aren't lost.
+func (r *snippetRepository) AddSnippet(ctx context.Context, snippet *entity.Snippet) error {
+	unlock, err := filelock.Lock(ctx, r.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock snippets file: %w", err)
+	}
+	defer unlock()
+
+	snippets, err := r.ListSnippets(ctx)
+	if err != nil {
+		return err
+	}
+	snippets = append(snippets, *snippet)
+
+	data, err := json.MarshalIndent(snippets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snippets: %w", err)
+	}
+
+	return writeFileAtomic(r.filePath, data, 0644)
+}
+
+// ListSnippets retrieves all stored snippets, oldest first.
+// A missing file means no snippets were saved yet.
+func (r *snippetRepository) ListSnippets(ctx context.Context) ([]entity.Snippet, error) {
+	data, err := os.ReadFile(r.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippets file: %w", err)
+	}
+
+	var snippets []entity.Snippet
+	if err := json.Unmarshal(data, &snippets); err != nil {
+		return nil, fmt.Errorf("failed to parse snippets file: %w", err)
+	}
+
+	return snippets, nil
+}
@@ -0,0 +1,96 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// stateRepository implements the repository.StateRepository interface using a JSON file.
+type stateRepository struct {
+	filePath string
+}
+
+// NewStateRepository creates a new instance of the JSON file-based state repository.
+// An empty filePath defaults to DefaultStatePath.
+func NewStateRepository(filePath string) repository.StateRepository {
+	if filePath == "" {
+		filePath = DefaultStatePath()
+	}
+
+	return &stateRepository{
+		filePath: filePath,
+	}
+}
+
+// DefaultStatePath returns the path of the state file, ~/.sprt/state.json.
+func DefaultStatePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".sprt", "state.json")
+}
+
+// SaveState stores the latest playback snapshot.
+// The file is replaced atomically so readers never see a partial write.
+func (r *stateRepository) SaveState(ctx context.Context, snapshot *entity.PlaybackSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	return writeFileAtomic(r.filePath, data, 0644)
+}
+
+// LoadState retrieves the last stored playback snapshot.
+func (r *stateRepository) LoadState(ctx context.Context) (*entity.PlaybackSnapshot, error) {
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var snapshot entity.PlaybackSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+
+	return nil
+}
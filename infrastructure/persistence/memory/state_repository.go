@@ -0,0 +1,89 @@
+// Package memory provides repositories that keep their data in memory for the lifetime of
+// the process, e.g. for the daemon to share the playback state over its control socket.
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// StateRepository implements the repository.StateRepository interface in memory and passes
+// every saved snapshot on to its subscribers.
+type StateRepository struct {
+	mu          sync.Mutex
+	snapshot    *entity.PlaybackSnapshot
+	subscribers map[chan *entity.PlaybackSnapshot]struct{}
+}
+
+// NewStateRepository creates a new instance of the in-memory state repository.
+func NewStateRepository() *StateRepository {
+	return &StateRepository{
+		subscribers: make(map[chan *entity.PlaybackSnapshot]struct{}),
+	}
+}
+
+// SaveState stores a copy of the snapshot and sends it to the subscribers. A subscriber that
+// hasn't received the previous snapshot yet only gets the latest one.
+func (r *StateRepository) SaveState(ctx context.Context, snapshot *entity.PlaybackSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.snapshot = cloneSnapshot(snapshot)
+	for ch := range r.subscribers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- cloneSnapshot(snapshot)
+	}
+	return nil
+}
+
+// LoadState retrieves a copy of the last stored playback snapshot.
+func (r *StateRepository) LoadState(ctx context.Context) (*entity.PlaybackSnapshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.snapshot == nil {
+		return nil, errors.New("no playback state yet")
+	}
+	return cloneSnapshot(r.snapshot), nil
+}
+
+// Subscribe returns a channel receiving the snapshots saved from now on, and a function
+// ending the subscription.
+func (r *StateRepository) Subscribe() (<-chan *entity.PlaybackSnapshot, func()) {
+	ch := make(chan *entity.PlaybackSnapshot, 1)
+
+	r.mu.Lock()
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+
+	return ch, func() {
+		r.mu.Lock()
+		delete(r.subscribers, ch)
+		r.mu.Unlock()
+	}
+}
+
+// cloneSnapshot copies a snapshot, so the one being updated by the state use case isn't shared.
+func cloneSnapshot(snapshot *entity.PlaybackSnapshot) *entity.PlaybackSnapshot {
+	clone := *snapshot
+	if snapshot.Track != nil {
+		track := *snapshot.Track
+		clone.Track = &track
+	}
+	if snapshot.Device != nil {
+		device := *snapshot.Device
+		clone.Device = &device
+	}
+	if snapshot.Lyric != nil {
+		lyric := *snapshot.Lyric
+		clone.Lyric = &lyric
+	}
+	clone.History = append([]entity.SnapshotLyric(nil), snapshot.History...)
+	return &clone
+}
@@ -0,0 +1,128 @@
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// Recorder writes the polls, lookups and updates of a lyric display to a recording as they
+// happen.
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	started time.Time
+	// err is the first failed write, returned by Close
+	err    error
+	closed bool
+}
+
+// Create starts a recording at path, replacing an existing file, for a display starting from
+// startMs into the track.
+func Create(path string, startMs int) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the recording: %w", err)
+	}
+
+	r := &Recorder{file: file, encoder: json.NewEncoder(file), started: time.Now()}
+	r.write(Entry{Type: TypeStart, StartMs: startMs})
+	return r, nil
+}
+
+// Tracks returns tracks recording the answer of every poll.
+func (r *Recorder) Tracks(tracks usecase.TrackSource) usecase.TrackSource {
+	return &recordedTracks{recorder: r, tracks: tracks}
+}
+
+// Lyrics returns source recording every lookup with the lyrics found.
+func (r *Recorder) Lyrics(source usecase.LyricSource) usecase.LyricSource {
+	return &recordedLyrics{recorder: r, source: source}
+}
+
+// Updates records the updates passing from updates to the returned channel, which is closed
+// after updates. Updates are dropped once ctx ends, as the display is gone.
+func (r *Recorder) Updates(ctx context.Context, updates <-chan *usecase.LyricUpdate) <-chan *usecase.LyricUpdate {
+	out := make(chan *usecase.LyricUpdate, cap(updates))
+	go func() {
+		defer close(out)
+		for update := range updates {
+			r.write(Entry{Type: TypeUpdate, Update: newUpdate(update)})
+			select {
+			case out <- update:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out
+}
+
+// Close finishes the recording, returning the first error writing it.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if r.err != nil {
+		return fmt.Errorf("failed to write the recording: %w", r.err)
+	}
+	return nil
+}
+
+// write appends an entry stamped with the current time, unless the recording is closed.
+func (r *Recorder) write(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+	entry.Time = time.Now()
+	entry.AtMs = entry.Time.Sub(r.started).Milliseconds()
+	if err := r.encoder.Encode(entry); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// recordedTracks records the polls of a track source.
+type recordedTracks struct {
+	recorder *Recorder
+	tracks   usecase.TrackSource
+}
+
+// GetCurrentlyPlayingDetails polls the track source and records the answer.
+func (t *recordedTracks) GetCurrentlyPlayingDetails(ctx context.Context) (*usecase.CurrentlyPlaying, error) {
+	track, err := t.tracks.GetCurrentlyPlayingDetails(ctx)
+	if ctx.Err() == nil {
+		entry := Entry{Type: TypePoll, Track: track}
+		entry.setError(err)
+		t.recorder.write(entry)
+	}
+	return track, err
+}
+
+// recordedLyrics records the lookups of a lyric source.
+type recordedLyrics struct {
+	recorder *Recorder
+	source   usecase.LyricSource
+}
+
+// GetLyrics looks up the lyrics and records them.
+func (l *recordedLyrics) GetLyrics(ctx context.Context, artist, title, album string, durationMs int) (*usecase.Lyrics, error) {
+	lyrics, err := l.source.GetLyrics(ctx, artist, title, album, durationMs)
+	if ctx.Err() == nil {
+		entry := Entry{Type: TypeLyrics, Artist: artist, Title: title, Album: album, Lyrics: lyrics}
+		entry.setError(err)
+		l.recorder.write(entry)
+	}
+	return lyrics, err
+}
@@ -0,0 +1,96 @@
+// Package recording captures the player polls, lyric lookups and lyric updates of a lyric
+// display to a JSON Lines file and replays them, so lyric drift can be reproduced from a
+// capture attached to a bug report.
+package recording
+
+import (
+	"errors"
+	"time"
+
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// Types of the entries of a recording.
+const (
+	TypeStart  = "start"  // The display started, from StartMs into the track
+	TypePoll   = "poll"   // Spotify answered a poll of the playing track
+	TypeLyrics = "lyrics" // The lyrics of a track were looked up
+	TypeUpdate = "update" // The lyric engine sent an update to the display
+)
+
+// Entry is one line of a recording. AtMs is the time since the recording started, when the
+// answer arrived or the update was sent.
+type Entry struct {
+	AtMs int64     `json:"at_ms"`
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+
+	// StartMs is the progress the display started from, in the start entry
+	StartMs int `json:"start_ms,omitempty"`
+	// Track is the answer of a poll
+	Track *usecase.CurrentlyPlaying `json:"track,omitempty"`
+	// Artist, Title and Album are the track whose lyrics were looked up, with the Lyrics found
+	Artist string          `json:"artist,omitempty"`
+	Title  string          `json:"title,omitempty"`
+	Album  string          `json:"album,omitempty"`
+	Lyrics *usecase.Lyrics `json:"lyrics,omitempty"`
+	// Update is what the display was sent
+	Update *Update `json:"update,omitempty"`
+
+	// Error is the error of a poll or a lookup, RetryAfterMs the wait asked for when Spotify
+	// was rate limiting
+	Error        string `json:"error,omitempty"`
+	RetryAfterMs int64  `json:"retry_after_ms,omitempty"`
+}
+
+// Update is a lyric update as the display got it, without the lyrics it carries along.
+type Update struct {
+	TrackID        string `json:"track_id,omitempty"`
+	Playing        bool   `json:"playing"`
+	LineIndex      int    `json:"line_index"`
+	Text           string `json:"text,omitempty"`
+	Error          string `json:"error,omitempty"`
+	NothingPlaying bool   `json:"nothing_playing,omitempty"`
+}
+
+// newUpdate describes a lyric update for the recording.
+func newUpdate(update *usecase.LyricUpdate) *Update {
+	u := &Update{
+		LineIndex:      update.LineIndex,
+		Text:           update.Text,
+		Error:          update.ErrorMsg,
+		NothingPlaying: update.NothingPlaying,
+	}
+	if update.Track != nil {
+		u.TrackID, u.Playing = update.Track.ID, update.Track.IsPlaying
+	}
+	return u
+}
+
+// setError records err in the entry, keeping the wait of a rate limit.
+func (e *Entry) setError(err error) {
+	if err == nil {
+		return
+	}
+	e.Error = err.Error()
+	var rateLimit *repository.RateLimitError
+	if errors.As(err, &rateLimit) {
+		e.RetryAfterMs = rateLimit.RetryAfter.Milliseconds()
+	}
+}
+
+// err returns the recorded error, as a rate limit when Spotify was rate limiting and as
+// usecase.ErrNothingPlaying when nothing played, so the lyric engine handles it as it did.
+func (e *Entry) err() error {
+	switch {
+	case e.Error == "":
+		return nil
+	case e.Error == usecase.ErrNothingPlaying.Error():
+		return usecase.ErrNothingPlaying
+	case e.RetryAfterMs > 0:
+		return &repository.RateLimitError{RetryAfter: time.Duration(e.RetryAfterMs) * time.Millisecond}
+	default:
+		return errors.New(e.Error)
+	}
+}
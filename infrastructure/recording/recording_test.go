@@ -0,0 +1,85 @@
+package recording_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/recording"
+)
+
+// answers returns the recorded answers of a fake player in turn.
+type answers struct {
+	tracks []*usecase.CurrentlyPlaying
+	errs   []error
+}
+
+func (a *answers) GetCurrentlyPlayingDetails(ctx context.Context) (*usecase.CurrentlyPlaying, error) {
+	track, err := a.tracks[0], a.errs[0]
+	a.tracks, a.errs = a.tracks[1:], a.errs[1:]
+	return track, err
+}
+
+func (a *answers) GetLyrics(ctx context.Context, artist, title, album string, durationMs int) (*usecase.Lyrics, error) {
+	return &usecase.Lyrics{Name: title, Synced: true, Lines: []usecase.Line{{StartTimeMs: 500, Text: "First"}}}, nil
+}
+
+func TestReplayReturnsRecordedAnswersWithTheirTiming(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	source := &answers{
+		tracks: []*usecase.CurrentlyPlaying{{ID: "1", Title: "Song", ProgressMs: 1200, IsPlaying: true}, nil},
+		errs:   []error{nil, &repository.RateLimitError{RetryAfter: 3 * time.Second}},
+	}
+	recorder, err := recording.Create(path, 1000)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	tracks, lyrics := recorder.Tracks(source), recorder.Lyrics(source)
+	tracks.GetCurrentlyPlayingDetails(ctx)
+	lyrics.GetLyrics(ctx, "Artist", "Song", "", 0)
+	time.Sleep(100 * time.Millisecond)
+	tracks.GetCurrentlyPlayingDetails(ctx)
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	replay, err := recording.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if replay.StartMs() != 1000 {
+		t.Errorf("StartMs() = %d, want 1000", replay.StartMs())
+	}
+
+	started := time.Now()
+	track, err := replay.GetCurrentlyPlayingDetails(ctx)
+	if err != nil || track.ID != "1" || track.ProgressMs != 1200 {
+		t.Fatalf("first poll = %+v, %v, want track 1 at 1200ms", track, err)
+	}
+	found, err := replay.GetLyrics(ctx, "Other", "Track", "", 0)
+	if err != nil || found.Name != "Song" || len(found.Lines) != 1 {
+		t.Fatalf("GetLyrics() = %+v, %v, want the recorded lyrics", found, err)
+	}
+
+	_, err = replay.GetCurrentlyPlayingDetails(ctx)
+	var rateLimit *repository.RateLimitError
+	if !errors.As(err, &rateLimit) || rateLimit.RetryAfter != 3*time.Second {
+		t.Fatalf("second poll error = %v, want the recorded rate limit", err)
+	}
+	if elapsed := time.Since(started); elapsed < 90*time.Millisecond {
+		t.Errorf("second poll answered after %s, want it as late as recorded", elapsed)
+	}
+
+	// Once the recording ends, polls wait for the display to close
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := replay.GetCurrentlyPlayingDetails(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("poll after the end error = %v, want the context's", err)
+	}
+}
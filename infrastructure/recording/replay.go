@@ -0,0 +1,126 @@
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// Replay feeds a recording back to the lyric engine. It implements usecase.TrackSource and
+// usecase.LyricSource: the polls and lookups get the recorded answers in their order, each once
+// as much time has passed since the first request as when it arrived in the recording, so the
+// display drifts as it did.
+type Replay struct {
+	startMs int
+
+	mu      sync.Mutex
+	polls   []Entry
+	lookups []Entry
+	// started is when the first request was made, as the time the recording started
+	started time.Time
+}
+
+// Open reads the recording at path.
+func Open(path string) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the recording: %w", err)
+	}
+	defer file.Close()
+
+	r := &Replay{}
+	scanner := bufio.NewScanner(file)
+	// Lyrics make long lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid recording %s, line %d: %w", path, line, err)
+		}
+		switch entry.Type {
+		case TypeStart:
+			r.startMs = entry.StartMs
+		case TypePoll:
+			r.polls = append(r.polls, entry)
+		case TypeLyrics:
+			r.lookups = append(r.lookups, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the recording: %w", err)
+	}
+	if len(r.polls) == 0 {
+		return nil, fmt.Errorf("the recording %s has no player polls", path)
+	}
+	return r, nil
+}
+
+// StartMs returns the progress the recorded display started from.
+func (r *Replay) StartMs() int {
+	return r.startMs
+}
+
+// GetCurrentlyPlayingDetails returns the answer of the next recorded poll. Once the recording
+// ends it waits for ctx, leaving the display as it was at the end.
+func (r *Replay) GetCurrentlyPlayingDetails(ctx context.Context) (*usecase.CurrentlyPlaying, error) {
+	entry, ok := r.next(&r.polls)
+	if !ok {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if err := r.wait(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry.Track, entry.err()
+}
+
+// GetLyrics returns the lyrics of the next recorded lookup, whichever track is asked for.
+func (r *Replay) GetLyrics(ctx context.Context, artist, title, album string, durationMs int) (*usecase.Lyrics, error) {
+	entry, ok := r.next(&r.lookups)
+	if !ok {
+		return nil, errors.New("the recording has no more lyrics")
+	}
+	if err := r.wait(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry.Lyrics, entry.err()
+}
+
+// next takes the first entry of entries, starting the replay clock with the first request.
+func (r *Replay) next(entries *[]Entry) (Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started.IsZero() {
+		r.started = time.Now()
+	}
+	if len(*entries) == 0 {
+		return Entry{}, false
+	}
+	entry := (*entries)[0]
+	*entries = (*entries)[1:]
+	return entry, true
+}
+
+// wait waits until the time an entry arrived in the recording.
+func (r *Replay) wait(ctx context.Context, entry Entry) error {
+	r.mu.Lock()
+	due := r.started.Add(time.Duration(entry.AtMs) * time.Millisecond)
+	r.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(due))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
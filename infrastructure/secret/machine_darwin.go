@@ -0,0 +1,25 @@
+package secret
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// machineID reads the hardware UUID from the I/O Registry.
+func machineID() (string, error) {
+	out, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run ioreg: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.Contains(line, "IOPlatformUUID") {
+			continue
+		}
+		if _, value, ok := strings.Cut(line, "="); ok {
+			return strings.Trim(strings.TrimSpace(value), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no IOPlatformUUID in ioreg output")
+}
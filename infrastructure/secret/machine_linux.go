@@ -0,0 +1,19 @@
+package secret
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// machineID reads the systemd or D-Bus machine ID.
+func machineID() (string, error) {
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if data, err := os.ReadFile(path); err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				return id, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no machine ID found in /etc/machine-id or /var/lib/dbus/machine-id")
+}
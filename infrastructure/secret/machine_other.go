@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package secret
+
+import "fmt"
+
+// machineID is not supported on this platform.
+func machineID() (string, error) {
+	return "", fmt.Errorf("machine keys aren't supported on this platform, use a passphrase")
+}
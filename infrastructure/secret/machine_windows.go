@@ -0,0 +1,23 @@
+package secret
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// machineID reads the MachineGuid created when Windows was installed.
+func machineID() (string, error) {
+	out, err := exec.Command("reg", "query", `HKLM\SOFTWARE\Microsoft\Cryptography`, "/v", "MachineGuid").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to query the registry: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "MachineGuid" {
+			return fields[2], nil
+		}
+	}
+	return "", fmt.Errorf("no MachineGuid in the registry")
+}
@@ -0,0 +1,171 @@
+// Package secret encrypts data at rest with keys derived from a passphrase or the machine.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+
+	"golang.org/x/term"
+)
+
+// Key sources supported by DeriveKey.
+const (
+	// KeyPassphrase derives the key from a passphrase taken from PassphraseEnv or asked for on the terminal
+	KeyPassphrase = "passphrase"
+	// KeyMachine derives the key from the machine ID and the user, so the data only opens on this account
+	KeyMachine = "machine"
+)
+
+// PassphraseEnv is the environment variable read before asking for the passphrase.
+const PassphraseEnv = "SPRT_PASSPHRASE"
+
+// keyIterations is the number of PBKDF2 rounds, as recommended by OWASP for SHA-256.
+const keyIterations = 600000
+
+// SaltSize is the size of the random salt mixed into derived keys.
+const SaltSize = 16
+
+// ErrWrongKey is returned by Open when the data can't be decrypted with the key.
+var ErrWrongKey = errors.New("wrong passphrase or key")
+
+// NewSalt returns a random salt for DeriveKey.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
+
+// DeriveKey derives a 256-bit key from the given source. A passphrase is asked for
+// twice when confirm is set, to catch typos when it's first chosen.
+func DeriveKey(source string, salt []byte, confirm bool) ([]byte, error) {
+	var password string
+	switch source {
+	case KeyPassphrase:
+		passphrase, err := Passphrase(confirm)
+		if err != nil {
+			return nil, err
+		}
+		password = passphrase
+	case KeyMachine:
+		id, err := machineID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get machine ID: %w", err)
+		}
+		password = id
+		if u, err := user.Current(); err == nil {
+			password += ":" + u.Uid
+		}
+	default:
+		return nil, fmt.Errorf("unknown key source %q (expected %s or %s)", source, KeyPassphrase, KeyMachine)
+	}
+
+	return PassphraseKey(password, salt)
+}
+
+// PassphraseKey derives a 256-bit key from the passphrase, e.g. to check it against the key
+// derived when it was chosen.
+func PassphraseKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, keyIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+// Passphrase returns the passphrase from PassphraseEnv, or asks for it on the terminal.
+func Passphrase(confirm bool) (string, error) {
+	return ReadPassphrase(PassphraseEnv, "sprt passphrase: ", confirm)
+}
+
+// ReadPassphrase returns the passphrase from the environment variable env, or asks for it on
+// the terminal with prompt. It's asked for twice when confirm is set.
+func ReadPassphrase(env, prompt string, confirm bool) (string, error) {
+	if passphrase := os.Getenv(env); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("a passphrase is needed but there is no terminal to ask for it; set %s", env)
+	}
+
+	passphrase, err := readPassword(fd, prompt)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("the passphrase is empty")
+	}
+	if confirm {
+		again, err := readPassword(fd, "Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("the passphrases don't match")
+		}
+	}
+
+	return passphrase, nil
+}
+
+// readPassword asks for a password on the terminal without echoing it.
+func readPassword(fd int, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(password), nil
+}
+
+// Seal encrypts the plaintext with AES-256-GCM, returning the random nonce and the ciphertext.
+func Seal(key, plaintext []byte) (nonce, ciphertext []byte, err error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// Open decrypts a ciphertext produced by Seal.
+func Open(key, nonce, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size %d", len(nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plaintext, nil
+}
+
+// newGCM creates an AES-GCM cipher for the key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,55 @@
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// fifoSink writes one line per update to a named pipe.
+type fifoSink struct {
+	path string
+}
+
+// NewFIFOSink creates a sink that writes to the named pipe at path, creating it if needed.
+func NewFIFOSink(path string) (usecase.LyricSink, error) {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		if err := mkfifo(path); err != nil {
+			return nil, fmt.Errorf("failed to create FIFO: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to check FIFO: %w", err)
+	case info.Mode()&os.ModeNamedPipe == 0:
+		return nil, fmt.Errorf("%s exists and is not a FIFO", path)
+	}
+
+	return &fifoSink{path: path}, nil
+}
+
+// Write sends the line to the pipe. Updates are dropped while no reader has it open,
+// so a missing reader never blocks the lyric display.
+func (s *fifoSink) Write(output usecase.LyricOutput) error {
+	f, err := openFIFO(s.path)
+	if err != nil {
+		if errors.Is(err, syscall.ENXIO) {
+			return nil
+		}
+		return fmt.Errorf("error opening FIFO: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, output.Text); err != nil && !errors.Is(err, syscall.EPIPE) {
+		return fmt.Errorf("error writing to FIFO: %w", err)
+	}
+	return nil
+}
+
+// Close leaves the FIFO in place so readers can keep it open across runs.
+func (s *fifoSink) Close() error {
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package sink
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// mkfifo is not supported on this platform.
+func mkfifo(path string) error {
+	return fmt.Errorf("FIFOs are not supported on %s", runtime.GOOS)
+}
+
+// openFIFO is not supported on this platform.
+func openFIFO(path string) (*os.File, error) {
+	return nil, fmt.Errorf("FIFOs are not supported on %s", runtime.GOOS)
+}
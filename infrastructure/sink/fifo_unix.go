@@ -0,0 +1,18 @@
+//go:build unix
+
+package sink
+
+import (
+	"os"
+	"syscall"
+)
+
+// mkfifo creates a named pipe at path.
+func mkfifo(path string) error {
+	return syscall.Mkfifo(path, 0644)
+}
+
+// openFIFO opens the named pipe for writing without waiting for a reader.
+func openFIFO(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+}
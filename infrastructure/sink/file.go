@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// fileSink writes the current line to a file, replacing its contents.
+type fileSink struct {
+	path string
+}
+
+// NewFileSink creates a sink that writes to the file at path.
+func NewFileSink(path string) usecase.LyricSink {
+	return &fileSink{path: path}
+}
+
+// Write replaces the file contents with the line.
+func (s *fileSink) Write(output usecase.LyricOutput) error {
+	// The file only holds the current line
+	if output.Separator {
+		return nil
+	}
+
+	if err := os.WriteFile(s.path, []byte(output.Text), 0644); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+	return nil
+}
+
+// Close leaves the last line in place for readers.
+func (s *fileSink) Close() error {
+	return nil
+}
@@ -0,0 +1,163 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/metrics"
+)
+
+// overlayPage is a transparent page for OBS browser sources and similar overlays.
+// It polls /line and shows the current lyric line below the previous ones.
+const overlayPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>sprt lyrics</title>
+<style>
+  body { margin: 0; background: transparent; font-family: sans-serif; }
+  #history { color: rgba(255, 255, 255, 0.6); font-size: 24px; text-align: center;
+             text-shadow: 0 0 4px #000; }
+  #line { color: #fff; font-size: 36px; text-align: center; padding: 16px;
+          text-shadow: 0 0 4px #000, 0 0 8px #000; }
+</style>
+</head>
+<body>
+<div id="history"></div>
+<div id="line"></div>
+<script>
+  async function poll() {
+    try {
+      const resp = await fetch("/line");
+      const data = await resp.json();
+      document.getElementById("history").innerText = data.history.join("\n");
+      document.getElementById("line").textContent = data.text;
+    } catch (e) {}
+    setTimeout(poll, 500);
+  }
+  poll();
+</script>
+</body>
+</html>
+`
+
+// httpShutdownTimeout bounds how long Close waits for open requests.
+const httpShutdownTimeout = 2 * time.Second
+
+// httpSink serves the current line as an HTML overlay and as JSON.
+type httpSink struct {
+	server *http.Server
+
+	mu     sync.RWMutex
+	output usecase.LyricOutput
+}
+
+// httpLine is the JSON document served at /line.
+type httpLine struct {
+	Text      string `json:"text"`
+	LineIndex int    `json:"line_index"`
+	Title     string `json:"title,omitempty"`
+	Artist    string `json:"artist,omitempty"`
+	// History holds the previously displayed lines, oldest first
+	History []string `json:"history"`
+}
+
+// NewHTTPSink creates a sink that serves the overlay on addr, e.g. "127.0.0.1:8975".
+func NewHTTPSink(addr string) (usecase.LyricSink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start overlay server: %w", err)
+	}
+
+	s := &httpSink{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleOverlay)
+	mux.HandleFunc("/line", s.handleLine)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Overlay server error: %v\n", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// handleOverlay serves the overlay page.
+func (s *httpSink) handleOverlay(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, overlayPage)
+}
+
+// handleLine serves the current line as JSON.
+func (s *httpSink) handleLine(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	line := httpLine{
+		Text:      s.output.Text,
+		LineIndex: s.output.LineIndex,
+		History:   make([]string, len(s.output.History)),
+	}
+	for i, previous := range s.output.History {
+		line.History[i] = previous.Text
+	}
+	if s.output.Track != nil {
+		line.Title = s.output.Track.Title
+		line.Artist = s.output.Track.Artist
+	}
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(line)
+}
+
+// handleMetrics serves the metrics of the process in the Prometheus text format.
+func (s *httpSink) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	track := s.output.Track
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.Write(w, track)
+}
+
+// Write stores the line for the next request.
+func (s *httpSink) Write(output usecase.LyricOutput) error {
+	// The overlay only shows the current line
+	if output.Separator {
+		return nil
+	}
+
+	s.mu.Lock()
+	// Keep the track of the previous output for updates that don't carry one
+	if output.Track == nil {
+		output.Track = s.output.Track
+	}
+	s.output = output
+	s.mu.Unlock()
+	return nil
+}
+
+// Close shuts the overlay server down.
+func (s *httpSink) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,72 @@
+package sink
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// NDJSON event types.
+const (
+	ndjsonLine      = "line"      // A lyric line, or an indicator shown in its place
+	ndjsonSeparator = "separator" // The track changed
+	ndjsonClear     = "clear"     // The output was cleared, e.g. by the paused-track reminder
+)
+
+// ndjsonSink prints one JSON object per update, for programs consuming structured events.
+type ndjsonSink struct {
+	encoder *json.Encoder
+}
+
+// ndjsonEvent is the JSON object printed for an update.
+type ndjsonEvent struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Text      string    `json:"text"`
+	LineIndex int       `json:"line_index"`
+	// StartTimeMs and EndTimeMs are the timing of the lyric line, left out for indicators
+	StartTimeMs *int `json:"start_time_ms,omitempty"`
+	EndTimeMs   *int `json:"end_time_ms,omitempty"`
+	ProgressMs  int  `json:"progress_ms"`
+	// Track is set when the update comes with the playing track, like lyric lines and separators
+	Track *usecase.CurrentlyPlaying `json:"track,omitempty"`
+}
+
+// NewNDJSONSink creates a sink that prints to w, or to stdout if w is nil.
+func NewNDJSONSink(w io.Writer) usecase.LyricSink {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &ndjsonSink{encoder: json.NewEncoder(w)}
+}
+
+// Write prints the update as a JSON object on a line of its own.
+func (s *ndjsonSink) Write(output usecase.LyricOutput) error {
+	event := ndjsonEvent{
+		Type:       ndjsonLine,
+		Time:       time.Now(),
+		Text:       output.Text,
+		LineIndex:  output.LineIndex,
+		ProgressMs: output.ProgressMs,
+		Track:      output.Track,
+	}
+	switch {
+	case output.Separator:
+		event.Type = ndjsonSeparator
+	case output.Text == "":
+		event.Type = ndjsonClear
+	}
+	if output.Line != nil {
+		event.StartTimeMs = &output.Line.StartTimeMs
+		event.EndTimeMs = &output.Line.EndTimeMs
+	}
+	return s.encoder.Encode(event)
+}
+
+// Close leaves stdout open for the rest of the program.
+func (s *ndjsonSink) Close() error {
+	return nil
+}
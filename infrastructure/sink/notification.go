@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/notification"
+)
+
+// notificationSink shows every line as a desktop notification.
+type notificationSink struct {
+	notifier *notification.Notifier
+}
+
+// NewNotificationSink creates a sink that sends lines through the notifier,
+// honoring its master switch and the Do-Not-Disturb mode.
+func NewNotificationSink(notifier *notification.Notifier) usecase.LyricSink {
+	return &notificationSink{notifier: notifier}
+}
+
+// Write shows the line in the background so a slow notification daemon doesn't delay the lyrics.
+func (s *notificationSink) Write(output usecase.LyricOutput) error {
+	if output.Text == "" || output.Separator {
+		return nil
+	}
+
+	title := "♪"
+	if output.Track != nil {
+		title = "♪ " + output.Track.Title
+	}
+	go s.notifier.Notify(title, output.Text)
+	return nil
+}
+
+// Close has nothing to release.
+func (s *notificationSink) Close() error {
+	return nil
+}
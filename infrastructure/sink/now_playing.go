@@ -0,0 +1,127 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// nowPlayingSink replaces a JSON file with the playing track, for desktop widgets that show
+// more than the lyric line.
+type nowPlayingSink struct {
+	path string
+	// last is the document last written, to skip rewriting it when nothing changed
+	last nowPlaying
+}
+
+// nowPlaying is the JSON document written by the now-playing sink.
+type nowPlaying struct {
+	IsPlaying  bool   `json:"is_playing"`
+	ID         string `json:"id,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Artist     string `json:"artist,omitempty"`
+	Album      string `json:"album,omitempty"`
+	ArtURL     string `json:"art_url,omitempty"`
+	ProgressMs int    `json:"progress_ms"`
+	DurationMs int    `json:"duration_ms"`
+	// Line is the current lyric line
+	Line      string    `json:"line"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewNowPlayingSink creates a sink that replaces the file at path with the playing track.
+func NewNowPlayingSink(path string) usecase.LyricSink {
+	return &nowPlayingSink{path: path}
+}
+
+// Write updates the lyric line and the progress.
+func (s *nowPlayingSink) Write(output usecase.LyricOutput) error {
+	if output.Separator {
+		return nil
+	}
+
+	doc := s.last
+	doc.Line = output.Text
+	doc.ProgressMs = output.ProgressMs
+	if output.Track != nil {
+		doc = trackDocument(output.Track, doc.Line)
+		doc.ProgressMs = output.ProgressMs
+	}
+	return s.write(doc)
+}
+
+// WriteTrack updates the track and the play state, clearing the file when nothing is playing.
+func (s *nowPlayingSink) WriteTrack(track *usecase.CurrentlyPlaying) error {
+	if track == nil {
+		return s.write(nowPlaying{})
+	}
+
+	doc := trackDocument(track, s.last.Line)
+	// Polls only change the progress as expected, which widgets can interpolate themselves
+	previous := s.last
+	previous.ProgressMs, previous.UpdatedAt = doc.ProgressMs, time.Time{}
+	if previous == doc {
+		return nil
+	}
+	return s.write(doc)
+}
+
+// trackDocument builds the document for the track with the given lyric line.
+func trackDocument(track *usecase.CurrentlyPlaying, line string) nowPlaying {
+	return nowPlaying{
+		IsPlaying:  track.IsPlaying,
+		ID:         track.ID,
+		URI:        track.URI,
+		Title:      track.Title,
+		Artist:     track.Artist,
+		Album:      track.Album,
+		ArtURL:     track.ImageURL,
+		ProgressMs: track.ProgressMs,
+		DurationMs: track.DurationMs,
+		Line:       line,
+	}
+}
+
+// write replaces the file through a temporary file, so widgets never read a partial document.
+func (s *nowPlayingSink) write(doc nowPlaying) error {
+	doc.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding now playing: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("error writing now playing: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing now playing: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing now playing: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing now playing: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error writing now playing: %w", err)
+	}
+
+	s.last = doc
+	return nil
+}
+
+// Close leaves the last document in place for readers.
+func (s *nowPlayingSink) Close() error {
+	return nil
+}
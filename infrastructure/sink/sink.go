@@ -0,0 +1,105 @@
+// Package sink implements the destinations the current lyric line is published to.
+package sink
+
+import (
+	"errors"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/notification"
+)
+
+// multiSink publishes every output to several sinks.
+type multiSink struct {
+	sinks []usecase.LyricSink
+}
+
+// New creates the sinks enabled in the configuration, combined into a single sink.
+// The terminal sink is left out when withTerminal is false, e.g. when a TUI owns the terminal.
+func New(cfg *config.Config, withTerminal bool) (usecase.LyricSink, error) {
+	m := &multiSink{}
+
+	switch {
+	case cfg.Sinks.Stdout && cfg.Sinks.NDJSON:
+		return nil, errors.New("the stdout and ndjson sinks both print to stdout, enable only one of them")
+	case cfg.Sinks.Stdout:
+		m.sinks = append(m.sinks, NewStdoutSink(nil))
+	case cfg.Sinks.NDJSON:
+		m.sinks = append(m.sinks, NewNDJSONSink(nil))
+	case cfg.Sinks.Terminal && withTerminal:
+		m.sinks = append(m.sinks, NewTerminalSink(nil))
+	}
+	if cfg.Sinks.File != "" {
+		m.sinks = append(m.sinks, NewFileSink(cfg.Sinks.File))
+	}
+	for _, path := range cfg.Sinks.Files {
+		m.sinks = append(m.sinks, NewFileSink(path))
+	}
+	if cfg.Sinks.NowPlaying != "" {
+		m.sinks = append(m.sinks, NewNowPlayingSink(cfg.Sinks.NowPlaying))
+	}
+	if cfg.Sinks.FIFO != "" {
+		s, err := NewFIFOSink(cfg.Sinks.FIFO)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.sinks = append(m.sinks, s)
+	}
+	if cfg.Sinks.Socket != "" {
+		s, err := NewSocketSink(cfg.Sinks.Socket)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.sinks = append(m.sinks, s)
+	}
+	if cfg.Sinks.HTTPAddr != "" {
+		s, err := NewHTTPSink(cfg.Sinks.HTTPAddr)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.sinks = append(m.sinks, s)
+	}
+	if cfg.Sinks.Notification {
+		m.sinks = append(m.sinks, NewNotificationSink(notification.NewNotifier(cfg.Notifications)))
+	}
+
+	return m, nil
+}
+
+// Write publishes the output to every sink, even if some of them fail.
+func (m *multiSink) Write(output usecase.LyricOutput) error {
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.Write(output); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriteTrack publishes the playing track to every sink following it.
+func (m *multiSink) WriteTrack(track *usecase.CurrentlyPlaying) error {
+	var errs []error
+	for _, s := range m.sinks {
+		if ts, ok := s.(usecase.TrackSink); ok {
+			if err := ts.WriteTrack(track); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every sink.
+func (m *multiSink) Close() error {
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,147 @@
+package sink
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// socketWriteTimeout bounds how long a slow client may delay an update.
+const socketWriteTimeout = time.Second
+
+// socketSink serves the current line on a UNIX socket.
+// Every connected client receives the latest line on connect and one line per update.
+type socketSink struct {
+	path     string
+	listener net.Listener
+
+	mu      sync.Mutex
+	clients map[net.Conn]*socketClient
+	last    string
+	closed  bool
+}
+
+// socketClient is a connection to the socket. Lines are written one at a time, as the
+// latest line on connect and the updates may be sent at once.
+type socketClient struct {
+	conn net.Conn
+	mu   sync.Mutex
+}
+
+// NewSocketSink creates a sink that listens on the UNIX socket at path.
+func NewSocketSink(path string) (usecase.LyricSink, error) {
+	// Remove a socket left behind by a previous run
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on socket: %w", err)
+	}
+
+	s := &socketSink{
+		path:     path,
+		listener: listener,
+		clients:  make(map[net.Conn]*socketClient),
+	}
+	go s.accept()
+
+	return s, nil
+}
+
+// accept registers new clients until the listener is closed.
+func (s *socketSink) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			// Accepted while closing
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		// The client is locked until it has the latest line, so updates can't overtake it
+		c := &socketClient{conn: conn}
+		c.mu.Lock()
+		s.clients[conn] = c
+		last := s.last
+		s.mu.Unlock()
+
+		var sendErr error
+		if last != "" {
+			sendErr = c.write(last)
+		}
+		c.mu.Unlock()
+		if sendErr != nil {
+			s.drop(c)
+		}
+	}
+}
+
+// Write sends the line to every connected client.
+func (s *socketSink) Write(output usecase.LyricOutput) error {
+	s.mu.Lock()
+	// New clients get the current line, not a stale separator
+	if !output.Separator {
+		s.last = output.Text
+	}
+	clients := make([]*socketClient, 0, len(s.clients))
+	for _, c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		s.send(c, output.Text)
+	}
+	return nil
+}
+
+// send writes a line to a client, dropping the client if it can't keep up.
+func (s *socketSink) send(c *socketClient, text string) {
+	c.mu.Lock()
+	err := c.write(text)
+	c.mu.Unlock()
+	if err != nil {
+		s.drop(c)
+	}
+}
+
+// drop disconnects a client.
+func (s *socketSink) drop(c *socketClient) {
+	s.mu.Lock()
+	delete(s.clients, c.conn)
+	s.mu.Unlock()
+	c.conn.Close()
+}
+
+// write writes a line to the connection. The caller holds c.mu.
+func (c *socketClient) write(text string) error {
+	c.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	_, err := fmt.Fprintln(c.conn, text)
+	return err
+}
+
+// Close disconnects all clients and removes the socket.
+func (s *socketSink) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.clients {
+		conn.Close()
+		delete(s.clients, conn)
+	}
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	os.Remove(s.path)
+	return err
+}
@@ -0,0 +1,58 @@
+package sink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muhadif/sprt/config"
+)
+
+// ParseSpecs builds the sink configuration from specs given on the command line, like "stdout",
+// "file:/tmp/lyric.txt" or "socket:/tmp/sprt.sock". Only the given sinks are enabled.
+func ParseSpecs(specs []string) (config.SinkConfig, error) {
+	var sinks config.SinkConfig
+	for _, spec := range specs {
+		kind, target, _ := strings.Cut(spec, ":")
+		switch kind {
+		case "terminal", "stdout", "ndjson", "notification":
+			if target != "" {
+				return sinks, fmt.Errorf("sink %q takes no path", kind)
+			}
+		case "file", "now-playing", "fifo", "socket", "http":
+			if target == "" {
+				return sinks, fmt.Errorf("sink %q needs a target, e.g. %s:<path>", kind, kind)
+			}
+		default:
+			return sinks, fmt.Errorf("unknown sink %q (expected terminal, stdout, ndjson, notification, file:<path>, now-playing:<path>, fifo:<path>, socket:<path> or http:<addr>)", spec)
+		}
+
+		var single *string
+		switch kind {
+		case "terminal":
+			sinks.Terminal = true
+		case "stdout":
+			sinks.Stdout = true
+		case "ndjson":
+			sinks.NDJSON = true
+		case "notification":
+			sinks.Notification = true
+		case "file":
+			sinks.Files = append(sinks.Files, target)
+		case "now-playing":
+			single = &sinks.NowPlaying
+		case "fifo":
+			single = &sinks.FIFO
+		case "socket":
+			single = &sinks.Socket
+		case "http":
+			single = &sinks.HTTPAddr
+		}
+		if single != nil {
+			if *single != "" {
+				return sinks, fmt.Errorf("only one %s sink can be used", kind)
+			}
+			*single = target
+		}
+	}
+	return sinks, nil
+}
@@ -0,0 +1,33 @@
+package sink
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// stdoutSink prints one line per update, for piping the lyrics into other programs.
+type stdoutSink struct {
+	w io.Writer
+}
+
+// NewStdoutSink creates a sink that prints to w, or to stdout if w is nil.
+func NewStdoutSink(w io.Writer) usecase.LyricSink {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &stdoutSink{w: w}
+}
+
+// Write prints the line followed by a newline; cleared outputs print an empty line.
+func (s *stdoutSink) Write(output usecase.LyricOutput) error {
+	_, err := fmt.Fprintln(s.w, output.Text)
+	return err
+}
+
+// Close leaves stdout open for the rest of the program.
+func (s *stdoutSink) Close() error {
+	return nil
+}
@@ -0,0 +1,39 @@
+package sink
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// terminalSink prints the current line over the previous one.
+type terminalSink struct {
+	w io.Writer
+}
+
+// NewTerminalSink creates a sink that prints to w, or to stdout if w is nil.
+func NewTerminalSink(w io.Writer) usecase.LyricSink {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &terminalSink{w: w}
+}
+
+// Write prints the line, replacing the previous one.
+// Separators are printed on a line of their own so they stay in the scrollback.
+func (s *terminalSink) Write(output usecase.LyricOutput) error {
+	if output.Separator {
+		_, err := fmt.Fprint(s.w, "\r\033[K", output.Text, "\n")
+		return err
+	}
+	_, err := fmt.Fprint(s.w, "\r\033[K", output.Text)
+	return err
+}
+
+// Close ends the line so the shell prompt starts on a new one.
+func (s *terminalSink) Close() error {
+	_, err := fmt.Fprintln(s.w)
+	return err
+}
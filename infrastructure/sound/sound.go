@@ -0,0 +1,69 @@
+// Package sound plays short audio cues.
+package sound
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Cue sounds accepted in the configuration.
+const (
+	SoundBell   = "bell"   // Ring the terminal bell
+	SoundSystem = "system" // Play a sound file through the platform's audio player
+)
+
+// Default sound files played by the system sound when none is configured.
+const (
+	defaultLinuxSound  = "/usr/share/sounds/freedesktop/stereo/message.oga"
+	defaultDarwinSound = "/System/Library/Sounds/Tink.aiff"
+)
+
+// Play plays the given cue sound. The system sound plays file, or a platform default when file is empty,
+// without waiting for it to finish.
+func Play(sound, file string) error {
+	switch sound {
+	case SoundBell, "":
+		return Bell()
+	case SoundSystem:
+		return playFile(file)
+	default:
+		return fmt.Errorf("unknown cue sound %q (expected %s or %s)", sound, SoundBell, SoundSystem)
+	}
+}
+
+// Bell rings the terminal bell. It is written to stderr so that it reaches the terminal
+// even when stdout is piped into another program.
+func Bell() error {
+	_, err := os.Stderr.WriteString("\a")
+	return err
+}
+
+// playFile starts playing a sound file with the platform's audio player.
+func playFile(file string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		if file == "" {
+			file = defaultLinuxSound
+		}
+		cmd = exec.Command("paplay", file)
+	case "darwin":
+		if file == "" {
+			file = defaultDarwinSound
+		}
+		cmd = exec.Command("afplay", file)
+	default:
+		// Fall back to the bell where no audio player is known
+		return Bell()
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to play sound: %w", err)
+	}
+	// Reap the player in the background so cues never hold up the display
+	go cmd.Wait()
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package spotify
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the number of responses kept in memory; the oldest is dropped first.
+const maxCacheEntries = 256
+
+// cacheEntry is a cached response body with its validator.
+type cacheEntry struct {
+	etag     string
+	body     []byte
+	storedAt time.Time
+}
+
+// responseCache keeps GET responses in memory. Responses are served without a request while
+// younger than the TTL, except for the live playback state, and revalidated with their ETag after.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+}
+
+// newResponseCache creates a cache serving responses for ttl; zero only revalidates with ETags.
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// fresh returns the cached body for rawURL if it may be used without asking Spotify.
+func (c *responseCache) fresh(rawURL string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[rawURL]
+	if !ok || isLive(rawURL) || now.Sub(entry.storedAt) >= c.ttl {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// etag returns the validator of the cached response for rawURL, if any.
+func (c *responseCache) etag(rawURL string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[rawURL]; ok {
+		return entry.etag
+	}
+	return ""
+}
+
+// revalidated returns the cached body for rawURL after Spotify answered 304 Not Modified,
+// restarting its TTL.
+func (c *responseCache) revalidated(rawURL string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[rawURL]
+	if !ok {
+		return nil, false
+	}
+	entry.storedAt = now
+	return entry.body, true
+}
+
+// store caches a successful response if it can be revalidated or served within the TTL.
+func (c *responseCache) store(rawURL, etag string, body []byte, now time.Time) {
+	if etag == "" && (c.ttl <= 0 || isLive(rawURL)) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[rawURL]; !ok && len(c.entries) >= maxCacheEntries {
+		c.evictOldest()
+	}
+	c.entries[rawURL] = &cacheEntry{etag: etag, body: body, storedAt: now}
+}
+
+// clear drops all cached responses, e.g. after a request that changed something.
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
+
+// evictOldest drops the least recently stored response. The caller holds the lock.
+func (c *responseCache) evictOldest() {
+	var oldest string
+	for key, entry := range c.entries {
+		if oldest == "" || entry.storedAt.Before(c.entries[oldest].storedAt) {
+			oldest = key
+		}
+	}
+	delete(c.entries, oldest)
+}
+
+// isLive reports whether rawURL is part of the playback state, which changes every second
+// and must never be served from memory.
+func isLive(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err != nil || strings.HasPrefix(u.Path, "/v1/me/player")
+}
@@ -0,0 +1,371 @@
+// Package spotify provides the Spotify Web API client shared by the use cases.
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/httpclient"
+)
+
+// Spotify endpoints.
+const (
+	APIBaseURL = "https://api.spotify.com/v1"
+	TokenURL   = "https://accounts.spotify.com/api/token"
+)
+
+// APIError is returned for responses with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// maxRetryAfter is the longest wait for a rate limit to pass before giving up right away;
+// Spotify sometimes asks for hours.
+const maxRetryAfter = time.Minute
+
+// client implements the repository.SpotifyClient interface.
+type client struct {
+	authRepo   repository.AuthRepository
+	config     config.APIConfig
+	httpClient *http.Client
+	cache      *responseCache
+	apiBaseURL string
+	tokenURL   string
+}
+
+// NewClient creates a new Spotify Web API client using the credentials in the auth repository.
+func NewClient(authRepo repository.AuthRepository, cfg config.APIConfig) repository.SpotifyClient {
+	return NewClientWithEndpoints(authRepo, cfg, APIBaseURL, TokenURL)
+}
+
+// NewClientWithEndpoints creates a new Spotify Web API client that talks to the given API base URL
+// and token endpoint instead of Spotify's, e.g. to a fake server in tests.
+func NewClientWithEndpoints(authRepo repository.AuthRepository, cfg config.APIConfig, apiBaseURL, tokenURL string) repository.SpotifyClient {
+	return &client{
+		authRepo:   authRepo,
+		config:     cfg,
+		httpClient: httpclient.New(cfg),
+		cache:      newResponseCache(time.Duration(max(cfg.CacheSeconds, 0)) * time.Second),
+		apiBaseURL: apiBaseURL,
+		tokenURL:   tokenURL,
+	}
+}
+
+// Get sends an authenticated GET request.
+func (c *client) Get(ctx context.Context, path string, v interface{}) error {
+	return c.Do(ctx, http.MethodGet, path, nil, v)
+}
+
+// Post sends an authenticated POST request.
+func (c *client) Post(ctx context.Context, path string, body, v interface{}) error {
+	return c.Do(ctx, http.MethodPost, path, body, v)
+}
+
+// Put sends an authenticated PUT request.
+func (c *client) Put(ctx context.Context, path string, body, v interface{}) error {
+	return c.Do(ctx, http.MethodPut, path, body, v)
+}
+
+// Delete sends an authenticated DELETE request.
+func (c *client) Delete(ctx context.Context, path string, body, v interface{}) error {
+	return c.Do(ctx, http.MethodDelete, path, body, v)
+}
+
+// Do sends an authenticated request. The access token is refreshed first if it has expired,
+// and once more followed by a single retry when Spotify rejects it with 401 Unauthorized.
+// Requests rejected with 429 Too Many Requests are retried after the Retry-After delay.
+// GET responses are cached in memory and revalidated with their ETag; other requests clear the cache.
+func (c *client) Do(ctx context.Context, method, path string, body, v interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode API request: %w", err)
+		}
+	}
+
+	rawURL := c.resolveURL(path)
+	if method == http.MethodGet {
+		if body, ok := c.cache.fresh(rawURL, time.Now()); ok {
+			return decodeBody(http.StatusOK, body, v)
+		}
+	}
+
+	auth, err := c.authRepo.GetToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+	if auth.IsExpired() {
+		if auth, err = c.RefreshToken(ctx); err != nil {
+			return fmt.Errorf("failed to refresh token: %w", err)
+		}
+	}
+
+	refreshed := false
+	for retries := 0; ; {
+		resp, err := c.send(ctx, method, rawURL, payload, auth)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && !refreshed:
+			// The token was revoked or expired early
+			resp.Body.Close()
+			refreshed = true
+			if auth, err = c.RefreshToken(ctx); err != nil {
+				return fmt.Errorf("failed to refresh token: %w", err)
+			}
+
+		case resp.StatusCode == http.StatusTooManyRequests:
+			resp.Body.Close()
+			wait := retryAfter(resp.Header.Get("Retry-After"), retries)
+			if retries >= c.config.RateLimitRetries || wait > maxRetryAfter {
+				return &repository.RateLimitError{RetryAfter: wait}
+			}
+			retries++
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+
+		default:
+			return c.finish(method, rawURL, resp, v)
+		}
+	}
+}
+
+// finish reads the response, updates the cache and decodes the body into v.
+func (c *client) finish(method, rawURL string, resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read API response: %w", err)
+	}
+
+	now := time.Now()
+	status := resp.StatusCode
+	if method == http.MethodGet && status == http.StatusNotModified {
+		if cached, ok := c.cache.revalidated(rawURL, now); ok {
+			body, status = cached, http.StatusOK
+		}
+	} else if status >= 200 && status < 300 {
+		if method == http.MethodGet {
+			c.cache.store(rawURL, resp.Header.Get("ETag"), body, now)
+		} else {
+			c.cache.clear()
+		}
+	}
+
+	return decodeBody(status, body, v)
+}
+
+// retryAfter returns the delay asked for by a Retry-After header, given in seconds or as a date.
+// Without a usable header the delay doubles with each retry, starting at one second.
+func retryAfter(header string, retries int) time.Duration {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return time.Second << min(retries, 6)
+}
+
+// send sends a single request with the access token, conditional on the cached ETag for GET requests.
+func (c *client) send(ctx context.Context, method, rawURL string, payload []byte, auth *entity.SpotifyAuth) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", auth.TokenType, auth.AccessToken))
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if method == http.MethodGet {
+		if etag := c.cache.etag(rawURL); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send API request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ExchangeCode exchanges an authorization code for tokens and stores them. The tokens are
+// stored under the token lock, along with the client credentials as another process left them.
+func (c *client) ExchangeCode(ctx context.Context, code, redirectURI string) (*entity.SpotifyAuth, error) {
+	unlock, err := c.authRepo.LockToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("code", code)
+	data.Set("redirect_uri", redirectURI)
+
+	return c.requestToken(ctx, data)
+}
+
+// RefreshToken refreshes the access token using the stored refresh token and stores it.
+// Processes refreshing at once take turns, and a token another process refreshed since
+// this one read it is used instead of refreshing again.
+func (c *client) RefreshToken(ctx context.Context) (*entity.SpotifyAuth, error) {
+	previous, err := c.authRepo.GetToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	previousToken := previous.AccessToken
+
+	unlock, err := c.authRepo.LockToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	auth, err := c.authRepo.GetToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if auth.AccessToken != previousToken && auth.AccessToken != "" && !auth.IsExpired() {
+		return auth, nil
+	}
+	if auth.RefreshToken == "" {
+		return nil, fmt.Errorf("no refresh token available")
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", auth.RefreshToken)
+
+	return c.requestToken(ctx, data)
+}
+
+// requestToken requests tokens from the accounts service with the stored client credentials
+// and stores them. The refresh token is kept when the response doesn't include a new one.
+func (c *client) requestToken(ctx context.Context, data url.Values) (*entity.SpotifyAuth, error) {
+	auth, err := c.authRepo.GetToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client credentials: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	authHeader := base64.StdEncoding.EncodeToString([]byte(auth.ClientID + ":" + auth.ClientSecret))
+	req.Header.Set("Authorization", "Basic "+authHeader)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send token request: %w", err)
+	}
+
+	var tokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+		RefreshToken string `json:"refresh_token"`
+		Scope        string `json:"scope"`
+	}
+	if err := decodeResponse(resp, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+
+	newAuth := &entity.SpotifyAuth{
+		ClientID:     auth.ClientID,
+		ClientSecret: auth.ClientSecret,
+		AccessToken:  tokenResponse.AccessToken,
+		RefreshToken: tokenResponse.RefreshToken,
+		ExpiresIn:    tokenResponse.ExpiresIn,
+		TokenType:    tokenResponse.TokenType,
+		Scope:        tokenResponse.Scope,
+		ExpiresAt:    time.Now().Unix() + int64(tokenResponse.ExpiresIn),
+	}
+	if newAuth.RefreshToken == "" {
+		newAuth.RefreshToken = auth.RefreshToken
+	}
+
+	if err := c.authRepo.StoreToken(ctx, newAuth); err != nil {
+		return nil, fmt.Errorf("failed to store token: %w", err)
+	}
+
+	return newAuth, nil
+}
+
+// resolveURL turns an API path into a URL; absolute URLs, such as "next" links, are kept.
+func (c *client) resolveURL(path string) string {
+	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		return path
+	}
+	return c.apiBaseURL + path
+}
+
+// decodeResponse checks the response status and decodes the JSON body into v.
+// A nil v only checks the status.
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read API response: %w", err)
+	}
+
+	return decodeBody(resp.StatusCode, body, v)
+}
+
+// decodeBody checks the response status and decodes the JSON body into v.
+// A nil v only checks the status.
+func decodeBody(statusCode int, body []byte, v interface{}) error {
+	if statusCode < 200 || statusCode >= 300 {
+		return &APIError{StatusCode: statusCode, Body: string(body)}
+	}
+
+	if v == nil {
+		return nil
+	}
+	if statusCode == http.StatusNoContent || len(body) == 0 {
+		return repository.ErrNoContent
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	return nil
+}
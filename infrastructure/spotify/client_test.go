@@ -0,0 +1,144 @@
+package spotify_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/spotify/spotifytest"
+)
+
+func TestClientSendsAccessToken(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.HandleFixture(http.MethodGet, "/v1/me", "me")
+
+	client := server.SpotifyClient(spotifytest.NewAuthRepository(spotifytest.ValidAuth()))
+
+	var profile struct {
+		DisplayName string `json:"display_name"`
+	}
+	if err := client.Get(context.Background(), "/me", &profile); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if profile.DisplayName != "Test User" {
+		t.Errorf("display name = %q, want %q", profile.DisplayName, "Test User")
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	if got, want := requests[0].Header.Get("Authorization"), "Bearer "+spotifytest.AccessToken; got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestClientRefreshesTokenOnUnauthorized(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.Handle(http.MethodGet, "/v1/me", http.StatusUnauthorized, `{"error":{"status":401,"message":"The access token expired"}}`)
+	server.HandleFixture(http.MethodGet, "/v1/me", "me")
+
+	authRepo := spotifytest.NewAuthRepository(spotifytest.ValidAuth())
+	client := server.SpotifyClient(authRepo)
+
+	if err := client.Get(context.Background(), "/me", nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want the request, a refresh and the retry", len(requests))
+	}
+	if requests[1].Path != "/api/token" {
+		t.Errorf("second request = %s, want the token refresh", requests[1].Path)
+	}
+	if got, want := requests[2].Header.Get("Authorization"), "Bearer "+spotifytest.RefreshedAccessToken; got != want {
+		t.Errorf("retry Authorization = %q, want %q", got, want)
+	}
+
+	// The refresh token is kept when Spotify doesn't send a new one
+	auth, _ := authRepo.GetToken(context.Background())
+	if auth.AccessToken != spotifytest.RefreshedAccessToken || auth.RefreshToken != spotifytest.RefreshToken {
+		t.Errorf("stored tokens = %q/%q, want %q/%q", auth.AccessToken, auth.RefreshToken,
+			spotifytest.RefreshedAccessToken, spotifytest.RefreshToken)
+	}
+}
+
+func TestClientUsesTokenRefreshedByOtherProcess(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.Handle(http.MethodGet, "/v1/me", http.StatusUnauthorized, `{"error":{"status":401,"message":"The access token expired"}}`)
+	server.HandleFixture(http.MethodGet, "/v1/me", "me")
+
+	authRepo := spotifytest.NewAuthRepository(spotifytest.ValidAuth())
+	refreshed := spotifytest.ValidAuth()
+	refreshed.AccessToken = "other-process-access-token"
+	authRepo.StoreFromOtherProcess(refreshed)
+	client := server.SpotifyClient(authRepo)
+
+	if err := client.Get(context.Background(), "/me", nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want the request and the retry without a refresh", len(requests))
+	}
+	if got, want := requests[1].Header.Get("Authorization"), "Bearer "+refreshed.AccessToken; got != want {
+		t.Errorf("retry Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestClientRefreshesExpiredToken(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.HandleFixture(http.MethodGet, "/v1/me", "me")
+
+	auth := spotifytest.ValidAuth()
+	auth.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	client := server.SpotifyClient(spotifytest.NewAuthRepository(auth))
+
+	if err := client.Get(context.Background(), "/me", nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 || requests[0].Path != "/api/token" {
+		t.Fatalf("requests = %+v, want a refresh before the request", requests)
+	}
+}
+
+func TestClientReturnsRateLimitError(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.HandleWithHeader(http.MethodGet, "/v1/me", http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}, "")
+
+	client := server.SpotifyClient(spotifytest.NewAuthRepository(spotifytest.ValidAuth()))
+
+	err := client.Get(context.Background(), "/me", nil)
+	var rateLimitErr *repository.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Get() error = %v, want a RateLimitError", err)
+	}
+	if rateLimitErr.RetryAfter != 7*time.Second {
+		t.Errorf("RetryAfter = %v, want 7s", rateLimitErr.RetryAfter)
+	}
+}
+
+func TestClientReturnsNoContent(t *testing.T) {
+	server := spotifytest.NewServer()
+	defer server.Close()
+	server.Handle(http.MethodGet, "/v1/me/player", http.StatusNoContent, "")
+
+	client := server.SpotifyClient(spotifytest.NewAuthRepository(spotifytest.ValidAuth()))
+
+	var state struct{}
+	if err := client.Get(context.Background(), "/me/player", &state); !errors.Is(err, repository.ErrNoContent) {
+		t.Errorf("Get() error = %v, want ErrNoContent", err)
+	}
+}
@@ -0,0 +1,315 @@
+// Package spotifytest provides a fake Spotify Web API and lrclib.net server, recorded fixtures and
+// an in-memory auth repository, for testing the use cases without hitting the real services.
+package spotifytest
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/infrastructure/spotify"
+)
+
+// Tokens handed out by the fake token endpoint.
+const (
+	AccessToken  = "test-access-token"
+	RefreshToken = "test-refresh-token"
+	// RefreshedAccessToken is returned when the access token is refreshed
+	RefreshedAccessToken = "test-refreshed-access-token"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fixture returns the recorded response with the given name, e.g. "currently_playing".
+// It panics for unknown names.
+func Fixture(name string) string {
+	data, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		panic(fmt.Sprintf("spotifytest: unknown fixture %q", name))
+	}
+	return string(data)
+}
+
+// Request is a request received by the fake server.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   string
+}
+
+// response is a canned response of the fake server.
+type response struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// Server is a fake of the Spotify Web API, the Spotify accounts service and lrclib.net.
+// Requests without a canned response are answered with 404 Not Found; the token endpoint
+// answers with RefreshedAccessToken unless it was given a response.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string][]response
+	requests  []Request
+}
+
+// NewServer starts a fake server. Close it when done.
+func NewServer() *Server {
+	s := &Server{responses: make(map[string][]response)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Handle answers requests for method and path with status and body. Several responses for the
+// same request are given out in order, the last one repeating.
+func (s *Server) Handle(method, path string, status int, body string) {
+	s.HandleWithHeader(method, path, status, nil, body)
+}
+
+// HandleFixture answers requests for method and path with 200 OK and a recorded fixture.
+func (s *Server) HandleFixture(method, path, fixture string) {
+	s.Handle(method, path, http.StatusOK, Fixture(fixture))
+}
+
+// HandleWithHeader is like Handle, also setting the response headers, e.g. Retry-After.
+func (s *Server) HandleWithHeader(method, path string, status int, header http.Header, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := method + " " + path
+	s.responses[key] = append(s.responses[key], response{status: status, header: header, body: body})
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// APIBaseURL is the base URL of the fake Web API, to use in place of spotify.APIBaseURL.
+func (s *Server) APIBaseURL() string {
+	return s.URL + "/v1"
+}
+
+// TokenURL is the URL of the fake token endpoint, to use in place of spotify.TokenURL.
+func (s *Server) TokenURL() string {
+	return s.URL + "/api/token"
+}
+
+// SpotifyClient returns a Spotify client talking to the fake server with the tokens in authRepo.
+// Retries are off, so a 429 response fails right away.
+func (s *Server) SpotifyClient(authRepo repository.AuthRepository) repository.SpotifyClient {
+	return spotify.NewClientWithEndpoints(authRepo, config.APIConfig{}, s.APIBaseURL(), s.TokenURL())
+}
+
+// HTTPClient returns a client sending every request to the fake server whatever its host,
+// for code with fixed URLs such as the lrclib.net requests of the lyric use case.
+func (s *Server) HTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.URL.Scheme = "http"
+			req.URL.Host = s.Listener.Addr().String()
+			req.Host = ""
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// serve records the request and writes its canned response.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   string(body),
+	})
+
+	key := r.Method + " " + r.URL.Path
+	queue, ok := s.responses[key]
+	var resp response
+	if ok {
+		resp = queue[0]
+		if len(queue) > 1 {
+			s.responses[key] = queue[1:]
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		if key == "POST /api/token" {
+			resp = response{status: http.StatusOK, body: Fixture("token")}
+		} else {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	for name, values := range resp.header {
+		w.Header()[name] = values
+	}
+	if resp.body != "" && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(resp.status)
+	io.WriteString(w, resp.body)
+}
+
+// AuthRepository is an in-memory repository.AuthRepository.
+type AuthRepository struct {
+	mu   sync.Mutex
+	auth *entity.SpotifyAuth
+	code string
+	// stored holds tokens stored by another process, picked up by LockToken
+	stored *entity.SpotifyAuth
+}
+
+// NewAuthRepository creates an auth repository holding auth; nil starts without credentials.
+func NewAuthRepository(auth *entity.SpotifyAuth) *AuthRepository {
+	return &AuthRepository{auth: auth}
+}
+
+// ValidAuth returns credentials with AccessToken valid for an hour and RefreshToken.
+func ValidAuth() *entity.SpotifyAuth {
+	return &entity.SpotifyAuth{
+		ClientID:     "test-client-id",
+		ClientSecret: "test-client-secret",
+		AccessToken:  AccessToken,
+		RefreshToken: RefreshToken,
+		ExpiresIn:    3600,
+		TokenType:    "Bearer",
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// StoreClientCredentials stores the client ID and secret.
+func (r *AuthRepository) StoreClientCredentials(ctx context.Context, clientID, clientSecret string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.auth == nil {
+		r.auth = &entity.SpotifyAuth{}
+	}
+	r.auth.ClientID = clientID
+	r.auth.ClientSecret = clientSecret
+	return nil
+}
+
+// StoreAuthCode stores the authorization code.
+func (r *AuthRepository) StoreAuthCode(ctx context.Context, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.code = code
+	return nil
+}
+
+// GetAuthCode retrieves the authorization code.
+func (r *AuthRepository) GetAuthCode(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.code == "" {
+		return "", fmt.Errorf("no auth code found")
+	}
+	return r.code, nil
+}
+
+// StoreToken stores a copy of the tokens.
+func (r *AuthRepository) StoreToken(ctx context.Context, auth *entity.SpotifyAuth) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *auth
+	r.auth = &stored
+	return nil
+}
+
+// GetToken retrieves a copy of the stored credentials.
+func (r *AuthRepository) GetToken(ctx context.Context) (*entity.SpotifyAuth, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.auth == nil {
+		return nil, fmt.Errorf("authentication data not found")
+	}
+	auth := *r.auth
+	return &auth, nil
+}
+
+// StoreFromOtherProcess stores tokens the way another sprt process would: they're only
+// picked up on the next LockToken.
+func (r *AuthRepository) StoreFromOtherProcess(auth *entity.SpotifyAuth) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *auth
+	r.stored = &stored
+}
+
+// LockToken picks up the tokens stored by StoreFromOtherProcess.
+func (r *AuthRepository) LockToken(ctx context.Context) (func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stored != nil {
+		r.auth, r.stored = r.stored, nil
+	}
+	return func() {}, nil
+}
+
+// DeleteToken removes the tokens, keeping the client credentials.
+func (r *AuthRepository) DeleteToken(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.auth != nil {
+		r.auth = &entity.SpotifyAuth{ClientID: r.auth.ClientID, ClientSecret: r.auth.ClientSecret}
+	}
+	return nil
+}
+
+// DeleteAll removes all stored credentials.
+func (r *AuthRepository) DeleteAll(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.auth = nil
+	r.code = ""
+	return nil
+}
+
+// SetEncryption does nothing; the credentials only live in memory.
+func (r *AuthRepository) SetEncryption(ctx context.Context, source string) error {
+	return nil
+}
+
+var _ repository.AuthRepository = (*AuthRepository)(nil)
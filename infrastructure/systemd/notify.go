@@ -0,0 +1,58 @@
+// Package systemd reports the state of sprt to the systemd service manager.
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notification states understood by systemd.
+const (
+	Ready    = "READY=1"    // Startup finished, for Type=notify services
+	Stopping = "STOPPING=1" // Shutdown started
+	Watchdog = "WATCHDOG=1" // Keep-alive for services with WatchdogSec
+)
+
+// Supervised reports whether sprt runs as a systemd service that accepts notifications.
+func Supervised() bool {
+	return os.Getenv("NOTIFY_SOCKET") != ""
+}
+
+// Notify sends a state to systemd. It does nothing outside a service with NotifyAccess.
+func Notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	// A leading @ stands for the abstract namespace
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to notify systemd: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("failed to notify systemd: %w", err)
+	}
+	return nil
+}
+
+// WatchdogInterval returns how often the watchdog must be notified, half the configured WatchdogSec,
+// or zero when the watchdog is off for this process.
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
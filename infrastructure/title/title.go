@@ -0,0 +1,152 @@
+// Package title shows the current track in the terminal window title or the tmux pane title.
+package title
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Title modes accepted in the configuration.
+const (
+	ModeOff      = "off"      // Leave the titles alone
+	ModeAuto     = "auto"     // Use the tmux pane title inside tmux, the terminal title otherwise
+	ModeTerminal = "terminal" // Set the terminal window title with an OSC 2 escape sequence
+	ModeTmux     = "tmux"     // Set the title of the tmux pane sprt runs in
+)
+
+// DefaultFormat is the title shown when no format is configured.
+const DefaultFormat = "♪ {title} – {artist}"
+
+// commandTimeout bounds how long a single tmux command may run.
+const commandTimeout = time.Second
+
+// Title sets a window or pane title and restores the previous one when cleared.
+// The zero value and a nil Title do nothing.
+type Title struct {
+	mode     string
+	pane     string
+	previous string
+	current  string
+	shown    bool // A title is shown and the previous one saved
+}
+
+// New returns a Title for the configured mode. Inside tmux the auto mode uses the pane title,
+// outside it uses the terminal title when stderr is a terminal; otherwise nil is returned.
+func New(mode string) (*Title, error) {
+	switch strings.ToLower(mode) {
+	case ModeOff, "":
+		return nil, nil
+	case ModeAuto:
+		if os.Getenv("TMUX") != "" {
+			return newTmux(), nil
+		}
+		if term.IsTerminal(int(os.Stderr.Fd())) {
+			return &Title{mode: ModeTerminal}, nil
+		}
+		return nil, nil
+	case ModeTerminal:
+		return &Title{mode: ModeTerminal}, nil
+	case ModeTmux:
+		if os.Getenv("TMUX") == "" {
+			return nil, fmt.Errorf("title mode %q needs sprt to run inside tmux", mode)
+		}
+		return newTmux(), nil
+	default:
+		return nil, fmt.Errorf("unknown title mode %q (expected %s, %s, %s or %s)", mode, ModeOff, ModeAuto, ModeTerminal, ModeTmux)
+	}
+}
+
+// newTmux returns a Title for the pane sprt runs in, remembering the pane's title to restore.
+func newTmux() *Title {
+	t := &Title{mode: ModeTmux, pane: os.Getenv("TMUX_PANE")}
+	t.previous, _ = t.tmux("display-message", "-p", "#{pane_title}")
+	return t
+}
+
+// Set shows text as the title. Setting the title that is already shown does nothing.
+func (t *Title) Set(text string) error {
+	if t == nil {
+		return nil
+	}
+	text = sanitize(text)
+	if t.shown && text == t.current {
+		return nil
+	}
+	first := !t.shown
+	t.current = text
+	t.shown = true
+
+	if t.mode == ModeTmux {
+		_, err := t.tmux("select-pane", "-T", text)
+		return err
+	}
+
+	// Save the title on the terminal's title stack the first time, where supported
+	if first {
+		if _, err := os.Stderr.WriteString("\x1b[22;0t"); err != nil {
+			return err
+		}
+	}
+	// The sequence goes to stderr so that it reaches the terminal even when stdout is piped
+	_, err := fmt.Fprintf(os.Stderr, "\x1b]2;%s\a", text)
+	return err
+}
+
+// Clear restores the title shown before the first Set. Terminals without a title stack
+// are left with an empty title.
+func (t *Title) Clear() error {
+	if t == nil || !t.shown {
+		return nil
+	}
+	t.current = ""
+	t.shown = false
+
+	if t.mode == ModeTmux {
+		_, err := t.tmux("select-pane", "-T", t.previous)
+		return err
+	}
+
+	_, err := os.Stderr.WriteString("\x1b]2;\a\x1b[23;0t")
+	return err
+}
+
+// tmux runs a tmux command against the pane sprt runs in and returns its trimmed output.
+func (t *Title) tmux(args ...string) (string, error) {
+	if t.pane != "" {
+		args = append([]string{args[0], "-t", t.pane}, args[1:]...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "tmux", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to set tmux pane title: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Format fills the {title}, {artist} and {album} placeholders of format, or of DefaultFormat
+// when format is empty.
+func Format(format, trackTitle, artist, album string) string {
+	if format == "" {
+		format = DefaultFormat
+	}
+	return strings.NewReplacer("{title}", trackTitle, "{artist}", artist, "{album}", album).Replace(format)
+}
+
+// sanitize drops control characters, which would end the escape sequence early.
+func sanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+}
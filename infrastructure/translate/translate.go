@@ -0,0 +1,160 @@
+// Package translate translates lyrics with LibreTranslate or DeepL.
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/repository"
+)
+
+// Translation providers.
+const (
+	ProviderLibreTranslate = "libretranslate"
+	ProviderDeepL          = "deepl"
+)
+
+// DeepLURL is the API of the free DeepL plan; the Pro plan is https://api.deepl.com.
+const DeepLURL = "https://api-free.deepl.com"
+
+// New creates the translator of the provider of cfg, or returns nil when no provider is
+// configured.
+func New(httpClient *http.Client, cfg config.TranslationConfig) (repository.Translator, error) {
+	url := strings.TrimSuffix(cfg.URL, "/")
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case ProviderLibreTranslate:
+		if url == "" {
+			return nil, fmt.Errorf("the %s translation provider needs the url of a server", cfg.Provider)
+		}
+		return &libreTranslate{httpClient: httpClient, url: url, apiKey: cfg.APIKey}, nil
+	case ProviderDeepL:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("the %s translation provider needs an API key", cfg.Provider)
+		}
+		if url == "" {
+			url = DeepLURL
+		}
+		return &deepL{httpClient: httpClient, url: url, apiKey: cfg.APIKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown translation provider %q (expected %s or %s)", cfg.Provider, ProviderLibreTranslate, ProviderDeepL)
+	}
+}
+
+// libreTranslate translates with a LibreTranslate server.
+type libreTranslate struct {
+	httpClient *http.Client
+	url        string
+	apiKey     string
+}
+
+// Translate posts the texts to /translate.
+func (t *libreTranslate) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	request := struct {
+		Q      []string `json:"q"`
+		Source string   `json:"source"`
+		Target string   `json:"target"`
+		Format string   `json:"format"`
+		APIKey string   `json:"api_key,omitempty"`
+	}{texts, "auto", target, "text", t.apiKey}
+
+	var response struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := post(ctx, t.httpClient, t.url+"/translate", nil, request, &response); err != nil {
+		return nil, fmt.Errorf("LibreTranslate %w", err)
+	}
+	return checkCount(response.TranslatedText, texts)
+}
+
+// deepL translates with the DeepL API.
+type deepL struct {
+	httpClient *http.Client
+	url        string
+	apiKey     string
+}
+
+// Translate posts the texts to /v2/translate.
+func (t *deepL) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	request := struct {
+		Text       []string `json:"text"`
+		TargetLang string   `json:"target_lang"`
+	}{texts, strings.ToUpper(target)}
+
+	var response struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	header := http.Header{"Authorization": {"DeepL-Auth-Key " + t.apiKey}}
+	if err := post(ctx, t.httpClient, t.url+"/v2/translate", header, request, &response); err != nil {
+		return nil, fmt.Errorf("DeepL %w", err)
+	}
+
+	translations := make([]string, len(response.Translations))
+	for i, translation := range response.Translations {
+		translations[i] = translation.Text
+	}
+	return checkCount(translations, texts)
+}
+
+// post posts request as JSON and decodes the JSON response. Its errors read well after the name
+// of the provider.
+func post(ctx context.Context, httpClient *http.Client, url string, header http.Header, request, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("request couldn't be encoded: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request couldn't be created: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("response couldn't be read: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Both providers explain errors as {"error": "..."} or {"message": "..."}
+		var failure struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &failure)
+		reason := failure.Error + failure.Message
+		if reason == "" {
+			reason = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, reason)
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("response couldn't be parsed: %w", err)
+	}
+	return nil
+}
+
+// checkCount returns the translations if there is one for every text.
+func checkCount(translations, texts []string) ([]string, error) {
+	if len(translations) != len(texts) {
+		return nil, fmt.Errorf("got %d translations for %d lines", len(translations), len(texts))
+	}
+	return translations, nil
+}
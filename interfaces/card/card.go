@@ -0,0 +1,132 @@
+// Package card renders shareable "now playing" cards.
+package card
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/interfaces/status"
+)
+
+// DefaultWidth is the number of cells of a card when no width is given.
+const DefaultWidth = 44
+
+// minWidth leaves room for the frame and a few characters of text.
+const minWidth = 20
+
+// coverCells is the width of the cover in cells; it's half as many rows tall,
+// as every cell shows two pixels stacked with a half block.
+const coverCells = 14
+
+// Options controls how a card is rendered.
+type Options struct {
+	Width int // Number of cells including the frame; 0 uses DefaultWidth
+	// Cover is drawn next to the text with 24-bit ANSI colors; nil leaves it out
+	Cover image.Image
+}
+
+// Render draws a box-framed card for the playing item.
+func Render(track *usecase.CurrentlyPlaying, opts Options) string {
+	width := opts.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	width = max(width, minWidth)
+
+	album := track.Album
+	if track.IsEpisode() {
+		album = "Podcast"
+	}
+	progress := fmt.Sprintf("%s %s %s", formatMs(track.ProgressMs),
+		status.ProgressBar(track.ProgressMs, track.DurationMs, 0), formatMs(track.DurationMs))
+
+	text := []string{"♫ Now Playing", "", track.Title, track.Artist, album, "", progress}
+
+	// The cover sits on the left, the text is vertically centered next to it
+	var cover []string
+	inner := width - 4
+	if opts.Cover != nil && inner-coverCells-2 >= minWidth/2 {
+		cover = renderCover(opts.Cover, coverCells)
+		inner -= coverCells + 2
+		if pad := len(cover) - len(text); pad > 0 {
+			text = append(make([]string, pad/2), text...)
+		}
+	}
+
+	rows := max(len(text), len(cover))
+
+	var sb strings.Builder
+	sb.WriteString("╭" + strings.Repeat("─", width-2) + "╮\n")
+	for i := 0; i < rows; i++ {
+		sb.WriteString("│ ")
+		if cover != nil {
+			if i < len(cover) {
+				sb.WriteString(cover[i])
+			} else {
+				sb.WriteString(strings.Repeat(" ", coverCells))
+			}
+			sb.WriteString("  ")
+		}
+		line := ""
+		if i < len(text) {
+			line = text[i]
+		}
+		sb.WriteString(runewidth.FillRight(status.Truncate(line, inner), inner))
+		sb.WriteString(" │\n")
+	}
+	sb.WriteString("╰" + strings.Repeat("─", width-2) + "╯\n")
+
+	return sb.String()
+}
+
+// renderCover scales the image to cells columns and draws it with upper half blocks,
+// the foreground coloring the top pixel and the background the bottom one.
+func renderCover(img image.Image, cells int) []string {
+	pixels := scale(img, cells, cells)
+
+	lines := make([]string, cells/2)
+	for row := range lines {
+		var sb strings.Builder
+		for x := 0; x < cells; x++ {
+			top, bottom := pixels[2*row][x], pixels[2*row+1][x]
+			fmt.Fprintf(&sb, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", top[0], top[1], top[2], bottom[0], bottom[1], bottom[2])
+		}
+		sb.WriteString("\x1b[0m")
+		lines[row] = sb.String()
+	}
+	return lines
+}
+
+// scale averages the image down to width×height RGB pixels.
+func scale(img image.Image, width, height int) [][][3]uint8 {
+	bounds := img.Bounds()
+	pixels := make([][][3]uint8, height)
+	for y := range pixels {
+		pixels[y] = make([][3]uint8, width)
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+		for x := range pixels[y] {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+
+			var r, g, b, n uint64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					pr, pg, pb, _ := img.At(px, py).RGBA()
+					r, g, b, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), n+1
+				}
+			}
+			pixels[y][x] = [3]uint8{uint8(r / n >> 8), uint8(g / n >> 8), uint8(b / n >> 8)}
+		}
+	}
+	return pixels
+}
+
+// formatMs formats a duration in milliseconds as m:ss.
+func formatMs(ms int) string {
+	totalSeconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", totalSeconds/60, totalSeconds%60)
+}
@@ -0,0 +1,222 @@
+package card
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size of PNG cards, the aspect ratio social media previews use.
+const (
+	pngWidth   = 1200
+	pngHeight  = 630
+	pngPadding = 60
+	pngFrame   = 24 // Inset of the frame drawn around the card
+)
+
+// Theme holds the colors of a PNG card.
+type Theme struct {
+	Background color.Color
+	Text       color.Color // Artist and album
+	Accent     color.Color // Title, heading and progress
+	Muted      color.Color // Frame, times and the empty part of the progress bar
+}
+
+// DefaultTheme returns the colors used when no theme is configured.
+func DefaultTheme() Theme {
+	return Theme{
+		Background: color.RGBA{0x12, 0x12, 0x12, 0xFF},
+		Text:       color.White,
+		Accent:     color.RGBA{0x1D, 0xB9, 0x54, 0xFF},
+		Muted:      color.RGBA{0x88, 0x88, 0x88, 0xFF},
+	}
+}
+
+// ParseHexColor parses a "#RRGGBB" color, returning fallback for empty or invalid values.
+func ParseHexColor(hex string, fallback color.Color) color.Color {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return fallback
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fallback
+	}
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 0xFF}
+}
+
+// pngFaces holds the font faces of a PNG card.
+type pngFaces struct {
+	heading, title, artist, album, small font.Face
+}
+
+// RenderPNG draws the card for the playing item as a PNG image, with the cover on the left when given.
+func RenderPNG(w io.Writer, track *usecase.CurrentlyPlaying, cover image.Image, theme Theme) error {
+	faces, err := loadFaces()
+	if err != nil {
+		return err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, pngWidth, pngHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theme.Background), image.Point{}, draw.Src)
+	drawFrame(img, pngFrame, 2, theme.Muted)
+
+	// The cover fills the height inside the padding, the text takes the rest
+	left := pngPadding
+	if cover != nil {
+		size := pngHeight - 2*pngPadding
+		rect := image.Rect(pngPadding, pngPadding, pngPadding+size, pngPadding+size)
+		draw.CatmullRom.Scale(img, rect, cover, cover.Bounds(), draw.Src, nil)
+		left = rect.Max.X + pngPadding
+	}
+	textWidth := pngWidth - pngPadding - left
+
+	album := track.Album
+	if track.IsEpisode() {
+		album = "Podcast"
+	}
+
+	y := pngPadding + 40
+	drawText(img, faces.heading, theme.Accent, left, y, "NOW PLAYING")
+	y += 90
+	for _, line := range wrapText(faces.title, track.Title, textWidth, 2) {
+		drawText(img, faces.title, theme.Accent, left, y, line)
+		y += 68
+	}
+	y += 10
+	drawText(img, faces.artist, theme.Text, left, y, fitText(faces.artist, track.Artist, textWidth))
+	y += 52
+	drawText(img, faces.album, theme.Muted, left, y, fitText(faces.album, album, textWidth))
+
+	// The progress bar sits at the bottom, with the times below it
+	barTop := pngHeight - pngPadding - 56
+	filled := 0
+	if track.DurationMs > 0 {
+		filled = min(max(track.ProgressMs*textWidth/track.DurationMs, 0), textWidth)
+	}
+	draw.Draw(img, image.Rect(left, barTop, left+textWidth, barTop+8), image.NewUniform(theme.Muted), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(left, barTop, left+filled, barTop+8), image.NewUniform(theme.Accent), image.Point{}, draw.Src)
+
+	duration := formatMs(track.DurationMs)
+	drawText(img, faces.small, theme.Muted, left, barTop+44, formatMs(track.ProgressMs))
+	drawText(img, faces.small, theme.Muted, left+textWidth-font.MeasureString(faces.small, duration).Round(), barTop+44, duration)
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode card: %w", err)
+	}
+	return nil
+}
+
+// loadFaces loads the Go fonts at the sizes used by PNG cards.
+func loadFaces() (*pngFaces, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+
+	face := func(f *opentype.Font, size float64) font.Face {
+		if err != nil {
+			return nil
+		}
+		var face font.Face
+		face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		return face
+	}
+
+	faces := &pngFaces{
+		heading: face(bold, 28),
+		title:   face(bold, 60),
+		artist:  face(regular, 40),
+		album:   face(regular, 32),
+		small:   face(regular, 26),
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font: %w", err)
+	}
+	return faces, nil
+}
+
+// drawText draws text with its baseline at y.
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// drawFrame draws a rectangular outline of the given thickness, inset from the image edges.
+func drawFrame(img draw.Image, inset, thickness int, c color.Color) {
+	b := img.Bounds().Inset(inset)
+	src := image.NewUniform(c)
+	for _, r := range []image.Rectangle{
+		image.Rect(b.Min.X, b.Min.Y, b.Max.X, b.Min.Y+thickness),
+		image.Rect(b.Min.X, b.Max.Y-thickness, b.Max.X, b.Max.Y),
+		image.Rect(b.Min.X, b.Min.Y, b.Min.X+thickness, b.Max.Y),
+		image.Rect(b.Max.X-thickness, b.Min.Y, b.Max.X, b.Max.Y),
+	} {
+		draw.Draw(img, r, src, image.Point{}, draw.Src)
+	}
+}
+
+// fitText shortens text with an ellipsis until it fits in width pixels.
+func fitText(face font.Face, text string, width int) string {
+	if font.MeasureString(face, text).Round() <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if candidate := strings.TrimRight(string(runes), " ") + "…"; font.MeasureString(face, candidate).Round() <= width {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// wrapText breaks text into at most maxLines lines of width pixels, shortening the last one with an ellipsis.
+func wrapText(face font.Face, text string, width, maxLines int) []string {
+	var lines []string
+	line := ""
+	words := strings.Fields(text)
+	for i, word := range words {
+		candidate := strings.TrimSpace(line + " " + word)
+		if line == "" || font.MeasureString(face, candidate).Round() <= width {
+			line = candidate
+			continue
+		}
+		if len(lines) == maxLines-1 {
+			// Put the rest on the last line and let fitText shorten it
+			line = strings.Join(append([]string{line}, words[i:]...), " ")
+			break
+		}
+		lines = append(lines, line)
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	for i := range lines {
+		lines[i] = fitText(face, lines[i], width)
+	}
+	return lines
+}
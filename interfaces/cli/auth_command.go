@@ -4,6 +4,7 @@ package cli
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -25,8 +26,8 @@ func NewAuthCommand(authUseCase usecase.AuthUseCase) *AuthCommand {
 	}
 }
 
-// Init initializes the authentication process.
-func (c *AuthCommand) Init() error {
+// Init initializes the authentication process. The requests end with ctx.
+func (c *AuthCommand) Init(ctx context.Context) error {
 	fmt.Println("Initializing Spotify authentication...")
 
 	// Prompt for client ID
@@ -42,7 +43,7 @@ func (c *AuthCommand) Init() error {
 	}
 
 	// Initialize authentication with the provided credentials
-	authURL, err := c.authUseCase.InitAuth(context.Background(), clientID, clientSecret)
+	authURL, err := c.authUseCase.InitAuth(ctx, clientID, clientSecret)
 	if err != nil {
 		return fmt.Errorf("failed to initialize authentication: %w", err)
 	}
@@ -67,9 +68,8 @@ func (c *AuthCommand) Init() error {
 	// Wait for user to press Enter
 	_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
 
-	// Stop the callback server
-	ctx := context.Background()
-	if err := callbackServer.Stop(ctx); err != nil {
+	// Stop the callback server, also when ctx ended while waiting
+	if err := callbackServer.Stop(context.WithoutCancel(ctx)); err != nil {
 		fmt.Printf("Error stopping callback server: %v\n", err)
 	}
 
@@ -78,21 +78,20 @@ func (c *AuthCommand) Init() error {
 }
 
 // TestCurrentlyPlaying tests the authentication by retrieving the currently playing track.
-func (c *AuthCommand) TestCurrentlyPlaying() error {
+func (c *AuthCommand) TestCurrentlyPlaying(ctx context.Context) error {
 	fmt.Println("Testing authentication by retrieving currently playing track...")
 
-	track, err := c.authUseCase.GetCurrentlyPlaying(context.Background())
+	track, err := c.authUseCase.GetCurrentlyPlaying(ctx)
 	if err != nil {
+		// Check if no track is playing
+		if errors.Is(err, usecase.ErrNothingPlaying) {
+			fmt.Println("No track is currently playing on Spotify. Please start playing a track and try again.")
+			return nil
+		}
 		return fmt.Errorf("failed to get currently playing track: %w", err)
 	}
 
-	// Check if no track is playing
-	if track == "No track currently playing" {
-		fmt.Println("No track is currently playing on Spotify. Please start playing a track and try again.")
-		return nil
-	}
-
-	fmt.Println(track)
+	fmt.Printf("Currently playing: %s by %s from the album %s\n", track.Title, track.Artist, track.Album)
 	return nil
 }
 
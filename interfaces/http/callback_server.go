@@ -15,37 +15,54 @@ import (
 type CallbackServer struct {
 	server      *http.Server
 	authUseCase usecase.AuthUseCase
+	done        chan error
 }
 
 // NewCallbackServer creates a new instance of CallbackServer.
 func NewCallbackServer(authUseCase usecase.AuthUseCase) *CallbackServer {
-	return &CallbackServer{
+	s := &CallbackServer{
 		authUseCase: authUseCase,
+		done:        make(chan error, 1),
 	}
-}
 
-// Start starts the callback server on the specified port.
-func (s *CallbackServer) Start(port int) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/callback", s.handleCallback)
+	s.server = &http.Server{Handler: mux}
+	return s
+}
 
-	s.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
-		Handler: mux,
+// Done returns a channel that receives the result of the first callback:
+// nil once the token is stored, or the error that stopped the exchange.
+func (s *CallbackServer) Done() <-chan error {
+	return s.done
+}
+
+// finish reports the result of a callback, keeping only the first one.
+func (s *CallbackServer) finish(err error) {
+	select {
+	case s.done <- err:
+	default:
 	}
+}
 
+// Start starts the callback server on the specified port. It returns http.ErrServerClosed
+// once stopped, also when stopped before it started.
+func (s *CallbackServer) Start(port int) error {
 	fmt.Printf("Callback server started on http://localhost:%d\n", port)
 	fmt.Println("Waiting for Spotify authorization...")
 
+	return s.Listen(port)
+}
+
+// Listen starts the callback server on the specified port like Start, without announcing it
+// on stdout, e.g. while a TUI takes the terminal.
+func (s *CallbackServer) Listen(port int) error {
+	s.server.Addr = fmt.Sprintf(":%d", port)
 	return s.server.ListenAndServe()
 }
 
 // Stop stops the callback server.
 func (s *CallbackServer) Stop(ctx context.Context) error {
-	if s.server == nil {
-		return nil
-	}
-
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -56,6 +73,9 @@ func (s *CallbackServer) Stop(ctx context.Context) error {
 func (s *CallbackServer) handleCallback(w http.ResponseWriter, r *http.Request) {
 	code := r.URL.Query().Get("code")
 	if code == "" {
+		if reason := r.URL.Query().Get("error"); reason != "" {
+			s.finish(fmt.Errorf("authorization was denied: %s", reason))
+		}
 		http.Error(w, "Authorization code not found", http.StatusBadRequest)
 		return
 	}
@@ -63,6 +83,7 @@ func (s *CallbackServer) handleCallback(w http.ResponseWriter, r *http.Request)
 	err := s.authUseCase.HandleCallback(r.Context(), code)
 	if err != nil {
 		log.Printf("Error handling callback: %v", err)
+		s.finish(err)
 		http.Error(w, "Error handling callback", http.StatusInternalServerError)
 		return
 	}
@@ -71,9 +92,11 @@ func (s *CallbackServer) handleCallback(w http.ResponseWriter, r *http.Request)
 	err = s.authUseCase.ExchangeCodeForToken(r.Context())
 	if err != nil {
 		log.Printf("Error exchanging code for token: %v", err)
+		s.finish(err)
 		http.Error(w, "Error exchanging code for token", http.StatusInternalServerError)
 		return
 	}
+	s.finish(nil)
 
 	// Return a success message to the user
 	w.Header().Set("Content-Type", "text/html")
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/interfaces/ipc"
+)
+
+// EventServer streams the playback events of the daemon as server-sent events, so OBS browser
+// sources and web dashboards are told about changes instead of polling for them.
+type EventServer struct {
+	server       *http.Server
+	stateUseCase usecase.StateUseCase
+	feed         ipc.StateFeed
+
+	// ctx ends the open streams on Stop, as the server doesn't wait for them by itself
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewEventServer creates a new instance of EventServer. The state is read from stateUseCase
+// and changes come from feed, as for the subscribers of the control socket.
+func NewEventServer(stateUseCase usecase.StateUseCase, feed ipc.StateFeed) *EventServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EventServer{
+		stateUseCase: stateUseCase,
+		feed:         feed,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Start starts serving /events on addr, e.g. "127.0.0.1:8977", in the background.
+func (s *EventServer) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start event server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", s.handleEvents)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Event server error: %v\n", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop ends the open streams and stops the event server.
+func (s *EventServer) Stop(ctx context.Context) error {
+	s.cancel()
+	if s.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.server.Shutdown(ctx)
+}
+
+// handleEvents streams the events until the client disconnects: the current state as a
+// "state" event, then a "track", "status" or "lyric" event for every change. The data of
+// each event is the playback state after it as JSON.
+func (s *EventServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	// Dashboards served from elsewhere, e.g. a file opened in the browser, may subscribe too
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	stop := context.AfterFunc(s.ctx, cancel)
+	defer stop()
+
+	ipc.FollowEvents(ctx, s.stateUseCase, s.feed, func(event ipc.Event) bool {
+		data, err := json.Marshal(event.State)
+		if err != nil {
+			return true
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Event, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	})
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// HealthServer serves the health of the daemon for container health checks and monitoring.
+type HealthServer struct {
+	server        *http.Server
+	healthUseCase usecase.HealthUseCase
+	staleAfter    time.Duration
+}
+
+// NewHealthServer creates a new instance of HealthServer. A playback poll older than staleAfter
+// makes the daemon unhealthy.
+func NewHealthServer(healthUseCase usecase.HealthUseCase, staleAfter time.Duration) *HealthServer {
+	return &HealthServer{
+		healthUseCase: healthUseCase,
+		staleAfter:    staleAfter,
+	}
+}
+
+// Start starts serving /healthz on addr, e.g. "127.0.0.1:8976", in the background.
+func (s *HealthServer) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start health server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Health server error: %v\n", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the health server.
+func (s *HealthServer) Stop(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.server.Shutdown(ctx)
+}
+
+// handleHealth serves the health report as JSON, with 503 Service Unavailable
+// while the daemon is starting or unhealthy.
+func (s *HealthServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	report := s.healthUseCase.Report(time.Now(), s.staleAfter)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == usecase.HealthStarting || report.Status == usecase.HealthUnhealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
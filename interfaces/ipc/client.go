@@ -0,0 +1,127 @@
+package ipc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// dialTimeout bounds connecting to the control socket.
+const dialTimeout = 500 * time.Millisecond
+
+// Client asks the daemon over its control socket, so commands share the playback state the
+// daemon polls and the lyrics it found instead of asking Spotify and lrclib.net themselves.
+// It implements usecase.TrackSource and usecase.LyricSource.
+type Client struct {
+	path string
+}
+
+// NewClient creates a new instance of Client for the socket at path.
+func NewClient(path string) *Client {
+	return &Client{path: path}
+}
+
+// Available reports whether a daemon is listening on the socket.
+func (c *Client) Available(ctx context.Context) bool {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// Current returns the playback snapshot of the daemon, as "sprt status" shows it.
+func (c *Client) Current(ctx context.Context) (*entity.PlaybackSnapshot, error) {
+	var snapshot entity.PlaybackSnapshot
+	if err := c.call(ctx, MethodStatus, nil, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetPlaybackState returns the full playback state, including the active device.
+func (c *Client) GetPlaybackState(ctx context.Context) (*usecase.PlaybackState, error) {
+	var state usecase.PlaybackState
+	if err := c.call(ctx, MethodPlayback, nil, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// GetCurrentlyPlayingDetails returns the playing track or episode.
+func (c *Client) GetCurrentlyPlayingDetails(ctx context.Context) (*usecase.CurrentlyPlaying, error) {
+	state, err := c.GetPlaybackState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &state.CurrentlyPlaying, nil
+}
+
+// GetLyrics returns the lyrics for the given artist, title, album, and duration.
+func (c *Client) GetLyrics(ctx context.Context, artist, title, album string, durationMs int) (*usecase.Lyrics, error) {
+	var lyrics usecase.Lyrics
+	params := LyricsParams{Artist: artist, Title: title, Album: album, DurationMs: durationMs}
+	if err := c.call(ctx, MethodLyrics, params, &lyrics); err != nil {
+		return nil, err
+	}
+	return &lyrics, nil
+}
+
+// dial connects to the socket.
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	return dialer.DialContext(ctx, "unix", c.path)
+}
+
+// call sends a request on a connection of its own and decodes the result into result.
+// An error answered by the daemon is returned as the error of the use cases its code stands
+// for, so callers check it like those, e.g. with errors.Is(err, usecase.ErrNothingPlaying).
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the daemon: %w", err)
+	}
+	defer conn.Close()
+
+	// Reads and writes return once ctx ends
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	request := Request{ID: json.RawMessage("1"), Method: method}
+	if params != nil {
+		if request.Params, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return c.failed(ctx, err)
+	}
+
+	var response struct {
+		Response
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return c.failed(ctx, err)
+	}
+	if err := response.err(); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// failed returns the error of a request that broke off, which is ctx's when it ended.
+func (c *Client) failed(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("failed to ask the daemon: %w", err)
+}
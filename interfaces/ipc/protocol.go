@@ -0,0 +1,168 @@
+// Package ipc provides the control socket of the daemon: a UNIX domain socket speaking
+// newline-delimited JSON, so other sprt invocations and third-party tools can query the
+// playback state and control playback without polling Spotify themselves.
+package ipc
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/repository"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// Methods of the control protocol.
+const (
+	// MethodStatus returns the playback state as an entity.PlaybackSnapshot
+	MethodStatus = "status"
+	// MethodLyric returns the current lyric line as a LyricResult
+	MethodLyric = "lyric"
+	// MethodPlayback returns the full playback state as a usecase.PlaybackState
+	MethodPlayback = "playback"
+	// MethodLyrics returns the lyrics of the track named by LyricsParams as a usecase.Lyrics
+	MethodLyrics = "lyrics"
+	// MethodPlayPause pauses playback when playing and resumes it otherwise
+	MethodPlayPause = "play-pause"
+	// MethodNext skips to the next track
+	MethodNext = "next"
+	// MethodSubscribe sends an Event for every change until the connection closes
+	MethodSubscribe = "subscribe"
+)
+
+// Events sent to subscribers.
+const (
+	// EventState carries the state at the time of subscribing
+	EventState = "state"
+	// EventTrack is sent when another track starts or playback stops
+	EventTrack = "track"
+	// EventStatus is sent when playback is paused or resumed
+	EventStatus = "status"
+	// EventLyric is sent when the current lyric line changes
+	EventLyric = "lyric"
+)
+
+// Request is a line sent by a client, e.g. {"id":1,"method":"status"}.
+type Request struct {
+	// ID is echoed in the response, so clients can match them; any JSON value is allowed
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	// Params holds the arguments of methods taking any, e.g. LyricsParams
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// LyricsParams are the arguments of MethodLyrics.
+type LyricsParams struct {
+	Artist string `json:"artist"`
+	Title  string `json:"title"`
+	Album  string `json:"album"`
+	// DurationMs is the length of the track, 0 when unknown, for matching the right version
+	DurationMs int `json:"duration_ms,omitempty"`
+}
+
+// Error codes of failed requests, for the errors clients tell apart.
+const (
+	// CodeNothingPlaying is answered when no track is playing
+	CodeNothingPlaying = "nothing_playing"
+	// CodeRateLimited is answered while Spotify is rate limiting, with the wait in RetryAfterMs
+	CodeRateLimited = "rate_limited"
+)
+
+// Response answers a request with its result, or with an error message when it failed.
+type Response struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Result interface{}     `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	// Code tells some errors apart, e.g. CodeNothingPlaying; it is empty for the others
+	Code string `json:"code,omitempty"`
+	// RetryAfterMs is how long Spotify asked to wait with CodeRateLimited
+	RetryAfterMs int64 `json:"retry_after_ms,omitempty"`
+}
+
+// LyricResult is the result of MethodLyric. Lyric is nil between lines and without lyrics.
+type LyricResult struct {
+	Track      *entity.SnapshotTrack `json:"track"`
+	Lyric      *entity.SnapshotLyric `json:"lyric"`
+	ProgressMs int                   `json:"progress_ms"`
+}
+
+// Event is a line sent to subscribers, carrying the state after the change.
+type Event struct {
+	Event string                   `json:"event"`
+	State *entity.PlaybackSnapshot `json:"state"`
+}
+
+// DefaultSocketPath returns the path of the control socket, ~/.sprt/daemon.sock.
+func DefaultSocketPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".sprt", "daemon.sock")
+}
+
+// changes names the events between two states; previous is nil for the first one.
+func changes(previous, current *entity.PlaybackSnapshot) []string {
+	if previous == nil {
+		return []string{EventState}
+	}
+
+	var events []string
+	if trackID(previous) != trackID(current) {
+		events = append(events, EventTrack)
+	}
+	if previous.Status != current.Status {
+		events = append(events, EventStatus)
+	}
+	if lyricLine(previous) != lyricLine(current) {
+		events = append(events, EventLyric)
+	}
+	return events
+}
+
+// trackID identifies the track of a state, empty when nothing plays.
+func trackID(snapshot *entity.PlaybackSnapshot) string {
+	if snapshot.Track == nil {
+		return ""
+	}
+	return snapshot.Track.ID
+}
+
+// lyricLine returns the lyric line of a state, with index -1 without one.
+func lyricLine(snapshot *entity.PlaybackSnapshot) entity.SnapshotLyric {
+	if snapshot.Lyric == nil {
+		return entity.SnapshotLyric{Index: -1}
+	}
+	return *snapshot.Lyric
+}
+
+// setError records err in the response, with the code of the errors clients tell apart.
+func (r *Response) setError(err error) {
+	r.Error = err.Error()
+	var rateLimit *repository.RateLimitError
+	switch {
+	case errors.Is(err, usecase.ErrNothingPlaying):
+		r.Code = CodeNothingPlaying
+	case errors.As(err, &rateLimit):
+		r.Code = CodeRateLimited
+		r.RetryAfterMs = rateLimit.RetryAfter.Milliseconds()
+	}
+}
+
+// err returns the error answered in the response, as the error of the use cases its code
+// stands for, so clients check it as they check those.
+func (r *Response) err() error {
+	switch {
+	case r.Error == "":
+		return nil
+	case r.Code == CodeNothingPlaying:
+		return usecase.ErrNothingPlaying
+	case r.Code == CodeRateLimited:
+		return &repository.RateLimitError{RetryAfter: time.Duration(r.RetryAfterMs) * time.Millisecond}
+	default:
+		return errors.New(r.Error)
+	}
+}
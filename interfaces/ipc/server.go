@@ -0,0 +1,328 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// writeTimeout bounds how long a slow client may hold up a response or an event.
+const writeTimeout = time.Second
+
+// StateFeed passes on the playback state whenever the daemon updates it.
+type StateFeed interface {
+	// Subscribe returns a channel receiving the updated states, and a function ending the subscription.
+	Subscribe() (<-chan *entity.PlaybackSnapshot, func())
+}
+
+// ControlFunc runs a playback action of "sprt control", e.g. "play-pause" or "next".
+type ControlFunc func(ctx context.Context, action string) error
+
+// Server serves the control protocol on a UNIX domain socket.
+type Server struct {
+	stateUseCase usecase.StateUseCase
+	feed         StateFeed
+	lyricUseCase usecase.LyricUseCase
+	control      ControlFunc
+
+	path     string
+	listener net.Listener
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// NewServer creates a new instance of Server. The state and the full playback state are read
+// from stateUseCase, which the daemon keeps following playback, changes come from feed, the
+// lyrics from lyricUseCase, and control runs the playback actions.
+func NewServer(stateUseCase usecase.StateUseCase, feed StateFeed, lyricUseCase usecase.LyricUseCase, control ControlFunc) *Server {
+	return &Server{
+		stateUseCase: stateUseCase,
+		feed:         feed,
+		lyricUseCase: lyricUseCase,
+		control:      control,
+		conns:        make(map[net.Conn]struct{}),
+	}
+}
+
+// Start listens on the socket at path and serves clients in the background. A socket left
+// behind by a daemon that didn't stop cleanly is replaced, one still in use is an error.
+// Only the current user may connect.
+func (s *Server) Start(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return fmt.Errorf("another daemon is listening on %s", path)
+		}
+		os.Remove(path)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on socket: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict socket: %w", err)
+	}
+
+	s.path = path
+	s.listener = listener
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go s.accept()
+	return nil
+}
+
+// Stop disconnects all clients, waits for their requests to finish and removes the socket.
+func (s *Server) Stop(ctx context.Context) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	s.cancel()
+	err := s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = errors.Join(err, ctx.Err())
+	}
+
+	os.Remove(s.path)
+	return err
+}
+
+// accept serves new clients until the listener is closed.
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.ctx.Err() != nil {
+			// Accepted while stopping
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+// serve answers the requests of a client, one per line, until it disconnects.
+func (s *Server) serve(conn net.Conn) {
+	defer s.wg.Done()
+	c := newClient(conn)
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		c.close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var request Request
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			c.send(Response{Error: fmt.Sprintf("invalid request: %v", err)})
+			continue
+		}
+
+		result, err := s.handle(c, request)
+		response := Response{ID: request.ID, Result: result}
+		if err != nil {
+			response.setError(err)
+		}
+		c.send(response)
+
+		// Events follow the response to the subscription
+		if request.Method == MethodSubscribe && err == nil {
+			s.wg.Add(1)
+			go s.publish(c)
+		}
+	}
+}
+
+// handle runs a request and returns its result.
+func (s *Server) handle(c *client, request Request) (interface{}, error) {
+	switch method := request.Method; method {
+	case MethodStatus:
+		return s.stateUseCase.Current(s.ctx)
+
+	case MethodLyric:
+		snapshot, err := s.stateUseCase.Current(s.ctx)
+		if err != nil {
+			return nil, err
+		}
+		return LyricResult{Track: snapshot.Track, Lyric: snapshot.Lyric, ProgressMs: snapshot.ProgressMs}, nil
+
+	case MethodPlayback:
+		return s.stateUseCase.GetPlaybackState(s.ctx)
+
+	case MethodLyrics:
+		var params LyricsParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		// The lyric use case keeps the lyrics it found, so every client after the first is
+		// answered from memory
+		return s.lyricUseCase.GetLyrics(s.ctx, params.Artist, params.Title, params.Album, params.DurationMs)
+
+	case MethodPlayPause, MethodNext:
+		if err := s.control(s.ctx, method); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case MethodSubscribe:
+		if !c.subscribe() {
+			return nil, errors.New("already subscribed")
+		}
+		return true, nil
+
+	default:
+		return nil, fmt.Errorf("unknown method %q (expected %s, %s, %s, %s, %s, %s or %s)",
+			method, MethodStatus, MethodLyric, MethodPlayback, MethodLyrics, MethodPlayPause, MethodNext, MethodSubscribe)
+	}
+}
+
+// publish sends the events to a subscribed client until it disconnects or the server stops.
+func (s *Server) publish(c *client) {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	FollowEvents(ctx, s.stateUseCase, s.feed, func(event Event) bool {
+		return c.send(event)
+	})
+}
+
+// FollowEvents calls send with the current state as an EventState, then with an event for
+// every change reported by feed, until ctx ends or send returns false.
+func FollowEvents(ctx context.Context, stateUseCase usecase.StateUseCase, feed StateFeed, send func(Event) bool) {
+	updates, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+
+	var previous *entity.PlaybackSnapshot
+	if snapshot, err := stateUseCase.Current(ctx); err == nil {
+		previous = snapshot
+		if !send(Event{Event: EventState, State: snapshot}) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case snapshot := <-updates:
+			for _, event := range changes(previous, snapshot) {
+				if !send(Event{Event: event, State: snapshot}) {
+					return
+				}
+			}
+			previous = snapshot
+		}
+	}
+}
+
+// client is a connection to the control socket. Responses and events are written one
+// line at a time, as both may be sent at once.
+type client struct {
+	conn net.Conn
+
+	mu         sync.Mutex
+	subscribed bool
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+// newClient creates a client for a connection.
+func newClient(conn net.Conn) *client {
+	return &client{conn: conn, closed: make(chan struct{})}
+}
+
+// subscribe marks the client as subscribed, returning false when it already was.
+func (c *client) subscribe() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscribed {
+		return false
+	}
+	c.subscribed = true
+	return true
+}
+
+// send writes v as a line, closing the connection when the client can't keep up.
+// It returns false once the connection is closed.
+func (c *client) send(v interface{}) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := c.conn.Write(append(data, '\n')); err != nil {
+		c.close()
+		return false
+	}
+	return true
+}
+
+// done returns a channel closed once the connection is closed.
+func (c *client) done() <-chan struct{} {
+	return c.closed
+}
+
+// close closes the connection, ending the subscription.
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.conn.Close()
+	})
+}
@@ -0,0 +1,252 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// Output formats supported by Format.
+const (
+	FormatPlain      = "plain"
+	FormatWaybar     = "waybar"
+	FormatI3StatusRS = "i3status-rust"
+	FormatSlstatus   = "slstatus"
+	FormatPolybar    = "polybar"
+)
+
+// Progress bar glyphs.
+const (
+	progressFilled = "▰"
+	progressEmpty  = "▱"
+)
+
+// DefaultProgressBarWidth is the number of cells used when no width is configured.
+const DefaultProgressBarWidth = 10
+
+// Options controls how a status line is rendered.
+type Options struct {
+	ProgressBar      bool   // Append a progress bar segment
+	ProgressBarWidth int    // Number of cells in the progress bar
+	StoppedText      string // Text shown when nothing is playing
+	// MaxWidth limits the artist and title to this many terminal cells; 0 means unlimited.
+	// Wide characters such as CJK and emoji take two cells.
+	MaxWidth int
+	// Marquee scrolls text longer than MaxWidth instead of truncating it
+	Marquee bool
+	// MarqueeOffset is the scroll position, advanced by the caller between invocations
+	MarqueeOffset int
+}
+
+// marqueeSeparator is shown between the end and the start of scrolling text.
+const marqueeSeparator = "   "
+
+// waybarOutput is the JSON object read by waybar custom modules.
+type waybarOutput struct {
+	Text       string `json:"text"`
+	Tooltip    string `json:"tooltip"`
+	Class      string `json:"class"`
+	Alt        string `json:"alt"`
+	Percentage int    `json:"percentage"`
+}
+
+// i3StatusRSOutput is the JSON object read by i3status-rust custom blocks with json = true.
+type i3StatusRSOutput struct {
+	Icon      string `json:"icon"`
+	State     string `json:"state"`
+	Text      string `json:"text"`
+	ShortText string `json:"short_text"`
+}
+
+// i3StatusRSShortWidth is the character budget of the short text i3status-rust
+// falls back to when the bar runs out of space.
+const i3StatusRSShortWidth = 20
+
+// Format renders the snapshot in the given output format.
+func Format(snapshot *entity.PlaybackSnapshot, format string, opts Options) (string, error) {
+	switch format {
+	case FormatPlain, "":
+		return Line(snapshot, opts), nil
+	case FormatWaybar:
+		return Waybar(snapshot, opts)
+	case FormatI3StatusRS:
+		return I3StatusRS(snapshot, opts)
+	case FormatSlstatus:
+		return Slstatus(snapshot, opts), nil
+	case FormatPolybar:
+		return Polybar(snapshot, opts), nil
+	default:
+		return "", fmt.Errorf("unknown status format %q (expected plain, waybar, i3status-rust, slstatus or polybar)", format)
+	}
+}
+
+// Line renders the snapshot as a single line of text.
+func Line(snapshot *entity.PlaybackSnapshot, opts Options) string {
+	if snapshot.Status == entity.StatusStopped || snapshot.Track == nil {
+		return opts.StoppedText
+	}
+
+	icon := "▶"
+	if snapshot.Status == entity.StatusPaused {
+		icon = "⏸"
+	}
+
+	text := Text(snapshot)
+	if opts.MaxWidth > 0 {
+		if opts.Marquee {
+			text = Marquee(text, opts.MaxWidth, opts.MarqueeOffset)
+		} else {
+			text = Truncate(text, opts.MaxWidth)
+		}
+	}
+
+	line := icon + " " + text
+	if opts.ProgressBar {
+		line += " " + ProgressBar(snapshot.ProgressMs, snapshot.Track.DurationMs, opts.ProgressBarWidth)
+	}
+
+	return line
+}
+
+// Text returns the artist and title shown in the status line, or an empty string when stopped.
+func Text(snapshot *entity.PlaybackSnapshot) string {
+	if snapshot.Status == entity.StatusStopped || snapshot.Track == nil {
+		return ""
+	}
+	return snapshot.Track.Artist + " - " + snapshot.Track.Title
+}
+
+// Truncate shortens text to width terminal cells, marking the cut with an ellipsis.
+func Truncate(text string, width int) string {
+	if width <= 1 {
+		return runewidth.Truncate(text, width, "")
+	}
+	return runewidth.Truncate(text, width, "…")
+}
+
+// Marquee returns a window of width terminal cells of text starting at offset cells in,
+// wrapping around so that successive offsets scroll the text. Text that fits is returned unchanged.
+// A wide character cut by the window's edge is replaced with spaces to keep the width constant.
+func Marquee(text string, width, offset int) string {
+	if runewidth.StringWidth(text) <= width {
+		return text
+	}
+
+	loop := text + marqueeSeparator
+	loopWidth := runewidth.StringWidth(loop)
+	start := offset % loopWidth
+	if start < 0 {
+		start += loopWidth
+	}
+
+	// Two copies of the loop always cover a window narrower than the text
+	window := runewidth.TruncateLeft(loop+loop, start, "")
+	return runewidth.FillRight(runewidth.Truncate(window, width, ""), width)
+}
+
+// Waybar renders the snapshot as a waybar custom module JSON object.
+func Waybar(snapshot *entity.PlaybackSnapshot, opts Options) (string, error) {
+	output := waybarOutput{
+		Text:  Line(snapshot, opts),
+		Class: snapshot.Status,
+		Alt:   snapshot.Status,
+	}
+
+	if track := snapshot.Track; track != nil && snapshot.Status != entity.StatusStopped {
+		output.Tooltip = fmt.Sprintf("%s\n%s\n%s\n%s / %s", track.Title, track.Artist, track.Album,
+			formatMs(snapshot.ProgressMs), formatMs(track.DurationMs))
+		output.Percentage = percentage(snapshot.ProgressMs, track.DurationMs)
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode waybar output: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// I3StatusRS renders the snapshot as an i3status-rust custom block JSON object.
+// The block draws its own icon, so the text carries no play/pause glyph.
+func I3StatusRS(snapshot *entity.PlaybackSnapshot, opts Options) (string, error) {
+	output := i3StatusRSOutput{
+		Icon:  "music",
+		State: "Idle",
+		Text:  opts.StoppedText,
+	}
+
+	if text := Text(snapshot); text != "" {
+		if snapshot.Status == entity.StatusPlaying {
+			output.State = "Info"
+		}
+		output.Text = trimLine(Line(snapshot, opts))
+		output.ShortText = Truncate(snapshot.Track.Title, i3StatusRSShortWidth)
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode i3status-rust output: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Slstatus renders the snapshot as plain ASCII-friendly text for slstatus-style bars,
+// which read a single line from a command and often use fonts without media glyphs.
+func Slstatus(snapshot *entity.PlaybackSnapshot, opts Options) string {
+	if Text(snapshot) == "" {
+		return opts.StoppedText
+	}
+
+	line := trimLine(Line(snapshot, opts))
+	if snapshot.Status == entity.StatusPaused {
+		line = "[paused] " + line
+	}
+	return line
+}
+
+// Polybar renders the snapshot as a line for a polybar script module. Polybar reads % as the
+// start of a formatting tag, so it is doubled in names.
+func Polybar(snapshot *entity.PlaybackSnapshot, opts Options) string {
+	return strings.ReplaceAll(Line(snapshot, opts), "%", "%%")
+}
+
+// trimLine drops the leading play/pause glyph from a line rendered by Line.
+func trimLine(line string) string {
+	_, rest, found := strings.Cut(line, " ")
+	if !found {
+		return line
+	}
+	return rest
+}
+
+// ProgressBar renders the progress as a bar of the given number of cells, e.g. ▰▰▰▱▱▱.
+func ProgressBar(progressMs, durationMs, width int) string {
+	if width <= 0 {
+		width = DefaultProgressBarWidth
+	}
+
+	filled := 0
+	if durationMs > 0 {
+		filled = min(max(progressMs*width/durationMs, 0), width)
+	}
+
+	return strings.Repeat(progressFilled, filled) + strings.Repeat(progressEmpty, width-filled)
+}
+
+// percentage returns the progress as a percentage of the duration.
+func percentage(progressMs, durationMs int) int {
+	if durationMs <= 0 {
+		return 0
+	}
+	return min(max(progressMs*100/durationMs, 0), 100)
+}
+
+// formatMs formats a duration in milliseconds as m:ss.
+func formatMs(ms int) string {
+	totalSeconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", totalSeconds/60, totalSeconds%60)
+}
@@ -2,160 +2,183 @@ package tui
 
 import (
 	"context"
-	"github.com/muhadif/sprt/domain/usecase"
-	"strings"
-	"time"
+	"errors"
+	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
 )
 
-// AppModel is the main application model that manages transitions between screens
+// AppUseCases are the use cases the screens of the app run with
+type AppUseCases struct {
+	Auth     usecase.AuthUseCase
+	Player   usecase.PlayerUseCase
+	Lyric    usecase.LyricUseCase
+	Search   usecase.SearchUseCase
+	Playlist usecase.PlaylistUseCase
+	Library  usecase.LibraryUseCase
+	// Authorize starts the authorization with the client credentials entered on the
+	// authentication screen
+	Authorize AuthorizeFunc
+}
+
+// Authorization is an authorization waiting for the user to allow sprt in the browser
+type Authorization struct {
+	URL           string
+	BrowserOpened bool
+	// Done receives the result of the callback: nil once the token is stored
+	Done <-chan error
+}
+
+// AuthorizeFunc starts the authorization with the client credentials; it's given up when ctx ends
+type AuthorizeFunc func(ctx context.Context, clientID, clientSecret string) (*Authorization, error)
+
+// AppModel is the model of the app: one program showing the menu and the screens opened from it.
+// Quitting a screen goes back to the menu, quitting the menu or pressing Ctrl+C ends the app.
 type AppModel struct {
-	currentScreen tea.Model
-	nextScreen    tea.Model
-	transitioning bool
-	progress      float64
-	windowWidth   int
-	windowHeight  int
-
-	// Use cases
-	authUseCase   usecase.AuthUseCase
-	playerUseCase usecase.PlayerUseCase
-	lyricUseCase  usecase.LyricUseCase
+	appConfig  *config.Config
+	useCases   AppUseCases
+	version    string
+	buildDate  string
+	commitHash string
+
+	// menu keeps the cursor of the menu while a screen is shown
+	menu   MenuModel
+	screen tea.Model
+	// screenID tells the messages of the current screen from those of the screens closed before
+	screenID  int
+	screenCtx context.Context
+	// cancelScreen cancels the requests of the current screen
+	cancelScreen context.CancelFunc
+	// opening is the menu item whose screen is being opened
+	opening string
+	// waitingFor is the menu item opened once a track plays, while no track is playing
+	waitingFor string
+	transition *TransitionManager
+	windowSize tea.WindowSizeMsg
 
 	// Context for cancellation
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-// TransitionMsg is a message sent when a transition to a new screen is requested
-type TransitionMsg struct {
-	Screen tea.Model
+// screenMsg carries a message of the screen with the given id
+type screenMsg struct {
+	id  int
+	msg tea.Msg
 }
 
-// TransitionTickMsg is a message sent when the transition animation should be updated
-type TransitionTickMsg time.Time
+// screenDoneMsg reports that the screen with the given id quit
+type screenDoneMsg struct {
+	id int
+}
 
-// NewAppModel creates a new app model with the main menu as the initial screen
-func NewAppModel(authUseCase usecase.AuthUseCase, playerUseCase usecase.PlayerUseCase, lyricUseCase usecase.LyricUseCase) *AppModel {
+// screenOpenedMsg carries the screen of a menu item, or the error opening it
+type screenOpenedMsg struct {
+	choice string
+	screen tea.Model
+	ctx    context.Context
+	cancel context.CancelFunc
+	err    error
+}
+
+// authorizationMsg carries the authorization started for the screen with the given id
+type authorizationMsg struct {
+	id            int
+	authorization *Authorization
+	err           error
+}
+
+// NewAppModel creates a new app model with the main menu as the initial screen; its screens run until ctx ends
+func NewAppModel(ctx context.Context, appConfig *config.Config, useCases AppUseCases, version, buildDate, commitHash string) *AppModel {
 	// Create a cancellable context
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 
+	menu := *NewMenuModel(appConfig)
 	return &AppModel{
-		currentScreen: NewMenuModel(),
-		transitioning: false,
-		progress:      0,
-		windowWidth:   80,
-		windowHeight:  24,
-		authUseCase:   authUseCase,
-		playerUseCase: playerUseCase,
-		lyricUseCase:  lyricUseCase,
-		ctx:           ctx,
-		cancel:        cancel,
+		appConfig:  appConfig,
+		useCases:   useCases,
+		version:    version,
+		buildDate:  buildDate,
+		commitHash: commitHash,
+		menu:       menu,
+		screen:     menu,
+		transition: NewTransitionManager(),
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 }
 
 // Init initializes the model
 func (m *AppModel) Init() tea.Cmd {
-	return tea.Batch(
-		m.currentScreen.Init(),
-	)
+	return hostCmd(m.screenID, m.screen.Init())
 }
 
 // Update updates the model
 func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.windowWidth = msg.Width
-		m.windowHeight = msg.Height
+		m.windowSize = msg
+		return m.updateScreen(msg)
 
-		// Pass the window size to the current screen
-		if m.currentScreen != nil {
-			_, _ = m.currentScreen.Update(msg)
+	case tea.KeyMsg:
+		// Ctrl+C ends the app on every screen, their other quit keys go back to the menu
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
 		}
-
-		// Pass the window size to the next screen if we're transitioning
-		if m.transitioning && m.nextScreen != nil {
-			_, _ = m.nextScreen.Update(msg)
+		if m.busy() {
+			return m, nil
 		}
+		return m.updateScreen(msg)
 
-	case TransitionMsg:
-		// Start a transition to the new screen
-		m.nextScreen = msg.Screen
-		m.transitioning = true
-		m.progress = 0
-
-		// Initialize the next screen
-		cmd := m.nextScreen.Init()
-
-		// Start the transition animation
-		return m, tea.Batch(cmd, m.tickTransition())
-
-	case TransitionTickMsg:
-		if !m.transitioning {
+	case tea.MouseMsg:
+		if m.busy() {
 			return m, nil
 		}
+		return m.updateScreen(msg)
 
-		// Update the transition progress
-		m.progress += 0.1
+	case TransitionUpdateMsg:
+		_, cmd := m.transition.UpdateTransition()
+		return m, cmd
 
-		if m.progress >= 1.0 {
-			// Transition complete, switch to the next screen
-			m.currentScreen = m.nextScreen
-			m.nextScreen = nil
-			m.transitioning = false
+	case screenMsg:
+		if msg.id != m.screenID {
 			return m, nil
 		}
+		return m.updateScreen(msg.msg)
 
-		// Continue the transition animation
-		return m, m.tickTransition()
-
-	default:
-		// If we're transitioning, don't pass messages to the current screen
-		if m.transitioning {
+	case screenDoneMsg:
+		if msg.id != m.screenID {
 			return m, nil
 		}
+		return m, m.screenDone()
 
-		// Pass the message to the current screen
-		newModel, cmd := m.currentScreen.Update(msg)
-
-		// Check if the current screen has changed
-		if newModel != m.currentScreen {
-			m.currentScreen = newModel.(tea.Model)
+	case screenOpenedMsg:
+		m.opening = ""
+		if msg.err != nil {
+			msg.cancel()
+			return m, m.showMenu(fmt.Sprintf("Error: %v", msg.err))
 		}
-
-		// Handle menu selection
-		if menuModel, ok := m.currentScreen.(MenuModel); ok && menuModel.choice != "" && menuModel.choice != "quit" {
-			// User selected a menu item, transition to the appropriate screen
-			var nextScreen tea.Model
-
-			switch menuModel.choice {
-			case "current":
-				nextScreen = NewCurrentTrackModel("", "", "", "", "", false)
-			case "lyric show":
-				// We'll need to initialize this properly later
-				// For now, just create a placeholder
-				nextScreen = &LyricModel{}
-			case "lyric pipe":
-				// We'll need to initialize this properly later
-				// For now, just create a placeholder
-				nextScreen = &PipeLyricModel{}
-			case "auth init":
-				nextScreen = NewAuthModel()
-			case "version":
-				nextScreen = NewVersionModel("", "", "")
-			default:
-				// Unknown command, stay on the menu
-				return m, cmd
-			}
-
-			// Start the transition to the new screen
-			return m, tea.Batch(cmd, func() tea.Msg {
-				return TransitionMsg{Screen: nextScreen}
-			})
+		m.waitingFor = ""
+		if _, ok := msg.screen.(*WaitingTrackModel); ok {
+			m.waitingFor = msg.choice
 		}
+		return m, m.show(msg.screen, msg.ctx, msg.cancel)
 
-		return m, cmd
+	case authorizationMsg:
+		auth, ok := m.screen.(*AuthModel)
+		if msg.id != m.screenID || !ok {
+			return m, nil
+		}
+		if msg.err != nil {
+			return m, m.showMenu(fmt.Sprintf("Error: %v", msg.err))
+		}
+		auth.authURL = msg.authorization.URL
+		auth.browserOpened = msg.authorization.BrowserOpened
+		auth.done = msg.authorization.Done
+		auth.status = "Waiting for authorization"
+		return m, hostCmd(m.screenID, auth.Init())
 	}
 
 	return m, nil
@@ -163,98 +186,214 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the model
 func (m *AppModel) View() string {
-	if m.transitioning && m.nextScreen != nil {
-		// Render a transition between the current and next screens
-		// This is a simple fade transition for now
-		return renderTransition(
-			m.currentScreen.View(),
-			m.nextScreen.View(),
-			m.progress,
-		)
+	if m.transition.IsTransitioning() {
+		return m.transition.RenderTransition()
 	}
 
 	// Render the current screen
-	return m.currentScreen.View()
+	return m.screen.View()
 }
 
-// tickTransition returns a command that will tick the transition animation
-func (m *AppModel) tickTransition() tea.Cmd {
-	return tea.Tick(time.Millisecond*50, func(t time.Time) tea.Msg {
-		return TransitionTickMsg(t)
-	})
+// busy reports whether the keys and the mouse are ignored, while a screen opens or slides in
+func (m *AppModel) busy() bool {
+	return m.opening != "" || m.transition.IsTransitioning()
 }
 
-// renderTransition renders a transition between two screens
-func renderTransition(currentView, nextView string, progress float64) string {
-	// For a simple fade effect, we'll use a crossfade approach
-	// As progress increases, we'll show more of the next view and less of the current view
+// updateScreen passes msg to the current screen
+func (m *AppModel) updateScreen(msg tea.Msg) (tea.Model, tea.Cmd) {
+	_, waiting := m.screen.(*WaitingTrackModel)
+	screen, cmd := m.screen.Update(msg)
+	m.screen = screen
 
-	// If we're at the beginning or end of the transition, just show the appropriate view
-	if progress <= 0 {
-		return currentView
+	// The waiting screen turns into the current track once a track plays, which opens the
+	// screen it waited for instead
+	if _, ok := screen.(*WaitingTrackModel); waiting && !ok && m.waitingFor != "current" {
+		return m, m.open(m.waitingFor)
 	}
-	if progress >= 1 {
-		return nextView
+	return m, hostCmd(m.screenID, cmd)
+}
+
+// screenDone handles the current screen quitting: the menu opens the item chosen or ends the
+// app, the authentication screen goes on with the credentials entered, and the others go back
+// to the menu
+func (m *AppModel) screenDone() tea.Cmd {
+	switch screen := m.screen.(type) {
+	case MenuModel:
+		if screen.choice == "" || screen.choice == "quit" {
+			return tea.Quit
+		}
+		choice := screen.choice
+		screen.choice = ""
+		screen.status = ""
+		m.menu, m.screen = screen, screen
+		return m.open(choice)
+
+	case *AuthModel:
+		if !screen.quitting && screen.step == 2 && screen.done == nil {
+			return m.authorize(screen.clientID, screen.clientSecret)
+		}
 	}
 
-	// For a simple crossfade, we'll use a slide-in effect
-	// We'll split both views into lines and blend them based on progress
-	currentLines := strings.Split(currentView, "\n")
-	nextLines := strings.Split(nextView, "\n")
+	return m.showMenu("")
+}
 
-	// Determine the maximum number of lines
-	maxLines := len(currentLines)
-	if len(nextLines) > maxLines {
-		maxLines = len(nextLines)
+// showMenu goes back to the menu, reporting status beneath it
+func (m *AppModel) showMenu(status string) tea.Cmd {
+	m.menu.status = status
+	if _, ok := m.screen.(MenuModel); ok {
+		// Still on the menu, e.g. when the screen chosen couldn't open
+		m.screen = m.menu
+		return nil
 	}
+	return m.show(m.menu, nil, nil)
+}
 
-	// Build the blended view
-	var result strings.Builder
+// open opens the screen of a menu item in the background, as some first ask Spotify what's playing
+func (m *AppModel) open(choice string) tea.Cmd {
+	m.opening = choice
+	ctx, cancel := context.WithCancel(m.ctx)
+	return func() tea.Msg {
+		screen, err := m.newScreen(ctx, choice)
+		return screenOpenedMsg{choice: choice, screen: screen, ctx: ctx, cancel: cancel, err: err}
+	}
+}
 
-	for i := 0; i < maxLines; i++ {
-		// Get the current and next lines, or empty strings if they don't exist
-		var currentLine, nextLine string
-		if i < len(currentLines) {
-			currentLine = currentLines[i]
-		}
-		if i < len(nextLines) {
-			nextLine = nextLines[i]
+// newScreen creates the screen of a menu item, running until ctx ends. The screens showing
+// the current track wait for one while nothing plays.
+func (m *AppModel) newScreen(ctx context.Context, choice string) (tea.Model, error) {
+	switch choice {
+	case "ui":
+		return NewPlayerModel(ctx, m.appConfig, m.useCases.Player), nil
+
+	case "current":
+		state, err := m.useCases.Player.GetPlaybackState(ctx)
+		if err != nil {
+			if errors.Is(err, usecase.ErrNothingPlaying) {
+				return NewWaitingTrackModel(ctx, m.appConfig, m.useCases.Auth), nil
+			}
+			return nil, fmt.Errorf("failed to get playback state: %w", err)
 		}
+		return NewCurrentTrackModelFromState(m.appConfig, state), nil
 
-		// Calculate the position for the slide effect
-		// As progress increases, the next line slides in from the right
-		slidePos := int(float64(len(nextLine)) * (1.0 - progress))
-
-		// If we're in the first half of the transition, show the current line fading out
-		if progress < 0.5 {
-			// Fade out the current line by replacing characters with spaces from right to left
-			fadePos := int(float64(len(currentLine)) * (progress * 2))
-			if fadePos < len(currentLine) {
-				// Replace characters from fadePos to the end with spaces
-				currentLine = currentLine[:fadePos] + strings.Repeat(" ", len(currentLine)-fadePos)
+	case "lyric show", "lyric pipe":
+		track, err := m.useCases.Player.GetCurrentlyPlayingDetails(ctx)
+		if err != nil {
+			if errors.Is(err, usecase.ErrNothingPlaying) {
+				return NewWaitingTrackModel(ctx, m.appConfig, m.useCases.Auth), nil
 			}
-			result.WriteString(currentLine)
-		} else {
-			// In the second half, show the next line sliding in
-			if slidePos > 0 {
-				// Pad the next line with spaces on the left
-				nextLine = strings.Repeat(" ", slidePos) + nextLine[:len(nextLine)-slidePos]
-			}
-			result.WriteString(nextLine)
+			return nil, fmt.Errorf("failed to get currently playing track: %w", err)
+		}
+		feed := func(ctx context.Context) <-chan *usecase.LyricUpdate {
+			return usecase.LyricChannel(ctx, track.ProgressMs, m.useCases.Player, m.useCases.Lyric)
 		}
+		if choice == "lyric show" {
+			return newLyricModel(ctx, m.appConfig, feed, m.useCases.Player)
+		}
+
+		// The app takes the terminal, so the lines aren't printed to stdout
+		sinks := m.appConfig.Sinks
+		sinks.Stdout, sinks.NDJSON = false, false
+		return NewPipeLyricModel(ctx, m.appConfig, feed, &sinks)
+
+	case "search":
+		return NewSearchModel(ctx, m.appConfig, m.useCases.Player, m.useCases.Search), nil
+
+	case "playlist":
+		return NewPlaylistModel(ctx, m.appConfig, m.useCases.Player, m.useCases.Playlist), nil
+
+	case "library":
+		return NewLibraryModel(ctx, m.appConfig, m.useCases.Player, m.useCases.Library), nil
+
+	case "auth init":
+		return NewAuthModel(m.appConfig), nil
+
+	case "version":
+		return NewVersionModel(m.appConfig, m.version, m.buildDate, m.commitHash), nil
+	}
+
+	return nil, fmt.Errorf("unknown menu item %q", choice)
+}
 
-		// Add a newline unless this is the last line
-		if i < maxLines-1 {
-			result.WriteString("\n")
+// show closes the current screen and slides in screen, whose requests end with ctx
+func (m *AppModel) show(screen tea.Model, ctx context.Context, cancel context.CancelFunc) tea.Cmd {
+	previous := m.screen.View()
+	m.closeScreen()
+
+	m.screenID++
+	m.screen, m.screenCtx, m.cancelScreen = screen, ctx, cancel
+	cmds := []tea.Cmd{m.screen.Init()}
+	if m.windowSize.Width > 0 {
+		var cmd tea.Cmd
+		m.screen, cmd = m.screen.Update(m.windowSize)
+		cmds = append(cmds, cmd)
+	}
+
+	return tea.Batch(hostCmd(m.screenID, tea.Batch(cmds...)), m.transition.StartTransition(previous, m.screen.View()))
+}
+
+// closeScreen cancels the requests of the current screen and restores what it changed
+func (m *AppModel) closeScreen() {
+	if m.cancelScreen != nil {
+		m.cancelScreen()
+		m.cancelScreen = nil
+	}
+
+	switch screen := m.screen.(type) {
+	case *LyricModel:
+		screen.windowTitle.clear()
+	case *PipeLyricModel:
+		screen.windowTitle.clear()
+		_ = screen.sink.Close()
+	}
+	m.screen = nil
+}
+
+// authorize starts the authorization with the credentials entered on the current screen
+func (m *AppModel) authorize(clientID, clientSecret string) tea.Cmd {
+	id, ctx := m.screenID, m.screenCtx
+	return func() tea.Msg {
+		authorization, err := m.useCases.Authorize(ctx, clientID, clientSecret)
+		return authorizationMsg{id: id, authorization: authorization, err: err}
+	}
+}
+
+// hostCmd runs cmd of the screen with the given id, tagging its messages so that the ones
+// arriving after the screen closed are dropped. The screen quitting ends up as a
+// screenDoneMsg instead of ending the app.
+func hostCmd(id int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.QuitMsg:
+			return screenDoneMsg{id: id}
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, cmd := range msg {
+				cmds[i] = hostCmd(id, cmd)
+			}
+			return cmds
+		default:
+			return screenMsg{id: id, msg: msg}
 		}
 	}
+}
 
-	return result.String()
+// close closes the current screen and cancels the requests still running
+func (m *AppModel) close() {
+	m.closeScreen()
+	m.cancel()
 }
 
-// RunApp runs the main application UI
-func RunApp(authUseCase usecase.AuthUseCase, playerUseCase usecase.PlayerUseCase, lyricUseCase usecase.LyricUseCase) error {
-	p := tea.NewProgram(NewAppModel(authUseCase, playerUseCase, lyricUseCase), tea.WithAltScreen())
-	_, err := p.Run()
+// RunApp runs the app until its menu quits, Ctrl+C is pressed or ctx ends
+func RunApp(ctx context.Context, appConfig *config.Config, useCases AppUseCases, version, buildDate, commitHash string) error {
+	model := NewAppModel(ctx, appConfig, useCases, version, buildDate, commitHash)
+	// Requests of the screens opened from the app are cancelled when it closes
+	defer model.close()
+
+	_, err := runProgram(ctx, model, tea.WithAltScreen(), withMouse(appConfig))
 	return err
 }
@@ -3,10 +3,12 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muhadif/sprt/config"
 )
 
 // AuthModel is the model for the authentication UI
@@ -20,38 +22,61 @@ type AuthModel struct {
 	input        string
 	quitting     bool
 	windowWidth  int
+	keys         keyMap
+	// done receives the result of the callback, advancing the UI without a key press
+	done          <-chan error
+	browserOpened bool
+	authErr       error
 }
 
+// authCallbackMsg carries the result of the authorization callback
+type authCallbackMsg struct {
+	err error
+}
+
+// authCompletedDelay is how long the completion message is shown before the UI exits
+const authCompletedDelay = 1500 * time.Millisecond
+
 // NewAuthModel creates a new authentication model
-func NewAuthModel() *AuthModel {
+func NewAuthModel(appConfig *config.Config) *AuthModel {
 	return &AuthModel{
 		step:        0,
 		status:      "Please enter your Spotify Client ID",
 		windowWidth: 80,
+		keys:        newKeyMap(appConfig.Keybindings),
 	}
 }
 
-func NewAuthModelWithStep(step int) *AuthModel {
+func NewAuthModelWithStep(appConfig *config.Config, step int) *AuthModel {
 	return &AuthModel{
 		step:   step,
 		status: "Waiting for authorization",
+		keys:   newKeyMap(appConfig.Keybindings),
 	}
 }
 
 // Init initializes the model
 func (m *AuthModel) Init() tea.Cmd {
+	if m.step == 2 && m.done != nil {
+		return m.waitForCallback
+	}
 	return nil
 }
 
+// waitForCallback waits for the callback server to finish the token exchange
+func (m *AuthModel) waitForCallback() tea.Msg {
+	return authCallbackMsg{err: <-m.done}
+}
+
 // Update updates the model
 func (m *AuthModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		switch key := msg.String(); {
+		case m.keys.matches(msg, keyQuit, keyBack):
 			m.quitting = true
 			return m, tea.Quit
-		case "enter":
+		case m.keys.matches(msg, keySelect):
 			if m.step == 0 {
 				m.clientID = m.input
 				m.input = ""
@@ -68,7 +93,7 @@ func (m *AuthModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.status = "Authentication completed"
 				return m, tea.Quit
 			}
-		case "ctrl+y", "cmd+y":
+		case key == "ctrl+y" || key == "cmd+y":
 			// Handle copy operation for the auth URL
 			if m.step == 2 && m.authURL != "" {
 				err := clipboard.WriteAll(m.authURL)
@@ -76,11 +101,11 @@ func (m *AuthModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.status = "URL copied to clipboard!"
 				}
 			}
-		case "backspace":
+		case key == "backspace":
 			if len(m.input) > 0 {
 				m.input = m.input[:len(m.input)-1]
 			}
-		case "ctrl+v", "cmd+v":
+		case key == "ctrl+v" || key == "cmd+v":
 			// Handle paste operation
 			text, err := clipboard.ReadAll()
 			if err == nil {
@@ -88,10 +113,20 @@ func (m *AuthModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		default:
 			// Only add printable characters
-			if len(msg.String()) == 1 {
-				m.input += msg.String()
+			if len(key) == 1 {
+				m.input += key
 			}
 		}
+	case authCallbackMsg:
+		if msg.err != nil {
+			m.authErr = msg.err
+			return m, nil
+		}
+		m.step = 3
+		m.status = "Authentication completed"
+		return m, tea.Tick(authCompletedDelay, func(time.Time) tea.Msg {
+			return tea.QuitMsg{}
+		})
 	case tea.WindowSizeMsg:
 		m.windowWidth = msg.Width
 	}
@@ -134,16 +169,28 @@ func (m *AuthModel) View() string {
 		content += infoStyle.Render("Press Enter to continue, Esc to cancel, Ctrl+V/Cmd+V to paste")
 	} else if m.step == 2 {
 		// Waiting for authorization
-		content += promptStyle.Render("Please open the following URL in your browser:") + "\n\n"
+		if m.browserOpened {
+			content += promptStyle.Render("The following URL was opened in your browser:") + "\n\n"
+		} else {
+			content += promptStyle.Render("Please open the following URL in your browser:") + "\n\n"
+		}
 		content += urlStyle.Render(m.authURL) + "\n\n"
 
+		if m.authErr != nil {
+			content += GetHeaderStyle().Foreground(lipgloss.Color("#FF0000")).Render("Authorization failed: "+m.authErr.Error()) + "\n\n"
+		}
+
 		// Show status message if URL was copied
 		if m.status == "URL copied to clipboard!" {
 			content += GetHeaderStyle().Foreground(lipgloss.Color("#00FF00")).Render(m.status) + "\n\n"
 		}
 
 		content += infoStyle.Render("After authorizing, you will be redirected to a local callback URL.") + "\n"
-		content += infoStyle.Render("Press Enter after you have completed the authorization process...") + "\n"
+		if m.done != nil {
+			content += infoStyle.Render("This screen continues on its own once you have authorized sprt.") + "\n"
+		} else {
+			content += infoStyle.Render("Press Enter after you have completed the authorization process...") + "\n"
+		}
 		content += infoStyle.Render("Press Ctrl+Y (or Cmd+Y on Mac) to copy the URL to clipboard")
 	} else if m.step == 3 {
 		// Completed
@@ -167,8 +214,8 @@ func (m *AuthModel) SetAuthURL(url string) {
 }
 
 // RunAuthUI runs the authentication UI for input
-func RunAuthUI() (string, string, error) {
-	p := tea.NewProgram(NewAuthModel(), tea.WithAltScreen())
+func RunAuthUI(appConfig *config.Config) (string, string, error) {
+	p := tea.NewProgram(NewAuthModel(appConfig), tea.WithAltScreen())
 	model, err := p.Run()
 	if err != nil {
 		return "", "", err
@@ -183,13 +230,16 @@ func RunAuthUI() (string, string, error) {
 	return clientID, clientSecret, nil
 }
 
-// RunAuthWaitingUI runs the authentication UI for waiting for authorization
-func RunAuthWaitingUI(authURL string, clientID string, clientSecret string) error {
-	model := NewAuthModelWithStep(2)
+// RunAuthWaitingUI runs the authentication UI for waiting for authorization.
+// When done is given, the UI completes as soon as it receives the callback result.
+func RunAuthWaitingUI(appConfig *config.Config, authURL string, clientID string, clientSecret string, browserOpened bool, done <-chan error) error {
+	model := NewAuthModelWithStep(appConfig, 2)
 	model.clientID = clientID
 	model.clientSecret = clientSecret
 	model.authURL = authURL
 	model.status = "Waiting for authorization"
+	model.browserOpened = browserOpened
+	model.done = done
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	_, err := p.Run()
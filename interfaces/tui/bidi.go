@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Bidi modes for right-to-left lyrics
+const (
+	// bidiTerminal leaves the visual ordering to terminals that implement the bidi algorithm
+	bidiTerminal = "terminal"
+	// bidiReorder reorders right-to-left text for terminals that print characters in logical order
+	bidiReorder = "reorder"
+)
+
+// bidiClass is the simplified bidi class of a character cluster
+type bidiClass int
+
+const (
+	bidiNeutral bidiClass = iota
+	bidiLTR
+	bidiRTL
+)
+
+// rtlScripts are the scripts written right to left
+var rtlScripts = []*unicode.RangeTable{unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko}
+
+// mirroredRunes maps brackets to their mirror image, used inside right-to-left runs
+var mirroredRunes = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+}
+
+// isRTL reports whether the rune belongs to a right-to-left script
+func isRTL(r rune) bool {
+	return unicode.In(r, rtlScripts...)
+}
+
+// classify returns the bidi class of a rune; digits count as left-to-right so numbers keep their order
+func classify(r rune) bidiClass {
+	switch {
+	case isRTL(r) && (unicode.IsLetter(r) || unicode.IsMark(r)):
+		return bidiRTL
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return bidiLTR
+	default:
+		return bidiNeutral
+	}
+}
+
+// isRTLText reports whether the text's base direction is right to left, decided by its
+// first strong character as in the Unicode bidi algorithm
+func isRTLText(text string) bool {
+	for _, r := range text {
+		switch classify(r) {
+		case bidiRTL:
+			return true
+		case bidiLTR:
+			if unicode.IsLetter(r) {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// hasRTL reports whether the text contains any right-to-left characters
+func hasRTL(text string) bool {
+	return strings.IndexFunc(text, isRTL) != -1
+}
+
+// visualOrder reorders a line from logical to visual order for terminals without bidi support.
+// It is a simplification of the Unicode bidi algorithm with one level of embedding:
+// right-to-left runs are reversed and, in right-to-left lines, so is the order of the runs,
+// while left-to-right words and numbers keep their reading order.
+func visualOrder(text string) string {
+	if !hasRTL(text) {
+		return text
+	}
+
+	// Split into clusters so combining marks stay after their base character
+	var clusters [][]rune
+	for _, r := range text {
+		if len(clusters) > 0 && unicode.Is(unicode.Mn, r) {
+			clusters[len(clusters)-1] = append(clusters[len(clusters)-1], r)
+			continue
+		}
+		clusters = append(clusters, []rune{r})
+	}
+
+	// Resolve neutrals from the surrounding strong characters, falling back to the base direction
+	base := bidiLTR
+	if isRTLText(text) {
+		base = bidiRTL
+	}
+	classes := make([]bidiClass, len(clusters))
+	for i, cluster := range clusters {
+		classes[i] = classify(cluster[0])
+	}
+	for i := 0; i < len(classes); {
+		if classes[i] != bidiNeutral {
+			i++
+			continue
+		}
+		end := i
+		for end < len(classes) && classes[end] == bidiNeutral {
+			end++
+		}
+		class := base
+		if i > 0 && end < len(classes) && classes[i-1] == classes[end] {
+			class = classes[end]
+		}
+		for j := i; j < end; j++ {
+			classes[j] = class
+		}
+		i = end
+	}
+
+	// Mirror brackets that end up inside right-to-left runs
+	for i, cluster := range clusters {
+		if mirrored, ok := mirroredRunes[cluster[0]]; ok && classes[i] == bidiRTL {
+			clusters[i] = []rune{mirrored}
+		}
+	}
+
+	// Reverse the whole line for a right-to-left base, then restore the left-to-right runs,
+	// or just reverse the right-to-left runs in a left-to-right line
+	if base == bidiRTL {
+		reverseClusters(clusters, classes, 0, len(clusters))
+		reverseRuns(clusters, classes, bidiLTR)
+	} else {
+		reverseRuns(clusters, classes, bidiRTL)
+	}
+
+	var sb strings.Builder
+	for _, cluster := range clusters {
+		sb.WriteString(string(cluster))
+	}
+	return sb.String()
+}
+
+// reverseRuns reverses every maximal run of clusters of the given class
+func reverseRuns(clusters [][]rune, classes []bidiClass, class bidiClass) {
+	for i := 0; i < len(clusters); {
+		if classes[i] != class {
+			i++
+			continue
+		}
+		end := i
+		for end < len(clusters) && classes[end] == class {
+			end++
+		}
+		reverseClusters(clusters, classes, i, end)
+		i = end
+	}
+}
+
+// reverseClusters reverses the clusters and their classes between start and end
+func reverseClusters(clusters [][]rune, classes []bidiClass, start, end int) {
+	for i, j := start, end-1; i < j; i, j = i+1, j-1 {
+		clusters[i], clusters[j] = clusters[j], clusters[i]
+		classes[i], classes[j] = classes[j], classes[i]
+	}
+}
+
+// displayBidi prepares a line for display in the given bidi mode
+func displayBidi(text, mode string) string {
+	if mode == bidiReorder {
+		return visualOrder(text)
+	}
+	return text
+}
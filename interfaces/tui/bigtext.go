@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Big text modes for the current lyric line
+const (
+	bigTextOff   = "off"
+	bigTextWide  = "wide"
+	bigTextBlock = "block"
+)
+
+// blockFont holds three-row box-drawing glyphs for the block big text mode
+var blockFont = map[rune][3]string{
+	'A':  {"╔═╗", "╠═╣", "╩ ╩"},
+	'B':  {"╔╗ ", "╠╩╗", "╚═╝"},
+	'C':  {"╔═╗", "║  ", "╚═╝"},
+	'D':  {"╔╦╗", " ║║", "═╩╝"},
+	'E':  {"╔═╗", "║╣ ", "╚═╝"},
+	'F':  {"╔═╗", "╠╣ ", "╚  "},
+	'G':  {"╔═╗", "║ ╦", "╚═╝"},
+	'H':  {"╦ ╦", "╠═╣", "╩ ╩"},
+	'I':  {"╦", "║", "╩"},
+	'J':  {" ╦", " ║", "╚╝"},
+	'K':  {"╦╔═", "╠╩╗", "╩ ╩"},
+	'L':  {"╦  ", "║  ", "╩═╝"},
+	'M':  {"╔╦╗", "║║║", "╩ ╩"},
+	'N':  {"╔╗╔", "║║║", "╝╚╝"},
+	'O':  {"╔═╗", "║ ║", "╚═╝"},
+	'P':  {"╔═╗", "╠═╝", "╩  "},
+	'Q':  {"╔═╗", "║═╬", "╚═╚"},
+	'R':  {"╦═╗", "╠╦╝", "╩╚═"},
+	'S':  {"╔═╗", "╚═╗", "╚═╝"},
+	'T':  {"╔╦╗", " ║ ", " ╩ "},
+	'U':  {"╦ ╦", "║ ║", "╚═╝"},
+	'V':  {"╦  ╦", "╚╗╔╝", " ╚╝ "},
+	'W':  {"╦ ╦", "║║║", "╚╩╝"},
+	'X':  {"═╗ ╦", "╔╩╦╝", "╩ ╚═"},
+	'Y':  {"╦ ╦", "╚╦╝", " ╩ "},
+	'Z':  {"╔═╗", "╔═╝", "╚═╝"},
+	'0':  {"╔═╗", "║/║", "╚═╝"},
+	'1':  {"╗", "║", "╩"},
+	'2':  {"╔═╗", " ╔╝", "╚═╝"},
+	'3':  {"╔═╗", " ═╣", "╚═╝"},
+	'4':  {"╦ ╦", "╚═╣", "  ╩"},
+	'5':  {"╔═ ", "╚═╗", "╚═╝"},
+	'6':  {"╔═ ", "╠═╗", "╚═╝"},
+	'7':  {"╔═╗", "  ║", "  ╩"},
+	'8':  {"╔═╗", "╠═╣", "╚═╝"},
+	'9':  {"╔═╗", "╚═╣", "╚═╝"},
+	' ':  {" ", " ", " "},
+	'.':  {" ", " ", "•"},
+	',':  {" ", " ", "╯"},
+	'!':  {"║", "║", "•"},
+	'?':  {"╔═╗", " ╔╝", " • "},
+	'\'': {"╗", " ", " "},
+	'"':  {"╗╗", "  ", "  "},
+	'-':  {"   ", "═══", "   "},
+	':':  {" ", "•", "•"},
+	'(':  {"╔", "║", "╚"},
+	')':  {"╗", "║", "╝"},
+}
+
+// renderBigText renders text in the given big text mode, wrapping block text to fit width.
+// Block text falls back to wide text if it contains characters the block font lacks.
+func renderBigText(text, mode string, width int) string {
+	switch mode {
+	case bigTextBlock:
+		if block, ok := renderBlockText(strings.TrimSpace(text), width); ok {
+			return block
+		}
+		return toFullWidth(text)
+	case bigTextWide:
+		return toFullWidth(text)
+	default:
+		return text
+	}
+}
+
+// renderBlockText renders text with the block font, one group of three rows per wrapped line
+func renderBlockText(text string, width int) (string, bool) {
+	var rows []string
+	var current [3]string
+	currentWidth := 0
+
+	flush := func() {
+		if currentWidth > 0 {
+			rows = append(rows, current[0], current[1], current[2])
+		}
+		current = [3]string{}
+		currentWidth = 0
+	}
+
+	for _, word := range strings.Fields(text) {
+		var glyphs [3]string
+		glyphsWidth := 0
+		for _, r := range word {
+			glyph, ok := blockFont[unicode.ToUpper(r)]
+			if !ok {
+				return "", false
+			}
+			for i := range glyphs {
+				glyphs[i] += glyph[i] + " "
+			}
+			glyphsWidth += runewidth.StringWidth(glyph[0]) + 1
+		}
+
+		// Start a new line if the word doesn't fit
+		if currentWidth > 0 && currentWidth+glyphsWidth+1 > width {
+			flush()
+		}
+		for i := range current {
+			if currentWidth > 0 {
+				current[i] += "  "
+			}
+			current[i] += glyphs[i]
+		}
+		if currentWidth > 0 {
+			currentWidth += 2
+		}
+		currentWidth += glyphsWidth
+	}
+	flush()
+
+	for i := range rows {
+		rows[i] = strings.TrimRight(rows[i], " ")
+	}
+	return strings.Join(rows, "\n"), true
+}
+
+// toFullWidth converts ASCII characters to their double-width fullwidth forms
+func toFullWidth(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case r == ' ':
+			sb.WriteRune('　')
+		case r >= '!' && r <= '~':
+			sb.WriteRune(r - '!' + '！')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
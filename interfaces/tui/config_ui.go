@@ -0,0 +1,550 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+)
+
+// configPreviewLines are the lyrics shown in the preview of the config editor
+var configPreviewLines = []string{
+	"Streetlights flicker into view",
+	"The city hums a quiet tune",
+	"We sing along to every line",
+	"And lose ourselves in borrowed time",
+	"Until the morning finds us here",
+}
+
+// configPreviewInterval is how long each preview line stays the current one
+const configPreviewInterval = 2 * time.Second
+
+// hexColor matches the colors the editor accepts, which the fade animation can blend
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// configField is a setting of the UI config shown in the editor
+type configField struct {
+	section string
+	label   string
+	// value formats the setting
+	value func(c *config.UIConfig) string
+	// change steps the setting by delta, -1 or 1: numbers are adjusted, choices cycled and
+	// switches flipped; nil for settings that are typed in
+	change func(c *config.UIConfig, delta int)
+	// set applies the text typed in for the setting; nil for settings that are only changed
+	set func(c *config.UIConfig, text string) error
+}
+
+// configSavedMsg carries the result of writing the UI config
+type configSavedMsg struct {
+	err error
+}
+
+// configPreviewTickMsg is a message sent when the preview should move to its next line
+type configPreviewTickMsg struct{}
+
+// configAnimationTickMsg is a message sent when the preview animation should advance a step
+type configAnimationTickMsg struct{}
+
+// ConfigModel is the model for editing the UI config with a live preview of the lyric display
+type ConfigModel struct {
+	uiConfig *config.UIConfig
+	fields   []configField
+	cursor   int
+	// editing is set while the setting under the cursor is typed in as input
+	editing bool
+	input   string
+	dirty   bool
+	// confirmQuit is set after quitting with unsaved changes, until a second quit discards them
+	confirmQuit bool
+	status      string
+	keys        keyMap
+	help        bool
+	windowWidth int
+	quitting    bool
+
+	// Preview state
+	previewLine      int
+	animating        bool
+	animationStep    int
+	animationTicking bool
+}
+
+// NewConfigModel creates a config editor for uiConfig, which it changes in place
+func NewConfigModel(appConfig *config.Config, uiConfig *config.UIConfig) *ConfigModel {
+	return &ConfigModel{
+		uiConfig:    uiConfig,
+		fields:      configFields(),
+		keys:        newKeyMap(appConfig.Keybindings),
+		windowWidth: 80,
+	}
+}
+
+// configFields lists the settings of the editor
+func configFields() []configField {
+	fields := []configField{
+		{
+			section: "Theme",
+			label:   "Theme",
+			value:   func(c *config.UIConfig) string { return themeName(c) },
+			change: func(c *config.UIConfig, delta int) {
+				names := themeNames(c)
+				if len(names) == 0 {
+					return
+				}
+				i := slices.Index(names, themeName(c))
+				if i < 0 && delta < 0 {
+					i = 0
+				}
+				_ = c.ApplyTheme(names[(i+delta+len(names))%len(names)])
+			},
+		},
+		{
+			section: "Theme",
+			label:   "Save styles as theme",
+			value:   func(c *config.UIConfig) string { return "enter a name" },
+			set: func(c *config.UIConfig, text string) error {
+				name := strings.TrimSpace(text)
+				if name == "" {
+					return fmt.Errorf("the theme needs a name")
+				}
+				if c.Themes == nil {
+					c.Themes = make(map[string]config.ThemeConfig)
+				}
+				c.Themes[name] = config.ThemeConfig{
+					CurrentLineStyle: c.Lyric.CurrentLineStyle,
+					OtherLineStyle:   c.Lyric.OtherLineStyle,
+				}
+				return nil
+			},
+		},
+	}
+	fields = append(fields, styleFields("Current line", func(c *config.UIConfig) *config.StyleConfig { return &c.Lyric.CurrentLineStyle })...)
+	fields = append(fields, styleFields("Other lines", func(c *config.UIConfig) *config.StyleConfig { return &c.Lyric.OtherLineStyle })...)
+	fields = append(fields,
+		switchField("Animation", "Enabled", func(c *config.UIConfig) *bool { return &c.Lyric.Animation.Enabled }),
+		choiceField("Animation", "Type", []string{"fade", "slide", "none"}, func(c *config.UIConfig) *string { return &c.Lyric.Animation.Type }),
+		numberField("Animation", "Duration (ms)", 50, 0, 5000, func(c *config.UIConfig) *int { return &c.Lyric.Animation.DurationMs }),
+		numberField("Animation", "Fade steps", 1, 1, 30, func(c *config.UIConfig) *int { return &c.Lyric.Animation.FadeSteps }),
+		numberField("Animation", "Slide distance", 1, 0, 20, func(c *config.UIConfig) *int { return &c.Lyric.Animation.SlideDistance }),
+		choiceField("Layout", "Layout", []string{layoutFull, layoutFocused}, func(c *config.UIConfig) *string { return &c.Lyric.Layout }),
+		choiceField("Layout", "Big text", []string{bigTextOff, bigTextWide, bigTextBlock}, func(c *config.UIConfig) *string { return &c.Lyric.BigText }),
+		numberField("Layout", "Line spacing", 1, 0, 5, func(c *config.UIConfig) *int { return &c.Lyric.LineSpacing }),
+	)
+	return fields
+}
+
+// styleFields lists the settings of the line style that style points to
+func styleFields(section string, style func(c *config.UIConfig) *config.StyleConfig) []configField {
+	return []configField{
+		colorField(section, "Foreground", func(c *config.UIConfig) *string { return &style(c).ForegroundColor }),
+		colorField(section, "Background", func(c *config.UIConfig) *string { return &style(c).BackgroundColor }),
+		switchField(section, "Bold", func(c *config.UIConfig) *bool { return &style(c).Bold }),
+		switchField(section, "Italic", func(c *config.UIConfig) *bool { return &style(c).Italic }),
+		switchField(section, "Underline", func(c *config.UIConfig) *bool { return &style(c).Underline }),
+	}
+}
+
+// colorField is a color typed in as #RRGGBB, or left empty for the terminal's color
+func colorField(section, label string, setting func(c *config.UIConfig) *string) configField {
+	return configField{
+		section: section,
+		label:   label,
+		value: func(c *config.UIConfig) string {
+			if *setting(c) == "" {
+				return "default"
+			}
+			return *setting(c)
+		},
+		set: func(c *config.UIConfig, text string) error {
+			text = strings.TrimSpace(text)
+			if text != "" && !hexColor.MatchString(text) {
+				return fmt.Errorf("colors are written as #RRGGBB, e.g. #00FF00")
+			}
+			*setting(c) = strings.ToUpper(text)
+			return nil
+		},
+	}
+}
+
+// switchField is a setting turned on and off
+func switchField(section, label string, setting func(c *config.UIConfig) *bool) configField {
+	return configField{
+		section: section,
+		label:   label,
+		value:   func(c *config.UIConfig) string { return onOff(*setting(c)) },
+		change:  func(c *config.UIConfig, _ int) { *setting(c) = !*setting(c) },
+	}
+}
+
+// choiceField is a setting cycled through choices
+func choiceField(section, label string, choices []string, setting func(c *config.UIConfig) *string) configField {
+	return configField{
+		section: section,
+		label:   label,
+		value:   func(c *config.UIConfig) string { return *setting(c) },
+		change: func(c *config.UIConfig, delta int) {
+			// Unknown values start over from the first choice
+			i := max(0, slices.Index(choices, *setting(c)))
+			*setting(c) = choices[(i+delta+len(choices))%len(choices)]
+		},
+	}
+}
+
+// numberField is a number stepped by step between lowest and highest, which can also be typed in
+func numberField(section, label string, step, lowest, highest int, setting func(c *config.UIConfig) *int) configField {
+	return configField{
+		section: section,
+		label:   label,
+		value:   func(c *config.UIConfig) string { return strconv.Itoa(*setting(c)) },
+		change: func(c *config.UIConfig, delta int) {
+			*setting(c) = max(lowest, min(highest, *setting(c)+delta*step))
+		},
+		set: func(c *config.UIConfig, text string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil || n < lowest || n > highest {
+				return fmt.Errorf("%s is a number from %d to %d", strings.ToLower(label), lowest, highest)
+			}
+			*setting(c) = n
+			return nil
+		},
+	}
+}
+
+// themeNames returns the names of the themes in c, sorted
+func themeNames(c *config.UIConfig) []string {
+	names := make([]string, 0, len(c.Themes))
+	for name := range c.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// themeName returns the name of the theme whose styles the lyrics have, or "custom" for none
+func themeName(c *config.UIConfig) string {
+	for _, name := range themeNames(c) {
+		theme := c.Themes[name]
+		if theme.CurrentLineStyle == c.Lyric.CurrentLineStyle && theme.OtherLineStyle == c.Lyric.OtherLineStyle {
+			return name
+		}
+	}
+	return "custom"
+}
+
+// Init initializes the model
+func (m *ConfigModel) Init() tea.Cmd {
+	return m.previewTick()
+}
+
+// Update updates the model
+func (m *ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.editing && msg.Type != tea.KeyCtrlC {
+			m.handleInput(msg)
+			return m, nil
+		}
+
+		field := m.fields[m.cursor]
+		confirmQuit := m.confirmQuit
+		m.confirmQuit = false
+		switch {
+		case m.keys.matches(msg, keyHelp):
+			m.help = !m.help
+		case m.help && m.keys.matches(msg, keyBack):
+			m.help = false
+		case m.keys.matches(msg, keyQuit, keyBack):
+			if m.dirty && !confirmQuit && msg.String() != "ctrl+c" {
+				m.confirmQuit = true
+				m.status = fmt.Sprintf("Unsaved changes: press %s again to discard them or %s to save",
+					m.keys.hint(keyQuit), m.keys.hint(keySave))
+				return m, nil
+			}
+			m.quitting = true
+			return m, tea.Quit
+		case m.keys.matches(msg, keySave):
+			return m, m.save
+		case m.keys.matches(msg, keyUp):
+			m.cursor = max(0, m.cursor-1)
+		case m.keys.matches(msg, keyDown):
+			m.cursor = min(len(m.fields)-1, m.cursor+1)
+		case m.keys.matches(msg, keyDecrease):
+			return m, m.changeField(field, -1)
+		case m.keys.matches(msg, keyIncrease):
+			return m, m.changeField(field, 1)
+		case m.keys.matches(msg, keySelect):
+			return m, m.selectField(field)
+		}
+
+	case tea.MouseMsg:
+		if m.editing || msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.cursor = max(0, m.cursor-1)
+		case tea.MouseButtonWheelDown:
+			m.cursor = min(len(m.fields)-1, m.cursor+1)
+		case tea.MouseButtonLeft:
+			// Select the setting clicked, and change it when it was already selected
+			i := m.fieldAt(msg.Y)
+			if i < 0 {
+				return m, nil
+			}
+			if i == m.cursor {
+				return m, m.selectField(m.fields[i])
+			}
+			m.cursor = i
+		}
+
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+
+	case configSavedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.dirty = false
+		m.status = "Saved to ~/.sprt/ui_config.json"
+
+	case configPreviewTickMsg:
+		// Move the preview to the next line, animated like the lyric display
+		m.previewLine = (m.previewLine + 1) % len(configPreviewLines)
+		cmds := []tea.Cmd{m.previewTick()}
+		if animation := m.uiConfig.Lyric.Animation; animation.Enabled && animation.Type != "none" {
+			m.animating = true
+			m.animationStep = 0
+			cmds = append(cmds, m.animationTick())
+		}
+		return m, tea.Batch(cmds...)
+
+	case configAnimationTickMsg:
+		m.animationTicking = false
+		if m.animating {
+			m.animationStep++
+			if m.animationStep >= m.animationSteps() {
+				m.animating = false
+				return m, nil
+			}
+			return m, m.animationTick()
+		}
+	}
+
+	return m, nil
+}
+
+// handleInput handles key presses while a setting is typed in
+func (m *ConfigModel) handleInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.editing = false
+		field := m.fields[m.cursor]
+		if err := field.set(m.uiConfig, m.input); err != nil {
+			m.status = fmt.Sprintf("Error: %v", err)
+			return
+		}
+		m.dirty = true
+		m.status = fmt.Sprintf("%s: %s", field.label, field.value(m.uiConfig))
+	case tea.KeyEsc:
+		m.editing = false
+	case tea.KeyBackspace:
+		if runes := []rune(m.input); len(runes) > 0 {
+			m.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.input += " "
+	case tea.KeyRunes:
+		m.input += string(msg.Runes)
+	}
+}
+
+// selectField starts typing in the setting of field, or steps it when it can't be typed in
+func (m *ConfigModel) selectField(field configField) tea.Cmd {
+	if field.set != nil {
+		m.editing = true
+		m.input = ""
+		m.status = ""
+		return nil
+	}
+	return m.changeField(field, 1)
+}
+
+// fieldAt returns the index of the setting drawn at the given screen row, or -1 for none. The
+// settings follow the title and a blank line, each section with a header and the ones after the
+// first with a blank line before it.
+func (m *ConfigModel) fieldAt(y int) int {
+	row := 2
+	section := ""
+	for i, field := range m.fields {
+		if field.section != section {
+			if section != "" {
+				row++
+			}
+			section = field.section
+			row++
+		}
+		if y == row {
+			return i
+		}
+		row++
+	}
+	return -1
+}
+
+// changeField steps the setting of field by delta and restarts the preview animation, so changes
+// to it show right away
+func (m *ConfigModel) changeField(field configField, delta int) tea.Cmd {
+	if field.change == nil {
+		return nil
+	}
+	field.change(m.uiConfig, delta)
+	m.dirty = true
+	m.status = ""
+
+	if field.section == "Animation" && m.uiConfig.Lyric.Animation.Enabled {
+		m.animating = true
+		m.animationStep = 0
+		return m.animationTick()
+	}
+	m.animating = false
+	return nil
+}
+
+// save writes the UI config to ui_config.json
+func (m *ConfigModel) save() tea.Msg {
+	return configSavedMsg{err: config.SaveUIConfig(m.uiConfig)}
+}
+
+// previewTick schedules the move of the preview to its next line
+func (m *ConfigModel) previewTick() tea.Cmd {
+	return tea.Tick(configPreviewInterval, func(time.Time) tea.Msg {
+		return configPreviewTickMsg{}
+	})
+}
+
+// animationTick schedules the next animation step unless one is already scheduled
+func (m *ConfigModel) animationTick() tea.Cmd {
+	if m.animationTicking {
+		return nil
+	}
+	m.animationTicking = true
+
+	duration := time.Duration(max(0, m.uiConfig.Lyric.Animation.DurationMs)) * time.Millisecond
+	return tea.Tick(duration/time.Duration(m.animationSteps()), func(time.Time) tea.Msg {
+		return configAnimationTickMsg{}
+	})
+}
+
+// animationSteps returns the number of steps of the preview animation, like the lyric display
+// takes for both fades and slides
+func (m *ConfigModel) animationSteps() int {
+	return max(1, m.uiConfig.Lyric.Animation.FadeSteps)
+}
+
+// View renders the model
+func (m *ConfigModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	titleStyle := GetTitleStyle(m.windowWidth)
+	headerStyle := GetHeaderStyle()
+	selectedStyle := GetSelectedStyle()
+	normalStyle := GetNormalStyle()
+	infoStyle := GetInfoStyle()
+
+	s := titleStyle.Render("sprt UI Config") + "\n\n"
+
+	section := ""
+	for i, field := range m.fields {
+		if field.section != section {
+			if section != "" {
+				s += "\n"
+			}
+			section = field.section
+			s += headerStyle.Render(section) + "\n"
+		}
+
+		value := field.value(m.uiConfig)
+		if i == m.cursor && m.editing {
+			value = GetInputStyle().Render(m.input + "█")
+		}
+		line := fmt.Sprintf("%-22s %s", field.label, value)
+		if i == m.cursor {
+			s += "> " + selectedStyle.Render(line) + "\n"
+		} else {
+			s += "  " + normalStyle.Render(line) + "\n"
+		}
+	}
+
+	s += "\n" + GetBorderStyle(m.windowWidth).Render(m.preview(max(10, m.windowWidth-8))) + "\n"
+
+	if m.status != "" {
+		s += infoStyle.Render(m.status) + "\n"
+	}
+	switch {
+	case m.help:
+		s += m.keys.helpView(m.windowWidth, keyUp, keyDown, keyDecrease, keyIncrease, keySelect, keySave, keyHelp, keyQuit, keyBack)
+	case m.editing:
+		s += infoStyle.Render("enter apply • esc cancel")
+	default:
+		s += infoStyle.Render(m.keys.footer(keyUp, keyDown, keyDecrease, keyIncrease, keySelect, keySave, keyQuit))
+	}
+
+	return s
+}
+
+// preview renders the preview lines in the styles, layout and animation being edited
+func (m *ConfigModel) preview(width int) string {
+	lyric := m.uiConfig.Lyric
+	current, other := lyricLineStyles(lyric, width)
+	previous := (m.previewLine - 1 + len(configPreviewLines)) % len(configPreviewLines)
+	progress := float64(m.animationStep) / float64(m.animationSteps())
+
+	// The focused layout only shows the lines around the current one
+	first, last := 0, len(configPreviewLines)-1
+	if lyric.Layout == layoutFocused {
+		first, last = m.previewLine-1, m.previewLine+1
+	}
+
+	lines := make([]string, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		index := (i + len(configPreviewLines)) % len(configPreviewLines)
+		line := configPreviewLines[index]
+		switch {
+		case index == m.previewLine && m.animating && lyric.Animation.Type == "fade":
+			lines = append(lines, fadeStyle(lyric, width, progress, true).Render(line))
+		case index == m.previewLine && m.animating && lyric.Animation.Type == "slide":
+			padding := int(float64(lyric.Animation.SlideDistance) * (1.0 - progress))
+			lines = append(lines, current.Render(slidePad(line, padding, false)))
+		case index == m.previewLine:
+			lines = append(lines, current.Render(renderBigText(line, lyric.BigText, width)))
+		case index == previous && m.animating && lyric.Animation.Type == "fade":
+			lines = append(lines, fadeStyle(lyric, width, progress, false).Render(line))
+		default:
+			lines = append(lines, other.Render(line))
+		}
+	}
+
+	return strings.Join(lines, strings.Repeat("\n", max(0, lyric.LineSpacing)+1))
+}
+
+// RunConfigUI runs the editor of the UI config until it quits or ctx ends
+func RunConfigUI(ctx context.Context, appConfig *config.Config) error {
+	uiConfig, err := config.LoadUIConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load UI config: %w", err)
+	}
+
+	_, err = runProgram(ctx, NewConfigModel(appConfig, uiConfig), tea.WithAltScreen(), withMouse(appConfig))
+	return err
+}
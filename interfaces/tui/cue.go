@@ -0,0 +1,39 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/sound"
+)
+
+// Cue modes
+const (
+	cueOff     = "off"
+	cueLine    = "line"
+	cueSection = "section"
+)
+
+// lineCue returns a command that plays the configured sound cue for the line at index,
+// or nil when no cue is due. gapMs is the shortest gap that separates two sections.
+func lineCue(cfg config.CueConfig, lyrics *usecase.Lyrics, index, gapMs int) tea.Cmd {
+	if lyrics == nil || index < 0 || index >= len(lyrics.Lines) || lyrics.Lines[index].Text == "" {
+		return nil
+	}
+
+	switch cfg.Mode {
+	case cueLine:
+	case cueSection:
+		if !usecase.IsSectionStart(lyrics, index, gapMs) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return func() tea.Msg {
+		// A missing audio player shouldn't interrupt the lyrics
+		_ = sound.Play(cfg.Sound, cfg.File)
+		return nil
+	}
+}
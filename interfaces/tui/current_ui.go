@@ -5,23 +5,32 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
 )
 
 // CurrentTrackModel is the model for the current track UI
 type CurrentTrackModel struct {
-	artist      string
-	title       string
-	album       string
-	duration    string
-	progress    string
-	isPlaying   bool
-	albumArt    string
+	artist    string
+	title     string
+	album     string
+	duration  string
+	progress  string
+	isPlaying bool
+	albumArt  string
+	device    string
+	owner     string
+	session   string
+	// episode is set for podcast episodes, which have a show instead of an artist and album
+	episode     bool
+	resumePoint string
 	quitting    bool
 	windowWidth int
+	keys        keyMap
 }
 
 // NewCurrentTrackModel creates a new current track model
-func NewCurrentTrackModel(artist, title, album, duration, progress string, isPlaying bool) *CurrentTrackModel {
+func NewCurrentTrackModel(appConfig *config.Config, artist, title, album, duration, progress string, isPlaying bool) *CurrentTrackModel {
 	return &CurrentTrackModel{
 		artist:      artist,
 		title:       title,
@@ -30,9 +39,31 @@ func NewCurrentTrackModel(artist, title, album, duration, progress string, isPla
 		progress:    progress,
 		isPlaying:   isPlaying,
 		windowWidth: 80,
+		keys:        newKeyMap(appConfig.Keybindings),
 	}
 }
 
+// NewCurrentTrackModelFromState creates a new current track model from the playback state
+func NewCurrentTrackModelFromState(appConfig *config.Config, state *usecase.PlaybackState) *CurrentTrackModel {
+	m := NewCurrentTrackModel(appConfig, state.Artist, state.Title, state.Album,
+		formatMs(state.DurationMs), formatMs(state.ProgressMs), state.IsPlaying)
+	m.SetDevice(state.Device.Name, state.Owner, state.SessionDescription())
+	if state.IsEpisode() {
+		m.episode = true
+		if state.ResumePointMs > 0 {
+			m.resumePoint = formatMs(state.ResumePointMs)
+		}
+	}
+	return m
+}
+
+// SetDevice sets the active device, the account controlling it and the session description
+func (m *CurrentTrackModel) SetDevice(device, owner, session string) {
+	m.device = device
+	m.owner = owner
+	m.session = session
+}
+
 // Init initializes the model
 func (m CurrentTrackModel) Init() tea.Cmd {
 	return nil
@@ -42,8 +73,7 @@ func (m CurrentTrackModel) Init() tea.Cmd {
 func (m CurrentTrackModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		if m.keys.matches(msg, keyQuit, keyBack) {
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -72,9 +102,16 @@ func (m CurrentTrackModel) View() string {
 	// Track info
 	trackInfo := ""
 	trackInfo += headerStyle.Render("Title: ") + valueStyle.Render(m.title) + "\n"
-	trackInfo += headerStyle.Render("Artist: ") + valueStyle.Render(m.artist) + "\n"
-	trackInfo += headerStyle.Render("Album: ") + valueStyle.Render(m.album) + "\n"
+	if m.episode {
+		trackInfo += headerStyle.Render("Show: ") + valueStyle.Render(m.artist) + "\n"
+	} else {
+		trackInfo += headerStyle.Render("Artist: ") + valueStyle.Render(m.artist) + "\n"
+		trackInfo += headerStyle.Render("Album: ") + valueStyle.Render(m.album) + "\n"
+	}
 	trackInfo += headerStyle.Render("Duration: ") + valueStyle.Render(m.duration) + "\n"
+	if m.resumePoint != "" {
+		trackInfo += headerStyle.Render("Resume point: ") + valueStyle.Render(m.resumePoint) + "\n"
+	}
 
 	// Status
 	status := "Paused"
@@ -83,6 +120,17 @@ func (m CurrentTrackModel) View() string {
 	}
 	trackInfo += headerStyle.Render("Status: ") + valueStyle.Render(status) + "\n"
 
+	// Device and session
+	if m.device != "" {
+		trackInfo += headerStyle.Render("Device: ") + valueStyle.Render(m.device) + "\n"
+	}
+	if m.owner != "" {
+		trackInfo += headerStyle.Render("DJ: ") + valueStyle.Render(m.owner) + "\n"
+	}
+	if m.session != "" {
+		trackInfo += headerStyle.Render("Session: ") + valueStyle.Render(m.session) + "\n"
+	}
+
 	// Progress bar
 	if m.progress != "" && m.duration != "" {
 		progressPercent := 0.0
@@ -118,14 +166,27 @@ func (m CurrentTrackModel) View() string {
 	}
 
 	s += border.Render(trackInfo)
-	s += "\n\n" + valueStyle.Render("Press q to return to menu")
+	s += "\n\n" + valueStyle.Render("Press "+m.keys.hint(keyQuit)+" to return to menu")
 
 	return s
 }
 
+// formatMs formats a duration in milliseconds as m:ss
+func formatMs(ms int) string {
+	totalSeconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", totalSeconds/60, totalSeconds%60)
+}
+
 // RunCurrentTrackUI runs the current track UI
-func RunCurrentTrackUI(artist, title, album, duration, progress string, isPlaying bool) error {
-	p := tea.NewProgram(NewCurrentTrackModel(artist, title, album, duration, progress, isPlaying), tea.WithAltScreen())
+func RunCurrentTrackUI(appConfig *config.Config, artist, title, album, duration, progress string, isPlaying bool) error {
+	p := tea.NewProgram(NewCurrentTrackModel(appConfig, artist, title, album, duration, progress, isPlaying), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// RunCurrentPlaybackUI runs the current track UI for the given playback state
+func RunCurrentPlaybackUI(appConfig *config.Config, state *usecase.PlaybackState) error {
+	p := tea.NewProgram(NewCurrentTrackModelFromState(appConfig, state), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// dashboardQueueRows is the number of queued tracks shown
+const dashboardQueueRows = 5
+
+// DashboardModel is the model for the dashboard, which shows the playback state, the lyrics,
+// the queue and the devices on one screen
+type DashboardModel struct {
+	dashboardUseCase usecase.DashboardUseCase
+	updateCh         <-chan usecase.DashboardUpdate
+	playback         *usecase.PlaybackState
+	polledAt         time.Time
+	queue            []usecase.Track
+	devices          []usecase.Device
+	lyrics           *usecase.Lyrics
+	// loaded marks the panels whose data arrived, errs holds the ones that failed
+	loaded      map[string]bool
+	errs        map[string]error
+	transforms  transformChain
+	keys        keyMap
+	help        bool
+	windowWidth int
+	quitting    bool
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// dashboardUpdateMsg carries a panel loaded from the updates channel ch
+type dashboardUpdateMsg struct {
+	update usecase.DashboardUpdate
+	ch     <-chan usecase.DashboardUpdate
+}
+
+// dashboardTickMsg is a message sent when the progress and the lyric line should advance
+type dashboardTickMsg struct{}
+
+// NewDashboardModel creates a new dashboard model
+func NewDashboardModel(ctx context.Context, appConfig *config.Config, dashboardUseCase usecase.DashboardUseCase) (*DashboardModel, error) {
+	transforms, err := newTransformChain(lyricTransforms(appConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up lyric transforms: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &DashboardModel{
+		dashboardUseCase: dashboardUseCase,
+		loaded:           make(map[string]bool),
+		errs:             make(map[string]error),
+		transforms:       transforms,
+		windowWidth:      80,
+		keys:             newKeyMap(appConfig.Keybindings),
+		ctx:              ctx,
+		cancel:           cancel,
+	}, nil
+}
+
+// Init starts loading the panels
+func (m *DashboardModel) Init() tea.Cmd {
+	return tea.Batch(m.load(), m.tick())
+}
+
+// Update updates the model
+func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case m.keys.matches(msg, keyHelp):
+			m.help = !m.help
+		case m.help && m.keys.matches(msg, keyBack):
+			m.help = false
+		case m.keys.matches(msg, keyQuit, keyBack):
+			m.quitting = true
+			m.cancel()
+			return m, tea.Quit
+		case m.keys.matches(msg, keyReload):
+			// The panels keep their data until the new one arrives
+			return m, m.load()
+		}
+
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+
+	case dashboardTickMsg:
+		return m, m.tick()
+
+	case dashboardUpdateMsg:
+		// Panels of an earlier load are superseded by the reload
+		if msg.ch != m.updateCh {
+			return m, nil
+		}
+		m.apply(msg.update)
+		return m, waitForDashboardUpdate(msg.ch)
+	}
+
+	return m, nil
+}
+
+// apply shows the data of a loaded panel
+func (m *DashboardModel) apply(update usecase.DashboardUpdate) {
+	m.loaded[update.Panel] = true
+	m.errs[update.Panel] = update.Err
+	if update.Err != nil {
+		return
+	}
+
+	switch update.Panel {
+	case usecase.PanelPlayback:
+		m.playback = update.Playback
+		m.polledAt = time.Now()
+	case usecase.PanelQueue:
+		m.queue = update.Queue
+	case usecase.PanelDevices:
+		m.devices = update.Devices
+	case usecase.PanelLyrics:
+		m.lyrics = update.Lyrics
+	}
+}
+
+// View renders the model
+func (m *DashboardModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	s := GetTitleStyle(m.windowWidth).Render("sprt Dashboard") + "\n\n"
+	s += m.panel("Now Playing", usecase.PanelPlayback, m.playbackView) + "\n"
+	s += m.panel("Lyrics", usecase.PanelLyrics, m.lyricsView) + "\n"
+	s += m.panel("Up Next", usecase.PanelQueue, m.queueView) + "\n"
+	s += m.panel("Devices", usecase.PanelDevices, m.devicesView) + "\n"
+	if m.help {
+		return s + m.keys.helpView(m.windowWidth, keyReload, keyHelp, keyQuit, keyBack)
+	}
+	s += GetInfoStyle().Render(m.keys.footer(keyReload, keyQuit))
+	return s
+}
+
+// panel renders a panel with its title, showing the loading state and errors in place of its content
+func (m *DashboardModel) panel(title, name string, content func() string) string {
+	body := GetHeaderStyle().Render(title) + "\n"
+	switch err := m.errs[name]; {
+	case !m.loaded[name]:
+		body += GetInfoStyle().Render("Loading...")
+	case err != nil && errors.Is(err, usecase.ErrNothingPlaying):
+		body += GetValueStyle().Render("No track currently playing")
+	case err != nil:
+		body += GetValueStyle().Render("Error: " + err.Error())
+	default:
+		body += content()
+	}
+	return GetBorderStyle(m.windowWidth).Render(body)
+}
+
+// playbackView renders the playing track with its progress
+func (m *DashboardModel) playbackView() string {
+	state := m.playback
+	headerStyle := GetHeaderStyle()
+	valueStyle := GetValueStyle()
+
+	status := "Paused"
+	if state.IsPlaying {
+		status = "Playing"
+	}
+
+	s := valueStyle.Render(state.Title) + "\n"
+	s += valueStyle.Render(state.Artist) + "\n"
+	progressMs := m.progressMs()
+	s += valueStyle.Render(renderSeekBar(progressMs, state.DurationMs, m.windowWidth-24)) + " " +
+		valueStyle.Render(formatMs(progressMs)+" / "+formatMs(state.DurationMs)) + "\n"
+	s += headerStyle.Render("Status: ") + valueStyle.Render(status) + "  "
+	s += headerStyle.Render("Volume: ") + valueStyle.Render(fmt.Sprintf("%d%%", state.Device.VolumePercent))
+	return s
+}
+
+// lyricsView renders the current lyric line between the previous and the next one
+func (m *DashboardModel) lyricsView() string {
+	if m.lyrics == nil || len(m.lyrics.Lines) == 0 {
+		return GetInfoStyle().Render("No lyrics found")
+	}
+	if !m.lyrics.Synced && !m.lyrics.Estimated {
+		return GetInfoStyle().Render("Only unsynced lyrics found")
+	}
+
+	current := m.lyrics.LineIndexAt(m.progressMs())
+	var lines []string
+	for i := current - 1; i <= current+1; i++ {
+		if i < 0 || i >= len(m.lyrics.Lines) {
+			lines = append(lines, "")
+			continue
+		}
+		text := m.transforms.apply(m.lyrics.Lines[i].Text, i == current)
+		if i == current {
+			lines = append(lines, GetValueStyle().Render(text))
+		} else {
+			lines = append(lines, GetInfoStyle().Render(text))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// queueView renders the next queued tracks
+func (m *DashboardModel) queueView() string {
+	if len(m.queue) == 0 {
+		return GetInfoStyle().Render("The queue is empty")
+	}
+
+	var lines []string
+	for i, track := range m.queue[:min(len(m.queue), dashboardQueueRows)] {
+		lines = append(lines, GetValueStyle().Render(fmt.Sprintf("%d. %s – %s", i+1, track.Title, track.Artist)))
+	}
+	if more := len(m.queue) - dashboardQueueRows; more > 0 {
+		lines = append(lines, GetInfoStyle().Render(fmt.Sprintf("and %d more", more)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// devicesView renders the available devices, marking the active one
+func (m *DashboardModel) devicesView() string {
+	if len(m.devices) == 0 {
+		return GetInfoStyle().Render("No devices available")
+	}
+
+	var lines []string
+	for _, device := range m.devices {
+		marker := "  "
+		if device.IsActive {
+			marker = "● "
+		}
+		lines = append(lines, GetValueStyle().Render(fmt.Sprintf("%s%s (%s)", marker, device.Name, device.Type)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// progressMs returns the playback position, interpolated since the playback panel loaded
+func (m *DashboardModel) progressMs() int {
+	if m.playback == nil {
+		return 0
+	}
+
+	progress := m.playback.ProgressMs
+	if m.playback.IsPlaying {
+		progress += int(time.Since(m.polledAt).Milliseconds())
+	}
+	if m.playback.DurationMs > 0 {
+		progress = min(progress, m.playback.DurationMs)
+	}
+	return progress
+}
+
+// load starts loading all panels, replacing an earlier load
+func (m *DashboardModel) load() tea.Cmd {
+	m.updateCh = m.dashboardUseCase.Load(m.ctx)
+	return waitForDashboardUpdate(m.updateCh)
+}
+
+// waitForDashboardUpdate waits for the next panel loaded on ch
+func waitForDashboardUpdate(ch <-chan usecase.DashboardUpdate) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return dashboardUpdateMsg{update: update, ch: ch}
+	}
+}
+
+// tick schedules the next redraw of the progress and the lyric line
+func (m *DashboardModel) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return dashboardTickMsg{}
+	})
+}
+
+// RunDashboardUI runs the dashboard
+func RunDashboardUI(ctx context.Context, appConfig *config.Config, dashboardUseCase usecase.DashboardUseCase) error {
+	_, err := runScreen(ctx, func(ctx context.Context) (tea.Model, []tea.ProgramOption, error) {
+		model, err := NewDashboardModel(ctx, appConfig, dashboardUseCase)
+		return model, []tea.ProgramOption{tea.WithAltScreen()}, err
+	})
+	return err
+}
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+)
+
+// keyAction is an action of the TUIs, named like in the keybindings of the config
+type keyAction string
+
+// Actions of the TUIs
+const (
+	keyQuit          keyAction = "quit"
+	keyBack          keyAction = "back"
+	keyHelp          keyAction = "help"
+	keyPalette       keyAction = "palette"
+	keyUp            keyAction = "up"
+	keyDown          keyAction = "down"
+	keyPageUp        keyAction = "page-up"
+	keyPageDown      keyAction = "page-down"
+	keyTop           keyAction = "top"
+	keyBottom        keyAction = "bottom"
+	keySelect        keyAction = "select"
+	keyPlayPause     keyAction = "play-pause"
+	keyNextTrack     keyAction = "next-track"
+	keyPreviousTrack keyAction = "previous-track"
+	keySeekBackward  keyAction = "seek-backward"
+	keySeekForward   keyAction = "seek-forward"
+	keyVolumeUp      keyAction = "volume-up"
+	keyVolumeDown    keyAction = "volume-down"
+	keyShuffle       keyAction = "shuffle"
+	keyRepeat        keyAction = "repeat"
+	keyLyrics        keyAction = "lyrics"
+	keySearch        keyAction = "search"
+	keySearchNext    keyAction = "search-next"
+	keySearchPrev    keyAction = "search-prev"
+	keySnippet       keyAction = "snippet"
+	keyTranslation   keyAction = "translation"
+	keyFollow        keyAction = "follow"
+	keyOffsetUp      keyAction = "offset-up"
+	keyOffsetDown    keyAction = "offset-down"
+	keyReload        keyAction = "reload"
+	keyQueue         keyAction = "queue"
+	keyUnlike        keyAction = "unlike"
+	keyDecrease      keyAction = "decrease"
+	keyIncrease      keyAction = "increase"
+	keySave          keyAction = "save"
+)
+
+// keyActionHelp describes the actions in the help view and the key hints
+var keyActionHelp = map[keyAction]string{
+	keyQuit:          "quit",
+	keyBack:          "back",
+	keyHelp:          "help",
+	keyPalette:       "commands",
+	keyUp:            "up",
+	keyDown:          "down",
+	keyPageUp:        "page up",
+	keyPageDown:      "page down",
+	keyTop:           "first",
+	keyBottom:        "last",
+	keySelect:        "select",
+	keyPlayPause:     "play/pause",
+	keyNextTrack:     "next track",
+	keyPreviousTrack: "previous track",
+	keySeekBackward:  "seek back",
+	keySeekForward:   "seek forward",
+	keyVolumeUp:      "volume up",
+	keyVolumeDown:    "volume down",
+	keyShuffle:       "shuffle",
+	keyRepeat:        "repeat",
+	keyLyrics:        "lyrics",
+	keySearch:        "search",
+	keySearchNext:    "next match",
+	keySearchPrev:    "previous match",
+	keySnippet:       "save snippet",
+	keyTranslation:   "translation",
+	keyFollow:        "follow current line",
+	keyOffsetUp:      "lyrics earlier",
+	keyOffsetDown:    "lyrics later",
+	keyReload:        "reload",
+	keyQueue:         "add to queue",
+	keyUnlike:        "unlike",
+	keyDecrease:      "decrease",
+	keyIncrease:      "increase",
+	keySave:          "save",
+}
+
+// keyMap resolves key presses to the actions they are bound to
+type keyMap map[keyAction][]string
+
+// newKeyMap builds a key map of bindings, keeping the default keys of actions without any.
+// Ctrl+C always quits, so a remapped quit can't lock anyone in.
+func newKeyMap(bindings map[string][]string) keyMap {
+	keys := make(keyMap)
+	for action, defaults := range config.DefaultKeybindings() {
+		if bound := bindings[action]; len(bound) > 0 {
+			defaults = bound
+		}
+		for _, key := range defaults {
+			if key == "space" {
+				key = " "
+			}
+			keys[keyAction(action)] = append(keys[keyAction(action)], key)
+		}
+	}
+	keys[keyQuit] = append(keys[keyQuit], "ctrl+c")
+	return keys
+}
+
+// matches reports whether msg is a key bound to any of the actions
+func (k keyMap) matches(msg tea.KeyMsg, actions ...keyAction) bool {
+	pressed := msg.String()
+	for _, action := range actions {
+		for _, key := range k[action] {
+			if key == pressed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key returns the first key bound to action, which the command palette presses to run it
+func (k keyMap) key(action keyAction) string {
+	if keys := k[action]; len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// hint names the first key of each action for display, joined with "/"
+func (k keyMap) hint(actions ...keyAction) string {
+	names := make([]string, 0, len(actions))
+	for _, action := range actions {
+		if key := k.key(action); key != "" {
+			names = append(names, keyHint(key))
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// footer lists the actions with their first keys for the footer of a screen, ending with help
+func (k keyMap) footer(actions ...keyAction) string {
+	hints := make([]string, 0, len(actions)+1)
+	for _, action := range append(actions, keyHelp) {
+		if key := k.key(action); key != "" {
+			hints = append(hints, keyHint(key)+" "+keyActionHelp[action])
+		}
+	}
+	return strings.Join(hints, " • ")
+}
+
+// helpView renders the keys bound to the actions of a screen, toggled with the help key
+func (k keyMap) helpView(width int, actions ...keyAction) string {
+	var sb strings.Builder
+	sb.WriteString(GetHeaderStyle().Render("Keybindings") + "\n\n")
+
+	rows := make([][2]string, 0, len(actions))
+	keyWidth := 0
+	for _, action := range actions {
+		names := make([]string, len(k[action]))
+		for i, key := range k[action] {
+			names[i] = keyHint(key)
+		}
+		keys := strings.Join(names, ", ")
+		keyWidth = max(keyWidth, len([]rune(keys)))
+		rows = append(rows, [2]string{keys, keyActionHelp[action]})
+	}
+	for _, row := range rows {
+		keys := row[0] + strings.Repeat(" ", keyWidth-len([]rune(row[0])))
+		sb.WriteString(GetSelectedStyle().Render(keys) + "  " + GetNormalStyle().Render(row[1]) + "\n")
+	}
+	sb.WriteString("\n" + GetInfoStyle().Render("Change them in the keybindings of ~/.sprt/config.json • "+k.hint(keyHelp)+" close"))
+
+	return GetBorderStyle(width).Render(sb.String())
+}
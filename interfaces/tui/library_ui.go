@@ -0,0 +1,336 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// libraryPrefetchRows is how close the cursor gets to the last loaded track before the next page is loaded
+const libraryPrefetchRows = 10
+
+// libraryChromeRows is the number of rows taken by the title, header and help text
+const libraryChromeRows = 7
+
+// LibraryModel is the model for browsing the user's saved tracks
+type LibraryModel struct {
+	playerUseCase  usecase.PlayerUseCase
+	libraryUseCase usecase.LibraryUseCase
+	tracks         []usecase.SavedTrack
+	total          int
+	nextOffset     int
+	loading        bool
+	cursor         int
+	top            int
+	status         string
+	err            error
+	quitting       bool
+	windowWidth    int
+	windowHeight   int
+	keys           keyMap
+	help           bool
+	// hideExplicit leaves explicit tracks out in the clean-content mode; hidden counts them
+	hideExplicit bool
+	hidden       int
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// savedTracksMsg carries a page of saved tracks
+type savedTracksMsg struct {
+	page *usecase.SavedTracksPage
+	err  error
+}
+
+// libraryActionMsg carries the result of an action on a saved track
+type libraryActionMsg struct {
+	status string
+	// removedID is the ID of a track removed from the library
+	removedID string
+	err       error
+}
+
+// NewLibraryModel creates a new library model
+func NewLibraryModel(ctx context.Context, appConfig *config.Config, playerUseCase usecase.PlayerUseCase, libraryUseCase usecase.LibraryUseCase) *LibraryModel {
+	ctx, cancel := context.WithCancel(ctx)
+	return &LibraryModel{
+		hideExplicit:   appConfig.Clean.Enabled,
+		playerUseCase:  playerUseCase,
+		libraryUseCase: libraryUseCase,
+		status:         "Loading saved tracks...",
+		windowWidth:    80,
+		windowHeight:   24,
+		keys:           newKeyMap(appConfig.Keybindings),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+// Init initializes the model
+func (m *LibraryModel) Init() tea.Cmd {
+	m.loading = true
+	return m.loadPage(0)
+}
+
+// Update updates the model
+func (m *LibraryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case m.keys.matches(msg, keyHelp):
+			m.help = !m.help
+		case m.help && m.keys.matches(msg, keyBack):
+			m.help = false
+		case m.keys.matches(msg, keyQuit, keyBack):
+			m.quitting = true
+			m.cancel()
+			return m, tea.Quit
+		case m.keys.matches(msg, keyUp):
+			m.moveCursor(-1)
+		case m.keys.matches(msg, keyDown):
+			m.moveCursor(1)
+		case m.keys.matches(msg, keyPageUp):
+			m.moveCursor(-m.visibleRows())
+		case m.keys.matches(msg, keyPageDown):
+			m.moveCursor(m.visibleRows())
+		case m.keys.matches(msg, keyTop):
+			m.moveCursor(-len(m.tracks))
+		case m.keys.matches(msg, keyBottom):
+			m.moveCursor(len(m.tracks))
+		case m.keys.matches(msg, keySelect):
+			if track, ok := m.selected(); ok {
+				return m, m.action(fmt.Sprintf("Playing %s by %s", track.Title, track.Artist), "", func(ctx context.Context) error {
+					return m.playerUseCase.PlayURI(ctx, track.URI)
+				})
+			}
+		case m.keys.matches(msg, keyQueue):
+			if track, ok := m.selected(); ok {
+				return m, m.action(fmt.Sprintf("Added to queue: %s by %s", track.Title, track.Artist), "", func(ctx context.Context) error {
+					return m.playerUseCase.AddToQueue(ctx, track.URI)
+				})
+			}
+		case m.keys.matches(msg, keyUnlike):
+			if track, ok := m.selected(); ok {
+				return m, m.action(fmt.Sprintf("Removed %s by %s from your library", track.Title, track.Artist), track.ID, func(ctx context.Context) error {
+					return m.libraryUseCase.RemoveSavedTracks(ctx, []string{track.ID})
+				})
+			}
+		}
+		return m, m.prefetch()
+
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.moveCursor(-1)
+		case tea.MouseButtonWheelDown:
+			m.moveCursor(1)
+		case tea.MouseButtonLeft:
+			// Select the track clicked, and play it when it was already selected
+			i := m.trackAt(msg.Y)
+			if i < 0 {
+				return m, nil
+			}
+			if i == m.cursor {
+				return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			}
+			m.moveCursor(i - m.cursor)
+		}
+		return m, m.prefetch()
+
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		m.moveCursor(0)
+
+	case savedTracksMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		tracks := msg.page.Tracks
+		if m.hideExplicit {
+			tracks = usecase.WithoutExplicit(tracks)
+		}
+		m.tracks = append(m.tracks, tracks...)
+		// Hidden tracks don't count towards the total
+		m.hidden += len(msg.page.Tracks) - len(tracks)
+		m.total = msg.page.Total - m.hidden
+		m.nextOffset = msg.page.NextOffset
+		if m.status == "Loading saved tracks..." {
+			m.status = ""
+		}
+		return m, m.prefetch()
+
+	case libraryActionMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.status = msg.status
+		if msg.removedID != "" {
+			m.remove(msg.removedID)
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the model
+func (m *LibraryModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	// Get styles from the shared styles
+	titleStyle := GetTitleStyle(m.windowWidth)
+	headerStyle := GetHeaderStyle()
+	valueStyle := GetValueStyle()
+	selectedStyle := GetSelectedStyle()
+	infoStyle := GetInfoStyle()
+
+	// Build the view
+	s := titleStyle.Render("sprt Library") + "\n\n"
+
+	if m.err != nil {
+		s += headerStyle.Render("Error: ") + valueStyle.Render(m.err.Error()) + "\n"
+		return s
+	}
+
+	s += headerStyle.Render(fmt.Sprintf("Saved tracks (%d of %d loaded)", len(m.tracks), m.total)) + "\n\n"
+
+	if len(m.tracks) == 0 && !m.loading {
+		s += valueStyle.Render("Your library has no saved tracks.") + "\n"
+	}
+
+	end := min(m.top+m.visibleRows(), len(m.tracks))
+	for i := m.top; i < end; i++ {
+		track := m.tracks[i]
+		line := truncateText(fmt.Sprintf("%4d  %s – %s  %s", i+1, track.Title, track.Artist, formatMs(track.DurationMs)), m.windowWidth-2)
+		if i == m.cursor {
+			s += "> " + selectedStyle.Render(line) + "\n"
+		} else {
+			s += "  " + valueStyle.Render(line) + "\n"
+		}
+	}
+
+	s += "\n"
+	if m.status != "" {
+		s += infoStyle.Render(m.status)
+	} else if m.loading {
+		s += infoStyle.Render("Loading more tracks...")
+	}
+	if m.help {
+		return s + "\n" + m.keys.helpView(m.windowWidth, keyUp, keyDown, keyPageUp, keyPageDown, keyTop, keyBottom,
+			keySelect, keyQueue, keyUnlike, keyHelp, keyQuit, keyBack)
+	}
+	s += "\n" + infoStyle.Render(m.keys.footer(keyUp, keyDown, keySelect, keyQueue, keyUnlike, keyQuit))
+
+	return s
+}
+
+// trackAt returns the index of the track drawn at the given screen row, or -1 for none. The
+// tracks start below the title and the count of saved tracks, both followed by a blank line.
+func (m *LibraryModel) trackAt(y int) int {
+	if m.err != nil {
+		return -1
+	}
+	i := m.top + y - 4
+	if y < 4 || i >= min(m.top+m.visibleRows(), len(m.tracks)) {
+		return -1
+	}
+	return i
+}
+
+// visibleRows returns the number of tracks that fit on screen
+func (m *LibraryModel) visibleRows() int {
+	return max(1, m.windowHeight-libraryChromeRows)
+}
+
+// moveCursor moves the cursor by delta tracks and scrolls it into view
+func (m *LibraryModel) moveCursor(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.tracks)-1))
+
+	rows := m.visibleRows()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	} else if m.cursor >= m.top+rows {
+		m.top = m.cursor - rows + 1
+	}
+	m.top = max(0, m.top)
+}
+
+// selected returns the track under the cursor
+func (m *LibraryModel) selected() (usecase.SavedTrack, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.tracks) {
+		return usecase.SavedTrack{}, false
+	}
+	return m.tracks[m.cursor], true
+}
+
+// remove drops the track with the given ID from the list
+func (m *LibraryModel) remove(id string) {
+	for i, track := range m.tracks {
+		if track.ID == id {
+			m.tracks = append(m.tracks[:i], m.tracks[i+1:]...)
+			m.total--
+			// The following page starts one track earlier now
+			if m.nextOffset > 0 {
+				m.nextOffset--
+			}
+			break
+		}
+	}
+	m.moveCursor(0)
+}
+
+// prefetch loads the next page once the cursor gets close to the last loaded track
+func (m *LibraryModel) prefetch() tea.Cmd {
+	if m.loading || m.nextOffset < 0 || m.cursor < len(m.tracks)-libraryPrefetchRows-m.visibleRows() {
+		return nil
+	}
+	m.loading = true
+	return m.loadPage(m.nextOffset)
+}
+
+// loadPage fetches the page of saved tracks starting at offset
+func (m *LibraryModel) loadPage(offset int) tea.Cmd {
+	return func() tea.Msg {
+		page, err := m.libraryUseCase.GetSavedTracks(m.ctx, offset, 0)
+		return savedTracksMsg{page: page, err: err}
+	}
+}
+
+// action runs an action on a saved track and reports the result
+func (m *LibraryModel) action(status, removedID string, fn func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		err := fn(m.ctx)
+		if err != nil {
+			removedID = ""
+		}
+		return libraryActionMsg{status: status, removedID: removedID, err: err}
+	}
+}
+
+// truncateText shortens text to width terminal cells, marking the cut with an ellipsis
+func truncateText(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+	return runewidth.Truncate(text, width, "…")
+}
+
+// RunLibraryUI runs the saved tracks browser
+func RunLibraryUI(ctx context.Context, appConfig *config.Config, playerUseCase usecase.PlayerUseCase, libraryUseCase usecase.LibraryUseCase) error {
+	_, err := runScreen(ctx, func(ctx context.Context) (tea.Model, []tea.ProgramOption, error) {
+		return NewLibraryModel(ctx, appConfig, playerUseCase, libraryUseCase), []tea.ProgramOption{tea.WithAltScreen(), withMouse(appConfig)}, nil
+	})
+	return err
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// LyricMatchModel is the model for choosing between the lyrics found for a track
+type LyricMatchModel struct {
+	title       string
+	matches     []usecase.LyricMatch
+	cursor      int
+	choice      int
+	windowWidth int
+	keys        keyMap
+}
+
+// NewLyricMatchModel creates a lyric match picker for the track described by title, starting on
+// the picked match
+func NewLyricMatchModel(appConfig *config.Config, title string, matches []usecase.LyricMatch) *LyricMatchModel {
+	m := &LyricMatchModel{
+		title:       title,
+		matches:     matches,
+		choice:      -1,
+		windowWidth: 80,
+		keys:        newKeyMap(appConfig.Keybindings),
+	}
+	for i, match := range matches {
+		if match.Picked {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+// Init initializes the model
+func (m *LyricMatchModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update updates the model
+func (m *LyricMatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case m.keys.matches(msg, keyQuit, keyBack):
+			return m, tea.Quit
+		case m.keys.matches(msg, keyUp):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case m.keys.matches(msg, keyDown):
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+		case m.keys.matches(msg, keySelect):
+			m.choice = m.cursor
+			return m, tea.Quit
+		}
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.cursor = max(0, m.cursor-1)
+		case tea.MouseButtonWheelDown:
+			m.cursor = min(len(m.matches)-1, m.cursor+1)
+		case tea.MouseButtonLeft:
+			// Select the match clicked, and use it when it was already selected
+			i := m.matchAt(msg.Y)
+			if i < 0 {
+				return m, nil
+			}
+			if i == m.cursor {
+				m.choice = i
+				return m, tea.Quit
+			}
+			m.cursor = i
+		}
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+	}
+
+	return m, nil
+}
+
+// matchAt returns the index of the match drawn at the given screen row, or -1 for none. The
+// matches follow the title and a blank line, each on two rows with its details.
+func (m *LyricMatchModel) matchAt(y int) int {
+	i := (y - 2) / 2
+	if y < 2 || i >= len(m.matches) {
+		return -1
+	}
+	return i
+}
+
+// View renders the model
+func (m *LyricMatchModel) View() string {
+	titleStyle := GetTitleStyle(m.windowWidth)
+	selectedStyle := GetSelectedStyle()
+	normalStyle := GetNormalStyle()
+	infoStyle := GetInfoStyle()
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("Lyrics for "+m.title) + "\n\n")
+
+	for i, match := range m.matches {
+		cursor := " "
+		style := normalStyle
+		if i == m.cursor {
+			cursor = ">"
+			style = selectedStyle
+		}
+
+		sb.WriteString(fmt.Sprintf("%s %d. %s\n", cursor, i+1, style.Render(match.Name+" – "+match.Artist)))
+		sb.WriteString("     " + infoStyle.Render(LyricMatchDetails(match)) + "\n")
+	}
+
+	sb.WriteString("\n" + normalStyle.Render(fmt.Sprintf("Press %s to use the selected lyrics, %s to navigate, %s to cancel",
+		m.keys.hint(keySelect), m.keys.hint(keyUp, keyDown), m.keys.hint(keyQuit))))
+	return sb.String()
+}
+
+// LyricMatchDetails describes the album, duration and kind of lyrics of a match, e.g.
+// "A Night At The Opera · 5:55 · synced · current".
+func LyricMatchDetails(match usecase.LyricMatch) string {
+	details := []string{}
+	if match.Album != "" {
+		details = append(details, match.Album)
+	}
+	if match.DurationMs > 0 {
+		details = append(details, formatMs(match.DurationMs))
+	}
+	switch {
+	case match.Instrumental:
+		details = append(details, "instrumental")
+	case match.Synced:
+		details = append(details, "synced")
+	default:
+		details = append(details, "unsynced")
+	}
+	if match.Picked {
+		details = append(details, "current")
+	}
+	return strings.Join(details, " · ")
+}
+
+// RunLyricMatchPicker lets the user choose between the lyrics found for the track described by
+// title and returns the index of the chosen match, or -1 when cancelled
+func RunLyricMatchPicker(ctx context.Context, appConfig *config.Config, title string, matches []usecase.LyricMatch) (int, error) {
+	model, err := runProgram(ctx, NewLyricMatchModel(appConfig, title, matches), tea.WithAltScreen(), withMouse(appConfig))
+	if err != nil {
+		return -1, err
+	}
+	return model.(*LyricMatchModel).choice, nil
+}
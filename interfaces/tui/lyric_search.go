@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lyricSearch holds the state of searching within the loaded lyrics
+type lyricSearch struct {
+	// typing is true while the query is being entered
+	typing bool
+	// searched is true once the query is entered, until returning to following playback
+	searched bool
+	query    string
+	matches  []int
+	// match is the index in matches of the line the viewport is jumped to
+	match int
+}
+
+// lyricSeekMsg carries the result of seeking to a lyric line
+type lyricSeekMsg struct {
+	err error
+}
+
+// active reports whether the search prompt or a jumped-to match is shown
+func (s *lyricSearch) active() bool {
+	return s.typing || s.searched
+}
+
+// line returns the index of the lyric line the viewport is jumped to, or -1 when following playback
+func (s *lyricSearch) line() int {
+	if !s.searched || s.match >= len(s.matches) {
+		return -1
+	}
+	return s.matches[s.match]
+}
+
+// find searches the lines for the query, case-insensitively
+func (s *lyricSearch) find(lines []string) {
+	s.typing = false
+	s.searched = true
+	s.matches = nil
+	s.match = 0
+
+	query := strings.ToLower(strings.TrimSpace(s.query))
+	if query == "" {
+		return
+	}
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(line), query) {
+			s.matches = append(s.matches, i)
+		}
+	}
+}
+
+// step moves to the next (1) or previous (-1) match, wrapping around
+func (s *lyricSearch) step(delta int) {
+	if len(s.matches) == 0 {
+		return
+	}
+	s.match = (s.match + delta + len(s.matches)) % len(s.matches)
+}
+
+// reset returns to following playback
+func (s *lyricSearch) reset() {
+	*s = lyricSearch{}
+}
+
+// handleSearchKey handles key presses while searching, returning false for keys it doesn't use
+func (m *LyricModel) handleSearchKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.search.typing {
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.search.find(m.lines)
+		case tea.KeyEsc:
+			m.search.reset()
+		case tea.KeyBackspace:
+			if runes := []rune(m.search.query); len(runes) > 0 {
+				m.search.query = string(runes[:len(runes)-1])
+			}
+		case tea.KeySpace:
+			m.search.query += " "
+		case tea.KeyRunes:
+			m.search.query += string(msg.Runes)
+		case tea.KeyCtrlC:
+			return nil, false
+		}
+		return nil, true
+	}
+
+	if !m.search.searched {
+		return nil, false
+	}
+
+	switch {
+	case m.keys.matches(msg, keySearchNext):
+		m.search.step(1)
+	case m.keys.matches(msg, keySearchPrev):
+		m.search.step(-1)
+	case m.keys.matches(msg, keyBack):
+		m.search.reset()
+	case m.keys.matches(msg, keySelect):
+		// Seek playback to the matched line and follow it from there
+		cmd := m.seekToLine(m.search.line())
+		m.search.reset()
+		return cmd, true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// seekToLine seeks playback to the start of the given lyric line
+func (m *LyricModel) seekToLine(index int) tea.Cmd {
+	if m.lyrics == nil || index < 0 || index >= len(m.lyrics.Lines) {
+		return nil
+	}
+	return m.seekTo(m.lyrics.Lines[index].StartTimeMs)
+}
+
+// seekTo seeks playback to positionMs
+func (m *LyricModel) seekTo(positionMs int) tea.Cmd {
+	return func() tea.Msg {
+		return lyricSeekMsg{err: m.playerUseCase.Seek(m.ctx, positionMs)}
+	}
+}
+
+// searchFooter describes the search state for the footer
+func (m *LyricModel) searchFooter() string {
+	if m.search.typing {
+		return "/" + m.search.query + "█  (enter search • esc cancel)"
+	}
+	if len(m.search.matches) == 0 {
+		return fmt.Sprintf("No lines match %q  (%s search again • %s back)", m.search.query,
+			m.keys.hint(keySearch), m.keys.hint(keyBack))
+	}
+	return fmt.Sprintf("Match %d/%d for %q  (%s next/prev • %s seek here • %s back)",
+		m.search.match+1, len(m.search.matches), m.search.query,
+		m.keys.hint(keySearchNext, keySearchPrev), m.keys.hint(keySelect), m.keys.hint(keyBack))
+}
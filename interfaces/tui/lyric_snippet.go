@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/muhadif/sprt/domain/entity"
+)
+
+// saveSnippet bookmarks the current lyric line with its track and returns a status message
+func (m *LyricModel) saveSnippet() string {
+	if m.snippets == nil || m.lyrics == nil || m.track == nil || m.track.IsEpisode() {
+		return "Nothing to save"
+	}
+	if m.currentLineIdx < 0 || m.currentLineIdx >= len(m.lyrics.Lines) {
+		return "Wait for the first line before saving a snippet"
+	}
+
+	line := m.lyrics.Lines[m.currentLineIdx]
+	snippet := &entity.Snippet{
+		SavedAt:     time.Now(),
+		TrackID:     m.track.ID,
+		TrackURI:    m.track.URI,
+		Title:       m.track.Title,
+		Artist:      m.track.Artist,
+		Album:       m.track.Album,
+		Text:        line.Text,
+		StartTimeMs: line.StartTimeMs,
+	}
+	if err := m.snippets.AddSnippet(m.ctx, snippet); err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+
+	return fmt.Sprintf("Saved snippet %q", line.Text)
+}
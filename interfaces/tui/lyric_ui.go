@@ -2,14 +2,20 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/repository"
 	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/httpclient"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+	"github.com/muhadif/sprt/infrastructure/translate"
 )
 
 // LyricModel is the model for the lyric UI
@@ -25,31 +31,94 @@ type LyricModel struct {
 	ctx            context.Context
 	cancel         context.CancelFunc
 	err            error
+	clock          progressClock
+	playerUseCase  usecase.PlayerUseCase
+	search         lyricSearch
+	transforms     transformChain
+	status         string
+	track          *usecase.CurrentlyPlaying
+	snippets       repository.SnippetRepository
+	windowTitle    *windowTitle
+	// kiosk ignores every key but Ctrl+C and hides the key hints, for public displays
+	kiosk   bool
+	palette commandPalette
+	keys    keyMap
+	help    bool
+	// offsetMs shifts the lyrics against playback, positive to show them earlier, for
+	// correcting lyrics that are out of sync while they play
+	offsetMs int
+	// scroll is the first line shown of unsynced lyrics, which have no current line to follow.
+	// Synced lyrics scrolled through are centered on it instead of the current line until
+	// scrolledAt, the time of the last scroll, is scrollSnapBack ago; it is zero while following.
+	scroll     int
+	scrolledAt time.Time
+	// lineRows holds the screen rows of the lines last drawn, to find the line clicked
+	lineRows []lineRow
+	// seekBar is where the progress bar of the header was last drawn, to seek to the position clicked
+	seekBar seekBarArea
+	// translation translates the lyrics, nil when no provider is configured; translations
+	// holds the translation of each line of lyrics, shown beneath it unless hidden
+	translation      usecase.TranslationUseCase
+	translations     []string
+	hideTranslations bool
+
+	// Pulse state
+	pulseStart   time.Time
+	pulseTicking bool
+	beats        []int
+	beatsTrackID string
+	lastBeatMs   int
 
 	// Animation state
-	animating       bool
-	animationStep   int
-	animationSteps  int
-	animationType   string
-	animationTicker *time.Ticker
+	animating        bool
+	animationStep    int
+	animationSteps   int
+	animationType    string
+	animationTicking bool
+}
+
+// NewLyricModel creates a new lyric model following the track of playerUseCase from startTimeMs
+func NewLyricModel(ctx context.Context, appConfig *config.Config, startTimeMs int, playerUseCase usecase.PlayerUseCase) (*LyricModel, error) {
+	lyricUseCase := usecase.NewLyricUseCase(httpclient.New(appConfig.API), jsonfile.NewLyricMatchRepository(""), appConfig.Lyrics.Dir, appConfig.Lyrics.EstimateTiming)
+
+	feed := func(ctx context.Context) <-chan *usecase.LyricUpdate {
+		return usecase.LyricChannel(ctx, startTimeMs, playerUseCase, lyricUseCase)
+	}
+	return newLyricModel(ctx, appConfig, feed, playerUseCase)
 }
 
-// NewLyricModel creates a new lyric model
-func NewLyricModel(ctx context.Context, startTimeMs int, playerUseCase usecase.PlayerUseCase) (*LyricModel, error) {
+// newLyricModel creates a new lyric model showing the updates of feed. Without playerUseCase,
+// e.g. replaying a recording, the beats aren't loaded; the model must be kiosk then, as the
+// palette controls playback.
+func newLyricModel(ctx context.Context, appConfig *config.Config, feed LyricFeed, playerUseCase usecase.PlayerUseCase) (*LyricModel, error) {
 	// Load UI config
-	uiConfig, err := config.LoadUIConfig()
+	uiConfig, err := loadUIConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load UI config: %w", err)
 	}
 
-	// Create the lyric use case
-	lyricUseCase := usecase.NewLyricUseCase()
+	transforms, err := newTransformChain(lyricTransforms(appConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up lyric transforms: %w", err)
+	}
+	windowTitle, err := newWindowTitle(appConfig.Title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the window title: %w", err)
+	}
+	translator, err := translate.New(httpclient.New(appConfig.API), appConfig.Lyrics.Translation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up lyric translation: %w", err)
+	}
+	var translation usecase.TranslationUseCase
+	if translator != nil {
+		translation = usecase.NewTranslationUseCase(translator, appConfig.Lyrics.Translation.Target)
+	}
 
 	// Create a context that can be cancelled
 	ctx, cancel := context.WithCancel(ctx)
 
 	// Get the lyric updates channel
-	updateCh := lyricUseCase.GetLyricChannel(ctx, startTimeMs, playerUseCase)
+	updateCh := feed(ctx)
 
 	return &LyricModel{
 		lines:          []string{"Loading lyrics..."},
@@ -61,6 +130,12 @@ func NewLyricModel(ctx context.Context, startTimeMs int, playerUseCase usecase.P
 		updateCh:       updateCh,
 		ctx:            ctx,
 		cancel:         cancel,
+		playerUseCase:  playerUseCase,
+		transforms:     transforms,
+		snippets:       jsonfile.NewSnippetRepository(""),
+		windowTitle:    windowTitle,
+		translation:    translation,
+		keys:           newKeyMap(appConfig.Keybindings),
 		animating:      false,
 		animationType:  uiConfig.Lyric.Animation.Type,
 		animationSteps: uiConfig.Lyric.Animation.FadeSteps,
@@ -69,39 +144,172 @@ func NewLyricModel(ctx context.Context, startTimeMs int, playerUseCase usecase.P
 
 // Init initializes the model
 func (m *LyricModel) Init() tea.Cmd {
-	return m.waitForUpdate
+	return tea.Batch(m.waitForUpdate, clockTick())
 }
 
 // Update updates the model
 func (m *LyricModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			m.cancel()
-			if m.animationTicker != nil {
-				m.animationTicker.Stop()
+		if m.kiosk && msg.String() != "ctrl+c" {
+			return m, nil
+		}
+		if m.palette.open && msg.String() != "ctrl+c" {
+			if action := m.palette.handleKey(msg); action != nil {
+				if action.key == "" {
+					return m, action.run()
+				}
+				return m.Update(paletteKeyMsg(action.key))
 			}
+			return m, nil
+		}
+		if cmd, handled := m.handleSearchKey(msg); handled {
+			return m, cmd
+		}
+
+		switch {
+		case m.keys.matches(msg, keyPalette):
+			m.palette.show(m.paletteActions())
+		case m.keys.matches(msg, keyHelp):
+			m.help = !m.help
+		case m.help && m.keys.matches(msg, keyBack):
+			m.help = false
+		case m.keys.matches(msg, keyQuit):
+			m.cancel()
 			return m, tea.Quit
+		case m.keys.matches(msg, keySearch):
+			// Search the loaded lyrics
+			if m.lyrics != nil {
+				m.search = lyricSearch{typing: true}
+				m.status = ""
+			}
+		case m.keys.matches(msg, keySnippet):
+			// Bookmark the current line
+			m.status = m.saveSnippet()
+		case m.keys.matches(msg, keyTranslation):
+			// Show or hide the translations
+			if m.translation != nil {
+				m.hideTranslations = !m.hideTranslations
+			}
+		case m.keys.matches(msg, keyPlayPause):
+			return m, m.playPause()()
+		case m.keys.matches(msg, keyOffsetUp):
+			return m, m.shiftOffset(lyricOffsetStepMs)
+		case m.keys.matches(msg, keyOffsetDown):
+			return m, m.shiftOffset(-lyricOffsetStepMs)
+		case m.keys.matches(msg, keyUp, keyDown, keyPageUp, keyPageDown):
+			// Scroll through the lyrics, pausing following the current line of synced ones
+			if m.lyrics != nil && len(m.lyrics.Lines) > 0 {
+				m.scrollBy(m.scrollDelta(msg))
+			}
+		case m.keys.matches(msg, keyFollow):
+			// Snap back to the current line
+			m.follow()
+		case m.keys.matches(msg, keySelect):
+			// Seek playback to the line scrolled to and follow it from there
+			if m.scrolling() {
+				m.follow()
+				return m, m.seekToLine(m.scroll)
+			}
+		}
+
+	case tea.MouseMsg:
+		if m.kiosk || m.palette.open || msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+			// Scroll through the lyrics like with the arrow keys
+			if m.lyrics != nil && len(m.lyrics.Lines) > 0 {
+				delta := 1
+				if msg.Button == tea.MouseButtonWheelUp {
+					delta = -1
+				}
+				m.scrollBy(delta)
+			}
+		case tea.MouseButtonLeft:
+			// Seek playback to the position clicked on the progress bar or to the line clicked
+			if position, ok := m.seekBar.positionAt(msg.X, msg.Y, m.clock.durationMs); ok {
+				m.follow()
+				return m, m.seekTo(position)
+			}
+			if m.unsynced() {
+				return m, nil
+			}
+			if i := m.lineAt(msg.Y); i >= 0 {
+				m.follow()
+				m.search.reset()
+				return m, m.seekToLine(i)
+			}
 		}
 
+	case lyricSeekMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+		}
+		return m, nil
+
+	case translationMsg:
+		if msg.lyrics == m.lyrics {
+			m.translations = msg.translations
+			if msg.err != nil {
+				m.status = fmt.Sprintf("Error: %v", msg.err)
+			}
+		}
+		return m, nil
+
+	case paletteDoneMsg:
+		m.status = msg.status
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+		}
+		return m, nil
+
 	case *usecase.LyricUpdate:
+		m.clock.observe(msg.Track)
+		if msg.Track != nil {
+			m.track = msg.Track
+		} else if msg.NothingPlaying {
+			m.track = nil
+		}
+		if msg.Track != nil || msg.NothingPlaying {
+			m.windowTitle.show(m.track)
+		}
+		cmds := []tea.Cmd{m.waitForUpdate}
+
+		// Load the beats of a new track for the beat pulse
+		pulse := m.uiConfig.Lyric.Pulse
+		if pulse.Enabled && pulse.Beats && m.playerUseCase != nil && msg.Track != nil && !msg.Track.IsEpisode() && msg.Track.ID != m.beatsTrackID {
+			m.beatsTrackID = msg.Track.ID
+			m.beats = nil
+			cmds = append(cmds, m.fetchBeats(msg.Track.ID))
+		}
+
 		if msg.IsError {
-			m.err = fmt.Errorf(msg.ErrorMsg)
+			m.err = errors.New(msg.ErrorMsg)
 			m.lines = []string{fmt.Sprintf("Error: %s", msg.ErrorMsg)}
+		} else if msg.Lyrics == nil && msg.Track != nil && msg.Track.IsEpisode() {
+			// Podcasts have no lyrics
+			m.lyrics = nil
+			m.lines = []string{msg.Text}
+			m.currentLineIdx = -1
+			m.search.reset()
 		} else if msg.Lyrics != nil {
+			if msg.Lyrics != m.lyrics {
+				m.follow()
+				m.scroll = 0
+				m.translations = nil
+				if m.translation != nil {
+					cmds = append(cmds, m.translate(msg.Lyrics))
+				}
+			}
 			m.lyrics = msg.Lyrics
 
-			// Store previous line index for animation
-			if m.currentLineIdx != msg.LineIndex {
-				m.prevLineIdx = m.currentLineIdx
-				m.currentLineIdx = msg.LineIndex
-
-				// Start animation if enabled
-				if m.uiConfig.Lyric.Animation.Enabled && m.prevLineIdx != -1 {
-					m.startAnimation()
-				}
+			lineIdx := msg.LineIndex
+			if m.offsetMs != 0 && (msg.Lyrics.Synced || msg.Lyrics.Estimated) {
+				lineIdx = msg.Lyrics.LineIndexAt(m.lyricMs())
 			}
+			cmds = append(cmds, m.moveToLine(lineIdx)...)
 
 			// Build the lines array with all lyrics
 			if len(m.lyrics.Lines) > 0 {
@@ -112,153 +320,256 @@ func (m *LyricModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		return m, m.waitForUpdate
+		return m, tea.Batch(cmds...)
+
+	case beatsMsg:
+		if msg.trackID == m.beatsTrackID {
+			m.beats = msg.beats
+			m.lastBeatMs = m.clock.now()
+			return m, m.startPulseTick()
+		}
+		return m, nil
+
+	case pulseTickMsg:
+		return m, m.updatePulse()
+
+	case clockTickMsg:
+		if m.scrolling() && time.Since(m.scrolledAt) >= scrollSnapBack {
+			m.follow()
+		}
+
+		// Shifted lyrics advance by the clock instead of the updates
+		var cmds []tea.Cmd
+		if m.offsetMs != 0 && m.lyrics != nil && !m.unsynced() {
+			cmds = m.moveToLine(m.lyrics.LineIndexAt(m.lyricMs()))
+		}
+
+		// Redraw the intro countdown and interlude indicator, and the sung words more often
+		if m.karaoke(m.currentLineIdx) {
+			return m, tea.Batch(append(cmds, tea.Tick(karaokeInterval, func(t time.Time) tea.Msg {
+				return clockTickMsg(t)
+			}))...)
+		}
+		return m, tea.Batch(append(cmds, clockTick())...)
 
 	case animationTickMsg:
+		m.animationTicking = false
 		if m.animating {
 			m.animationStep++
 			if m.animationStep >= m.animationSteps {
 				m.animating = false
-				if m.animationTicker != nil {
-					m.animationTicker.Stop()
-					m.animationTicker = nil
-				}
+				return m, nil
 			}
-			return m, nil
+			return m, m.startAnimationTick()
 		}
 	}
 
 	return m, nil
 }
 
-// animationTickMsg is a message sent when the animation ticker ticks
-type animationTickMsg struct{}
+// moveToLine makes the line at index the current one, starting its animation, pulse and cue
+func (m *LyricModel) moveToLine(index int) []tea.Cmd {
+	if index == m.currentLineIdx {
+		return nil
+	}
 
-// startAnimation starts the animation for transitioning between lyric lines
-func (m *LyricModel) startAnimation() {
-	if m.animationTicker != nil {
-		m.animationTicker.Stop()
+	// Store previous line index for animation
+	m.prevLineIdx = m.currentLineIdx
+	m.currentLineIdx = index
+	if index < 0 {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+
+	// Start animation if enabled
+	if m.uiConfig.Lyric.Animation.Enabled && m.prevLineIdx != -1 {
+		cmds = append(cmds, m.startAnimation())
 	}
 
+	// Pulse the background of the new line
+	if m.uiConfig.Lyric.Pulse.Enabled {
+		cmds = append(cmds, m.startPulse())
+	}
+
+	// Sound the cue for practicing without watching the screen
+	if cue := lineCue(m.uiConfig.Lyric.Cue, m.lyrics, index, m.uiConfig.Lyric.InterludeSeconds*1000); cue != nil {
+		cmds = append(cmds, cue)
+	}
+	return cmds
+}
+
+// lyricOffsetStepMs is how far the offset keys shift the lyrics
+const lyricOffsetStepMs = 100
+
+// lyricMs returns the position in the lyrics, which is the playback position shifted by the offset
+func (m *LyricModel) lyricMs() int {
+	return m.clock.now() + m.offsetMs
+}
+
+// shiftOffset shifts the lyrics by deltaMs against playback and moves to the line due then
+func (m *LyricModel) shiftOffset(deltaMs int) tea.Cmd {
+	if m.lyrics == nil || m.unsynced() {
+		return nil
+	}
+
+	m.offsetMs += deltaMs
+	m.status = fmt.Sprintf("Lyric offset %+.1fs", float64(m.offsetMs)/1000)
+	return tea.Batch(m.moveToLine(m.lyrics.LineIndexAt(m.lyricMs()))...)
+}
+
+// animationTickMsg is a message sent when the animation should advance a step
+type animationTickMsg struct{}
+
+// startAnimation starts the animation for transitioning between lyric lines
+func (m *LyricModel) startAnimation() tea.Cmd {
 	m.animating = true
 	m.animationStep = 0
+	return m.startAnimationTick()
+}
+
+// startAnimationTick schedules the next animation step unless one is already scheduled, so the
+// steps end with the program instead of outliving it in a goroutine
+func (m *LyricModel) startAnimationTick() tea.Cmd {
+	if m.animationTicking {
+		return nil
+	}
+	m.animationTicking = true
 
 	// Calculate tick duration based on total animation duration and steps
 	tickDuration := time.Duration(m.uiConfig.Lyric.Animation.DurationMs) * time.Millisecond / time.Duration(m.animationSteps)
-	m.animationTicker = time.NewTicker(tickDuration)
-
-	// Send animation tick messages
-	go func() {
-		for range m.animationTicker.C {
-			if !m.animating {
-				return
-			}
-			cmd := func() tea.Msg {
-				return animationTickMsg{}
-			}
-			tea.NewProgram(m).Send(cmd())
-		}
-	}()
+	return tea.Tick(tickDuration, func(time.Time) tea.Msg {
+		return animationTickMsg{}
+	})
 }
 
 // View renders the model
 func (m *LyricModel) View() string {
 	if m.err != nil {
-		return fmt.Sprintf("Error: %v\n\nPress q to quit.", m.err)
+		if m.kiosk {
+			return fmt.Sprintf("Error: %v", m.err)
+		}
+		return fmt.Sprintf("Error: %v\n\nPress %s to quit.", m.err, m.keys.hint(keyQuit))
 	}
 
 	// Get base styles from the shared styles
 	titleStyle := GetTitleStyle(m.width)
 
-	// Create styles for current and other lines based on config
-	currentStyle := GetCurrentLineStyle(m.width)
-	otherStyle := GetOtherLineStyle(m.width)
-	prevStyle := GetOtherLineStyle(m.width)
+	// Keep the lyrics clear of the configured horizontal margins
+	margin := max(0, m.uiConfig.Lyric.MarginX)
+	width := max(1, m.width-2*margin)
 
-	// Apply custom styling from config if available
-	if m.uiConfig.Lyric.CurrentLineStyle.ForegroundColor != "" {
-		currentStyle = currentStyle.Foreground(lipgloss.Color(m.uiConfig.Lyric.CurrentLineStyle.ForegroundColor))
-	}
-	if m.uiConfig.Lyric.CurrentLineStyle.BackgroundColor != "" {
-		currentStyle = currentStyle.Background(lipgloss.Color(m.uiConfig.Lyric.CurrentLineStyle.BackgroundColor))
-	}
-	if m.uiConfig.Lyric.CurrentLineStyle.Bold {
-		currentStyle = currentStyle.Bold(true)
+	// Create styles for current and other lines based on config
+	currentStyle, otherStyle := lyricLineStyles(m.uiConfig.Lyric, width)
+	prevStyle := otherStyle
+	if strength := m.pulseStrength(); strength > 0 {
+		background := interpolateColor(m.uiConfig.Lyric.CurrentLineStyle.BackgroundColor, m.uiConfig.Lyric.Pulse.Color, strength)
+		currentStyle = currentStyle.Background(lipgloss.Color(background))
 	}
-	if m.uiConfig.Lyric.CurrentLineStyle.Italic {
-		currentStyle = currentStyle.Italic(true)
+
+	// Build the view
+	var sb strings.Builder
+
+	// Add the header with the track and its progress
+	header := m.header(titleStyle, width, m.clock.now())
+	if header != "" {
+		sb.WriteString(header)
+		sb.WriteString("\n\n")
 	}
-	if m.uiConfig.Lyric.CurrentLineStyle.Underline {
-		currentStyle = currentStyle.Underline(true)
+	progressMs := m.lyricMs()
+
+	// Show the countdown instead of highlighting the first line before it's due
+	highlightIdx := m.currentLineIdx
+	if countdown := usecase.IntroCountdown(m.lyrics, progressMs); countdown != "" {
+		sb.WriteString(currentStyle.Render(countdown))
+		sb.WriteString("\n\n")
+		highlightIdx = -1
 	}
 
-	// Apply custom styling for other lines from config if available
-	if m.uiConfig.Lyric.OtherLineStyle.ForegroundColor != "" {
-		otherStyle = otherStyle.Foreground(lipgloss.Color(m.uiConfig.Lyric.OtherLineStyle.ForegroundColor))
-		prevStyle = prevStyle.Foreground(lipgloss.Color(m.uiConfig.Lyric.OtherLineStyle.ForegroundColor))
+	// Show the interlude indicator after the last sung line during instrumental gaps
+	interlude := ""
+	if highlightIdx != -1 {
+		interlude = usecase.Interlude(m.lyrics, m.currentLineIdx, progressMs, m.clock.durationMs, m.uiConfig.Lyric.InterludeSeconds*1000)
+		if interlude != "" {
+			highlightIdx = -1
+		}
 	}
-	if m.uiConfig.Lyric.OtherLineStyle.BackgroundColor != "" {
-		otherStyle = otherStyle.Background(lipgloss.Color(m.uiConfig.Lyric.OtherLineStyle.BackgroundColor))
-		prevStyle = prevStyle.Background(lipgloss.Color(m.uiConfig.Lyric.OtherLineStyle.BackgroundColor))
+
+	// Calculate how many lines to show before and after the current line
+	spacing := max(0, m.uiConfig.Lyric.LineSpacing)
+	paddingTop := max(0, m.uiConfig.Lyric.PaddingTop)
+	paddingBottom := max(0, m.uiConfig.Lyric.PaddingBottom)
+	bodyHeight := m.height - 2 - max(1, lipgloss.Height(header)) - paddingTop - paddingBottom // -2 for spacing and footer
+	linesBeforeAfter := bodyHeight / (spacing + 1) / 2
+	translated := m.translations != nil && !m.hideTranslations
+	if translated {
+		// Every line takes another for its translation
+		linesBeforeAfter = bodyHeight / (spacing + 2) / 2
 	}
-	if m.uiConfig.Lyric.OtherLineStyle.Bold {
-		otherStyle = otherStyle.Bold(true)
-		prevStyle = prevStyle.Bold(true)
+	focused := m.uiConfig.Lyric.Layout == layoutFocused
+	if focused {
+		linesBeforeAfter = 1
+		spacing = max(spacing, focusedLineSpacing)
 	}
-	if m.uiConfig.Lyric.OtherLineStyle.Italic {
-		otherStyle = otherStyle.Italic(true)
-		prevStyle = prevStyle.Italic(true)
+	lineSeparator := strings.Repeat("\n", spacing+1)
+
+	// Center the view on the search match instead of the current line while jumped to one
+	// or on the line scrolled to
+	centerIdx := m.currentLineIdx
+	if matchIdx := m.search.line(); matchIdx >= 0 {
+		centerIdx = matchIdx
+	} else if m.scrolling() {
+		centerIdx = m.scroll
 	}
-	if m.uiConfig.Lyric.OtherLineStyle.Underline {
-		otherStyle = otherStyle.Underline(true)
-		prevStyle = prevStyle.Underline(true)
+	startIdx := max(0, centerIdx-linesBeforeAfter)
+	endIdx := min(len(m.lines), centerIdx+linesBeforeAfter+1)
+	if m.unsynced() && m.search.line() < 0 {
+		// Unsynced lyrics fill the screen from the line scrolled to
+		startIdx = min(m.scroll, len(m.lines)-1)
+		endIdx = min(len(m.lines), startIdx+2*linesBeforeAfter+1)
 	}
 
-	// Build the view
-	var sb strings.Builder
+	// Show all lyrics with the current line highlighted
+	var body strings.Builder
+	m.lineRows = m.lineRows[:0]
+	for i := startIdx; i < endIdx; i++ {
+		line := m.transforms.apply(m.lines[i], i == highlightIdx)
+		top := strings.Count(body.String(), "\n")
 
-	// Add a title
-	if m.lyrics != nil {
-		title := fmt.Sprintf("%s - %s", m.lyrics.Artist, m.lyrics.Name)
-		sb.WriteString(titleStyle.Render(title))
-		sb.WriteString("\n\n")
-	}
+		// Give duet parts their own color and column
+		part := m.linePart(i)
+		lineCurrentStyle := m.alignPart(currentStyle, part)
+		lineOtherStyle := m.colorPart(m.alignPart(otherStyle, part), part)
+		if i == m.search.line() || (m.scrolling() && i == m.scroll) {
+			lineOtherStyle = lineOtherStyle.Reverse(true)
+		}
+		linePrevStyle := m.colorPart(m.alignPart(prevStyle, part), part)
+		if part != "" && i == highlightIdx {
+			line = part + ": " + line
+		}
 
-	// Calculate how many lines to show before and after the current line
-	linesBeforeAfter := (m.height - 3) / 2 // -3 for title and spacing
-	startIdx := max(0, m.currentLineIdx-linesBeforeAfter)
-	endIdx := min(len(m.lines), m.currentLineIdx+linesBeforeAfter+1)
+		rtl := isRTLText(line)
+		if i == highlightIdx && m.karaoke(i) {
+			// Highlight the words sung so far
+			line = m.karaokeText(i, progressMs)
+		} else {
+			// Order right-to-left lyrics for terminals without bidi support
+			line = displayBidi(line, m.uiConfig.Lyric.Bidi)
 
-	// Show all lyrics with the current line highlighted
-	for i := startIdx; i < endIdx; i++ {
-		line := m.lines[i]
+			// Make the current line readable from across the room
+			if i == highlightIdx {
+				line = renderBigText(line, m.uiConfig.Lyric.BigText, width)
+			}
+		}
 
 		// Apply animation if enabled and currently animating
 		if m.animating && m.uiConfig.Lyric.Animation.Enabled {
 			if i == m.currentLineIdx {
 				// Current line is fading in
 				if m.animationType == "fade" {
-					// Calculate fade-in progress (0.0 to 1.0)
+					// Fade in from the style of the other lines to that of the current line
 					progress := float64(m.animationStep) / float64(m.animationSteps)
-
-					// Interpolate between other style and current style
-					fgColor := interpolateColor(
-						m.uiConfig.Lyric.OtherLineStyle.ForegroundColor,
-						m.uiConfig.Lyric.CurrentLineStyle.ForegroundColor,
-						progress,
-					)
-
-					// Create a style with the interpolated color
-					fadeStyle := lipgloss.NewStyle().
-						Foreground(lipgloss.Color(fgColor)).
-						Width(m.width).
-						Align(lipgloss.Center)
-
-					if m.uiConfig.Lyric.CurrentLineStyle.Bold {
-						fadeStyle = fadeStyle.Bold(progress > 0.5)
-					}
-
-					sb.WriteString(fadeStyle.Render(line))
+					body.WriteString(m.alignPart(fadeStyle(m.uiConfig.Lyric, width, progress, true), part).Render(line))
 				} else if m.animationType == "slide" {
 					// Slide animation
 					slideDistance := m.uiConfig.Lyric.Animation.SlideDistance
@@ -266,37 +577,19 @@ func (m *LyricModel) View() string {
 
 					// Calculate padding based on progress
 					padding := int(float64(slideDistance) * (1.0 - progress))
-					paddedLine := strings.Repeat(" ", padding) + line
+					paddedLine := slidePad(line, padding, rtl)
 
-					sb.WriteString(currentStyle.Render(paddedLine))
+					body.WriteString(lineCurrentStyle.Render(paddedLine))
 				} else {
 					// No animation or unknown type
-					sb.WriteString(currentStyle.Render(line))
+					body.WriteString(lineCurrentStyle.Render(line))
 				}
 			} else if i == m.prevLineIdx {
 				// Previous line is fading out
 				if m.animationType == "fade" {
-					// Calculate fade-out progress (0.0 to 1.0)
+					// Fade out from the style of the current line to that of the other lines
 					progress := float64(m.animationStep) / float64(m.animationSteps)
-
-					// Interpolate between current style and other style
-					fgColor := interpolateColor(
-						m.uiConfig.Lyric.CurrentLineStyle.ForegroundColor,
-						m.uiConfig.Lyric.OtherLineStyle.ForegroundColor,
-						progress,
-					)
-
-					// Create a style with the interpolated color
-					fadeStyle := lipgloss.NewStyle().
-						Foreground(lipgloss.Color(fgColor)).
-						Width(m.width).
-						Align(lipgloss.Center)
-
-					if m.uiConfig.Lyric.CurrentLineStyle.Bold {
-						fadeStyle = fadeStyle.Bold(progress < 0.5)
-					}
-
-					sb.WriteString(fadeStyle.Render(line))
+					body.WriteString(m.alignPart(fadeStyle(m.uiConfig.Lyric, width, progress, false), part).Render(line))
 				} else if m.animationType == "slide" {
 					// Slide animation
 					slideDistance := m.uiConfig.Lyric.Animation.SlideDistance
@@ -304,34 +597,425 @@ func (m *LyricModel) View() string {
 
 					// Calculate padding based on progress
 					padding := int(float64(slideDistance) * progress)
-					paddedLine := strings.Repeat(" ", padding) + line
+					paddedLine := slidePad(line, padding, rtl)
 
-					sb.WriteString(prevStyle.Render(paddedLine))
+					body.WriteString(linePrevStyle.Render(paddedLine))
 				} else {
 					// No animation or unknown type
-					sb.WriteString(otherStyle.Render(line))
+					body.WriteString(lineOtherStyle.Render(line))
 				}
 			} else {
-				sb.WriteString(otherStyle.Render(line))
+				body.WriteString(lineOtherStyle.Render(line))
 			}
 		} else {
 			// No animation
-			if i == m.currentLineIdx {
-				sb.WriteString(currentStyle.Render(line))
+			if i == highlightIdx {
+				body.WriteString(lineCurrentStyle.Render(line))
 			} else {
-				sb.WriteString(otherStyle.Render(line))
+				body.WriteString(lineOtherStyle.Render(line))
 			}
 		}
 
-		sb.WriteString("\n")
+		// Show the translation beneath the line
+		if translated && i < len(m.translations) && m.translations[i] != "" {
+			translation := displayBidi(m.translations[i], m.uiConfig.Lyric.Bidi)
+			body.WriteString("\n" + lineOtherStyle.Faint(true).Italic(true).Render(translation))
+		}
+		m.lineRows = append(m.lineRows, lineRow{index: i, top: top, bottom: strings.Count(body.String(), "\n")})
+
+		body.WriteString(lineSeparator)
+
+		if interlude != "" && i == m.currentLineIdx {
+			body.WriteString(lineCurrentStyle.Render(interlude))
+			body.WriteString(lineSeparator)
+		}
 	}
 
+	sb.WriteString(strings.Repeat("\n", paddingTop))
+
+	// Center the few lines of the focused layout vertically
+	if focused {
+		padding := (bodyHeight - lipgloss.Height(strings.TrimRight(body.String(), "\n"))) / 2
+		sb.WriteString(strings.Repeat("\n", max(0, padding)))
+	}
+	bodyTop := strings.Count(sb.String(), "\n")
+	for i := range m.lineRows {
+		m.lineRows[i].top += bodyTop
+		m.lineRows[i].bottom += bodyTop
+	}
+	if margin > 0 {
+		sb.WriteString(lipgloss.NewStyle().MarginLeft(margin).Render(strings.TrimRight(body.String(), "\n")) + "\n")
+	} else {
+		sb.WriteString(body.String())
+	}
+	sb.WriteString(strings.Repeat("\n", paddingBottom))
+
 	// Add a footer
-	sb.WriteString("\nPress q to quit")
+	switch {
+	case m.palette.open:
+		sb.WriteString("\n" + m.palette.view(m.width))
+	case m.search.active():
+		sb.WriteString("\n" + m.searchFooter())
+	case m.kiosk:
+		if m.status != "" {
+			sb.WriteString("\n" + m.status)
+		}
+	case m.help:
+		sb.WriteString("\n" + m.keys.helpView(m.width, m.keyActions()...))
+	case m.status != "":
+		sb.WriteString("\n" + m.status + "  (press " + m.keys.hint(keyQuit) + " to quit)")
+	case m.unsynced():
+		sb.WriteString("\n" + m.keys.footer(keyQuit, keyUp, keyDown, keySearch, keyPalette))
+	case m.scrolling():
+		sb.WriteString("\nScrolled • " + m.keys.footer(keySelect, keyFollow, keyQuit))
+	default:
+		sb.WriteString("\n" + m.keys.footer(keyQuit, keySearch, keySnippet, keyPalette))
+	}
 
 	return sb.String()
 }
 
+// lyricLineStyles returns the styles of the current and the other lines of lyrics configured in
+// lyric, centered in width
+func lyricLineStyles(lyric config.LyricConfig, width int) (current, other lipgloss.Style) {
+	return applyStyleConfig(GetCurrentLineStyle(width), lyric.CurrentLineStyle),
+		applyStyleConfig(GetOtherLineStyle(width), lyric.OtherLineStyle)
+}
+
+// applyStyleConfig applies the colors and text attributes set in cfg to style
+func applyStyleConfig(style lipgloss.Style, cfg config.StyleConfig) lipgloss.Style {
+	if cfg.ForegroundColor != "" {
+		style = style.Foreground(lipgloss.Color(cfg.ForegroundColor))
+	}
+	if cfg.BackgroundColor != "" {
+		style = style.Background(lipgloss.Color(cfg.BackgroundColor))
+	}
+	if cfg.Bold {
+		style = style.Bold(true)
+	}
+	if cfg.Italic {
+		style = style.Italic(true)
+	}
+	if cfg.Underline {
+		style = style.Underline(true)
+	}
+	return style
+}
+
+// fadeStyle returns the style of a line fading in to the current line or out of it, progress
+// from 0 to 1 through the fade animation
+func fadeStyle(lyric config.LyricConfig, width int, progress float64, in bool) lipgloss.Style {
+	from, to := lyric.OtherLineStyle.ForegroundColor, lyric.CurrentLineStyle.ForegroundColor
+	bold := progress > 0.5
+	if !in {
+		from, to = to, from
+		bold = progress < 0.5
+	}
+
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(interpolateColor(from, to, progress))).
+		Width(width).
+		Align(lipgloss.Center)
+	if lyric.CurrentLineStyle.Bold {
+		style = style.Bold(bold)
+	}
+	return style
+}
+
+// header renders the title of the track with its album, elapsed and total time and a progress
+// bar, which advances with the interpolated progressMs between polls. It falls back to the
+// names of the lyrics when no track was reported and is empty until lyrics are loaded.
+func (m *LyricModel) header(titleStyle lipgloss.Style, width, progressMs int) string {
+	m.seekBar = seekBarArea{}
+	if m.lyrics == nil {
+		return ""
+	}
+
+	artist, name, album := m.lyrics.Artist, m.lyrics.Name, m.lyrics.Album
+	if m.track != nil && m.track.Title != "" {
+		artist, name, album = m.track.Artist, m.track.Title, m.track.Album
+	}
+	title := fmt.Sprintf("%s - %s", artist, name)
+	if m.lyrics.Estimated {
+		title += " (estimated timing)"
+	} else if !m.lyrics.Synced {
+		title += " (unsynced)"
+	}
+	lines := []string{titleStyle.Render(title)}
+
+	infoStyle := lipgloss.NewStyle().Faint(true).Width(m.width).Align(lipgloss.Center)
+	if album != "" {
+		lines = append(lines, infoStyle.Render(album))
+	}
+	if durationMs := m.clock.durationMs; durationMs > 0 {
+		progressMs = max(0, min(progressMs, durationMs))
+		elapsed, total := formatMs(progressMs), formatMs(durationMs)
+		barWidth := seekBarWidth(min(width, 60) - len(elapsed) - len(total) - 2)
+		bar := renderSeekBar(progressMs, durationMs, barWidth)
+
+		// The line is centered, with the elapsed time in front of the bar
+		lineWidth := len(elapsed) + 1 + barWidth + 1 + len(total)
+		m.seekBar = seekBarArea{
+			row:   lipgloss.Height(strings.Join(lines, "\n")),
+			left:  max(0, m.width-lineWidth)/2 + len(elapsed) + 1,
+			width: barWidth,
+		}
+		lines = append(lines, infoStyle.Render(elapsed+" "+bar+" "+total))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// karaoke reports whether the line at index i is highlighted word by word: in karaoke mode, for
+// lines with word timestamps, unless big text draws the whole line
+func (m *LyricModel) karaoke(i int) bool {
+	bigText := m.uiConfig.Lyric.BigText
+	return m.uiConfig.Lyric.Karaoke && (bigText == "" || bigText == "off") &&
+		m.lyrics != nil && i >= 0 && i < len(m.lyrics.Lines) && len(m.lyrics.Lines[i].Words) > 0
+}
+
+// karaokeText renders the line at index i with the words sung by progressMs in the color of the
+// current line and the words still to come in the color of the other lines
+func (m *LyricModel) karaokeText(i, progressMs int) string {
+	sung := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(m.uiConfig.Lyric.CurrentLineStyle.ForegroundColor)).
+		Bold(m.uiConfig.Lyric.CurrentLineStyle.Bold)
+	unsung := lipgloss.NewStyle().Foreground(lipgloss.Color(m.uiConfig.Lyric.OtherLineStyle.ForegroundColor))
+
+	// The part and text in front of the words, such as a bracketed ad-lib, count as sung
+	line := m.lyrics.Lines[i]
+	var words strings.Builder
+	for _, word := range line.Words {
+		words.WriteString(word.Text)
+	}
+	prefix := strings.TrimSuffix(line.Text, words.String())
+	if line.Part != "" {
+		prefix = line.Part + ": " + prefix
+	}
+
+	var b strings.Builder
+	if prefix != "" {
+		b.WriteString(sung.Render(m.transforms.apply(prefix, true)))
+	}
+	for _, word := range line.Words {
+		style := unsung
+		if word.StartTimeMs <= progressMs {
+			style = sung
+		}
+		b.WriteString(style.Render(m.transforms.apply(word.Text, true)))
+	}
+	return b.String()
+}
+
+// translationMsg is a message sent when the lyrics have been translated
+type translationMsg struct {
+	lyrics       *usecase.Lyrics
+	translations []string
+	err          error
+}
+
+// translate translates the lines of lyrics in the background
+func (m *LyricModel) translate(lyrics *usecase.Lyrics) tea.Cmd {
+	return func() tea.Msg {
+		translations, err := m.translation.TranslateLyrics(m.ctx, lyrics)
+		return translationMsg{lyrics: lyrics, translations: translations, err: err}
+	}
+}
+
+// unsynced reports whether the lyrics shown are unsynced ones without estimated timing
+func (m *LyricModel) unsynced() bool {
+	return m.lyrics != nil && !m.lyrics.Synced && !m.lyrics.Estimated
+}
+
+// lineRow is where a line of lyrics was drawn: from the top to the bottom row, which includes
+// its translation
+type lineRow struct {
+	index       int
+	top, bottom int
+}
+
+// lineAt returns the index of the line drawn at the given screen row, or -1 for none
+func (m *LyricModel) lineAt(y int) int {
+	for _, row := range m.lineRows {
+		if y >= row.top && y <= row.bottom {
+			return row.index
+		}
+	}
+	return -1
+}
+
+// scrolling reports whether synced lyrics are scrolled through instead of following the
+// current line
+func (m *LyricModel) scrolling() bool {
+	return !m.scrolledAt.IsZero()
+}
+
+// follow stops scrolling, centering the view on the current line again
+func (m *LyricModel) follow() {
+	m.scrolledAt = time.Time{}
+}
+
+// scrollDelta returns how many lines a scroll key moves: one, or half a screen for page keys
+func (m *LyricModel) scrollDelta(msg tea.KeyMsg) int {
+	page := max(1, m.height/2)
+	switch {
+	case m.keys.matches(msg, keyUp):
+		return -1
+	case m.keys.matches(msg, keyDown):
+		return 1
+	case m.keys.matches(msg, keyPageUp):
+		return -page
+	case m.keys.matches(msg, keyPageDown):
+		return page
+	}
+	return 0
+}
+
+// scrollBy scrolls the lyrics delta lines up or down. Scrolling synced lyrics starts from the
+// current line and stops following it until snapping back.
+func (m *LyricModel) scrollBy(delta int) {
+	if !m.unsynced() {
+		if !m.scrolling() {
+			m.scroll = max(0, m.currentLineIdx)
+		}
+		m.scrolledAt = time.Now()
+	}
+	m.scroll = max(0, min(m.scroll+delta, len(m.lines)-1))
+}
+
+// slidePad pads a line for the slide animation, sliding right-to-left lines in from the other side
+func slidePad(line string, padding int, rtl bool) string {
+	if rtl {
+		return line + strings.Repeat(" ", padding)
+	}
+	return strings.Repeat(" ", padding) + line
+}
+
+// Lyric layouts
+const (
+	layoutFull    = "full"
+	layoutFocused = "focused"
+)
+
+// focusedLineSpacing is the number of blank lines between lines in the focused layout
+const focusedLineSpacing = 2
+
+// scrollSnapBack is how long after the last scroll the view snaps back to the current line
+const scrollSnapBack = 5 * time.Second
+
+// karaokeInterval is how often the words of the current line are redrawn in karaoke mode
+const karaokeInterval = 50 * time.Millisecond
+
+// pulseInterval is how often the background pulse is redrawn
+const pulseInterval = 40 * time.Millisecond
+
+// pulseTickMsg is a message sent when the background pulse should be redrawn
+type pulseTickMsg time.Time
+
+// beatsMsg is a message sent when the beats of a track have been loaded
+type beatsMsg struct {
+	trackID string
+	beats   []int
+}
+
+// startPulse starts a background pulse of the current line
+func (m *LyricModel) startPulse() tea.Cmd {
+	m.pulseStart = time.Now()
+	return m.startPulseTick()
+}
+
+// startPulseTick starts redrawing the pulse unless it's already being redrawn
+func (m *LyricModel) startPulseTick() tea.Cmd {
+	if m.pulseTicking {
+		return nil
+	}
+	m.pulseTicking = true
+	return tea.Tick(pulseInterval, func(t time.Time) tea.Msg {
+		return pulseTickMsg(t)
+	})
+}
+
+// updatePulse starts a pulse when a beat has passed and keeps redrawing while
+// a pulse is fading or beats are being followed
+func (m *LyricModel) updatePulse() tea.Cmd {
+	m.pulseTicking = false
+
+	if len(m.beats) > 0 {
+		progressMs := m.clock.now()
+		if progressMs < m.lastBeatMs {
+			// Seeked backwards
+			m.lastBeatMs = progressMs
+		}
+		if i := sort.SearchInts(m.beats, m.lastBeatMs+1); i < len(m.beats) && m.beats[i] <= progressMs {
+			m.pulseStart = time.Now()
+		}
+		m.lastBeatMs = progressMs
+		return m.startPulseTick()
+	}
+
+	if m.pulseStrength() > 0 {
+		return m.startPulseTick()
+	}
+	return nil
+}
+
+// pulseStrength returns the current strength of the pulse, from 0 to the configured intensity
+func (m *LyricModel) pulseStrength() float64 {
+	pulse := m.uiConfig.Lyric.Pulse
+	if !pulse.Enabled || m.pulseStart.IsZero() || pulse.DurationMs <= 0 {
+		return 0
+	}
+
+	elapsed := time.Since(m.pulseStart)
+	duration := time.Duration(pulse.DurationMs) * time.Millisecond
+	if elapsed >= duration {
+		return 0
+	}
+
+	return pulse.Intensity * (1 - float64(elapsed)/float64(duration))
+}
+
+// fetchBeats loads the beats of a track; beat pulses are skipped if the audio analysis isn't available
+func (m *LyricModel) fetchBeats(trackID string) tea.Cmd {
+	return func() tea.Msg {
+		beats, err := m.playerUseCase.GetBeats(m.ctx, trackID)
+		if err != nil {
+			return nil
+		}
+		return beatsMsg{trackID: trackID, beats: beats}
+	}
+}
+
+// linePart returns the duet part of the line at index i, if any
+func (m *LyricModel) linePart(i int) string {
+	if m.lyrics == nil || i >= len(m.lyrics.Lines) {
+		return ""
+	}
+	return m.lyrics.Lines[i].Part
+}
+
+// colorPart applies the configured color of a duet part to a style
+func (m *LyricModel) colorPart(style lipgloss.Style, part string) lipgloss.Style {
+	if color, ok := m.uiConfig.Lyric.Duet.PartColors[part]; ok && color != "" {
+		return style.Foreground(lipgloss.Color(color))
+	}
+	return style
+}
+
+// alignPart moves the lines of a duet part to their column when columns are enabled:
+// the first voice on the left, the second on the right and lines sung together centered
+func (m *LyricModel) alignPart(style lipgloss.Style, part string) lipgloss.Style {
+	if !m.uiConfig.Lyric.Duet.Columns {
+		return style
+	}
+	switch part {
+	case "M", "V1":
+		return style.Align(lipgloss.Left)
+	case "F", "V2":
+		return style.Align(lipgloss.Right)
+	}
+	return style
+}
+
 // interpolateColor interpolates between two hex colors
 func interpolateColor(startColor, endColor string, progress float64) string {
 	// Parse hex colors
@@ -358,11 +1042,11 @@ func (m *LyricModel) waitForUpdate() tea.Msg {
 	select {
 	case update, ok := <-m.updateCh:
 		if !ok {
-			return tea.Quit
+			return tea.Quit()
 		}
 		return update
 	case <-m.ctx.Done():
-		return tea.Quit
+		return tea.Quit()
 	}
 }
 
@@ -382,17 +1066,91 @@ func min(a, b int) int {
 	return b
 }
 
-// RunLyricUI runs the lyric UI
-func RunLyricUI(ctx context.Context, startTimeMs int, playerUseCase usecase.PlayerUseCase) error {
-	model, err := NewLyricModel(ctx, startTimeMs, playerUseCase)
-	if err != nil {
-		return err
+// paletteActions lists the actions of the command palette: the keybindings of the lyric display,
+// controlling playback and switching between the themes of the UI config
+func (m *LyricModel) paletteActions() []paletteAction {
+	actions := []paletteAction{
+		{title: "Search lyrics", key: m.keys.key(keySearch)},
+		{title: "Save snippet", key: m.keys.key(keySnippet)},
 	}
+	if m.translation != nil {
+		actions = append(actions, paletteAction{title: "Show/hide translation", key: m.keys.key(keyTranslation)})
+	}
+	actions = append(actions, []paletteAction{
+		{title: "Shift lyrics earlier", key: m.keys.key(keyOffsetUp)},
+		{title: "Shift lyrics later", key: m.keys.key(keyOffsetDown)},
+		{title: "Show keybindings", key: m.keys.key(keyHelp)},
+		{title: "Play/pause", key: m.keys.key(keyPlayPause)},
+		{title: "Next track", run: m.playbackAction("Skipped to next track", m.playerUseCase.Next)},
+		{title: "Previous track", run: m.playbackAction("Back to previous track", m.playerUseCase.Previous)},
+	}...)
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		return err
+	names := make([]string, 0, len(m.uiConfig.Themes))
+	for name := range m.uiConfig.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		actions = append(actions, paletteAction{
+			title: "Change theme: " + name,
+			run: func() tea.Cmd {
+				m.status = "Theme " + name
+				if err := m.uiConfig.ApplyTheme(name); err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+				}
+				return nil
+			},
+		})
 	}
 
-	return nil
+	return append(actions, paletteAction{title: "Quit", key: m.keys.key(keyQuit)})
+}
+
+// keyActions lists the actions of the lyric display for its help view
+func (m *LyricModel) keyActions() []keyAction {
+	actions := []keyAction{keySearch, keySearchNext, keySearchPrev, keySnippet}
+	if m.translation != nil {
+		actions = append(actions, keyTranslation)
+	}
+	return append(actions, keyPlayPause, keyOffsetUp, keyOffsetDown, keyUp, keyDown, keyPageUp, keyPageDown,
+		keySelect, keyFollow, keyPalette, keyHelp, keyQuit)
+}
+
+// playPause returns a palette action pausing or resuming playback
+func (m *LyricModel) playPause() func() tea.Cmd {
+	if m.track != nil && m.track.IsPlaying {
+		return m.playbackAction("Paused", m.playerUseCase.Pause)
+	}
+	return m.playbackAction("Playing", m.playerUseCase.Play)
+}
+
+// playbackAction returns a palette action running the playback control in the background
+func (m *LyricModel) playbackAction(status string, control func(ctx context.Context) error) func() tea.Cmd {
+	return func() tea.Cmd {
+		return func() tea.Msg {
+			return paletteDoneMsg{status: status, err: control(m.ctx)}
+		}
+	}
+}
+
+// RunLyricUI runs the lyric UI for the updates of feed, with playback controlled by playerUseCase;
+// kiosk mode ignores every key but Ctrl+C and hides the key hints. A nil playerUseCase, for
+// replays, requires kiosk mode.
+func RunLyricUI(ctx context.Context, appConfig *config.Config, feed LyricFeed, playerUseCase usecase.PlayerUseCase, kiosk bool) error {
+	var model *LyricModel
+	defer func() {
+		if model != nil {
+			model.windowTitle.clear()
+		}
+	}()
+
+	_, err := runScreen(ctx, func(ctx context.Context) (tea.Model, []tea.ProgramOption, error) {
+		var err error
+		if model, err = newLyricModel(ctx, appConfig, feed, playerUseCase); err != nil {
+			return nil, nil, err
+		}
+		model.kiosk = kiosk
+		return model, []tea.ProgramOption{tea.WithAltScreen(), withMouse(appConfig)}, nil
+	})
+	return err
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
 )
 
 // MenuItem represents an item in the menu
@@ -15,26 +16,34 @@ type MenuItem struct {
 
 // MenuModel is the model for the menu UI
 type MenuModel struct {
-	items       []MenuItem
-	cursor      int
-	choice      string
+	items  []MenuItem
+	cursor int
+	choice string
+	// status reports on the item chosen last, e.g. why its screen couldn't open
+	status      string
 	quitting    bool
 	windowWidth int
+	keys        keyMap
 }
 
 // NewMenuModel creates a new menu model
-func NewMenuModel() *MenuModel {
+func NewMenuModel(appConfig *config.Config) *MenuModel {
 	return &MenuModel{
 		items: []MenuItem{
+			{title: "Player", description: "Control playback with an interactive player", command: "ui"},
 			{title: "Current Track", description: "Display information about the currently playing track", command: "current"},
 			{title: "Show Lyrics", description: "Display lyrics with a nice UI", command: "lyric show"},
 			{title: "Pipe Lyrics", description: "Display lyrics in the terminal", command: "lyric pipe"},
+			{title: "Search", description: "Search the Spotify catalog and play the results", command: "search"},
+			{title: "Playlists", description: "Browse and play your playlists", command: "playlist"},
+			{title: "Library", description: "Browse the tracks saved in your library", command: "library"},
 			{title: "Authenticate", description: "Initialize authentication with Spotify", command: "auth init"},
 			{title: "Version", description: "Display version information", command: "version"},
 			{title: "Quit", description: "Exit the application", command: "quit"},
 		},
 		cursor:      0,
 		windowWidth: 80,
+		keys:        newKeyMap(appConfig.Keybindings),
 	}
 }
 
@@ -47,24 +56,35 @@ func (m MenuModel) Init() tea.Cmd {
 func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "ctrl+c", "q":
+		switch {
+		case m.keys.matches(msg, keyQuit):
 			m.quitting = true
 			return m, tea.Quit
-		case "up", "k":
+		case m.keys.matches(msg, keyUp):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "down", "j":
+		case m.keys.matches(msg, keyDown):
 			if m.cursor < len(m.items)-1 {
 				m.cursor++
 			}
-		case "enter":
-			m.choice = m.items[m.cursor].command
-			if m.choice == "quit" {
-				m.quitting = true
+		case m.keys.matches(msg, keySelect):
+			return m.choose()
+		}
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.cursor = max(0, m.cursor-1)
+		case tea.MouseButtonWheelDown:
+			m.cursor = min(len(m.items)-1, m.cursor+1)
+		case tea.MouseButtonLeft:
+			if i := m.itemAt(msg.Y); i >= 0 {
+				m.cursor = i
+				return m.choose()
 			}
-			return m, tea.Quit
 		}
 	case tea.WindowSizeMsg:
 		m.windowWidth = msg.Width
@@ -73,6 +93,31 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// choose picks the item under the cursor
+func (m MenuModel) choose() (tea.Model, tea.Cmd) {
+	m.choice = m.items[m.cursor].command
+	if m.choice == "quit" {
+		m.quitting = true
+	}
+	return m, tea.Quit
+}
+
+// itemAt returns the index of the item drawn at the given screen row, or -1 for none. The items
+// follow the title and a blank line, the one under the cursor with its description beneath.
+func (m MenuModel) itemAt(y int) int {
+	row := 2
+	for i := range m.items {
+		if y == row {
+			return i
+		}
+		row++
+		if i == m.cursor {
+			row++
+		}
+	}
+	return -1
+}
+
 // View renders the model
 func (m MenuModel) View() string {
 	if m.quitting {
@@ -102,7 +147,11 @@ func (m MenuModel) View() string {
 		}
 	}
 
-	s += "\n" + normalStyle.Render("Press q to quit, up/down to navigate, enter to select")
+	if m.status != "" {
+		s += "\n" + descriptionStyle.Render(m.status) + "\n"
+	}
+	s += "\n" + normalStyle.Render(fmt.Sprintf("Press %s to quit, %s to navigate, %s to select",
+		m.keys.hint(keyQuit), m.keys.hint(keyUp, keyDown), m.keys.hint(keySelect)))
 
 	return s
 }
@@ -113,8 +162,8 @@ func (m MenuModel) GetChoice() string {
 }
 
 // RunMainMenu runs the main menu UI and returns the selected command
-func RunMainMenu() (string, error) {
-	p := tea.NewProgram(NewMenuModel(), tea.WithAltScreen())
+func RunMainMenu(appConfig *config.Config) (string, error) {
+	p := tea.NewProgram(NewMenuModel(appConfig), tea.WithAltScreen(), withMouse(appConfig))
 	model, err := p.Run()
 	if err != nil {
 		return "", err
@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteRows is the number of matching actions listed at once
+const paletteRows = 8
+
+// paletteAction is an action offered by the command palette
+type paletteAction struct {
+	title string
+	// key is the keybinding of the action, run by sending it to the screen and shown as a hint
+	key string
+	// run runs actions without a keybinding
+	run func() tea.Cmd
+}
+
+// paletteDoneMsg carries the result of an action run from the command palette
+type paletteDoneMsg struct {
+	status string
+	err    error
+}
+
+// commandPalette is an overlay for finding and running actions by name
+type commandPalette struct {
+	open    bool
+	query   string
+	cursor  int
+	actions []paletteAction
+	matches []paletteAction
+}
+
+// show opens the palette with the actions currently available
+func (p *commandPalette) show(actions []paletteAction) {
+	*p = commandPalette{open: true, actions: actions}
+	p.filter()
+}
+
+// handleKey handles key presses while the palette is open. It returns the chosen action once
+// one is picked with enter, or nil while typing or after closing the palette with esc.
+func (p *commandPalette) handleKey(msg tea.KeyMsg) *paletteAction {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlP:
+		p.open = false
+	case tea.KeyEnter:
+		p.open = false
+		if p.cursor < len(p.matches) {
+			return &p.matches[p.cursor]
+		}
+	case tea.KeyUp, tea.KeyCtrlK:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlJ:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(p.query); len(runes) > 0 {
+			p.query = string(runes[:len(runes)-1])
+			p.filter()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		p.query += string(msg.Runes)
+		p.filter()
+	}
+	return nil
+}
+
+// filter lists the actions matching the query, best match first
+func (p *commandPalette) filter() {
+	type scored struct {
+		action paletteAction
+		score  int
+	}
+	var found []scored
+	for _, action := range p.actions {
+		if score, ok := fuzzyScore(action.title, p.query); ok {
+			found = append(found, scored{action, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].score > found[j].score
+	})
+
+	p.matches = make([]paletteAction, len(found))
+	for i, f := range found {
+		p.matches[i] = f.action
+	}
+	p.cursor = 0
+}
+
+// view renders the palette with the query and the matching actions
+func (p *commandPalette) view(width int) string {
+	var sb strings.Builder
+	sb.WriteString(GetInputStyle().Render("> "+p.query+"█") + "\n")
+
+	// Keep the cursor in view
+	start := max(0, p.cursor-paletteRows+1)
+	end := min(len(p.matches), start+paletteRows)
+	for i := start; i < end; i++ {
+		action := p.matches[i]
+		line := action.title
+		if action.key != "" {
+			line += "  " + GetInfoStyle().Render(keyHint(action.key))
+		}
+		if i == p.cursor {
+			sb.WriteString(GetSelectedStyle().Render("> "+line) + "\n")
+		} else {
+			sb.WriteString(GetNormalStyle().Render("  "+line) + "\n")
+		}
+	}
+	if len(p.matches) == 0 {
+		sb.WriteString(GetInfoStyle().Render("  No matching action") + "\n")
+	}
+	sb.WriteString(GetInfoStyle().Render("↑/↓ select • enter run • esc close"))
+
+	return GetBorderStyle(width).Render(sb.String())
+}
+
+// fuzzyScore matches the query against the title as a case-insensitive subsequence. Matches at
+// the start of words and runs of consecutive letters score higher; ok is false without a match.
+func fuzzyScore(title, query string) (score int, ok bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return 0, true
+	}
+
+	titleRunes := []rune(strings.ToLower(title))
+	queryRunes := []rune(query)
+	q, previous := 0, -2
+	for i, r := range titleRunes {
+		if q == len(queryRunes) {
+			break
+		}
+		if r != queryRunes[q] {
+			continue
+		}
+
+		score++
+		if i == previous+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(titleRunes[i-1]) && !unicode.IsDigit(titleRunes[i-1]) {
+			score += 3
+		}
+		previous = i
+		q++
+	}
+	if q < len(queryRunes) {
+		return 0, false
+	}
+	// Shorter titles are closer matches
+	return score*100 - len(titleRunes), true
+}
+
+// keyHint names a keybinding for display
+func keyHint(key string) string {
+	switch key {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	default:
+		return key
+	}
+}
+
+// paletteKeyMsg builds the key press of an action's keybinding, to run it like the key was pressed
+func paletteKeyMsg(key string) tea.KeyMsg {
+	alt := false
+	if name, ok := strings.CutPrefix(key, "alt+"); ok && name != "" {
+		alt, key = true, name
+	}
+	if key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}
+	}
+
+	// Named keys such as "left" or "ctrl+u" have a key type of their own, numbered from 0 to 127
+	// for the control characters and below 0 for the others
+	for t := tea.KeyType(-100); t <= 127; t++ {
+		if t != tea.KeyRunes && (tea.Key{Type: t}).String() == key {
+			return tea.KeyMsg{Type: t, Alt: alt}
+		}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: alt}
+}
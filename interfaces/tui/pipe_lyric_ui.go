@@ -2,12 +2,17 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
 	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/notification"
+	"github.com/muhadif/sprt/infrastructure/sink"
 )
 
 // PipeLyricModel is the model for the pipe lyric UI
@@ -23,20 +28,79 @@ type PipeLyricModel struct {
 	err            error
 	quitting       bool
 	windowWidth    int
+	keys           keyMap
+	notifier       *notification.Notifier
+	notifyTrack    bool
+	lastTrack      string
+	idleTracker    *usecase.IdleTracker
+	idleAction     string
+	staleTracker   *usecase.IdleTracker
+	stoppedText    string
+	stopped        bool
+	lineText       string
+	sink           usecase.LyricSink
+	history        *usecase.LyricHistory
+	separator      bool
+	clock          progressClock
+	overlay        string
+	interludeMs    int
+	bidi           string
+	transforms     transformChain
+	cue            config.CueConfig
+	windowTitle    *windowTitle
+	// headless runs without drawing the display, while the stdout or ndjson sink prints the lines
+	headless bool
 }
 
-// NewPipeLyricModel creates a new pipe lyric model
-func NewPipeLyricModel(ctx context.Context, startTimeMs int, playerUseCase usecase.PlayerUseCase) (*PipeLyricModel, error) {
-	// Create the lyric use case
-	lyricUseCase := usecase.NewLyricUseCase()
+// playbackCheckInterval is how often the pipe UI checks for paused or stopped playback
+const playbackCheckInterval = time.Second
+
+// playbackCheckMsg is a message sent when the idle reminder and stale state should be checked
+type playbackCheckMsg time.Time
+
+// LyricFeed starts the lyric updates shown by the lyric UIs, which end with ctx
+type LyricFeed func(ctx context.Context) <-chan *usecase.LyricUpdate
+
+// NewPipeLyricModel creates a new pipe lyric model showing the updates of feed and publishing
+// them to the given sinks, or to the sinks of appConfig when sinks is nil
+func NewPipeLyricModel(ctx context.Context, appConfig *config.Config, feed LyricFeed, sinks *config.SinkConfig) (*PipeLyricModel, error) {
+	if sinks != nil {
+		withSinks := *appConfig
+		withSinks.Sinks = *sinks
+		appConfig = &withSinks
+	}
+	headless := appConfig.Sinks.Stdout || appConfig.Sinks.NDJSON
+
+	// Load the UI config for the lyric display options
+	uiConfig, err := loadUIConfig()
+	if err != nil {
+		uiConfig = config.DefaultUIConfig()
+	}
+
+	// Create the configured outputs; the TUI itself takes the place of the terminal sink
+	lyricSink, err := sink.New(appConfig, headless)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up lyric outputs: %w", err)
+	}
+
+	transforms, err := newTransformChain(lyricTransforms(appConfig))
+	if err != nil {
+		lyricSink.Close()
+		return nil, fmt.Errorf("failed to set up lyric transforms: %w", err)
+	}
+	windowTitle, err := newWindowTitle(appConfig.Title)
+	if err != nil {
+		lyricSink.Close()
+		return nil, fmt.Errorf("failed to set up the window title: %w", err)
+	}
 
 	// Create a context that can be cancelled
 	ctx, cancel := context.WithCancel(ctx)
 
 	// Get the lyric updates channel
-	updateCh := lyricUseCase.GetLyricChannel(ctx, startTimeMs, playerUseCase)
+	updateCh := feed(ctx)
 
-	return &PipeLyricModel{
+	model := &PipeLyricModel{
 		currentLine:    "Loading lyrics...",
 		currentLineIdx: -1,
 		width:          80,
@@ -45,44 +109,159 @@ func NewPipeLyricModel(ctx context.Context, startTimeMs int, playerUseCase useca
 		ctx:            ctx,
 		cancel:         cancel,
 		windowWidth:    80,
-	}, nil
+		keys:           newKeyMap(appConfig.Keybindings),
+		notifier:       notification.NewNotifier(appConfig.Notifications),
+		notifyTrack:    appConfig.Notifications.TrackChange && !appConfig.Notifications.Actions,
+		sink:           lyricSink,
+		history:        usecase.NewLyricHistory(appConfig.Output.HistorySize),
+		separator:      appConfig.Output.SongSeparator,
+		interludeMs:    uiConfig.Lyric.InterludeSeconds * 1000,
+		bidi:           uiConfig.Lyric.Bidi,
+		transforms:     transforms,
+		cue:            uiConfig.Lyric.Cue,
+		windowTitle:    windowTitle,
+		headless:       headless,
+	}
+
+	// Set up the paused-track reminder
+	if appConfig.IdleReminder.Enabled && appConfig.IdleReminder.Minutes > 0 {
+		model.idleTracker = usecase.NewIdleTracker(time.Duration(appConfig.IdleReminder.Minutes) * time.Minute)
+		model.idleAction = appConfig.IdleReminder.Action
+	}
+
+	// Set up the stale-state expiry
+	if appConfig.Output.StaleAfterSeconds > 0 {
+		model.staleTracker = usecase.NewIdleTracker(time.Duration(appConfig.Output.StaleAfterSeconds) * time.Second)
+		model.stoppedText = appConfig.Output.StoppedText
+	}
+
+	return model, nil
 }
 
 // Init initializes the model
 func (m *PipeLyricModel) Init() tea.Cmd {
-	return m.waitForUpdate
+	if m.idleTracker != nil || m.staleTracker != nil {
+		return tea.Batch(m.waitForUpdate, clockTick(), m.playbackCheck())
+	}
+	return tea.Batch(m.waitForUpdate, clockTick())
 }
 
 // Update updates the model
 func (m *PipeLyricModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		if m.keys.matches(msg, keyQuit) {
 			m.quitting = true
 			m.cancel()
 			return m, tea.Quit
 		}
 
+	case playbackCheckMsg:
+		now := time.Time(msg)
+		if m.idleTracker != nil && m.idleTracker.Due(now) {
+			m.remindIdle(now)
+		}
+		if m.staleTracker != nil && m.staleTracker.Due(now) {
+			m.markStopped()
+		}
+		return m, m.playbackCheck()
+
+	case clockTickMsg:
+		m.updateOverlay()
+		return m, clockTick()
+
 	case *usecase.LyricUpdate:
-		if msg.IsError {
-			m.err = fmt.Errorf(msg.ErrorMsg)
+		m.clock.observe(msg.Track)
+		var cue tea.Cmd
+
+		if msg.Track != nil && m.idleTracker != nil {
+			m.idleTracker.Observe(msg.Track.ID+msg.Track.Title, msg.Track.IsPlaying, time.Now())
+		}
+		if m.staleTracker != nil {
+			if msg.NothingPlaying {
+				m.staleTracker.Observe("", false, time.Now())
+			} else if msg.Track != nil {
+				m.staleTracker.Observe(msg.Track.ID+msg.Track.Title, msg.Track.IsPlaying, time.Now())
+			}
+		}
+		if msg.NothingPlaying {
+			m.windowTitle.show(nil)
+			m.writeTrack(nil)
+		} else if msg.Track != nil {
+			m.windowTitle.show(msg.Track)
+			m.writeTrack(msg.Track)
+		}
+
+		if msg.NothingPlaying {
+			// Nothing playing is an expected state, not a fatal error
+			if !m.stopped {
+				m.currentLine = msg.ErrorMsg
+			}
+		} else if msg.Lyrics == nil && msg.Track != nil && msg.Track.IsEpisode() {
+			// Podcasts have no lyrics, say so once per episode
+			m.stopped = false
+			m.lyrics = nil
+			m.overlay = ""
+			if m.lineText != msg.Text {
+				m.currentLine = msg.Text
+				m.lineText = msg.Text
+				m.write(msg.Text, msg.Track)
+			}
+		} else if msg.Lyrics == nil && msg.Track != nil && msg.Track.IsPlaying && m.stopped {
+			// Playback resumed on the same line, restore it
+			m.stopped = false
+			m.currentLine = m.lineText
+			m.write(m.lineText, msg.Track)
+			m.overlay = ""
+		} else if msg.IsError {
+			m.fail(errors.New(msg.ErrorMsg))
 			m.currentLine = fmt.Sprintf("Error: %s", msg.ErrorMsg)
 		} else if msg.Lyrics != nil {
+			// Remember the previous line, starting over when the track changes
+			if msg.Track != nil && msg.Track.Title != m.lastTrack {
+				m.history.Reset()
+			} else if m.lyrics != nil && m.currentLineIdx >= 0 && m.currentLineIdx < len(m.lyrics.Lines) && m.currentLineIdx != msg.LineIndex {
+				m.history.Add(m.currentLineIdx, m.lyrics.Lines[m.currentLineIdx])
+			}
+
+			// Sound the cue for practicing without watching the screen
+			if msg.Lyrics != m.lyrics || msg.LineIndex != m.currentLineIdx {
+				cue = lineCue(m.cue, msg.Lyrics, msg.LineIndex, m.interludeMs)
+			}
+
+			m.stopped = false
 			m.lyrics = msg.Lyrics
 			m.currentLineIdx = msg.LineIndex
-			m.currentLine = msg.Text
+			text := m.transforms.apply(msg.Text, true)
+			m.currentLine = text
+			m.lineText = text
+
+			// Announce track changes with a desktop notification
+			if msg.Track != nil && msg.Track.Title != m.lastTrack {
+				if m.lastTrack != "" && m.notifyTrack {
+					track := *msg.Track
+					go m.notifier.Notify("Now playing", fmt.Sprintf("%s — %s", track.Title, track.Artist))
+				}
+				m.lastTrack = msg.Track.Title
 
-			// Write the current line to a file for external use
-			if msg.Text != "" {
-				err := os.WriteFile("/tmp/current-lyric.txt", []byte(msg.Text), 0644)
-				if err != nil {
-					m.err = fmt.Errorf("error writing to file: %v", err)
+				// Delimit the songs in streaming outputs
+				if m.separator {
+					separator := usecase.LyricOutput{Text: usecase.SongSeparator(msg.Track), ProgressMs: m.clock.now(), Track: msg.Track, Separator: true}
+					if err := m.sink.Write(separator); err != nil {
+						m.fail(err)
+					}
 				}
 			}
+
+			// Publish the current line for external use, unless an indicator takes its place
+			m.overlay = ""
+			m.updateOverlay()
+			if m.overlay == "" && text != "" {
+				m.write(text, msg.Track)
+			}
 		}
 
-		return m, m.waitForUpdate
+		return m, tea.Batch(m.waitForUpdate, cue)
 	case tea.WindowSizeMsg:
 		m.windowWidth = msg.Width
 	}
@@ -119,39 +298,138 @@ func (m *PipeLyricModel) View() string {
 	}
 
 	// Display the current line
-	sb.WriteString(currentLineStyle.Render(m.currentLine))
+	sb.WriteString(currentLineStyle.Render(displayBidi(m.currentLine, m.bidi)))
 	sb.WriteString("\n\n")
 
 	// Add a footer
-	sb.WriteString(infoStyle.Render("Press q to quit"))
+	sb.WriteString(infoStyle.Render("Press " + m.keys.hint(keyQuit) + " to quit"))
 
 	return sb.String()
 }
 
+// playbackCheck schedules the next idle reminder and stale state check
+func (m *PipeLyricModel) playbackCheck() tea.Cmd {
+	return tea.Tick(playbackCheckInterval, func(t time.Time) tea.Msg {
+		return playbackCheckMsg(t)
+	})
+}
+
+// markStopped switches the outputs to the explicit stopped state
+func (m *PipeLyricModel) markStopped() {
+	m.stopped = true
+	m.currentLine = "⏹ Stopped"
+	m.write(m.stoppedText, nil)
+}
+
+// remindIdle performs the configured idle reminder action
+func (m *PipeLyricModel) remindIdle(now time.Time) {
+	minutes := int(m.idleTracker.PausedFor(now).Minutes())
+
+	if m.idleAction == "notify" || m.idleAction == "both" {
+		go m.notifier.Notify("Playback paused", fmt.Sprintf("%s has been paused for %d minutes", m.lastTrack, minutes))
+	}
+
+	if m.idleAction == "clear" || m.idleAction == "both" {
+		// Clear the output so status bars don't show stale "now playing" data
+		m.currentLine = "Paused"
+		m.write("", nil)
+	}
+}
+
+// updateOverlay shows the intro countdown or the interlude indicator in place of
+// the current line while one applies, and restores the line afterwards
+func (m *PipeLyricModel) updateOverlay() {
+	progressMs := m.clock.now()
+	overlay := usecase.IntroCountdown(m.lyrics, progressMs)
+	if overlay == "" {
+		overlay = usecase.Interlude(m.lyrics, m.currentLineIdx, progressMs, m.clock.durationMs, m.interludeMs)
+	}
+
+	if overlay == m.overlay || m.stopped {
+		return
+	}
+	m.overlay = overlay
+
+	if overlay == "" {
+		// The indicator is over, show the line again
+		m.currentLine = m.lineText
+		m.write(m.lineText, nil)
+		return
+	}
+
+	m.currentLine = overlay
+	m.write(overlay, nil)
+}
+
+// write publishes a line to the configured sinks
+func (m *PipeLyricModel) write(text string, track *usecase.CurrentlyPlaying) {
+	output := usecase.LyricOutput{
+		Text:       text,
+		LineIndex:  m.currentLineIdx,
+		ProgressMs: m.clock.now(),
+		Track:      track,
+		History:    m.history.Lines(),
+	}
+	if text != "" && m.overlay == "" && !m.stopped && m.lyrics != nil && m.currentLineIdx >= 0 && m.currentLineIdx < len(m.lyrics.Lines) {
+		line := m.lyrics.Lines[m.currentLineIdx]
+		output.Line = &line
+	}
+	if err := m.sink.Write(output); err != nil {
+		m.fail(err)
+	}
+}
+
+// writeTrack publishes the playing track to the sinks following it
+func (m *PipeLyricModel) writeTrack(track *usecase.CurrentlyPlaying) {
+	if trackSink, ok := m.sink.(usecase.TrackSink); ok {
+		if err := trackSink.WriteTrack(track); err != nil {
+			m.fail(err)
+		}
+	}
+}
+
+// fail shows the error in place of the display, or reports it on stderr without a display
+func (m *PipeLyricModel) fail(err error) {
+	if m.headless {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+	m.err = err
+}
+
 // waitForUpdate waits for an update from the lyric channel
 func (m *PipeLyricModel) waitForUpdate() tea.Msg {
 	select {
 	case update, ok := <-m.updateCh:
 		if !ok {
-			return tea.Quit
+			return tea.Quit()
 		}
 		return update
 	case <-m.ctx.Done():
-		return tea.Quit
+		return tea.Quit()
 	}
 }
 
-// RunPipeLyricUI runs the pipe lyric UI
-func RunPipeLyricUI(ctx context.Context, startTimeMs int, playerUseCase usecase.PlayerUseCase) error {
-	model, err := NewPipeLyricModel(ctx, startTimeMs, playerUseCase)
-	if err != nil {
-		return err
-	}
-
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		return err
-	}
+// RunPipeLyricUI runs the pipe lyric UI for the updates of feed, publishing to the given sinks or to the
+// sinks of the config when sinks is nil. With the stdout or ndjson sink the display isn't drawn, leaving stdout to the lines.
+func RunPipeLyricUI(ctx context.Context, appConfig *config.Config, feed LyricFeed, sinks *config.SinkConfig) error {
+	var model *PipeLyricModel
+	defer func() {
+		if model != nil {
+			model.windowTitle.clear()
+			model.sink.Close()
+		}
+	}()
 
-	return nil
+	_, err := runScreen(ctx, func(ctx context.Context) (tea.Model, []tea.ProgramOption, error) {
+		var err error
+		if model, err = NewPipeLyricModel(ctx, appConfig, feed, sinks); err != nil {
+			return nil, nil, err
+		}
+		if model.headless {
+			return model, []tea.ProgramOption{tea.WithoutRenderer(), tea.WithInput(nil)}, nil
+		}
+		return model, []tea.ProgramOption{tea.WithAltScreen()}, nil
+	})
+	return err
 }
@@ -0,0 +1,430 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// seekStepMs is how far the left/right keys move the seek bar
+const seekStepMs = 10000
+
+// volumeStep is how much the +/- keys change the volume
+const volumeStep = 10
+
+// PlayerModel is the model for the interactive player UI
+type PlayerModel struct {
+	appConfig     *config.Config
+	playerUseCase usecase.PlayerUseCase
+	state         *usecase.PlaybackState
+	polledAt      time.Time
+	status        string
+	err           error
+	quitting      bool
+	windowWidth   int
+	// kiosk ignores the playback controls and hides the key hints, for public displays
+	kiosk   bool
+	palette commandPalette
+	keys    keyMap
+	help    bool
+	// seekBar is where the seek bar was last drawn, to seek to the position clicked
+	seekBar seekBarArea
+	// parent is the context the player was opened with, handed on to the lyric UI; ctx ends
+	// with the player, cancelling its requests
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// playerTickMsg is a message sent when the player should poll the playback state
+type playerTickMsg struct{}
+
+// playbackStateMsg carries the result of polling the playback state
+type playbackStateMsg struct {
+	state *usecase.PlaybackState
+	err   error
+}
+
+// playerActionMsg carries the result of a playback control action
+type playerActionMsg struct {
+	status string
+	err    error
+}
+
+// NewPlayerModel creates a new player model
+func NewPlayerModel(parent context.Context, appConfig *config.Config, playerUseCase usecase.PlayerUseCase) *PlayerModel {
+	ctx, cancel := context.WithCancel(parent)
+	return &PlayerModel{
+		playerUseCase: playerUseCase,
+		status:        "Loading playback state...",
+		windowWidth:   80,
+		appConfig:     appConfig,
+		keys:          newKeyMap(appConfig.Keybindings),
+		parent:        parent,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Init initializes the model
+func (m *PlayerModel) Init() tea.Cmd {
+	return tea.Batch(m.poll, m.tick())
+}
+
+// Update updates the model
+func (m *PlayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.kiosk && msg.String() != "ctrl+c" && !m.keys.matches(msg, keyLyrics) {
+			return m, nil
+		}
+		if m.palette.open && msg.String() != "ctrl+c" {
+			if action := m.palette.handleKey(msg); action != nil {
+				if action.key == "" {
+					return m, action.run()
+				}
+				return m.Update(paletteKeyMsg(action.key))
+			}
+			return m, nil
+		}
+
+		switch {
+		case m.keys.matches(msg, keyPalette):
+			m.palette.show(m.paletteActions())
+		case m.keys.matches(msg, keyHelp):
+			m.help = !m.help
+		case m.help && m.keys.matches(msg, keyBack):
+			m.help = false
+		case m.keys.matches(msg, keyQuit, keyBack):
+			m.quitting = true
+			m.cancel()
+			return m, tea.Quit
+		case m.keys.matches(msg, keyPlayPause):
+			if m.state != nil && m.state.IsPlaying {
+				return m, m.action("Paused", m.playerUseCase.Pause)
+			}
+			return m, m.action("Playing", m.playerUseCase.Play)
+		case m.keys.matches(msg, keyNextTrack):
+			return m, m.action("Skipped to next track", m.playerUseCase.Next)
+		case m.keys.matches(msg, keyPreviousTrack):
+			return m, m.action("Skipped to previous track", m.playerUseCase.Previous)
+		case m.keys.matches(msg, keyVolumeUp):
+			return m, m.changeVolume(volumeStep)
+		case m.keys.matches(msg, keyVolumeDown):
+			return m, m.changeVolume(-volumeStep)
+		case m.keys.matches(msg, keyShuffle):
+			if m.state == nil {
+				return m, nil
+			}
+			shuffle := !m.state.ShuffleState
+			return m, m.action(fmt.Sprintf("Shuffle %s", onOff(shuffle)), func(ctx context.Context) error {
+				return m.playerUseCase.SetShuffle(ctx, shuffle)
+			})
+		case m.keys.matches(msg, keyRepeat):
+			if m.state == nil {
+				return m, nil
+			}
+			mode := nextRepeatMode(m.state.RepeatState)
+			return m, m.action(fmt.Sprintf("Repeat %s", mode), func(ctx context.Context) error {
+				return m.playerUseCase.SetRepeat(ctx, mode)
+			})
+		case m.keys.matches(msg, keySeekBackward):
+			return m, m.seekBy(-seekStepMs)
+		case m.keys.matches(msg, keySeekForward):
+			return m, m.seekBy(seekStepMs)
+		case m.keys.matches(msg, keyLyrics):
+			// Hand over to the lyric UI, starting from the interpolated position; the polls
+			// and actions of the player still running are cancelled
+			lyricModel, err := NewLyricModel(m.parent, m.appConfig, m.progressMs(), m.playerUseCase)
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			lyricModel.kiosk = m.kiosk
+			m.cancel()
+			return lyricModel, lyricModel.Init()
+		}
+
+	case tea.MouseMsg:
+		// Seek to the position clicked on the seek bar
+		if m.kiosk || m.palette.open || m.state == nil || msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return m, nil
+		}
+		if position, ok := m.seekBar.positionAt(msg.X, msg.Y, m.state.DurationMs); ok {
+			return m, m.seekBy(position - m.progressMs())
+		}
+
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+
+	case playerTickMsg:
+		return m, tea.Batch(m.poll, m.tick())
+
+	case playbackStateMsg:
+		if msg.err != nil {
+			if errors.Is(msg.err, usecase.ErrNothingPlaying) {
+				m.state = nil
+				m.status = "No track currently playing"
+				m.err = nil
+			} else {
+				m.err = msg.err
+			}
+			return m, nil
+		}
+		m.state = msg.state
+		m.polledAt = time.Now()
+		m.err = nil
+		if m.status == "Loading playback state..." {
+			m.status = ""
+		}
+
+	case playerActionMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.status = msg.status
+		// Refresh right away so the UI reflects the change
+		return m, m.poll
+	}
+
+	return m, nil
+}
+
+// View renders the model
+func (m *PlayerModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	// Get styles from the shared styles
+	titleStyle := GetTitleStyle(m.windowWidth)
+	headerStyle := GetHeaderStyle()
+	valueStyle := GetValueStyle()
+	infoStyle := GetInfoStyle()
+	border := GetBorderStyle(m.windowWidth)
+
+	// Build the view
+	s := titleStyle.Render("sprt Player") + "\n\n"
+
+	content := ""
+	m.seekBar = seekBarArea{}
+	if m.err != nil {
+		content += headerStyle.Render("Error: ") + valueStyle.Render(m.err.Error()) + "\n"
+	} else if m.state == nil {
+		content += valueStyle.Render(m.status) + "\n"
+	} else {
+		state := m.state
+		status := "Paused"
+		if state.IsPlaying {
+			status = "Playing"
+		}
+
+		content += headerStyle.Render("Title: ") + valueStyle.Render(state.Title) + "\n"
+		if state.IsEpisode() {
+			content += headerStyle.Render("Show: ") + valueStyle.Render(state.Show) + "\n\n"
+		} else {
+			content += headerStyle.Render("Artist: ") + valueStyle.Render(state.Artist) + "\n"
+			content += headerStyle.Render("Album: ") + valueStyle.Render(state.Album) + "\n\n"
+		}
+
+		// Seek bar, below the title and the border and padding of the box around the content
+		progressMs := m.progressMs()
+		m.seekBar = seekBarArea{
+			row:   strings.Count(s, "\n") + 2 + strings.Count(content, "\n"),
+			left:  3,
+			width: seekBarWidth(m.windowWidth - 24),
+		}
+		content += valueStyle.Render(renderSeekBar(progressMs, state.DurationMs, m.windowWidth-24)) + " " +
+			valueStyle.Render(formatMs(progressMs)+" / "+formatMs(state.DurationMs)) + "\n\n"
+
+		content += headerStyle.Render("Status: ") + valueStyle.Render(status) + "  "
+		content += headerStyle.Render("Shuffle: ") + valueStyle.Render(onOff(state.ShuffleState)) + "  "
+		content += headerStyle.Render("Repeat: ") + valueStyle.Render(state.RepeatState) + "  "
+		content += headerStyle.Render("Volume: ") + valueStyle.Render(fmt.Sprintf("%d%%", state.Device.VolumePercent)) + "\n"
+		if state.Device.Name != "" {
+			content += headerStyle.Render("Device: ") + valueStyle.Render(state.Device.Name) + "\n"
+		}
+		if m.status != "" {
+			content += "\n" + infoStyle.Render(m.status)
+		}
+	}
+
+	s += border.Render(content)
+	if m.kiosk {
+		return s
+	}
+	if m.palette.open {
+		return s + "\n" + m.palette.view(m.windowWidth)
+	}
+	if m.help {
+		return s + "\n" + m.keys.helpView(m.windowWidth, playerKeyActions...)
+	}
+	s += "\n\n" + infoStyle.Render(m.keys.footer(keyPlayPause, keyNextTrack, keyPreviousTrack, keySeekBackward, keySeekForward,
+		keyVolumeUp, keyVolumeDown, keyShuffle, keyRepeat, keyLyrics, keyPalette, keyQuit))
+
+	return s
+}
+
+// paletteActions lists the actions of the command palette, which run like their keys were pressed
+func (m *PlayerModel) paletteActions() []paletteAction {
+	return []paletteAction{
+		{title: "Play/pause", key: m.keys.key(keyPlayPause)},
+		{title: "Next track", key: m.keys.key(keyNextTrack)},
+		{title: "Previous track", key: m.keys.key(keyPreviousTrack)},
+		{title: "Seek backward 10 seconds", key: m.keys.key(keySeekBackward)},
+		{title: "Seek forward 10 seconds", key: m.keys.key(keySeekForward)},
+		{title: "Volume up", key: m.keys.key(keyVolumeUp)},
+		{title: "Volume down", key: m.keys.key(keyVolumeDown)},
+		{title: "Toggle shuffle", key: m.keys.key(keyShuffle)},
+		{title: "Cycle repeat mode", key: m.keys.key(keyRepeat)},
+		{title: "Open lyrics", key: m.keys.key(keyLyrics)},
+		{title: "Show keybindings", key: m.keys.key(keyHelp)},
+		{title: "Quit", key: m.keys.key(keyQuit)},
+	}
+}
+
+// playerKeyActions are the actions of the player, listed in its help view
+var playerKeyActions = []keyAction{
+	keyPlayPause, keyNextTrack, keyPreviousTrack, keySeekBackward, keySeekForward, keyVolumeUp,
+	keyVolumeDown, keyShuffle, keyRepeat, keyLyrics, keyPalette, keyHelp, keyQuit, keyBack,
+}
+
+// progressMs returns the playback position, interpolated since the last poll
+func (m *PlayerModel) progressMs() int {
+	if m.state == nil {
+		return 0
+	}
+
+	progress := m.state.ProgressMs
+	if m.state.IsPlaying && !m.polledAt.IsZero() {
+		progress += int(time.Since(m.polledAt).Milliseconds())
+	}
+	if m.state.DurationMs > 0 && progress > m.state.DurationMs {
+		progress = m.state.DurationMs
+	}
+
+	return progress
+}
+
+// poll fetches the current playback state
+func (m *PlayerModel) poll() tea.Msg {
+	state, err := m.playerUseCase.GetPlaybackState(m.ctx)
+	return playbackStateMsg{state: state, err: err}
+}
+
+// tick schedules the next poll
+func (m *PlayerModel) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return playerTickMsg{}
+	})
+}
+
+// action runs a playback control function and reports the result
+func (m *PlayerModel) action(status string, fn func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		return playerActionMsg{status: status, err: fn(m.ctx)}
+	}
+}
+
+// changeVolume adjusts the volume of the active device by delta percent
+func (m *PlayerModel) changeVolume(delta int) tea.Cmd {
+	if m.state == nil {
+		return nil
+	}
+
+	volume := max(0, min(100, m.state.Device.VolumePercent+delta))
+	m.state.Device.VolumePercent = volume
+	return m.action(fmt.Sprintf("Volume %d%%", volume), func(ctx context.Context) error {
+		return m.playerUseCase.SetVolume(ctx, volume)
+	})
+}
+
+// seekBy moves the playback position by deltaMs
+func (m *PlayerModel) seekBy(deltaMs int) tea.Cmd {
+	if m.state == nil {
+		return nil
+	}
+
+	position := max(0, m.progressMs()+deltaMs)
+	if m.state.DurationMs > 0 {
+		position = min(position, m.state.DurationMs)
+	}
+
+	// Move the seek bar immediately instead of waiting for the next poll
+	m.state.ProgressMs = position
+	m.polledAt = time.Now()
+
+	return m.action(fmt.Sprintf("Seeked to %s", formatMs(position)), func(ctx context.Context) error {
+		return m.playerUseCase.Seek(ctx, position)
+	})
+}
+
+// seekBarArea is where a seek bar was drawn: the screen row and the columns from left on
+type seekBarArea struct {
+	row, left, width int
+}
+
+// positionAt returns the position in a track of durationMs clicked at column x of row y, with ok
+// false for clicks beside the bar
+func (a seekBarArea) positionAt(x, y, durationMs int) (positionMs int, ok bool) {
+	if a.width <= 0 || durationMs <= 0 || y != a.row || x < a.left || x >= a.left+a.width {
+		return 0, false
+	}
+	return (x - a.left) * durationMs / a.width, true
+}
+
+// seekBarWidth returns the width of a seek bar drawn with the given width, which has a minimum
+func seekBarWidth(width int) int {
+	return max(10, width)
+}
+
+// renderSeekBar renders a seek bar of the given width
+func renderSeekBar(progressMs, durationMs, width int) string {
+	width = seekBarWidth(width)
+
+	progress := 0.0
+	if durationMs > 0 {
+		progress = float64(progressMs) / float64(durationMs)
+	}
+	position := min(width-1, int(float64(width)*progress))
+
+	return strings.Repeat("━", position) + "●" + strings.Repeat("─", width-position-1)
+}
+
+// nextRepeatMode returns the repeat mode that follows the given one
+func nextRepeatMode(mode string) string {
+	switch mode {
+	case "off":
+		return "context"
+	case "context":
+		return "track"
+	default:
+		return "off"
+	}
+}
+
+// onOff formats a boolean as "on" or "off"
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// RunPlayerUI runs the interactive player UI. In kiosk mode it only shows what's playing:
+// the playback controls are disabled, the key hints are hidden and only Ctrl+C quits.
+func RunPlayerUI(ctx context.Context, appConfig *config.Config, playerUseCase usecase.PlayerUseCase, kiosk bool) error {
+	_, err := runScreen(ctx, func(ctx context.Context) (tea.Model, []tea.ProgramOption, error) {
+		model := NewPlayerModel(ctx, appConfig, playerUseCase)
+		model.kiosk = kiosk
+		return model, []tea.ProgramOption{tea.WithAltScreen(), withMouse(appConfig)}, nil
+	})
+	return err
+}
@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// playlistChromeRows is the number of rows taken by the title, header and help text
+const playlistChromeRows = 7
+
+// PlaylistModel is the model for browsing and playing the user's playlists
+type PlaylistModel struct {
+	playerUseCase   usecase.PlayerUseCase
+	playlistUseCase usecase.PlaylistUseCase
+	playlists       []usecase.Playlist
+	loading         bool
+	cursor          int
+	top             int
+	status          string
+	err             error
+	quitting        bool
+	windowWidth     int
+	windowHeight    int
+	keys            keyMap
+	help            bool
+	ctx             context.Context
+	cancel          context.CancelFunc
+}
+
+// playlistsMsg carries the playlists of the user
+type playlistsMsg struct {
+	playlists []usecase.Playlist
+	err       error
+}
+
+// playlistActionMsg carries the result of an action on a playlist
+type playlistActionMsg struct {
+	status string
+	err    error
+}
+
+// NewPlaylistModel creates a new playlist model
+func NewPlaylistModel(ctx context.Context, appConfig *config.Config, playerUseCase usecase.PlayerUseCase, playlistUseCase usecase.PlaylistUseCase) *PlaylistModel {
+	ctx, cancel := context.WithCancel(ctx)
+	return &PlaylistModel{
+		playerUseCase:   playerUseCase,
+		playlistUseCase: playlistUseCase,
+		status:          "Loading playlists...",
+		windowWidth:     80,
+		windowHeight:    24,
+		keys:            newKeyMap(appConfig.Keybindings),
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// Init initializes the model
+func (m *PlaylistModel) Init() tea.Cmd {
+	m.loading = true
+	return m.load
+}
+
+// Update updates the model
+func (m *PlaylistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case m.keys.matches(msg, keyHelp):
+			m.help = !m.help
+		case m.help && m.keys.matches(msg, keyBack):
+			m.help = false
+		case m.keys.matches(msg, keyQuit, keyBack):
+			m.quitting = true
+			m.cancel()
+			return m, tea.Quit
+		case m.keys.matches(msg, keyUp):
+			m.moveCursor(-1)
+		case m.keys.matches(msg, keyDown):
+			m.moveCursor(1)
+		case m.keys.matches(msg, keyPageUp):
+			m.moveCursor(-m.visibleRows())
+		case m.keys.matches(msg, keyPageDown):
+			m.moveCursor(m.visibleRows())
+		case m.keys.matches(msg, keyTop):
+			m.moveCursor(-len(m.playlists))
+		case m.keys.matches(msg, keyBottom):
+			m.moveCursor(len(m.playlists))
+		case m.keys.matches(msg, keySelect):
+			return m, m.play()
+		}
+
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.moveCursor(-1)
+		case tea.MouseButtonWheelDown:
+			m.moveCursor(1)
+		case tea.MouseButtonLeft:
+			// Select the playlist clicked, and play it when it was already selected
+			i := m.playlistAt(msg.Y)
+			if i < 0 {
+				return m, nil
+			}
+			if i == m.cursor {
+				return m, m.play()
+			}
+			m.moveCursor(i - m.cursor)
+		}
+
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		m.moveCursor(0)
+
+	case playlistsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.playlists = msg.playlists
+		m.status = ""
+		m.moveCursor(0)
+
+	case playlistActionMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.status = msg.status
+	}
+
+	return m, nil
+}
+
+// View renders the model
+func (m *PlaylistModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	// Get styles from the shared styles
+	titleStyle := GetTitleStyle(m.windowWidth)
+	headerStyle := GetHeaderStyle()
+	valueStyle := GetValueStyle()
+	selectedStyle := GetSelectedStyle()
+	infoStyle := GetInfoStyle()
+
+	// Build the view
+	s := titleStyle.Render("sprt Playlists") + "\n\n"
+
+	if m.err != nil {
+		s += headerStyle.Render("Error: ") + valueStyle.Render(m.err.Error()) + "\n"
+		return s
+	}
+
+	s += headerStyle.Render(fmt.Sprintf("Your playlists (%d)", len(m.playlists))) + "\n\n"
+
+	if len(m.playlists) == 0 && !m.loading {
+		s += valueStyle.Render("You have no playlists.") + "\n"
+	}
+
+	end := min(m.top+m.visibleRows(), len(m.playlists))
+	for i := m.top; i < end; i++ {
+		playlist := m.playlists[i]
+		line := truncateText(fmt.Sprintf("%4d  %s  %d tracks · %s", i+1, playlist.Name, playlist.TrackCount, playlist.Owner), m.windowWidth-2)
+		if i == m.cursor {
+			s += "> " + selectedStyle.Render(line) + "\n"
+		} else {
+			s += "  " + valueStyle.Render(line) + "\n"
+		}
+	}
+
+	s += "\n"
+	if m.status != "" {
+		s += infoStyle.Render(m.status)
+	}
+	if m.help {
+		return s + "\n" + m.keys.helpView(m.windowWidth, keyUp, keyDown, keyPageUp, keyPageDown, keyTop, keyBottom,
+			keySelect, keyHelp, keyQuit, keyBack)
+	}
+	s += "\n" + infoStyle.Render(m.keys.footer(keyUp, keyDown, keySelect, keyQuit))
+
+	return s
+}
+
+// playlistAt returns the index of the playlist drawn at the given screen row, or -1 for none.
+// The playlists start below the title and the count of playlists, both followed by a blank line.
+func (m *PlaylistModel) playlistAt(y int) int {
+	if m.err != nil {
+		return -1
+	}
+	i := m.top + y - 4
+	if y < 4 || i >= min(m.top+m.visibleRows(), len(m.playlists)) {
+		return -1
+	}
+	return i
+}
+
+// visibleRows returns the number of playlists that fit on screen
+func (m *PlaylistModel) visibleRows() int {
+	return max(1, m.windowHeight-playlistChromeRows)
+}
+
+// moveCursor moves the cursor by delta playlists and scrolls it into view
+func (m *PlaylistModel) moveCursor(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.playlists)-1))
+
+	rows := m.visibleRows()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	} else if m.cursor >= m.top+rows {
+		m.top = m.cursor - rows + 1
+	}
+	m.top = max(0, m.top)
+}
+
+// load fetches the playlists of the user
+func (m *PlaylistModel) load() tea.Msg {
+	playlists, err := m.playlistUseCase.ListPlaylists(m.ctx)
+	return playlistsMsg{playlists: playlists, err: err}
+}
+
+// play starts playing the playlist under the cursor
+func (m *PlaylistModel) play() tea.Cmd {
+	if m.cursor < 0 || m.cursor >= len(m.playlists) {
+		return nil
+	}
+	playlist := m.playlists[m.cursor]
+	return func() tea.Msg {
+		err := m.playerUseCase.PlayURI(m.ctx, playlist.URI)
+		return playlistActionMsg{status: "Playing " + playlist.Name, err: err}
+	}
+}
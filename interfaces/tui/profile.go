@@ -0,0 +1,25 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/infrastructure/persistence/jsonfile"
+)
+
+// loadUIConfig loads the UI configuration with the theme of the active time-of-day profile applied.
+func loadUIConfig() (*config.UIConfig, error) {
+	uiConfig, err := config.LoadUIConfig()
+	if err != nil {
+		return uiConfig, err
+	}
+
+	profile, err := jsonfile.NewProfileRepository("").LoadActiveProfile(context.Background())
+	if err == nil && profile.ActiveAt(time.Now()) && profile.Theme != "" {
+		// An unknown theme keeps the configured styles
+		_ = uiConfig.ApplyTheme(profile.Theme)
+	}
+
+	return uiConfig, nil
+}
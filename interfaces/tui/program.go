@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+)
+
+// runProgram runs a program until it quits or ctx ends, e.g. when the --timeout passes.
+// A program stopped by ctx ends without an error.
+func runProgram(ctx context.Context, model tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	p := tea.NewProgram(model, append(opts, tea.WithContext(ctx))...)
+	finalModel, err := p.Run()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		return finalModel, nil
+	}
+	return finalModel, err
+}
+
+// runScreen runs the screen of a Run function like runProgram. newModel builds the model with a
+// context of its own, so requests of the screen still running when it closes are cancelled, and
+// returns the options of the program with it.
+func runScreen(ctx context.Context, newModel func(ctx context.Context) (tea.Model, []tea.ProgramOption, error)) (tea.Model, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	model, opts, err := newModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return runProgram(ctx, model, opts...)
+}
+
+// withMouse reports clicks and wheel scrolls to the program unless the mouse is turned off in
+// appConfig, for terminals that capture it themselves
+func withMouse(appConfig *config.Config) tea.ProgramOption {
+	if !appConfig.Mouse {
+		return func(*tea.Program) {}
+	}
+	return tea.WithMouseCellMotion()
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// clockInterval is how often position-based indicators such as the intro countdown are redrawn
+const clockInterval = 250 * time.Millisecond
+
+// clockTickMsg is a message sent when position-based indicators should be redrawn
+type clockTickMsg time.Time
+
+// clockTick schedules the next redraw of position-based indicators
+func clockTick() tea.Cmd {
+	return tea.Tick(clockInterval, func(t time.Time) tea.Msg {
+		return clockTickMsg(t)
+	})
+}
+
+// progressClock interpolates the playback position between lyric updates
+type progressClock struct {
+	progressMs int
+	durationMs int
+	polledAt   time.Time
+	playing    bool
+}
+
+// observe records the position reported with a lyric update
+func (c *progressClock) observe(track *usecase.CurrentlyPlaying) {
+	if track == nil {
+		return
+	}
+	c.progressMs = track.ProgressMs
+	c.durationMs = track.DurationMs
+	c.polledAt = time.Now()
+	c.playing = track.IsPlaying
+}
+
+// now returns the interpolated playback position
+func (c *progressClock) now() int {
+	if c.playing && !c.polledAt.IsZero() {
+		return c.progressMs + int(time.Since(c.polledAt).Milliseconds())
+	}
+	return c.progressMs
+}
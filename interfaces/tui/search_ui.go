@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+)
+
+// searchLimit is the number of results a search shows
+const searchLimit = 20
+
+// searchResultsRow is the screen row of the first result, below the title and the query
+const searchResultsRow = 4
+
+// SearchModel is the model for searching the Spotify catalog and playing the results
+type SearchModel struct {
+	playerUseCase usecase.PlayerUseCase
+	searchUseCase usecase.SearchUseCase
+	query         string
+	// typing sends the keys to the query instead of the results
+	typing      bool
+	itemType    string
+	results     []usecase.SearchResult
+	searching   bool
+	cursor      int
+	status      string
+	quitting    bool
+	windowWidth int
+	keys        keyMap
+	help        bool
+	// hideExplicit leaves explicit tracks out in the clean-content mode
+	hideExplicit bool
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// searchResultsMsg carries the results of a search
+type searchResultsMsg struct {
+	query   string
+	results []usecase.SearchResult
+	err     error
+}
+
+// searchActionMsg carries the result of an action on a search result
+type searchActionMsg struct {
+	status string
+	err    error
+}
+
+// NewSearchModel creates a new search model, starting with the query to type in
+func NewSearchModel(ctx context.Context, appConfig *config.Config, playerUseCase usecase.PlayerUseCase, searchUseCase usecase.SearchUseCase) *SearchModel {
+	ctx, cancel := context.WithCancel(ctx)
+	return &SearchModel{
+		playerUseCase: playerUseCase,
+		searchUseCase: searchUseCase,
+		typing:        true,
+		itemType:      usecase.SearchTypes[0],
+		hideExplicit:  appConfig.Clean.Enabled,
+		windowWidth:   80,
+		keys:          newKeyMap(appConfig.Keybindings),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Init initializes the model
+func (m *SearchModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update updates the model
+func (m *SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.typing && msg.Type != tea.KeyCtrlC {
+			return m, m.handleInput(msg)
+		}
+
+		switch {
+		case m.keys.matches(msg, keyHelp):
+			m.help = !m.help
+		case m.help && m.keys.matches(msg, keyBack):
+			m.help = false
+		case m.keys.matches(msg, keyQuit, keyBack):
+			m.quitting = true
+			m.cancel()
+			return m, tea.Quit
+		case m.keys.matches(msg, keySearch):
+			m.typing = true
+		case m.keys.matches(msg, keyUp):
+			m.cursor = max(0, m.cursor-1)
+		case m.keys.matches(msg, keyDown):
+			m.cursor = max(0, min(len(m.results)-1, m.cursor+1))
+		case m.keys.matches(msg, keyTop):
+			m.cursor = 0
+		case m.keys.matches(msg, keyBottom):
+			m.cursor = max(0, len(m.results)-1)
+		case m.keys.matches(msg, keySelect):
+			if result, ok := m.selected(); ok {
+				return m, m.action(fmt.Sprintf("Playing %s (%s)", result.Name, result.Detail), func(ctx context.Context) error {
+					return m.playerUseCase.PlayURI(ctx, result.URI)
+				})
+			}
+		case m.keys.matches(msg, keyQueue):
+			if result, ok := m.selected(); ok {
+				if result.Type != "track" {
+					m.status = "Only tracks can be added to the queue"
+					return m, nil
+				}
+				return m, m.action(fmt.Sprintf("Added to queue: %s (%s)", result.Name, result.Detail), func(ctx context.Context) error {
+					return m.playerUseCase.AddToQueue(ctx, result.URI)
+				})
+			}
+		}
+
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.cursor = max(0, m.cursor-1)
+		case tea.MouseButtonWheelDown:
+			m.cursor = max(0, min(len(m.results)-1, m.cursor+1))
+		case tea.MouseButtonLeft:
+			// Select the result clicked, and play it when it was already selected
+			i := m.resultAt(msg.Y)
+			if i < 0 {
+				return m, nil
+			}
+			if i == m.cursor && !m.typing {
+				return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			}
+			m.typing = false
+			m.cursor = i
+		}
+
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+
+	case searchResultsMsg:
+		m.searching = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.results = msg.results
+		if m.hideExplicit {
+			m.results = usecase.WithoutExplicit(m.results)
+		}
+		m.cursor = 0
+		m.status = ""
+		if len(m.results) == 0 {
+			m.status = fmt.Sprintf("No %ss found for %q", m.itemType, msg.query)
+			return m, nil
+		}
+		// Move on to the results, which the search key goes back from
+		m.typing = false
+
+	case searchActionMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.status = msg.status
+	}
+
+	return m, nil
+}
+
+// handleInput handles key presses while the query is typed in
+func (m *SearchModel) handleInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		query := strings.TrimSpace(m.query)
+		if query == "" || m.searching {
+			return nil
+		}
+		m.searching = true
+		m.status = "Searching..."
+		return m.search(query, m.itemType)
+	case tea.KeyEsc:
+		// Back to the results, or out of the search without any
+		if len(m.results) > 0 {
+			m.typing = false
+			return nil
+		}
+		m.quitting = true
+		m.cancel()
+		return tea.Quit
+	case tea.KeyTab:
+		// Cycle through the types of items to search for
+		for i, itemType := range usecase.SearchTypes {
+			if itemType == m.itemType {
+				m.itemType = usecase.SearchTypes[(i+1)%len(usecase.SearchTypes)]
+				break
+			}
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.query += " "
+	case tea.KeyRunes:
+		m.query += string(msg.Runes)
+	}
+	return nil
+}
+
+// View renders the model
+func (m *SearchModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	// Get styles from the shared styles
+	titleStyle := GetTitleStyle(m.windowWidth)
+	headerStyle := GetHeaderStyle()
+	valueStyle := GetValueStyle()
+	selectedStyle := GetSelectedStyle()
+	infoStyle := GetInfoStyle()
+
+	// Build the view
+	s := titleStyle.Render("sprt Search") + "\n\n"
+
+	query := m.query
+	if m.typing {
+		query += "█"
+	}
+	s += headerStyle.Render(fmt.Sprintf("Search %ss: ", m.itemType)) + GetInputStyle().Render(query) + "\n\n"
+
+	for i, result := range m.results {
+		line := truncateText(fmt.Sprintf("%2d. %s – %s", i+1, result.Name, result.Detail), m.windowWidth-2)
+		if i == m.cursor && !m.typing {
+			s += "> " + selectedStyle.Render(line) + "\n"
+		} else {
+			s += "  " + valueStyle.Render(line) + "\n"
+		}
+	}
+
+	s += "\n"
+	if m.status != "" {
+		s += infoStyle.Render(m.status) + "\n"
+	}
+	switch {
+	case m.help:
+		s += m.keys.helpView(m.windowWidth, keyUp, keyDown, keyTop, keyBottom, keySelect, keyQueue, keySearch, keyHelp, keyQuit, keyBack)
+	case m.typing:
+		s += infoStyle.Render("enter search • tab type • esc back")
+	default:
+		s += infoStyle.Render(m.keys.footer(keyUp, keyDown, keySelect, keyQueue, keySearch, keyQuit))
+	}
+
+	return s
+}
+
+// resultAt returns the index of the result drawn at the given screen row, or -1 for none
+func (m *SearchModel) resultAt(y int) int {
+	i := y - searchResultsRow
+	if i < 0 || i >= len(m.results) {
+		return -1
+	}
+	return i
+}
+
+// selected returns the result under the cursor
+func (m *SearchModel) selected() (usecase.SearchResult, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.results) {
+		return usecase.SearchResult{}, false
+	}
+	return m.results[m.cursor], true
+}
+
+// search looks up the items of itemType matching query
+func (m *SearchModel) search(query, itemType string) tea.Cmd {
+	return func() tea.Msg {
+		results, err := m.searchUseCase.Search(m.ctx, query, itemType, searchLimit)
+		return searchResultsMsg{query: query, results: results, err: err}
+	}
+}
+
+// action runs an action on a search result and reports the result
+func (m *SearchModel) action(status string, fn func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		return searchActionMsg{status: status, err: fn(m.ctx)}
+	}
+}
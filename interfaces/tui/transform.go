@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/muhadif/sprt/config"
+)
+
+// Lyric text transform types
+const (
+	transformUppercase        = "uppercase"
+	transformLowercase        = "lowercase"
+	transformStripPunctuation = "strip-punctuation"
+	transformStripBrackets    = "strip-brackets"
+	transformRegex            = "regex"
+	transformMaskExplicit     = "mask-explicit"
+)
+
+// bracketedPattern matches bracketed ad-libs such as "(yeah)" or "[chorus]" with the space before them
+var bracketedPattern = regexp.MustCompile(`\s*(\([^()]*\)|\[[^\[\]]*\])`)
+
+// explicitPattern matches common English swear words and slurs with their usual inflections
+var explicitPattern = regexp.MustCompile(`(?i)\b(` +
+	`(mother)?fuck\w*|shit\w*|bullshit\w*|bitch\w*|ass|asses|asshole\w*|dick|dicks|cunt\w*|` +
+	`pussy|pussies|cock|cocks|goddamn\w*|damn|damned|whore\w*|slut\w*|bastard\w*|piss|pissed|pissing|` +
+	`nigga\w*|nigger\w*|fag|fags|faggot\w*` +
+	`)\b`)
+
+// textTransform is one step of the lyric text transform chain
+type textTransform struct {
+	apply       func(string) string
+	currentOnly bool
+}
+
+// transformChain applies lyric text transforms in order
+type transformChain []textTransform
+
+// newTransformChain builds the transform chain from the configuration
+func newTransformChain(configs []config.TransformConfig) (transformChain, error) {
+	chain := make(transformChain, 0, len(configs))
+	for i, cfg := range configs {
+		var apply func(string) string
+		switch cfg.Type {
+		case transformUppercase:
+			apply = strings.ToUpper
+		case transformLowercase:
+			apply = strings.ToLower
+		case transformStripPunctuation:
+			apply = stripPunctuation
+		case transformStripBrackets:
+			apply = func(text string) string {
+				return strings.TrimSpace(bracketedPattern.ReplaceAllString(text, ""))
+			}
+		case transformMaskExplicit:
+			apply = maskExplicit
+		case transformRegex:
+			pattern, err := regexp.Compile(cfg.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern in transform %d: %w", i+1, err)
+			}
+			replace := cfg.Replace
+			apply = func(text string) string {
+				return pattern.ReplaceAllString(text, replace)
+			}
+		default:
+			return nil, fmt.Errorf("unknown transform type %q (expected %s, %s, %s, %s, %s or %s)", cfg.Type,
+				transformUppercase, transformLowercase, transformStripPunctuation, transformStripBrackets,
+				transformMaskExplicit, transformRegex)
+		}
+		chain = append(chain, textTransform{apply: apply, currentOnly: cfg.CurrentOnly})
+	}
+	return chain, nil
+}
+
+// lyricTransforms returns the configured transforms, followed by masking explicit words
+// while the clean-content mode asks for it, so that no other transform can undo it.
+func lyricTransforms(cfg *config.Config) []config.TransformConfig {
+	transforms := cfg.Output.Transforms
+	if cfg.Clean.Enabled && cfg.Clean.MaskLyrics {
+		transforms = append(slices.Clip(transforms), config.TransformConfig{Type: transformMaskExplicit})
+	}
+	return transforms
+}
+
+// apply runs the text through the chain; current tells whether it is the current line.
+// Surrounding whitespace used for padding is kept as is.
+func (c transformChain) apply(text string, current bool) string {
+	if len(c) == 0 {
+		return text
+	}
+
+	core := strings.TrimSpace(text)
+	start := strings.Index(text, core)
+	prefix, suffix := text[:start], text[start+len(core):]
+
+	for _, transform := range c {
+		if transform.currentOnly && !current {
+			continue
+		}
+		core = transform.apply(core)
+	}
+	return prefix + core + suffix
+}
+
+// maskExplicit replaces all but the first letter of explicit words with asterisks, e.g. "s***"
+func maskExplicit(text string) string {
+	return explicitPattern.ReplaceAllStringFunc(text, func(word string) string {
+		runes := []rune(word)
+		return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+	})
+}
+
+// stripPunctuation removes punctuation, keeping apostrophes inside words such as "don't"
+func stripPunctuation(text string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsPunct(r) {
+			inWord := (r == '\'' || r == '’') && i > 0 && i < len(runes)-1 &&
+				unicode.IsLetter(runes[i-1]) && unicode.IsLetter(runes[i+1])
+			if !inWord {
+				continue
+			}
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
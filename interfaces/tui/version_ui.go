@@ -2,6 +2,7 @@ package tui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
 )
 
 // VersionModel is the model for the version UI
@@ -11,15 +12,17 @@ type VersionModel struct {
 	commitHash  string
 	quitting    bool
 	windowWidth int
+	keys        keyMap
 }
 
 // NewVersionModel creates a new version model
-func NewVersionModel(version, buildDate, commitHash string) *VersionModel {
+func NewVersionModel(appConfig *config.Config, version, buildDate, commitHash string) *VersionModel {
 	return &VersionModel{
 		version:     version,
 		buildDate:   buildDate,
 		commitHash:  commitHash,
 		windowWidth: 80,
+		keys:        newKeyMap(appConfig.Keybindings),
 	}
 }
 
@@ -32,8 +35,7 @@ func (m VersionModel) Init() tea.Cmd {
 func (m VersionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		if m.keys.matches(msg, keyQuit, keyBack) {
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -72,8 +74,8 @@ func (m VersionModel) View() string {
 }
 
 // RunVersionUI runs the version UI
-func RunVersionUI(version, buildDate, commitHash string) error {
-	p := tea.NewProgram(NewVersionModel(version, buildDate, commitHash), tea.WithAltScreen())
+func RunVersionUI(appConfig *config.Config, version, buildDate, commitHash string) error {
+	p := tea.NewProgram(NewVersionModel(appConfig, version, buildDate, commitHash), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
@@ -5,11 +5,13 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muhadif/sprt/config"
 	"github.com/muhadif/sprt/domain/usecase"
 )
 
 // WaitingTrackModel is the model for the waiting track UI
 type WaitingTrackModel struct {
+	appConfig   *config.Config
 	authUseCase usecase.AuthUseCase
 	status      string
 	dots        int
@@ -17,19 +19,22 @@ type WaitingTrackModel struct {
 	ticker      *time.Ticker
 	quitting    bool
 	windowWidth int
+	keys        keyMap
 	ctx         context.Context
 	cancel      context.CancelFunc
 }
 
-// NewWaitingTrackModel creates a new waiting track model
-func NewWaitingTrackModel(authUseCase usecase.AuthUseCase) *WaitingTrackModel {
-	ctx, cancel := context.WithCancel(context.Background())
+// NewWaitingTrackModel creates a new waiting track model that polls until ctx ends
+func NewWaitingTrackModel(ctx context.Context, appConfig *config.Config, authUseCase usecase.AuthUseCase) *WaitingTrackModel {
+	ctx, cancel := context.WithCancel(ctx)
 	return &WaitingTrackModel{
 		authUseCase: authUseCase,
 		status:      "No track currently playing",
 		dots:        0,
 		maxDots:     3,
 		windowWidth: 80,
+		appConfig:   appConfig,
+		keys:        newKeyMap(appConfig.Keybindings),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
@@ -45,8 +50,7 @@ func (m *WaitingTrackModel) Init() tea.Cmd {
 func (m *WaitingTrackModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		if m.keys.matches(msg, keyQuit, keyBack) {
 			m.quitting = true
 			m.ticker.Stop()
 			m.cancel()
@@ -59,17 +63,14 @@ func (m *WaitingTrackModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.dots = (m.dots + 1) % (m.maxDots + 1)
 
 		// Check if a track is playing
-		trackInfo, err := m.authUseCase.GetCurrentlyPlaying(m.ctx)
-		if err == nil && trackInfo != "No track currently playing" {
+		track, err := m.authUseCase.GetCurrentlyPlaying(m.ctx)
+		if err == nil {
 			// Track is now playing, return it
 			m.ticker.Stop()
 			m.cancel()
 
-			// Parse the track information
-			title, artist, album := parseTrackInfo(trackInfo)
-
 			// Create and return the current track model
-			return NewCurrentTrackModel(artist, title, album, "Unknown", "Unknown", true), nil
+			return NewCurrentTrackModel(m.appConfig, track.Artist, track.Title, track.Album, "Unknown", "Unknown", true), nil
 		}
 
 		return m, m.tick
@@ -102,7 +103,7 @@ func (m *WaitingTrackModel) View() string {
 	// Content
 	content := headerStyle.Render(m.status) + valueStyle.Render(dots) + "\n\n"
 	content += valueStyle.Render("Waiting for a track to play on Spotify") + "\n\n"
-	content += valueStyle.Render("Press q to return to menu")
+	content += valueStyle.Render("Press " + m.keys.hint(keyQuit) + " to return to menu")
 
 	s += border.Render(content)
 
@@ -112,19 +113,21 @@ func (m *WaitingTrackModel) View() string {
 // tickMsg is a message sent when the ticker ticks
 type tickMsg struct{}
 
-// tick is a command that waits for the ticker to tick
+// tick is a command that waits for the ticker to tick, stopping it once ctx ends
 func (m *WaitingTrackModel) tick() tea.Msg {
 	select {
 	case <-m.ticker.C:
 		return tickMsg{}
 	case <-m.ctx.Done():
+		m.ticker.Stop()
 		return nil
 	}
 }
 
-// RunWaitingTrackUI runs the waiting track UI
-func RunWaitingTrackUI(authUseCase usecase.AuthUseCase) error {
-	p := tea.NewProgram(NewWaitingTrackModel(authUseCase), tea.WithAltScreen())
-	_, err := p.Run()
+// RunWaitingTrackUI runs the waiting track UI until a track plays or ctx ends
+func RunWaitingTrackUI(ctx context.Context, appConfig *config.Config, authUseCase usecase.AuthUseCase) error {
+	_, err := runScreen(ctx, func(ctx context.Context) (tea.Model, []tea.ProgramOption, error) {
+		return NewWaitingTrackModel(ctx, appConfig, authUseCase), []tea.ProgramOption{tea.WithAltScreen()}, nil
+	})
 	return err
 }
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"github.com/muhadif/sprt/config"
+	"github.com/muhadif/sprt/domain/usecase"
+	"github.com/muhadif/sprt/infrastructure/title"
+)
+
+// windowTitle shows the current track in the terminal window or tmux pane title.
+// A nil windowTitle does nothing.
+type windowTitle struct {
+	title  *title.Title
+	format string
+}
+
+// newWindowTitle sets up the window title for the configured mode; nil is returned when it is off.
+func newWindowTitle(cfg config.TitleConfig) (*windowTitle, error) {
+	t, err := title.New(cfg.Mode)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return &windowTitle{title: t, format: cfg.Format}, nil
+}
+
+// show puts track in the title, or restores the previous title when nothing is playing.
+func (w *windowTitle) show(track *usecase.CurrentlyPlaying) {
+	if w == nil {
+		return
+	}
+	// The title is cosmetic, failing to set it never interrupts the lyrics
+	if track == nil {
+		_ = w.title.Clear()
+		return
+	}
+	_ = w.title.Set(title.Format(w.format, track.Title, track.Artist, track.Album))
+}
+
+// clear restores the title shown before sprt started.
+func (w *windowTitle) clear() {
+	if w != nil {
+		_ = w.title.Clear()
+	}
+}